@@ -15,7 +15,7 @@ import (
 	"caller/internal/server"
 )
 
-// CreateServices create grpc or http service
+// CreateServices create grpc or http service, and the background workers
 func CreateServices() []app.IServer {
 	var cfg = config.Get()
 	var servers []app.IServer
@@ -29,6 +29,15 @@ func CreateServices() []app.IServer {
 	)
 	servers = append(servers, httpServer)
 
+	// creating the worker that deletes expired records, it is stopped with the other servers on close
+	if cfg.Retention.Enable {
+		servers = append(servers, newRetentionWorker(cfg.Retention))
+	}
+	// creating the worker that sends the instructions of the scheduled calls, every replica can run it
+	if cfg.Scheduler.Enable {
+		servers = append(servers, newSchedulerWorker(cfg.Scheduler))
+	}
+
 	return servers
 }
 
@@ -6,7 +6,9 @@ package initial
 import (
 	"flag"
 	"fmt"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/jinzhu/copier"
 
@@ -18,6 +20,8 @@ import (
 
 	"caller/configs"
 	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/handler"
 	"caller/internal/model"
 )
 
@@ -29,32 +33,23 @@ var (
 
 // InitApp initial app configuration
 func InitApp() {
-	initConfig()
+	initConfig(os.Args[1:])
+	initLogger()
 	cfg := config.Get()
 
-	// initializing log
-	_, err := logger.Init(
-		logger.WithLevel(cfg.Logger.Level),
-		logger.WithFormat(cfg.Logger.Format),
-		logger.WithSave(
-			cfg.Logger.IsSave,
-			//logger.WithFileName(cfg.Logger.LogFileConfig.Filename),
-			//logger.WithFileMaxSize(cfg.Logger.LogFileConfig.MaxSize),
-			//logger.WithFileMaxBackups(cfg.Logger.LogFileConfig.MaxBackups),
-			//logger.WithFileMaxAge(cfg.Logger.LogFileConfig.MaxAge),
-			//logger.WithFileIsCompression(cfg.Logger.LogFileConfig.IsCompression),
-		),
-	)
-	if err != nil {
-		panic(err)
-	}
-	logger.Debug(config.Show())
-	logger.Info("init logger succeeded")
-
 	// initializing database
 	model.InitDB()
 	logger.Infof("init %s succeeded", cfg.Database.Driver)
 	model.InitCache(cfg.App.CacheType)
+	dao.SetCursorSecret(cfg.App.CursorSecret)
+	dao.SetIPMassChange(cfg.App.IPMassChangeThreshold, time.Duration(cfg.App.IPMassChangeWindow)*time.Second)
+	if cfg.App.DeviceCommandTTL > 0 {
+		model.DeviceCommandTTL = time.Duration(cfg.App.DeviceCommandTTL) * time.Second
+	}
+	err := handler.SetRecordingStorage(cfg.Recording)
+	if err != nil {
+		panic("init recording storage error: " + err.Error())
+	}
 
 	// initializing tracing
 	if cfg.App.EnableTrace {
@@ -79,12 +74,34 @@ func InitApp() {
 	}
 }
 
-func initConfig() {
+func initLogger() {
+	cfg := config.Get()
+	_, err := logger.Init(
+		logger.WithLevel(cfg.Logger.Level),
+		logger.WithFormat(cfg.Logger.Format),
+		logger.WithSave(
+			cfg.Logger.IsSave,
+			//logger.WithFileName(cfg.Logger.LogFileConfig.Filename),
+			//logger.WithFileMaxSize(cfg.Logger.LogFileConfig.MaxSize),
+			//logger.WithFileMaxBackups(cfg.Logger.LogFileConfig.MaxBackups),
+			//logger.WithFileMaxAge(cfg.Logger.LogFileConfig.MaxAge),
+			//logger.WithFileIsCompression(cfg.Logger.LogFileConfig.IsCompression),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+	logger.Debug(config.Show())
+	logger.Info("init logger succeeded")
+}
+
+// initConfig parse the command line arguments and load the configuration
+func initConfig(args []string) {
 	flag.StringVar(&version, "version", "", "service Version Number")
 	flag.BoolVar(&enableConfigCenter, "enable-cc", false, "whether to get from the configuration center, "+
 		"if true, the '-c' parameter indicates the configuration center")
 	flag.StringVar(&configFile, "c", "", "configuration file")
-	flag.Parse()
+	_ = flag.CommandLine.Parse(args) // exits on error
 
 	if enableConfigCenter {
 		// get the configuration from the configuration center (first get the nacos configuration,
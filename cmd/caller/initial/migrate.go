@@ -0,0 +1,99 @@
+package initial
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+
+	"caller/internal/migration"
+	"caller/internal/model"
+)
+
+const migrateUsage = `usage: caller migrate <up|down|status> [-c configFile] [-enable-cc] [-steps n] [-lock-timeout 1m]
+
+  up      apply pending migrations, -steps limits the number applied, default all
+  down    roll back applied migrations, -steps is the number rolled back, default 1
+  status  list migrations and whether they are applied
+`
+
+// RunMigrate run the migrate subcommand with the arguments after "migrate" and return the exit code
+func RunMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	action := args[0]
+	if action != "up" && action != "down" && action != "status" {
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", action, migrateUsage)
+		return 2
+	}
+
+	var steps int
+	var lockTimeout time.Duration
+	flag.IntVar(&steps, "steps", 0, "number of migrations to apply or roll back")
+	flag.DurationVar(&lockTimeout, "lock-timeout", time.Minute, "how long to wait for another replica to finish migrating")
+	initConfig(args[1:])
+	initLogger()
+
+	model.InitDB()
+	defer func() {
+		_ = model.CloseDB()
+	}()
+
+	m, err := migration.New(model.GetDB(), migration.WithLockTimeout(lockTimeout))
+	if err != nil {
+		logger.Error("migration.New error", logger.Err(err))
+		return 1
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "up":
+		done, err := m.Up(ctx, steps)
+		printMigrations("applied", done)
+		if err != nil {
+			logger.Error("migrate up error", logger.Err(err))
+			return 1
+		}
+	case "down":
+		done, err := m.Down(ctx, steps)
+		printMigrations("rolled back", done)
+		if err != nil {
+			logger.Error("migrate down error", logger.Err(err))
+			return 1
+		}
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			logger.Error("migrate status error", logger.Err(err))
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			status, appliedAt := "pending", ""
+			if s.Applied {
+				status, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+		}
+		_ = w.Flush()
+	}
+
+	return 0
+}
+
+func printMigrations(verb string, migrations []*migration.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("no migrations %s\n", verb)
+		return
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
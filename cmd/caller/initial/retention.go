@@ -0,0 +1,48 @@
+package initial
+
+import (
+	"fmt"
+	"time"
+
+	"caller/internal/cache"
+	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/model"
+	"caller/internal/retention"
+)
+
+// newRetentionWorker create the worker that deletes the expired records of the configured tables
+func newRetentionWorker(cfg config.Retention) *retention.Worker {
+	policies := make([]retention.Policy, 0, len(cfg.Tables))
+	for _, table := range cfg.Tables {
+		if table.MaxAge <= 0 {
+			panic(fmt.Sprintf("retention maxAge of table %s must be greater than 0", table.Name))
+		}
+		policies = append(policies, retention.Policy{
+			Table:      table.Name,
+			MaxAge:     time.Duration(table.MaxAge) * 24 * time.Hour,
+			HardDelete: table.HardDelete,
+			Deleter:    newRetentionDeleter(table.Name),
+		})
+	}
+
+	return retention.NewWorker(policies,
+		retention.WithInterval(time.Duration(cfg.Interval)*time.Minute),
+		retention.WithBatchSize(cfg.BatchSize),
+		retention.WithBatchInterval(time.Duration(cfg.BatchInterval)*time.Millisecond),
+	)
+}
+
+// newRetentionDeleter the dao that deletes the records of the table, its cache is the one of the handlers,
+// so the deleted records are not served from the cache afterwards
+func newRetentionDeleter(table string) retention.Deleter {
+	switch table {
+	case "call_history":
+		return dao.NewCallHistoryDao(model.GetDB(), cache.NewCallHistoryCache(model.GetCacheType()))
+	case "sms":
+		return dao.NewSmsDao(model.GetDB(), cache.NewSmsCache(model.GetCacheType()))
+	case "unanswerd_call":
+		return dao.NewUnanswerdCallDao(model.GetDB(), cache.NewUnanswerdCallCache(model.GetCacheType()))
+	}
+	panic(fmt.Sprintf("retention is not supported for table %s", table))
+}
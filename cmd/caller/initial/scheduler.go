@@ -0,0 +1,21 @@
+package initial
+
+import (
+	"time"
+
+	"caller/internal/cache"
+	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/model"
+	"caller/internal/scheduler"
+)
+
+// newSchedulerWorker create the worker that sends the instructions of the scheduled calls when they are due
+func newSchedulerWorker(cfg config.Scheduler) *scheduler.Worker {
+	return scheduler.NewWorker(
+		dao.NewCallHistoryDao(model.GetDB(), cache.NewCallHistoryCache(model.GetCacheType())),
+		cache.NewDeviceCommandNotifier(model.GetCacheType()),
+		scheduler.WithInterval(time.Duration(cfg.Interval)*time.Second),
+		scheduler.WithBatchSize(cfg.BatchSize),
+	)
+}
@@ -2,6 +2,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/zhufuyi/sponge/pkg/app"
 
 	"caller/cmd/caller/initial"
@@ -17,6 +19,11 @@ import (
 // @name Authorization
 // @description Type Bearer your-jwt-token to Value
 func main() {
+	// database schema migrations, e.g. caller migrate up -c configs/caller.yml
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(initial.RunMigrate(os.Args[2:]))
+	}
+
 	initial.InitApp()
 	services := initial.CreateServices()
 	closes := initial.Close(services)
@@ -22,7 +22,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create callHistory",
+                "description": "submit information to create callHistory, the instruction and its payload are validated, see GET /api/v1/instructions. with scheduledAt or scheduleIn in the future the call is scheduled, its instruction is sent to the client device at that time",
                 "consumes": [
                     "application/json"
                 ],
@@ -54,6 +54,45 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/callHistory/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit at most 100 callHistorys in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "callHistory"
+                ],
+                "summary": "create callHistorys in batch",
+                "parameters": [
+                    {
+                        "description": "callHistory information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateCallHistorysBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateCallHistorysBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/callHistory/condition": {
             "post": {
                 "security": [
@@ -139,7 +178,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of callHistorys by last id and limit",
+                "description": "list of callHistorys by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,15 +188,13 @@ const docTemplate = `{
                 "tags": [
                     "callHistory"
                 ],
-                "summary": "list of callHistorys by last id and limit",
+                "summary": "list of callHistorys by cursor and limit",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
@@ -169,7 +206,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "default": "-id",
-                        "description": "sort by column name of table, and the ",
+                        "description": "sort by id, created_at, updated_at, request_machine_code, client_machine_code, mobile_number, multiple columns separated by commas, the ",
                         "name": "sort",
                         "in": "query"
                     }
@@ -178,7 +215,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListCallHistorysRespond"
+                            "$ref": "#/definitions/types.ListCallHistorysByCursorRespond"
                         }
                     }
                 }
@@ -260,6 +297,93 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/callHistory/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted callHistorys by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "callHistory"
+                ],
+                "summary": "list of deleted callHistorys by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, request_machine_code, client_machine_code, mobile_number, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListCallHistorysByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/callHistory/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "permanently delete a callHistory in the trash by id with its recording, it cannot be restored afterwards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "callHistory"
+                ],
+                "summary": "purge callHistory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeCallHistoryByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/callHistory/{id}": {
             "get": {
                 "security": [
@@ -292,6 +416,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/types.GetCallHistoryByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
                         }
                     }
                 }
@@ -321,6 +451,12 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "callHistory information",
                         "name": "data",
@@ -364,6 +500,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -374,16 +516,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/clients": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create clients",
+                "description": "update the callHistory fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
                 "consumes": [
                     "application/json"
                 ],
@@ -391,17 +531,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clients"
+                    "callHistory"
                 ],
-                "summary": "create clients",
+                "summary": "patch callHistory",
                 "parameters": [
                     {
-                        "description": "clients information",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "callHistory fields to update",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateClientsRequest"
+                            "$ref": "#/definitions/types.PatchCallHistoryByIDRequest"
                         }
                     }
                 ],
@@ -409,20 +562,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateClientsRespond"
+                            "$ref": "#/definitions/types.PatchCallHistoryByIDRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/clients/condition": {
+        "/api/v1/callHistory/{id}/cancel": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get clients by condition",
+                "description": "cancel a call that is scheduled, its instruction is never sent to the client device. cancelling a cancelled call again is not an error, a call that has been requested cannot be cancelled",
                 "consumes": [
                     "application/json"
                 ],
@@ -430,77 +583,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clients"
+                    "callHistory"
                 ],
-                "summary": "get clients by condition",
+                "summary": "cancel a scheduled call",
                 "parameters": [
                     {
-                        "description": "query condition",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Conditions"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetClientsByConditionRespond"
+                            "$ref": "#/definitions/types.CancelCallHistoryRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/clients/delete/ids": {
-            "post": {
+        "/api/v1/callHistory/{id}/recording/download": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete clientss by batch id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "download the audio of the recording of a call that has been uploaded and not deleted, a range of the audio can be requested\nwith the Range header. the X-Checksum-Sha256 header is the hex sha256 of the audio",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "clients"
+                    "callRecording"
                 ],
-                "summary": "delete clientss",
+                "summary": "download a call recording",
                 "parameters": [
                     {
-                        "description": "id array",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.DeleteClientssByIDsRequest"
-                        }
+                        "type": "string",
+                        "description": "id of the call history",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "bytes of the audio to download, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteClientssByIDsRespond"
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/api/v1/clients/list": {
-            "get": {
+        "/api/v1/callHistory/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of clientss by last id and limit",
+                "description": "restore a deleted callHistory by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -508,49 +666,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clients"
+                    "callHistory"
                 ],
-                "summary": "list of clientss by last id and limit",
+                "summary": "restore callHistory",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "size in each page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "default": "-id",
-                        "description": "sort by column name of table, and the ",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListClientssRespond"
+                            "$ref": "#/definitions/types.RestoreCallHistoryByIDRespond"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/callHistory/{id}/schedule": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of clientss by paging and conditions",
+                "description": "change the time the instruction of a scheduled call is sent to the client device, a time that is not in the future sends it at the next run of the scheduler. a call that has been requested or cancelled cannot be rescheduled",
                 "consumes": [
                     "application/json"
                 ],
@@ -558,17 +703,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clients"
+                    "callHistory"
                 ],
-                "summary": "list of clientss by query parameters",
+                "summary": "reschedule a scheduled call",
                 "parameters": [
                     {
-                        "description": "query parameters",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "time of the call",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Params"
+                            "$ref": "#/definitions/types.RescheduleCallHistoryRequest"
                         }
                     }
                 ],
@@ -576,20 +728,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListClientssRespond"
+                            "$ref": "#/definitions/types.RescheduleCallHistoryRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/clients/list/ids": {
+        "/api/v1/clients": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of clientss by batch id",
+                "description": "submit information to create clients",
                 "consumes": [
                     "application/json"
                 ],
@@ -599,15 +751,15 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "list of clientss by batch id",
+                "summary": "create clients",
                 "parameters": [
                     {
-                        "description": "id array",
+                        "description": "clients information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ListClientssByIDsRequest"
+                            "$ref": "#/definitions/types.CreateClientsRequest"
                         }
                     }
                 ],
@@ -615,20 +767,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListClientssByIDsRespond"
+                            "$ref": "#/definitions/types.CreateClientsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/clients/{id}": {
-            "get": {
+        "/api/v1/clients/batch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get clients detail by id",
+                "description": "submit at most 100 clientss in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
                 "consumes": [
                     "application/json"
                 ],
@@ -638,32 +790,36 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "get clients detail",
+                "summary": "create clientss in batch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                        "description": "clients information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateClientssBatchRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetClientsByIDRespond"
+                            "$ref": "#/definitions/types.CreateClientssBatchRespond"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/clients/condition": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update clients information by id",
+                "description": "get clients by condition",
                 "consumes": [
                     "application/json"
                 ],
@@ -673,22 +829,15 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "update clients",
+                "summary": "get clients by condition",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "clients information",
+                        "description": "query condition",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateClientsByIDRequest"
+                            "$ref": "#/definitions/types.Conditions"
                         }
                     }
                 ],
@@ -696,18 +845,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateClientsByIDRespond"
+                            "$ref": "#/definitions/types.GetClientsByConditionRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/clients/delete/ids": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete clients by id",
+                "description": "delete clientss by batch id",
                 "consumes": [
                     "application/json"
                 ],
@@ -717,34 +868,31 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "delete clients",
+                "summary": "delete clientss",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteClientssByIDsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteClientsByIDRespond"
+                            "$ref": "#/definitions/types.DeleteClientssByIDsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution": {
+        "/api/v1/clients/enroll": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "submit information to create distribution",
+                "description": "exchange a one-time enrollment code and the machine code of a device for its credential, the client is created if it does not exist, a device that has a credential is only enrolled again with a code issued for its machine code, the credential is only returned in this respond",
                 "consumes": [
                     "application/json"
                 ],
@@ -752,17 +900,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "create distribution",
+                "summary": "enroll device",
                 "parameters": [
                     {
-                        "description": "distribution information",
+                        "description": "enrollment information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateDistributionRequest"
+                            "$ref": "#/definitions/types.EnrollClientsRequest"
                         }
                     }
                 ],
@@ -770,20 +918,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateDistributionRespond"
+                            "$ref": "#/definitions/types.EnrollClientsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution/condition": {
+        "/api/v1/clients/enrollmentCode": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get distribution by condition",
+                "description": "issue a one-time code that a device exchanges for its credential, the code is only returned in this respond. a device that already has a credential can only use a code issued for its machine code",
                 "consumes": [
                     "application/json"
                 ],
@@ -791,17 +939,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "get distribution by condition",
+                "summary": "issue enrollment code",
                 "parameters": [
                     {
-                        "description": "query condition",
+                        "description": "enrollment code information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Conditions"
+                            "$ref": "#/definitions/types.IssueClientsEnrollmentCodeRequest"
                         }
                     }
                 ],
@@ -809,20 +957,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetDistributionByConditionRespond"
+                            "$ref": "#/definitions/types.IssueClientsEnrollmentCodeRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution/delete/ids": {
+        "/api/v1/clients/heartbeat": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete distributions by batch id",
+                "description": "report that the client with the machine code is alive, the time of the heartbeat is kept as its presence and its ip address is updated",
                 "consumes": [
                     "application/json"
                 ],
@@ -830,17 +978,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "delete distributions",
+                "summary": "clients heartbeat",
                 "parameters": [
                     {
-                        "description": "id array",
+                        "description": "heartbeat information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteDistributionsByIDsRequest"
+                            "$ref": "#/definitions/types.HeartbeatClientsRequest"
                         }
                     }
                 ],
@@ -848,20 +996,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteDistributionsByIDsRespond"
+                            "$ref": "#/definitions/types.HeartbeatClientsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution/list": {
+        "/api/v1/clients/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of distributions by last id and limit",
+                "description": "list of clientss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -869,17 +1017,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "list of distributions by last id and limit",
+                "summary": "list of clientss by cursor and limit",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
@@ -891,16 +1037,28 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "default": "-id",
-                        "description": "sort by column name of table, and the ",
+                        "description": "sort by id, created_at, updated_at, machine_code, device_model, android_version, app_version_code, battery_level, is_charging, signal_strength, carrier, sim_slot_count, multiple columns separated by commas, the ",
                         "name": "sort",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true: only online clients, false: only offline clients, empty: all clients",
+                        "name": "online",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "select the clients by their labels, e.g. site=shanghai,carrier!=cmcc",
+                        "name": "labelSelector",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListDistributionsRespond"
+                            "$ref": "#/definitions/types.ListClientssByCursorRespond"
                         }
                     }
                 }
@@ -911,7 +1069,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of distributions by paging and conditions",
+                "description": "list of clientss by paging and conditions",
                 "consumes": [
                     "application/json"
                 ],
@@ -919,17 +1077,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "list of distributions by query parameters",
+                "summary": "list of clientss by query parameters",
                 "parameters": [
                     {
-                        "description": "query parameters",
+                        "description": "query parameters and label selector",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Params"
+                            "$ref": "#/definitions/types.LabelParams"
                         }
                     }
                 ],
@@ -937,20 +1095,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListDistributionsRespond"
+                            "$ref": "#/definitions/types.ListClientssRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution/list/ids": {
+        "/api/v1/clients/list/ids": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of distributions by batch id",
+                "description": "list of clientss by batch id",
                 "consumes": [
                     "application/json"
                 ],
@@ -958,9 +1116,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "list of distributions by batch id",
+                "summary": "list of clientss by batch id",
                 "parameters": [
                     {
                         "description": "id array",
@@ -968,7 +1126,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ListDistributionsByIDsRequest"
+                            "$ref": "#/definitions/types.ListClientssByIDsRequest"
                         }
                     }
                 ],
@@ -976,20 +1134,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListDistributionsByIDsRespond"
+                            "$ref": "#/definitions/types.ListClientssByIDsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/distribution/{id}": {
-            "get": {
+        "/api/v1/clients/metadata": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get distribution detail by id",
+                "description": "update the model, versions, battery, signal, carrier and sim slots reported by a device, the client is created if the machine code does not exist. appVersionCode is taken from the X-App-Version-Code header if it is empty",
                 "consumes": [
                     "application/json"
                 ],
@@ -997,34 +1155,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "get distribution detail",
+                "summary": "upsert clients metadata",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "clients metadata",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpsertClientsMetadataRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetDistributionByIDRespond"
+                            "$ref": "#/definitions/types.UpsertClientsMetadataRespond"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/clients/releaseChannel": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update distribution information by id",
+                "description": "assign the clients with the machine codes to a release channel, an empty channel removes the assignment and the clients are placed by the client percent of the channels again",
                 "consumes": [
                     "application/json"
                 ],
@@ -1032,24 +1194,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "release"
                 ],
-                "summary": "update distribution",
+                "summary": "assign clients to a release channel",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "distribution information",
+                        "description": "machine codes and channel",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateDistributionByIDRequest"
+                            "$ref": "#/definitions/types.SetReleaseChannelClientsRequest"
                         }
                     }
                 ],
@@ -1057,18 +1212,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateDistributionByIDRespond"
+                            "$ref": "#/definitions/types.SetReleaseChannelClientsRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/clients/trash": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete distribution by id",
+                "description": "list of deleted clientss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -1076,36 +1233,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "distribution"
+                    "clients"
                 ],
-                "summary": "delete distribution",
+                "summary": "list of deleted clientss by cursor and limit",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, machine_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteDistributionByIDRespond"
+                            "$ref": "#/definitions/types.ListClientssByCursorRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupCall": {
-            "post": {
+        "/api/v1/clients/trash/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create groupCall",
+                "description": "permanently delete a clients in the trash by id, it cannot be restored afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -1113,38 +1283,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "create groupCall",
+                "summary": "purge clients",
                 "parameters": [
                     {
-                        "description": "groupCall information",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.CreateGroupCallRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateGroupCallRespond"
+                            "$ref": "#/definitions/types.PurgeClientsByIDRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupCall/condition": {
-            "post": {
+        "/api/v1/clients/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get groupCall by condition",
+                "description": "get clients detail by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1152,38 +1320,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "get groupCall by condition",
+                "summary": "get clients detail",
                 "parameters": [
                     {
-                        "description": "query condition",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Conditions"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetGroupCallByConditionRespond"
+                            "$ref": "#/definitions/types.GetClientsByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/groupCall/delete/ids": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete groupCalls by batch id",
+                "description": "update clients information by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1191,17 +1361,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "delete groupCalls",
+                "summary": "update clients",
                 "parameters": [
                     {
-                        "description": "id array",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "clients information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupCallsByIDsRequest"
+                            "$ref": "#/definitions/types.UpdateClientsByIDRequest"
                         }
                     }
                 ],
@@ -1209,20 +1392,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupCallsByIDsRespond"
+                            "$ref": "#/definitions/types.UpdateClientsByIDRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/groupCall/list": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupCalls by last id and limit",
+                "description": "delete clients by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1230,49 +1411,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "list of groupCalls by last id and limit",
+                "summary": "delete clients",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "size in each page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "default": "-id",
-                        "description": "sort by column name of table, and the ",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupCallsRespond"
+                            "$ref": "#/definitions/types.DeleteClientsByIDRespond"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupCalls by paging and conditions",
+                "description": "update the clients fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
                 "consumes": [
                     "application/json"
                 ],
@@ -1280,17 +1452,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "list of groupCalls by query parameters",
+                "summary": "patch clients",
                 "parameters": [
                     {
-                        "description": "query parameters",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "clients fields to update",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Params"
+                            "$ref": "#/definitions/types.PatchClientsByIDRequest"
                         }
                     }
                 ],
@@ -1298,20 +1483,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupCallsRespond"
+                            "$ref": "#/definitions/types.PatchClientsByIDRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupCall/list/ids": {
-            "post": {
+        "/api/v1/clients/{id}/credential": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupCalls by batch id",
+                "description": "revoke the credential of a device, the device has to be enrolled again with a new enrollment code",
                 "consumes": [
                     "application/json"
                 ],
@@ -1319,38 +1504,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "list of groupCalls by batch id",
+                "summary": "revoke device credential",
                 "parameters": [
                     {
-                        "description": "id array",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ListGroupCallsByIDsRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupCallsByIDsRespond"
+                            "$ref": "#/definitions/types.RevokeClientsCredentialRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupCall/{id}": {
-            "get": {
+        "/api/v1/clients/{id}/credential/rotate": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get groupCall detail by id",
+                "description": "replace the credential of a device, the previous credential is no longer valid, a device can only rotate its own credential with device auth enabled",
                 "consumes": [
                     "application/json"
                 ],
@@ -1358,9 +1541,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "get groupCall detail",
+                "summary": "rotate device credential",
                 "parameters": [
                     {
                         "type": "string",
@@ -1374,18 +1557,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetGroupCallByIDRespond"
+                            "$ref": "#/definitions/types.RotateClientsCredentialRespond"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/clients/{id}/ip-history": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update groupCall information by id",
+                "description": "list of ip changes of a clients by cursor and limit, the latest change comes first, changes to another subnet and changes of many clients at the same time are flagged",
                 "consumes": [
                     "application/json"
                 ],
@@ -1393,9 +1578,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "update groupCall",
+                "summary": "list of ip changes of a clients",
                 "parameters": [
                     {
                         "type": "string",
@@ -1405,41 +1590,50 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "groupCall information",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.UpdateGroupCallByIDRequest"
-                        }
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true: only the changes flagged as subnetChanged or massChange",
+                        "name": "flagged",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateGroupCallByIDRespond"
+                            "$ref": "#/definitions/types.ListClientsIPHistoryRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/clients/{id}/labels": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete groupCall by id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "get the key/value labels of clients by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groupCall"
+                    "clients"
                 ],
-                "summary": "delete groupCall",
+                "summary": "get the labels of clients",
                 "parameters": [
                     {
                         "type": "string",
@@ -1453,20 +1647,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupCallByIDRespond"
+                            "$ref": "#/definitions/types.LabelsRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/groupClient": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create groupClient",
+                "description": "replace all the key/value labels of clients by id, a key starts and ends with a letter or digit and may have - _ . / in between, a value may be empty and may not have /, both have at most 63 characters, a clients has at most 64 labels",
                 "consumes": [
                     "application/json"
                 ],
@@ -1474,17 +1666,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "clients"
                 ],
-                "summary": "create groupClient",
+                "summary": "set the labels of clients",
                 "parameters": [
                     {
-                        "description": "groupClient information",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "labels",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateGroupClientRequest"
+                            "$ref": "#/definitions/types.SetLabelsRequest"
                         }
                     }
                 ],
@@ -1492,20 +1691,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateGroupClientRespond"
+                            "$ref": "#/definitions/types.LabelsRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/groupClient/condition": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get groupClient by condition",
+                "description": "add or change the labels in the request, a label with a null value is removed, the other labels of the clients are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -1513,17 +1710,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "clients"
                 ],
-                "summary": "get groupClient by condition",
+                "summary": "patch the labels of clients",
                 "parameters": [
                     {
-                        "description": "query condition",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "labels",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Conditions"
+                            "$ref": "#/definitions/types.PatchLabelsRequest"
                         }
                     }
                 ],
@@ -1531,20 +1735,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetGroupClientByConditionRespond"
+                            "$ref": "#/definitions/types.LabelsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupClient/delete/ids": {
+        "/api/v1/clients/{id}/restore": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete groupClients by batch id",
+                "description": "restore a deleted clients by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1552,38 +1756,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "clients"
                 ],
-                "summary": "delete groupClients",
+                "summary": "restore clients",
                 "parameters": [
                     {
-                        "description": "id array",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupClientsByIDsRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupClientsByIDsRespond"
+                            "$ref": "#/definitions/types.RestoreClientsByIDRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupClient/list": {
+        "/api/v1/clients/{id}/sims": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupClients by last id and limit",
+                "description": "list the sims in the device of the client, ordered by slot",
                 "consumes": [
                     "application/json"
                 ],
@@ -1591,49 +1793,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "sim"
                 ],
-                "summary": "list of groupClients by last id and limit",
+                "summary": "list of the sims of a client",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "size in each page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "default": "-id",
-                        "description": "sort by column name of table, and the ",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "id of the client",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupClientsRespond"
+                            "$ref": "#/definitions/types.ListClientSimsRespond"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/clients/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupClients by paging and conditions",
+                "description": "set a client active, disabled or quarantined with the reason, the operator is the user of the token, a client that is not active is not routed calls, is skipped in the members of its groups, and its device apis are rejected with a status error code, the websocket of the device is closed",
                 "consumes": [
                     "application/json"
                 ],
@@ -1641,17 +1830,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "clients"
                 ],
-                "summary": "list of groupClients by query parameters",
+                "summary": "set clients status",
                 "parameters": [
                     {
-                        "description": "query parameters",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "status information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Params"
+                            "$ref": "#/definitions/types.SetClientsStatusRequest"
                         }
                     }
                 ],
@@ -1659,59 +1861,59 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupClientsRespond"
+                            "$ref": "#/definitions/types.SetClientsStatusRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/groupClient/list/ids": {
-            "post": {
+        "/api/v1/devices/{machineCode}/callHistory/{id}/recording": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of groupClients by batch id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "the client device gets the status of the upload of a recording and the bytes received, to resume the upload from them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "callRecording"
                 ],
-                "summary": "list of groupClients by batch id",
+                "summary": "get the upload of a call recording",
                 "parameters": [
                     {
-                        "description": "id array",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ListGroupClientsByIDsRequest"
-                        }
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the call history",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListGroupClientsByIDsRespond"
+                            "$ref": "#/definitions/types.GetCallRecordingUploadRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/groupClient/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get groupClient detail by id",
+                "description": "the client device starts to upload the recording of a call it placed, then uploads the audio in chunks.\nstarting again with the same checksum resumes the upload from the received bytes, a different checksum\nreplaces the audio that has not been uploaded completely. a recording that has been uploaded cannot be replaced",
                 "consumes": [
                     "application/json"
                 ],
@@ -1719,59 +1921,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "callRecording"
                 ],
-                "summary": "get groupClient detail",
+                "summary": "start the upload of a call recording",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the call history",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "recording information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.StartCallRecordingRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetGroupClientByIDRespond"
+                            "$ref": "#/definitions/types.StartCallRecordingRespond"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/devices/{machineCode}/callHistory/{id}/recording/content": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update groupClient information by id",
+                "description": "the client device uploads the next chunk of the audio in the body, the Upload-Offset header is the number of bytes\nreceived before, the received bytes are returned by the start and the get of the upload. when the last chunk is\nreceived the checksum of the audio is checked, if it does not match the audio is dropped and has to be uploaded again",
                 "consumes": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "callRecording"
                 ],
-                "summary": "update groupClient",
+                "summary": "upload a chunk of a call recording",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the call history",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "groupClient information",
+                        "type": "integer",
+                        "description": "bytes of the audio received before the chunk",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "chunk of the audio",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateGroupClientByIDRequest"
+                            "type": "string"
                         }
                     }
                 ],
@@ -1779,18 +2013,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateGroupClientByIDRespond"
+                            "$ref": "#/definitions/types.UploadCallRecordingRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/devices/{machineCode}/callHistory/{id}/state": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete groupClient by id",
+                "description": "the client device reports a change of the state of a call, a call moves from requested to dispatched when its command is delivered, then to ringing, answered and one of the final states ended, failed or busy. reporting the current state again is not an error, a change the call cannot make, e.g. ended before dispatched, is rejected",
                 "consumes": [
                     "application/json"
                 ],
@@ -1798,36 +2034,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groupClient"
+                    "callHistory"
                 ],
-                "summary": "delete groupClient",
+                "summary": "report the state of a call",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "state of the call",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ReportCallHistoryStateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteGroupClientByIDRespond"
+                            "$ref": "#/definitions/types.ReportCallHistoryStateRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/sms": {
-            "post": {
+        "/api/v1/devices/{machineCode}/commands": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create sms",
+                "description": "deliver the pending commands of the device, the oldest first, if there are none the request waits until a command is queued or the wait ends. a command is delivered only once, ack it after it is executed",
                 "consumes": [
                     "application/json"
                 ],
@@ -1835,38 +2087,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "deviceCommand"
                 ],
-                "summary": "create sms",
+                "summary": "poll the commands of a device",
                 "parameters": [
                     {
-                        "description": "sms information",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.CreateSmsRequest"
-                        }
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "0s",
+                        "description": "how long to wait for a command, e.g. 30s, at most 60s, 0 returns at once",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "most commands in the respond",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateSmsRespond"
+                            "$ref": "#/definitions/types.PollDeviceCommandsRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/sms/condition": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get sms by condition",
+                "description": "queue a command for a device, the device receives it from the poll api, the commands queued for call history are created with the call history. the instruction must be one of GET /api/v1/instructions and its payload is validated. a command cannot be queued for a disabled or quarantined client",
                 "consumes": [
                     "application/json"
                 ],
@@ -1874,17 +2136,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "deviceCommand"
                 ],
-                "summary": "get sms by condition",
+                "summary": "queue a command for a device",
                 "parameters": [
                     {
-                        "description": "query condition",
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "command information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Conditions"
+                            "$ref": "#/definitions/types.CreateDeviceCommandRequest"
                         }
                     }
                 ],
@@ -1892,20 +2161,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetSmsByConditionRespond"
+                            "$ref": "#/definitions/types.CreateDeviceCommandRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/sms/delete/ids": {
-            "post": {
+        "/api/v1/devices/{machineCode}/commands/list": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete smss by batch id",
+                "description": "list of the commands of a device by cursor and limit, the latest command comes first, pass the nextCursor of the respond to get the next page",
                 "consumes": [
                     "application/json"
                 ],
@@ -1913,38 +2182,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "deviceCommand"
                 ],
-                "summary": "delete smss",
+                "summary": "list of the commands of a device",
                 "parameters": [
                     {
-                        "description": "id array",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.DeleteSmssByIDsRequest"
-                        }
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered, acked or expired, empty means every state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteSmssByIDsRespond"
+                            "$ref": "#/definitions/types.ListDeviceCommandsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/sms/list": {
-            "get": {
+        "/api/v1/devices/{machineCode}/commands/{id}/ack": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of smss by last id and limit",
+                "description": "confirm that a delivered command was executed by the device, acking a command again is not an error",
                 "consumes": [
                     "application/json"
                 ],
@@ -1952,49 +2238,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "deviceCommand"
                 ],
-                "summary": "list of smss by last id and limit",
+                "summary": "ack a command",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "size in each page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "default": "-id",
-                        "description": "sort by column name of table, and the ",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListSmssRespond"
+                            "$ref": "#/definitions/types.AckDeviceCommandRespond"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/devices/{machineCode}/commands/{id}/redeliver": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of smss by paging and conditions",
+                "description": "put a command that was delivered to the device but not acked back in the queue, it is delivered again on the next poll or push, if it has not expired. the device may have executed the command without acking it",
                 "consumes": [
                     "application/json"
                 ],
@@ -2002,38 +2282,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "deviceCommand"
                 ],
-                "summary": "list of smss by query parameters",
+                "summary": "redeliver a command",
                 "parameters": [
                     {
-                        "description": "query parameters",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Params"
-                        }
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListSmssRespond"
+                            "$ref": "#/definitions/types.RedeliverDeviceCommandRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/sms/list/ids": {
-            "post": {
+        "/api/v1/devices/{machineCode}/sims": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of smss by batch id",
+                "description": "the device reports all the sims it holds, the sims are matched by iccid, the unknown ones are created and the sims that are no longer reported are removed from the device",
                 "consumes": [
                     "application/json"
                 ],
@@ -2041,17 +2326,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "sim"
                 ],
-                "summary": "list of smss by batch id",
+                "summary": "report the sims of a device",
                 "parameters": [
                     {
-                        "description": "id array",
+                        "type": "string",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "sims of the device",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ListSmssByIDsRequest"
+                            "$ref": "#/definitions/types.ReportSimsRequest"
                         }
                     }
                 ],
@@ -2059,20 +2351,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListSmssByIDsRespond"
+                            "$ref": "#/definitions/types.ListClientSimsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/sms/{id}": {
+        "/api/v1/devices/{machineCode}/update": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get sms detail by id",
+                "description": "get the release of the channel of the device and whether it is newer than the app, the app sends its version code in the versionCode query or the X-App-Version-Code header. this api is not rejected for outdated apps, mandatory is true if the app is older than the minimum supported version",
                 "consumes": [
                     "application/json"
                 ],
@@ -2080,34 +2372,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "release"
                 ],
-                "summary": "get sms detail",
+                "summary": "check the update of a device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
+                        "description": "machine code of the device",
+                        "name": "machineCode",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "versionCode of the app, if empty the X-App-Version-Code header is used, 0 means unknown",
+                        "name": "versionCode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetSmsByIDRespond"
+                            "$ref": "#/definitions/types.CheckUpdateRespond"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/distribution": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update sms information by id",
+                "description": "submit information to create distribution",
                 "consumes": [
                     "application/json"
                 ],
@@ -2115,24 +2415,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "distribution"
                 ],
-                "summary": "update sms",
+                "summary": "create distribution",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "sms information",
+                        "description": "distribution information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateSmsByIDRequest"
+                            "$ref": "#/definitions/types.CreateDistributionRequest"
                         }
                     }
                 ],
@@ -2140,18 +2433,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateSmsByIDRespond"
+                            "$ref": "#/definitions/types.CreateDistributionRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/distribution/batch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete sms by id",
+                "description": "submit at most 100 distributions in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
                 "consumes": [
                     "application/json"
                 ],
@@ -2159,36 +2454,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sms"
+                    "distribution"
                 ],
-                "summary": "delete sms",
+                "summary": "create distributions in batch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "distribution information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateDistributionsBatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteSmsByIDRespond"
+                            "$ref": "#/definitions/types.CreateDistributionsBatchRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/unanswerdCall": {
+        "/api/v1/distribution/condition": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create unanswerdCall",
+                "description": "get distribution by condition",
                 "consumes": [
                     "application/json"
                 ],
@@ -2196,17 +2493,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "create unanswerdCall",
+                "summary": "get distribution by condition",
                 "parameters": [
                     {
-                        "description": "unanswerdCall information",
+                        "description": "query condition",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateUnanswerdCallRequest"
+                            "$ref": "#/definitions/types.Conditions"
                         }
                     }
                 ],
@@ -2214,59 +2511,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateUnanswerdCallRespond"
+                            "$ref": "#/definitions/types.GetDistributionByConditionRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/unanswerdCall/condition": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "get unanswerdCall by condition",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "unanswerdCall"
-                ],
-                "summary": "get unanswerdCall by condition",
-                "parameters": [
-                    {
-                        "description": "query condition",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Conditions"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/types.GetUnanswerdCallByConditionRespond"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/unanswerdCall/delete/ids": {
+        "/api/v1/distribution/delete/ids": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete unanswerdCalls by batch id",
+                "description": "delete distributions by batch id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2274,9 +2532,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "delete unanswerdCalls",
+                "summary": "delete distributions",
                 "parameters": [
                     {
                         "description": "id array",
@@ -2284,7 +2542,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUnanswerdCallsByIDsRequest"
+                            "$ref": "#/definitions/types.DeleteDistributionsByIDsRequest"
                         }
                     }
                 ],
@@ -2292,20 +2550,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUnanswerdCallsByIDsRespond"
+                            "$ref": "#/definitions/types.DeleteDistributionsByIDsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/unanswerdCall/list": {
+        "/api/v1/distribution/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of unanswerdCalls by last id and limit",
+                "description": "list of distributions by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -2313,17 +2571,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "list of unanswerdCalls by last id and limit",
+                "summary": "list of distributions by cursor and limit",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
@@ -2335,7 +2591,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "default": "-id",
-                        "description": "sort by column name of table, and the ",
+                        "description": "sort by id, created_at, updated_at, user_id, group_call_id, multiple columns separated by commas, the ",
                         "name": "sort",
                         "in": "query"
                     }
@@ -2344,7 +2600,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUnanswerdCallsRespond"
+                            "$ref": "#/definitions/types.ListDistributionsByCursorRespond"
                         }
                     }
                 }
@@ -2355,7 +2611,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of unanswerdCalls by paging and conditions",
+                "description": "list of distributions by paging and conditions",
                 "consumes": [
                     "application/json"
                 ],
@@ -2363,9 +2619,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "list of unanswerdCalls by query parameters",
+                "summary": "list of distributions by query parameters",
                 "parameters": [
                     {
                         "description": "query parameters",
@@ -2381,20 +2637,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUnanswerdCallsRespond"
+                            "$ref": "#/definitions/types.ListDistributionsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/unanswerdCall/list/ids": {
+        "/api/v1/distribution/list/ids": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of unanswerdCalls by batch id",
+                "description": "list of distributions by batch id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2402,9 +2658,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "list of unanswerdCalls by batch id",
+                "summary": "list of distributions by batch id",
                 "parameters": [
                     {
                         "description": "id array",
@@ -2412,7 +2668,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ListUnanswerdCallsByIDsRequest"
+                            "$ref": "#/definitions/types.ListDistributionsByIDsRequest"
                         }
                     }
                 ],
@@ -2420,20 +2676,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUnanswerdCallsByIDsRespond"
+                            "$ref": "#/definitions/types.ListDistributionsByIDsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/unanswerdCall/{id}": {
+        "/api/v1/distribution/trash": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get unanswerdCall detail by id",
+                "description": "list of deleted distributions by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -2441,34 +2697,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "get unanswerdCall detail",
+                "summary": "list of deleted distributions by cursor and limit",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, user_id, group_call_id, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetUnanswerdCallByIDRespond"
+                            "$ref": "#/definitions/types.ListDistributionsByCursorRespond"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/distribution/trash/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update unanswerdCall information by id",
+                "description": "permanently delete a distribution in the trash by id, it cannot be restored afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -2476,9 +2747,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "update unanswerdCall",
+                "summary": "purge distribution",
                 "parameters": [
                     {
                         "type": "string",
@@ -2486,33 +2757,26 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "unanswerdCall information",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.UpdateUnanswerdCallByIDRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateUnanswerdCallByIDRespond"
+                            "$ref": "#/definitions/types.PurgeDistributionByIDRespond"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/distribution/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete unanswerdCall by id",
+                "description": "get distribution detail by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2520,9 +2784,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "unanswerdCall"
+                    "distribution"
                 ],
-                "summary": "delete unanswerdCall",
+                "summary": "get distribution detail",
                 "parameters": [
                     {
                         "type": "string",
@@ -2536,20 +2800,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUnanswerdCallByIDRespond"
+                            "$ref": "#/definitions/types.GetDistributionByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "submit information to create user",
+                "description": "update distribution information by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2557,17 +2825,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "distribution"
                 ],
-                "summary": "create user",
+                "summary": "update distribution",
                 "parameters": [
                     {
-                        "description": "user information",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "distribution information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateUserRequest"
+                            "$ref": "#/definitions/types.UpdateDistributionByIDRequest"
                         }
                     }
                 ],
@@ -2575,20 +2856,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateUserRespond"
+                            "$ref": "#/definitions/types.UpdateDistributionByIDRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/condition": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get user by condition",
+                "description": "delete distribution by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2596,38 +2875,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "distribution"
                 ],
-                "summary": "get user by condition",
+                "summary": "delete distribution",
                 "parameters": [
                     {
-                        "description": "query condition",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Conditions"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetUserByConditionRespond"
+                            "$ref": "#/definitions/types.DeleteDistributionByIDRespond"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/delete/ids": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete users by batch id",
+                "description": "update the distribution fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
                 "consumes": [
                     "application/json"
                 ],
@@ -2635,17 +2916,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "distribution"
                 ],
-                "summary": "delete users",
+                "summary": "patch distribution",
                 "parameters": [
                     {
-                        "description": "id array",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "distribution fields to update",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUsersByIDsRequest"
+                            "$ref": "#/definitions/types.PatchDistributionByIDRequest"
                         }
                     }
                 ],
@@ -2653,20 +2947,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUsersByIDsRespond"
+                            "$ref": "#/definitions/types.PatchDistributionByIDRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/list": {
-            "get": {
+        "/api/v1/distribution/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of users by last id and limit",
+                "description": "restore a deleted distribution by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2674,49 +2968,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "distribution"
                 ],
-                "summary": "list of users by last id and limit",
+                "summary": "restore distribution",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "last id, default is MaxInt32",
-                        "name": "lastID",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "size in each page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "default": "-id",
-                        "description": "sort by column name of table, and the ",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUsersRespond"
+                            "$ref": "#/definitions/types.RestoreDistributionByIDRespond"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/groupCall": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of users by paging and conditions",
+                "description": "submit information to create groupCall",
                 "consumes": [
                     "application/json"
                 ],
@@ -2724,17 +3005,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "groupCall"
                 ],
-                "summary": "list of users by query parameters",
+                "summary": "create groupCall",
                 "parameters": [
                     {
-                        "description": "query parameters",
+                        "description": "groupCall information",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Params"
+                            "$ref": "#/definitions/types.CreateGroupCallRequest"
                         }
                     }
                 ],
@@ -2742,20 +3023,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUsersRespond"
+                            "$ref": "#/definitions/types.CreateGroupCallRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/list/ids": {
+        "/api/v1/groupCall/batch": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "list of users by batch id",
+                "description": "submit at most 100 groupCalls in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
                 "consumes": [
                     "application/json"
                 ],
@@ -2763,9 +3044,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "groupCall"
                 ],
-                "summary": "list of users by batch id",
+                "summary": "create groupCalls in batch",
+                "parameters": [
+                    {
+                        "description": "groupCall information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupCallsBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupCallsBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/condition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get groupCall by condition",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "get groupCall by condition",
+                "parameters": [
+                    {
+                        "description": "query condition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Conditions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetGroupCallByConditionRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/delete/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete groupCalls by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "delete groupCalls",
                 "parameters": [
                     {
                         "description": "id array",
@@ -2773,7 +3132,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ListUsersByIDsRequest"
+                            "$ref": "#/definitions/types.DeleteGroupCallsByIDsRequest"
                         }
                     }
                 ],
@@ -2781,20 +3140,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ListUsersByIDsRespond"
+                            "$ref": "#/definitions/types.DeleteGroupCallsByIDsRespond"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/{id}": {
+        "/api/v1/groupCall/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get user detail by id",
+                "description": "list of groupCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
                 "consumes": [
                     "application/json"
                 ],
@@ -2802,34 +3161,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "groupCall"
                 ],
-                "summary": "get user detail",
+                "summary": "list of groupCalls by cursor and limit",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, group_number, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "select the groupCalls by their labels, e.g. site=shanghai,carrier!=cmcc",
+                        "name": "labelSelector",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GetUserByIDRespond"
+                            "$ref": "#/definitions/types.ListGroupCallsByCursorRespond"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update user information by id",
+                "description": "list of groupCalls by paging and conditions",
                 "consumes": [
                     "application/json"
                 ],
@@ -2837,24 +3215,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "groupCall"
                 ],
-                "summary": "update user",
+                "summary": "list of groupCalls by query parameters",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+                        "description": "query parameters and label selector",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.LabelParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupCallsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/list/ids": {
+            "post": {
+                "security": [
                     {
-                        "description": "user information",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of groupCalls by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "list of groupCalls by batch id",
+                "parameters": [
+                    {
+                        "description": "id array",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateUserByIDRequest"
+                            "$ref": "#/definitions/types.ListGroupCallsByIDsRequest"
                         }
                     }
                 ],
@@ -2862,18 +3272,70 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateUserByIDRespond"
+                            "$ref": "#/definitions/types.ListGroupCallsByIDsRespond"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/groupCall/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted groupCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "list of deleted groupCalls by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, group_number, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupCallsByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/trash/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "delete user by id",
+                "description": "permanently delete a groupCall in the trash by id, it cannot be restored afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -2881,9 +3343,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "groupCall"
                 ],
-                "summary": "delete user",
+                "summary": "purge groupCall",
                 "parameters": [
                     {
                         "type": "string",
@@ -2897,112 +3359,6302 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.DeleteUserByIDRespond"
+                            "$ref": "#/definitions/types.PurgeGroupCallByIDRespond"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "types.CallHistoryObjDetail": {
-            "type": "object",
-            "properties": {
-                "clientMachineCode": {
-                    "type": "string"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "description": "convert to string id",
-                    "type": "string"
-                },
-                "instruction": {
-                    "type": "string"
-                },
-                "mobileNumber": {
-                    "type": "string"
-                },
-                "requestMachineCode": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                }
-            }
         },
-        "types.ClientsObjDetail": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "description": "convert to string id",
+        "/api/v1/groupCall/{groupNumber}/dial": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "choose an active client of the group by its group number and create the dial instruction of the call for it.\nthe client is chosen from the members of the group whose labels match the label selector, with the strategy\nof the request or else the strategy of the config: roundRobin, leastRecentlyUsed, random or stickyPerCallee",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "dial by groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "group number",
+                        "name": "groupNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "call information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DialGroupCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DialGroupCallRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get groupCall detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "get groupCall detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetGroupCallByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update groupCall information by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "update groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "groupCall information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateGroupCallByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateGroupCallByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete groupCall by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "delete groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteGroupCallByIDRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update the groupCall fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "patch groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "groupCall fields to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchGroupCallByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchGroupCallByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/{id}/labels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get the key/value labels of groupCall by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "get the labels of groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LabelsRespond"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "replace all the key/value labels of groupCall by id, a key starts and ends with a letter or digit and may have - _ . / in between, a value may be empty and may not have /, both have at most 63 characters, a groupCall has at most 64 labels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "set the labels of groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "labels",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.SetLabelsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LabelsRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "add or change the labels in the request, a label with a null value is removed, the other labels of the groupCall are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "patch the labels of groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "labels",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchLabelsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LabelsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupCall/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a deleted groupCall by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupCall"
+                ],
+                "summary": "restore groupCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RestoreGroupCallByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit information to create groupClient",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "create groupClient",
+                "parameters": [
+                    {
+                        "description": "groupClient information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupClientRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit at most 100 groupClients in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "create groupClients in batch",
+                "parameters": [
+                    {
+                        "description": "groupClient information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupClientsBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateGroupClientsBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/condition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get groupClient by condition",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "get groupClient by condition",
+                "parameters": [
+                    {
+                        "description": "query condition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Conditions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetGroupClientByConditionRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/delete/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete groupClients by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "delete groupClients",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteGroupClientsByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteGroupClientsByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of groupClients by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "list of groupClients by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, group_id, client_id, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientsByCursorRespond"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of groupClients by paging and conditions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "list of groupClients by query parameters",
+                "parameters": [
+                    {
+                        "description": "query parameters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Params"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/list/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of groupClients by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "list of groupClients by batch id",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientsByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientsByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/members/{groupId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of the clients that are members of a group through groupClient, the disabled and quarantined clients are skipped unless all is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "list of the clients of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "group id",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "true: include the clients that are not active",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientMembersRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted groupClients by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "list of deleted groupClients by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, group_id, client_id, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListGroupClientsByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "permanently delete a groupClient in the trash by id, it cannot be restored afterwards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "purge groupClient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeGroupClientByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get groupClient detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "get groupClient detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetGroupClientByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update groupClient information by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "update groupClient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "groupClient information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateGroupClientByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateGroupClientByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete groupClient by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "delete groupClient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteGroupClientByIDRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update the groupClient fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "patch groupClient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "groupClient fields to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchGroupClientByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchGroupClientByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groupClient/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a deleted groupClient by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groupClient"
+                ],
+                "summary": "restore groupClient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RestoreGroupClientByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/instructions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list the instructions that can be sent to the client devices and the fields of their payloads, the app compares the version with the version it was built for to check that it understands all the instructions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instruction"
+                ],
+                "summary": "list instructions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListInstructionsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releaseChannels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of all the release channels, the oldest first, which is the order in which they share the client percent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "list of release channels",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListReleaseChannelsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releaseChannels/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "create a release channel such as stable or beta, or update its settings. when the release of the channel is replaced, the clients outside the rollout percent are still offered the replaced release. the clients that are not assigned to a channel are placed in the channels by their client percent, the others are in the stable channel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "save a release channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "channel name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "channel settings",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpsertReleaseChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpsertReleaseChannelRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releases": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "upload the apk of a release as multipart/form-data, the sha256 checksum of the apk is computed and stored with it, the version code of a release is unique",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "upload a release",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "apk file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "versionCode of the apk",
+                        "name": "versionCode",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "versionName of the apk",
+                        "name": "versionName",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "hex sha256 of the apk, if set the upload is rejected when the file does not match",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "release notes",
+                        "name": "releaseNotes",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UploadReleaseRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releases/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of releases by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "list of releases by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-version_code",
+                        "description": "sort by id, created_at, updated_at, version_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListReleasesRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releases/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get release detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "get release detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetReleaseByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/releases/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "download the apk of a release, the X-Checksum-Sha256 header is the hex sha256 of the apk",
+                "produces": [
+                    "application/vnd.android.package-archive"
+                ],
+                "tags": [
+                    "release"
+                ],
+                "summary": "download a release",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sim": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit information to create a sim, the sim is not in a device until it is bound or reported by a device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "create sim",
+                "parameters": [
+                    {
+                        "description": "sim information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSimRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sim/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list the sims in the devices whose phone number is also on a sim in another device, a cancelled sim is not counted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "list of the sims sharing a phone number",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListDuplicateSimsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sim/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of sims by cursor and limit, pass the nextCursor of the respond to get the next page, the sort of the first page is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "list of sims by cursor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, iccid, phone_number, expires_at, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSimsByCursorRespond"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of sims by paging and conditions, duplicateNumber is true for the sims whose phone number is also in another device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "list of sims by query parameters",
+                "parameters": [
+                    {
+                        "description": "query parameters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Params"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSimsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sim/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get sim detail by id, the version of the sim is returned in the ETag header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "get sim detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetSimByIDRespond"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update sim information by id, the empty fields are not changed, use the bind api to move the sim to a device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "update sim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "sim information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateSimByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateSimByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete sim by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "delete sim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteSimByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sim/{id}/binding": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "put the sim in a slot of a client device, the sim that was in the slot is removed from the device, a clientId of 0 removes the sim from its device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sim"
+                ],
+                "summary": "bind a sim to a client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "client and slot",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.BindSimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.BindSimRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit information to create sms",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "create sms",
+                "parameters": [
+                    {
+                        "description": "sms information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSmsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSmsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit at most 100 smss in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "create smss in batch",
+                "parameters": [
+                    {
+                        "description": "sms information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSmssBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSmssBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/condition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get sms by condition",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "get sms by condition",
+                "parameters": [
+                    {
+                        "description": "query condition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Conditions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetSmsByConditionRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/delete/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete smss by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "delete smss",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteSmssByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteSmssByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of smss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "list of smss by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, machine_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSmssByCursorRespond"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of smss by paging and conditions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "list of smss by query parameters",
+                "parameters": [
+                    {
+                        "description": "query parameters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Params"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSmssRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/list/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of smss by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "list of smss by batch id",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSmssByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSmssByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted smss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "list of deleted smss by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, machine_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListSmssByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "permanently delete a sms in the trash by id, it cannot be restored afterwards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "purge sms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeSmsByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get sms detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "get sms detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetSmsByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update sms information by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "update sms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "sms information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateSmsByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateSmsByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete sms by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "delete sms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteSmsByIDRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update the sms fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "patch sms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "sms fields to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchSmsByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchSmsByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sms/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a deleted sms by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sms"
+                ],
+                "summary": "restore sms",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RestoreSmsByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit information to create unanswerdCall",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "create unanswerdCall",
+                "parameters": [
+                    {
+                        "description": "unanswerdCall information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUnanswerdCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUnanswerdCallRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit at most 100 unanswerdCalls in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "create unanswerdCalls in batch",
+                "parameters": [
+                    {
+                        "description": "unanswerdCall information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUnanswerdCallsBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUnanswerdCallsBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/condition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get unanswerdCall by condition",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "get unanswerdCall by condition",
+                "parameters": [
+                    {
+                        "description": "query condition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Conditions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetUnanswerdCallByConditionRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/delete/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete unanswerdCalls by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "delete unanswerdCalls",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUnanswerdCallsByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUnanswerdCallsByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of unanswerdCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "list of unanswerdCalls by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, client_machine_code, mobile_number, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUnanswerdCallsByCursorRespond"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of unanswerdCalls by paging and conditions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "list of unanswerdCalls by query parameters",
+                "parameters": [
+                    {
+                        "description": "query parameters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Params"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUnanswerdCallsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/list/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of unanswerdCalls by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "list of unanswerdCalls by batch id",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUnanswerdCallsByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUnanswerdCallsByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted unanswerdCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "list of deleted unanswerdCalls by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, client_machine_code, mobile_number, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUnanswerdCallsByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "permanently delete a unanswerdCall in the trash by id, it cannot be restored afterwards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "purge unanswerdCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeUnanswerdCallByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get unanswerdCall detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "get unanswerdCall detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetUnanswerdCallByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update unanswerdCall information by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "update unanswerdCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "unanswerdCall information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateUnanswerdCallByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateUnanswerdCallByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete unanswerdCall by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "delete unanswerdCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUnanswerdCallByIDRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update the unanswerdCall fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "patch unanswerdCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "unanswerdCall fields to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchUnanswerdCallByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchUnanswerdCallByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/unanswerdCall/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a deleted unanswerdCall by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unanswerdCall"
+                ],
+                "summary": "restore unanswerdCall",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RestoreUnanswerdCallByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit information to create user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "create user",
+                "parameters": [
+                    {
+                        "description": "user information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUserRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "submit at most 100 users in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "create users in batch",
+                "parameters": [
+                    {
+                        "description": "user information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUsersBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateUsersBatchRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/condition": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get user by condition",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "get user by condition",
+                "parameters": [
+                    {
+                        "description": "query condition",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Conditions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetUserByConditionRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/delete/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete users by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "delete users",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUsersByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUsersByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of users by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "list of users by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-id",
+                        "description": "sort by id, created_at, updated_at, machine_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUsersByCursorRespond"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of users by paging and conditions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "list of users by query parameters",
+                "parameters": [
+                    {
+                        "description": "query parameters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.Params"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUsersRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/list/ids": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of users by batch id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "list of users by batch id",
+                "parameters": [
+                    {
+                        "description": "id array",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUsersByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUsersByIDsRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "list of deleted users by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "list of deleted users by cursor and limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page, empty means the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "size in each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted_at",
+                        "description": "sort by id, created_at, updated_at, deleted_at, machine_code, multiple columns separated by commas, the ",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.ListUsersByCursorRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "permanently delete a user in the trash by id, it cannot be restored afterwards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "purge user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeUserByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get user detail by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "get user detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GetUserByIDRespond"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in the If-Match header to update or delete the record"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update user information by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "update user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "user information",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateUserByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateUserByIDRespond"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "delete user by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeleteUserByIDRespond"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update the user fields present in a json merge patch (RFC 7396), null or \"\" clears a field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "patch user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag returned by GetByID, the request fails with 412 if the record has been changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "user fields to update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchUserByIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchUserByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "restore a deleted user by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "restore user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RestoreUserByIDRespond"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/device": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "connect a device with a websocket, see types.DeviceMessage for the json text messages. the pending commands are pushed on connect and when they are queued, a command is pushed only once, ack it after it is executed, a command that is not acked is pushed again only if an operator redelivers it. status messages update the metadata of the device. the device must answer pings, the connection is closed if it is silent for 60s",
+                "tags": [
+                    "deviceCommand"
+                ],
+                "summary": "device websocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "machine code of the device, if empty the machine code of the device credential is used",
+                        "name": "machineCode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/types.DeviceMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "types.AckDeviceCommandRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.BindSimRequest": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "description": "0 removes the sim from its device",
+                    "type": "integer"
+                },
+                "slotIndex": {
+                    "description": "slot of the device, starting from 0, the sim in the slot is removed from it",
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                }
+            }
+        },
+        "types.BindSimRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sim": {
+                            "$ref": "#/definitions/types.SimObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CallHistoryObjDetail": {
+            "type": "object",
+            "properties": {
+                "answeredAt": {
+                    "type": "string"
+                },
+                "clientMachineCode": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
+                "dispatchedAt": {
+                    "type": "string"
+                },
+                "endedAt": {
+                    "type": "string"
+                },
+                "failureReason": {
+                    "type": "string"
+                },
+                "groupCallId": {
+                    "description": "the group the call was dialed by, 0 if the client was given",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "instruction": {
+                    "type": "string"
+                },
+                "mobileNumber": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "recording": {
+                    "description": "the recording of the call, only in the detail of a call",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CallRecordingObjDetail"
+                        }
+                    ]
+                },
+                "requestMachineCode": {
+                    "type": "string"
+                },
+                "ringingAt": {
+                    "type": "string"
+                },
+                "scheduledAt": {
+                    "description": "the time the instruction is sent to the client device, empty if it was sent when the call was created",
+                    "type": "string"
+                },
+                "simId": {
+                    "description": "0 if unknown",
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "scheduled, requested, dispatched, ringing, answered, ended, failed, busy or cancelled",
+                    "type": "string"
+                },
+                "stateUpdatedAt": {
+                    "type": "string"
+                },
+                "talkDuration": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CallRecordingObjDetail": {
+            "type": "object",
+            "properties": {
+                "callHistoryId": {
+                    "type": "integer"
+                },
+                "checksum": {
+                    "description": "hex sha256 of the audio",
+                    "type": "string"
+                },
+                "codec": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "downloadUrl": {
+                    "description": "empty until the upload is complete",
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "milliseconds of audio",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "received": {
+                    "description": "bytes uploaded, the upload is resumed from here, only in the responds to the device",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "bytes of the audio",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "uploading or ready, only a ready recording can be downloaded",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "uploadedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CancelCallHistoryRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistory": {
+                            "$ref": "#/definitions/types.CallHistoryObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CheckUpdateRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "channel": {
+                            "description": "release channel of the device, empty if there is no channel",
+                            "type": "string"
+                        },
+                        "mandatory": {
+                            "description": "the app is older than the minimum supported version, the device apis reject it until it is updated",
+                            "type": "boolean"
+                        },
+                        "release": {
+                            "description": "the release offered to the device, null if there is none",
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/types.ReleaseObjDetail"
+                                }
+                            ]
+                        },
+                        "updateAvailable": {
+                            "description": "whether the release is newer than the app of the device",
+                            "type": "boolean"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ClientsIPHistoryObjDetail": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "time of the change",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "ipAddress": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                },
+                "massChange": {
+                    "description": "many clients changed their ip address at the same time",
+                    "type": "boolean"
+                },
+                "previousIp": {
+                    "description": "empty if the clients had no ip address",
+                    "type": "string"
+                },
+                "subnetChanged": {
+                    "description": "the ip address moved to another /24 (ipv4) or /64 (ipv6) network",
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.ClientsObjDetail": {
+            "type": "object",
+            "properties": {
+                "androidVersion": {
+                    "type": "string"
+                },
+                "appVersion": {
+                    "type": "string"
+                },
+                "appVersionCode": {
+                    "description": "versionCode of the app, sort and filter by it to find the outdated devices",
+                    "type": "integer"
+                },
+                "batteryLevel": {
+                    "description": "percent, 0-100",
+                    "type": "integer"
+                },
+                "carrier": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "credentialIssuedAt": {
+                    "description": "time the device credential was issued, empty if the client has no valid credential",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
+                "deviceModel": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "ipAddress": {
+                    "type": "string"
+                },
+                "isCharging": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "description": "key/value labels, e.g. the site or the customer of the client",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lastSeenAt": {
+                    "description": "time of the last heartbeat, empty if none was received",
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                },
+                "metadataUpdatedAt": {
+                    "description": "time the device last reported its metadata, empty if it never did",
+                    "type": "string"
+                },
+                "online": {
+                    "description": "whether a heartbeat was received within the offline timeout",
+                    "type": "boolean"
+                },
+                "releaseChannel": {
+                    "description": "release channel the client is assigned to, empty if it is not assigned",
+                    "type": "string"
+                },
+                "signalStrength": {
+                    "description": "dBm",
+                    "type": "integer"
+                },
+                "simSlotCount": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "active, disabled or quarantined",
+                    "type": "string"
+                },
+                "statusOperator": {
+                    "description": "the operator who set the status",
+                    "type": "string"
+                },
+                "statusReason": {
+                    "description": "why the status was set",
+                    "type": "string"
+                },
+                "statusUpdatedAt": {
+                    "description": "time the status was set, empty if it never changed",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.Column": {
+            "type": "object",
+            "properties": {
+                "exp": {
+                    "description": "expressions, which default to = when the value is null, have =, !=, \u003e, \u003e=, \u003c, \u003c=, like",
+                    "type": "string"
+                },
+                "logic": {
+                    "description": "logical type, defaults to and when value is null, only \u0026(and), ||(or)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "column name",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "column value"
+                }
+            }
+        },
+        "types.Conditions": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "columns info",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Column"
+                    }
+                }
+            }
+        },
+        "types.CreateBatchData": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "number of records created",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "number of records not created",
+                    "type": "integer"
+                },
+                "results": {
+                    "description": "result of each record, in the order of the request",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CreateBatchResult"
+                    }
+                }
+            }
+        },
+        "types.CreateBatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "reason why the record is invalid or failed to be created",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id of the created record",
+                    "type": "integer"
+                },
+                "index": {
+                    "description": "index of the record in the request",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CreateCallHistoryRequest": {
+            "type": "object",
+            "properties": {
+                "clientMachineCode": {
+                    "type": "string"
+                },
+                "instruction": {
+                    "description": "one of the instructions listed by GET /api/v1/instructions, empty if nothing is sent to the client device",
+                    "type": "string"
+                },
+                "mobileNumber": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "payload of the instruction, see the fields of the instruction in GET /api/v1/instructions",
+                    "type": "object"
+                },
+                "requestMachineCode": {
+                    "type": "string"
+                },
+                "scheduleIn": {
+                    "description": "a duration such as 30m or 2h, the instruction is sent this long after the call is created, cannot be used with scheduledAt",
+                    "type": "string"
+                },
+                "scheduledAt": {
+                    "description": "the instruction is sent to the client device at this time, empty or a time in the past sends it now",
+                    "type": "string"
+                },
+                "simId": {
+                    "description": "the sim used, it must be in the device of the client, 0 if unknown",
+                    "type": "integer"
+                },
+                "simSlot": {
+                    "description": "slot of the device the sim is in, used to find the sim if simId is 0, the slot must not be empty",
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                }
+            }
+        },
+        "types.CreateCallHistoryRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateCallHistorysBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateCallHistoryRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateCallHistorysBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateClientsRequest": {
+            "type": "object",
+            "properties": {
+                "ipAddress": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateClientsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateClientssBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateClientsRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateClientssBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateDeviceCommandRequest": {
+            "type": "object",
+            "required": [
+                "instruction"
+            ],
+            "properties": {
+                "expiresIn": {
+                    "description": "seconds until the command expires if it is not delivered, default is the deviceCommandTTL of the config, at most 1 day",
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 0
+                },
+                "instruction": {
+                    "description": "one of the instructions listed by GET /api/v1/instructions",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "mobileNumber": {
+                    "type": "string",
+                    "maxLength": 11
+                },
+                "payload": {
+                    "description": "payload of the instruction",
+                    "type": "object"
+                }
+            }
+        },
+        "types.CreateDeviceCommandRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateDistributionRequest": {
+            "type": "object",
+            "properties": {
+                "groupCallId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CreateDistributionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateDistributionsBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateDistributionRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateDistributionsBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGroupCallRequest": {
+            "type": "object",
+            "properties": {
+                "groupNumber": {
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "transferClientId": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGroupCallRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGroupCallsBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateGroupCallRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateGroupCallsBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGroupClientRequest": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "integer"
+                },
+                "groupId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CreateGroupClientRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGroupClientsBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateGroupClientRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateGroupClientsBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateSimRequest": {
+            "type": "object",
+            "required": [
+                "iccid"
+            ],
+            "properties": {
+                "carrier": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "expiresAt": {
+                    "description": "empty if the sim does not expire",
+                    "type": "string"
+                },
+                "iccid": {
+                    "type": "string",
+                    "maxLength": 22,
+                    "minLength": 18
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "status": {
+                    "description": "default is active",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "cancelled"
+                    ]
+                }
+            }
+        },
+        "types.CreateSimRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateSmsRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                },
+                "simId": {
+                    "description": "the sim used, it must be in the device, 0 if unknown",
+                    "type": "integer"
+                },
+                "simSlot": {
+                    "description": "slot of the device the sim is in, used to find the sim if simId is 0, the slot must not be empty",
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "smsType": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateSmsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateSmssBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateSmsRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateSmssBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUnanswerdCallRequest": {
+            "type": "object",
+            "properties": {
+                "clientMachineCode": {
+                    "type": "string"
+                },
+                "mobileNumber": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUnanswerdCallRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUnanswerdCallsBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateUnanswerdCallRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateUnanswerdCallsBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "machineCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUserRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "description": "id",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateUsersBatchRequest": {
+            "type": "object",
+            "required": [
+                "records"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "true: create all or none of the records, false: create the valid records and report the others",
+                    "type": "boolean"
+                },
+                "records": {
+                    "description": "at most 100 records",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CreateUserRequest"
+                    }
+                }
+            }
+        },
+        "types.CreateUsersBatchRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.CreateBatchData"
+                        }
+                    ]
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteCallHistoryByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteCallHistorysByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteCallHistorysByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteClientsByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteClientssByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteClientssByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteDistributionByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteDistributionsByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteDistributionsByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteGroupCallByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteGroupCallsByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteGroupCallsByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteGroupClientByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteGroupClientsByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteGroupClientsByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteSimByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteSmsByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteSmssByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteSmssByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteUnanswerdCallByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteUnanswerdCallsByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteUnanswerdCallsByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteUserByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeleteUsersByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.DeleteUsersByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeviceCommandObjDetail": {
+            "type": "object",
+            "properties": {
+                "ackedAt": {
+                    "type": "string"
+                },
+                "callHistoryId": {
+                    "description": "\"0\" if the command was not queued for a call history",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "instruction": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                },
+                "mobileNumber": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "omitted if the instruction has no payload",
+                    "type": "object"
+                },
+                "state": {
+                    "description": "pending, delivered, acked or expired",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DeviceMessage": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "description": "commands of commands messages",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DeviceCommandObjDetail"
+                    }
+                },
+                "id": {
+                    "description": "id of the command of ack and acked messages",
+                    "type": "string"
+                },
+                "msg": {
+                    "description": "reason of error messages",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "metadata of status messages, the machine code is the one of the connection",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.UpsertClientsMetadataRequest"
+                        }
+                    ]
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DialGroupCallRequest": {
+            "type": "object",
+            "required": [
+                "mobileNumber"
+            ],
+            "properties": {
+                "labelSelector": {
+                    "description": "only the clients whose labels match it are chosen, e.g. \"site=shanghai,carrier!=cmcc\"",
+                    "type": "string"
+                },
+                "mobileNumber": {
+                    "description": "the number to call",
+                    "type": "string",
+                    "maxLength": 11
+                },
+                "requestMachineCode": {
+                    "type": "string"
+                },
+                "simSlot": {
+                    "description": "slot of the sim the client calls with, only the clients with a sim in the slot are chosen",
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "strategy": {
+                    "description": "how the client is chosen, default is the strategy of the config",
+                    "type": "string",
+                    "enum": [
+                        "roundRobin",
+                        "leastRecentlyUsed",
+                        "random",
+                        "stickyPerCallee"
+                    ]
+                }
+            }
+        },
+        "types.DialGroupCallRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistoryId": {
+                            "description": "the call history created for the call",
+                            "type": "string"
+                        },
+                        "clientId": {
+                            "description": "the client chosen to place the call",
+                            "type": "string"
+                        },
+                        "machineCode": {
+                            "description": "machine code of the client",
+                            "type": "string"
+                        },
+                        "strategy": {
+                            "description": "the strategy the client was chosen with",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.DistributionObjDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
+                "groupCallId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.EnrollClientsRequest": {
+            "type": "object",
+            "required": [
+                "enrollmentCode",
+                "machineCode"
+            ],
+            "properties": {
+                "enrollmentCode": {
+                    "type": "string"
+                },
+                "ipAddress": {
+                    "description": "if empty, the ip address of the request is used",
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.EnrollClientsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
+                        },
+                        "credential": {
+                            "description": "send it in the header \"Authorization: Device \u003ccredential\u003e\", it is only returned here",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetCallHistoryByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistory": {
+                            "$ref": "#/definitions/types.CallHistoryObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetCallHistoryByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistory": {
+                            "$ref": "#/definitions/types.CallHistoryObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetCallRecordingUploadRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callRecording": {
+                            "$ref": "#/definitions/types.CallRecordingObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetClientsByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetClientsByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetDistributionByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "distribution": {
+                            "$ref": "#/definitions/types.DistributionObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetDistributionByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "distribution": {
+                            "$ref": "#/definitions/types.DistributionObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetGroupCallByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupCall": {
+                            "$ref": "#/definitions/types.GroupCallObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetGroupCallByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupCall": {
+                            "$ref": "#/definitions/types.GroupCallObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetGroupClientByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupClient": {
+                            "$ref": "#/definitions/types.GroupClientObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetGroupClientByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupClient": {
+                            "$ref": "#/definitions/types.GroupClientObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetReleaseByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "release": {
+                            "$ref": "#/definitions/types.ReleaseObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetSimByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sim": {
+                            "$ref": "#/definitions/types.SimObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetSmsByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sms": {
+                            "$ref": "#/definitions/types.SmsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetSmsByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sms": {
+                            "$ref": "#/definitions/types.SmsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetUnanswerdCallByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "unanswerdCall": {
+                            "$ref": "#/definitions/types.UnanswerdCallObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetUnanswerdCallByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "unanswerdCall": {
+                            "$ref": "#/definitions/types.UnanswerdCallObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetUserByConditionRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "user": {
+                            "$ref": "#/definitions/types.UserObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GetUserByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "user": {
+                            "$ref": "#/definitions/types.UserObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.GroupCallObjDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
+                "groupNumber": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "key/value labels, e.g. the customer of the group",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "transferClientId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.GroupClientObjDetail": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
+                "groupId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.HeartbeatClientsRequest": {
+            "type": "object",
+            "required": [
+                "machineCode"
+            ],
+            "properties": {
+                "ipAddress": {
+                    "description": "if empty, the ip address of the request is used",
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.HeartbeatClientsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.InstructionFieldObjDetail": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "string, integer or boolean",
+                    "type": "string"
+                }
+            }
+        },
+        "types.InstructionObjDetail": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "mobileNumberRequired": {
+                    "description": "the instruction needs the mobile number of the call history or the command",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "fields of the payload, empty if the instruction has no payload",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InstructionFieldObjDetail"
+                    }
+                }
+            }
+        },
+        "types.IssueClientsEnrollmentCodeRequest": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "description": "seconds until the code expires, default is 86400, at most 30 days",
+                    "type": "integer",
+                    "maximum": 2592000,
+                    "minimum": 0
+                },
+                "machineCode": {
+                    "description": "if set, only the device with this machine code can use the code",
+                    "type": "string"
+                }
+            }
+        },
+        "types.IssueClientsEnrollmentCodeRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "enrollmentCode": {
+                            "description": "one-time code, it is only returned here",
+                            "type": "string"
+                        },
+                        "expiresAt": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.LabelParams": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "query conditions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Column"
+                    }
+                },
+                "labelSelector": {
+                    "description": "select the records by their labels, e.g. site=shanghai,carrier!=cmcc",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "page number, starting from page 0",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "lines per page",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "sorted fields, multi-column sorting separated by commas",
+                    "type": "string"
+                }
+            }
+        },
+        "types.LabelsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "labels": {
+                            "description": "the labels of the record",
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
+                }
+            }
+        },
+        "types.ListCallHistorysByCursorRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "ipAddress": {
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistorys": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.CallHistoryObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
+                }
+            }
+        },
+        "types.ListCallHistorysByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ListCallHistorysByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "machineCode": {
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistorys": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.CallHistoryObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListCallHistorysRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callHistorys": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.CallHistoryObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListClientSimsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sims": {
+                            "description": "ordered by slot",
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SimObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListClientsIPHistoryRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "ipHistory": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ClientsIPHistoryObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListClientssByCursorRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clientss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ClientsObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListClientssByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ListClientssByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clientss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ClientsObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListClientssRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clientss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ClientsObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ListDeviceCommandsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "commands": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.DeviceCommandObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
+                }
+            }
+        },
+        "types.ListDistributionsByCursorRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "distributions": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.DistributionObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
                 },
-                "updatedAt": {
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
                 }
             }
         },
-        "types.Column": {
+        "types.ListDistributionsByIDsRequest": {
             "type": "object",
             "properties": {
-                "exp": {
-                    "description": "expressions, which default to = when the value is null, have =, !=, \u003e, \u003e=, \u003c, \u003c=, like",
-                    "type": "string"
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ListDistributionsByIDsRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "logic": {
-                    "description": "logical type, defaults to and when value is null, only \u0026(and), ||(or)",
-                    "type": "string"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "distributions": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.DistributionObjDetail"
+                            }
+                        }
+                    }
                 },
-                "name": {
-                    "description": "column name",
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
-                },
-                "value": {
-                    "description": "column value"
                 }
             }
         },
-        "types.Conditions": {
+        "types.ListDistributionsRespond": {
             "type": "object",
             "properties": {
-                "columns": {
-                    "description": "columns info",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.Column"
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "distributions": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.DistributionObjDetail"
+                            }
+                        }
                     }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
                 }
             }
         },
-        "types.CreateCallHistoryRequest": {
+        "types.ListDuplicateSimsRespond": {
             "type": "object",
             "properties": {
-                "clientMachineCode": {
-                    "type": "string"
-                },
-                "instruction": {
-                    "type": "string"
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "mobileNumber": {
-                    "type": "string"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "sims": {
+                            "description": "ordered by phone number, the sims sharing a number are next to each other",
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SimObjDetail"
+                            }
+                        }
+                    }
                 },
-                "requestMachineCode": {
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
                 }
             }
         },
-        "types.CreateCallHistoryRespond": {
+        "types.ListGroupCallsByCursorRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3013,9 +9665,15 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "groupCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupCallObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
                         }
                     }
                 },
@@ -3025,18 +9683,20 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateClientsRequest": {
+        "types.ListGroupCallsByIDsRequest": {
             "type": "object",
             "properties": {
-                "ipAddress": {
-                    "type": "string"
-                },
-                "machineCode": {
-                    "type": "string"
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "types.CreateClientsRespond": {
+        "types.ListGroupCallsByIDsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3047,9 +9707,11 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "groupCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupCallObjDetail"
+                            }
                         }
                     }
                 },
@@ -3059,18 +9721,32 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateDistributionRequest": {
+        "types.ListGroupCallsRespond": {
             "type": "object",
             "properties": {
-                "groupCallId": {
+                "code": {
+                    "description": "return code",
                     "type": "integer"
                 },
-                "userId": {
-                    "type": "integer"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupCallObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
                 }
             }
         },
-        "types.CreateDistributionRespond": {
+        "types.ListGroupClientMembersRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3081,9 +9757,11 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "clientss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ClientsObjDetail"
+                            }
                         }
                     }
                 },
@@ -3093,21 +9771,49 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateGroupCallRequest": {
+        "types.ListGroupClientsByCursorRespond": {
             "type": "object",
             "properties": {
-                "groupNumber": {
-                    "type": "string"
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "phoneNumber": {
-                    "type": "string"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupClients": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupClientObjDetail"
+                            }
+                        },
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        }
+                    }
                 },
-                "transferClientId": {
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
                 }
             }
         },
-        "types.CreateGroupCallRespond": {
+        "types.ListGroupClientsByIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "id list",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ListGroupClientsByIDsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3118,9 +9824,11 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "groupClients": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupClientObjDetail"
+                            }
                         }
                     }
                 },
@@ -3130,18 +9838,32 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateGroupClientRequest": {
+        "types.ListGroupClientsRespond": {
             "type": "object",
             "properties": {
-                "clientId": {
+                "code": {
+                    "description": "return code",
                     "type": "integer"
                 },
-                "groupId": {
-                    "type": "integer"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "groupClients": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GroupClientObjDetail"
+                            }
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
                 }
             }
         },
-        "types.CreateGroupClientRespond": {
+        "types.ListInstructionsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3152,8 +9874,14 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
+                        "instructions": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.InstructionObjDetail"
+                            }
+                        },
+                        "version": {
+                            "description": "version of the instruction set, increased when an instruction or a payload field is added or changed",
                             "type": "integer"
                         }
                     }
@@ -3164,27 +9892,32 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateSmsRequest": {
+        "types.ListReleaseChannelsRespond": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "body": {
-                    "type": "string"
-                },
-                "date": {
-                    "type": "string"
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
                 },
-                "machineCode": {
-                    "type": "string"
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "channels": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ReleaseChannelObjDetail"
+                            }
+                        }
+                    }
                 },
-                "smsType": {
+                "msg": {
+                    "description": "return information description",
                     "type": "string"
                 }
             }
         },
-        "types.CreateSmsRespond": {
+        "types.ListReleasesRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3195,9 +9928,15 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        },
+                        "releases": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.ReleaseObjDetail"
+                            }
                         }
                     }
                 },
@@ -3207,18 +9946,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateUnanswerdCallRequest": {
-            "type": "object",
-            "properties": {
-                "clientMachineCode": {
-                    "type": "string"
-                },
-                "mobileNumber": {
-                    "type": "string"
-                }
-            }
-        },
-        "types.CreateUnanswerdCallRespond": {
+        "types.ListSimsByCursorRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3229,9 +9957,15 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
-                            "type": "integer"
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        },
+                        "sims": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SimObjDetail"
+                            }
                         }
                     }
                 },
@@ -3241,15 +9975,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CreateUserRequest": {
-            "type": "object",
-            "properties": {
-                "machineCode": {
-                    "type": "string"
-                }
-            }
-        },
-        "types.CreateUserRespond": {
+        "types.ListSimsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3260,8 +9986,13 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "id": {
-                            "description": "id",
+                        "sims": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SimObjDetail"
+                            }
+                        },
+                        "total": {
                             "type": "integer"
                         }
                     }
@@ -3272,7 +10003,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteCallHistoryByIDRespond": {
+        "types.ListSmssByCursorRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3280,7 +10011,20 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        },
+                        "smss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SmsObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3288,7 +10032,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteCallHistorysByIDsRequest": {
+        "types.ListSmssByIDsRequest": {
             "type": "object",
             "properties": {
                 "ids": {
@@ -3301,7 +10045,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteCallHistorysByIDsRespond": {
+        "types.ListSmssByIDsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3309,7 +10053,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "smss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SmsObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3317,7 +10070,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteClientsByIDRespond": {
+        "types.ListSmssRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3325,36 +10078,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
-                },
-                "msg": {
-                    "description": "return information description",
-                    "type": "string"
-                }
-            }
-        },
-        "types.DeleteClientssByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "smss": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SmsObjDetail"
+                            }
+                        }
                     }
-                }
-            }
-        },
-        "types.DeleteClientssByIDsRespond": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
-                },
-                "data": {
-                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3362,7 +10095,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteDistributionByIDRespond": {
+        "types.ListUnanswerdCallsByCursorRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3370,7 +10103,20 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        },
+                        "unanswerdCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UnanswerdCallObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3378,7 +10124,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteDistributionsByIDsRequest": {
+        "types.ListUnanswerdCallsByIDsRequest": {
             "type": "object",
             "properties": {
                 "ids": {
@@ -3391,7 +10137,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteDistributionsByIDsRespond": {
+        "types.ListUnanswerdCallsByIDsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3399,7 +10145,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "unanswerdCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UnanswerdCallObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3407,7 +10162,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteGroupCallByIDRespond": {
+        "types.ListUnanswerdCallsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3415,36 +10170,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
-                },
-                "msg": {
-                    "description": "return information description",
-                    "type": "string"
-                }
-            }
-        },
-        "types.DeleteGroupCallsByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "unanswerdCalls": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UnanswerdCallObjDetail"
+                            }
+                        }
                     }
-                }
-            }
-        },
-        "types.DeleteGroupCallsByIDsRespond": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
-                },
-                "data": {
-                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3452,7 +10187,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteGroupClientByIDRespond": {
+        "types.ListUsersByCursorRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3460,7 +10195,20 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "nextCursor": {
+                            "description": "cursor of the next page, empty if there are no more records",
+                            "type": "string"
+                        },
+                        "users": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UserObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3468,7 +10216,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteGroupClientsByIDsRequest": {
+        "types.ListUsersByIDsRequest": {
             "type": "object",
             "properties": {
                 "ids": {
@@ -3481,7 +10229,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteGroupClientsByIDsRespond": {
+        "types.ListUsersByIDsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3489,7 +10237,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "users": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UserObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3497,7 +10254,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteSmsByIDRespond": {
+        "types.ListUsersRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3505,7 +10262,16 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data"
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "users": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UserObjDetail"
+                            }
+                        }
+                    }
                 },
                 "msg": {
                     "description": "return information description",
@@ -3513,36 +10279,54 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteSmssByIDsRequest": {
+        "types.Params": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "description": "id list",
+                "columns": {
+                    "description": "query conditions",
                     "type": "array",
-                    "minItems": 1,
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/types.Column"
                     }
+                },
+                "page": {
+                    "description": "page number, starting from page 0",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "lines per page",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "sorted fields, multi-column sorting separated by commas",
+                    "type": "string"
                 }
             }
         },
-        "types.DeleteSmssByIDsRespond": {
+        "types.PatchCallHistoryByIDRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
+                "clientMachineCode": {
+                    "type": "string"
                 },
-                "data": {
-                    "description": "return data"
+                "instruction": {
+                    "type": "string"
                 },
-                "msg": {
-                    "description": "return information description",
+                "mobileNumber": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "requestMachineCode": {
                     "type": "string"
+                },
+                "simId": {
+                    "type": "integer"
                 }
             }
         },
-        "types.DeleteUnanswerdCallByIDRespond": {
+        "types.PatchCallHistoryByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3558,20 +10342,18 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteUnanswerdCallsByIDsRequest": {
+        "types.PatchClientsByIDRequest": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
+                "ipAddress": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
                 }
             }
         },
-        "types.DeleteUnanswerdCallsByIDsRespond": {
+        "types.PatchClientsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3587,7 +10369,18 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteUserByIDRespond": {
+        "types.PatchDistributionByIDRequest": {
+            "type": "object",
+            "properties": {
+                "groupCallId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PatchDistributionByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3603,20 +10396,21 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DeleteUsersByIDsRequest": {
+        "types.PatchGroupCallByIDRequest": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
+                "groupNumber": {
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "transferClientId": {
+                    "type": "string"
                 }
             }
         },
-        "types.DeleteUsersByIDsRespond": {
+        "types.PatchGroupCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3632,28 +10426,18 @@ const docTemplate = `{
                 }
             }
         },
-        "types.DistributionObjDetail": {
+        "types.PatchGroupClientByIDRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "groupCallId": {
+                "clientId": {
                     "type": "integer"
                 },
-                "id": {
-                    "description": "convert to string id",
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "userId": {
+                "groupId": {
                     "type": "integer"
                 }
             }
         },
-        "types.GetCallHistoryByConditionRespond": {
+        "types.PatchGroupClientByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3661,13 +10445,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "callHistory": {
-                            "$ref": "#/definitions/types.CallHistoryObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3675,29 +10453,44 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetCallHistoryByIDRespond": {
+        "types.PatchLabelsRequest": {
             "type": "object",
+            "required": [
+                "labels"
+            ],
             "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
-                },
-                "data": {
-                    "description": "return data",
+                "labels": {
                     "type": "object",
-                    "properties": {
-                        "callHistory": {
-                            "$ref": "#/definitions/types.CallHistoryObjDetail"
-                        }
+                    "additionalProperties": {
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "types.PatchSmsByIDRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
                 },
-                "msg": {
-                    "description": "return information description",
+                "body": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "machineCode": {
+                    "type": "string"
+                },
+                "simId": {
+                    "type": "integer"
+                },
+                "smsType": {
                     "type": "string"
                 }
             }
         },
-        "types.GetClientsByConditionRespond": {
+        "types.PatchSmsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3705,13 +10498,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "clients": {
-                            "$ref": "#/definitions/types.ClientsObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3719,29 +10506,18 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetClientsByIDRespond": {
+        "types.PatchUnanswerdCallByIDRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
-                },
-                "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "clients": {
-                            "$ref": "#/definitions/types.ClientsObjDetail"
-                        }
-                    }
+                "clientMachineCode": {
+                    "type": "string"
                 },
-                "msg": {
-                    "description": "return information description",
+                "mobileNumber": {
                     "type": "string"
                 }
             }
         },
-        "types.GetDistributionByConditionRespond": {
+        "types.PatchUnanswerdCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3749,13 +10525,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "distribution": {
-                            "$ref": "#/definitions/types.DistributionObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3763,7 +10533,15 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetDistributionByIDRespond": {
+        "types.PatchUserByIDRequest": {
+            "type": "object",
+            "properties": {
+                "machineCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.PatchUserByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3771,13 +10549,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "distribution": {
-                            "$ref": "#/definitions/types.DistributionObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3785,7 +10557,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetGroupCallByConditionRespond": {
+        "types.PollDeviceCommandsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3796,8 +10568,12 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "groupCall": {
-                            "$ref": "#/definitions/types.GroupCallObjDetail"
+                        "commands": {
+                            "description": "empty if no command was queued before the wait ended",
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.DeviceCommandObjDetail"
+                            }
                         }
                     }
                 },
@@ -3807,7 +10583,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetGroupCallByIDRespond": {
+        "types.PurgeCallHistoryByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3815,13 +10591,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupCall": {
-                            "$ref": "#/definitions/types.GroupCallObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3829,7 +10599,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetGroupClientByConditionRespond": {
+        "types.PurgeClientsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3837,13 +10607,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupClient": {
-                            "$ref": "#/definitions/types.GroupClientObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3851,7 +10615,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetGroupClientByIDRespond": {
+        "types.PurgeDistributionByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3859,13 +10623,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupClient": {
-                            "$ref": "#/definitions/types.GroupClientObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3873,7 +10631,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetSmsByConditionRespond": {
+        "types.PurgeGroupCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3881,13 +10639,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "sms": {
-                            "$ref": "#/definitions/types.SmsObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3895,7 +10647,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetSmsByIDRespond": {
+        "types.PurgeGroupClientByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3903,13 +10655,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "sms": {
-                            "$ref": "#/definitions/types.SmsObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3917,7 +10663,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetUnanswerdCallByConditionRespond": {
+        "types.PurgeSmsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3925,13 +10671,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "unanswerdCall": {
-                            "$ref": "#/definitions/types.UnanswerdCallObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3939,7 +10679,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetUnanswerdCallByIDRespond": {
+        "types.PurgeUnanswerdCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3947,13 +10687,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "unanswerdCall": {
-                            "$ref": "#/definitions/types.UnanswerdCallObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3961,7 +10695,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetUserByConditionRespond": {
+        "types.PurgeUserByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3969,13 +10703,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "user": {
-                            "$ref": "#/definitions/types.UserObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -3983,7 +10711,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GetUserByIDRespond": {
+        "types.RedeliverDeviceCommandRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -3991,13 +10719,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "user": {
-                            "$ref": "#/definitions/types.UserObjDetail"
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4005,65 +10727,97 @@ const docTemplate = `{
                 }
             }
         },
-        "types.GroupCallObjDetail": {
+        "types.ReleaseChannelObjDetail": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
+                "clientPercent": {
+                    "type": "integer"
                 },
-                "groupNumber": {
+                "name": {
                     "type": "string"
                 },
-                "id": {
-                    "description": "convert to string id",
+                "previousReleaseId": {
+                    "description": "offered to the clients outside the rollout, \"0\" if none",
                     "type": "string"
                 },
-                "phoneNumber": {
+                "releaseId": {
+                    "description": "\"0\" if the channel has no release",
                     "type": "string"
                 },
-                "transferClientId": {
-                    "type": "string"
+                "rolloutPercent": {
+                    "type": "integer"
                 },
                 "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "types.GroupClientObjDetail": {
+        "types.ReleaseObjDetail": {
             "type": "object",
             "properties": {
-                "clientId": {
-                    "type": "integer"
+                "checksum": {
+                    "description": "hex sha256 of the apk",
+                    "type": "string"
                 },
                 "createdAt": {
                     "type": "string"
                 },
-                "groupId": {
+                "downloadUrl": {
+                    "type": "string"
+                },
+                "fileSize": {
+                    "description": "bytes",
                     "type": "integer"
                 },
                 "id": {
                     "description": "convert to string id",
                     "type": "string"
                 },
-                "updatedAt": {
+                "releaseNotes": {
+                    "type": "string"
+                },
+                "versionCode": {
+                    "type": "integer"
+                },
+                "versionName": {
                     "type": "string"
                 }
             }
         },
-        "types.ListCallHistorysByIDsRequest": {
+        "types.ReportCallHistoryStateRequest": {
             "type": "object",
+            "required": [
+                "state"
+            ],
             "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
+                "at": {
+                    "description": "time of the change on the device, empty is now, a time in the future is now",
+                    "type": "string"
+                },
+                "failureReason": {
+                    "description": "for failed and busy",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "dispatched",
+                        "ringing",
+                        "answered",
+                        "ended",
+                        "failed",
+                        "busy"
+                    ]
+                },
+                "talkDuration": {
+                    "description": "seconds, for a final state, if 0 it is counted from the time the call was answered",
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "types.ListCallHistorysByIDsRespond": {
+        "types.ReportCallHistoryStateRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4074,11 +10828,8 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "callHistorys": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.CallHistoryObjDetail"
-                            }
+                        "callHistory": {
+                            "$ref": "#/definitions/types.CallHistoryObjDetail"
                         }
                     }
                 },
@@ -4088,70 +10839,60 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListCallHistorysRespond": {
+        "types.ReportSimRequest": {
             "type": "object",
+            "required": [
+                "iccid"
+            ],
             "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
+                "carrier": {
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "callHistorys": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.CallHistoryObjDetail"
-                            }
-                        }
-                    }
+                "iccid": {
+                    "type": "string",
+                    "maxLength": 22,
+                    "minLength": 18
                 },
-                "msg": {
-                    "description": "return information description",
-                    "type": "string"
+                "phoneNumber": {
+                    "description": "empty if the device cannot read it, the known number is kept",
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "slotIndex": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
                 }
             }
         },
-        "types.ListClientssByIDsRequest": {
+        "types.ReportSimsRequest": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "description": "id list",
+                "sims": {
+                    "description": "all the sims in the device, an empty list if it has none",
                     "type": "array",
-                    "minItems": 1,
+                    "maxItems": 8,
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/types.ReportSimRequest"
                     }
                 }
             }
         },
-        "types.ListClientssByIDsRespond": {
+        "types.RescheduleCallHistoryRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "return code",
-                    "type": "integer"
-                },
-                "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "clientss": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.ClientsObjDetail"
-                            }
-                        }
-                    }
+                "scheduleIn": {
+                    "description": "a duration such as 30m or 2h from now",
+                    "type": "string"
                 },
-                "msg": {
-                    "description": "return information description",
+                "scheduledAt": {
+                    "description": "a time in the past sends the instruction at the next run of the scheduler",
                     "type": "string"
                 }
             }
         },
-        "types.ListClientssRespond": {
+        "types.RescheduleCallHistoryRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4162,11 +10903,8 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "clientss": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.ClientsObjDetail"
-                            }
+                        "callHistory": {
+                            "$ref": "#/definitions/types.CallHistoryObjDetail"
                         }
                     }
                 },
@@ -4176,20 +10914,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListDistributionsByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "types.ListDistributionsByIDsRespond": {
+        "types.RestoreCallHistoryByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4197,16 +10922,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "distributions": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.DistributionObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4214,7 +10930,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListDistributionsRespond": {
+        "types.RestoreClientsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4222,16 +10938,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "distributions": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.DistributionObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4239,20 +10946,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListGroupCallsByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "types.ListGroupCallsByIDsRespond": {
+        "types.RestoreDistributionByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4260,16 +10954,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupCalls": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.GroupCallObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4277,7 +10962,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListGroupCallsRespond": {
+        "types.RestoreGroupCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4285,16 +10970,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupCalls": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.GroupCallObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4302,20 +10978,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListGroupClientsByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "types.ListGroupClientsByIDsRespond": {
+        "types.RestoreGroupClientByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4323,16 +10986,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupClients": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.GroupClientObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4340,7 +10994,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListGroupClientsRespond": {
+        "types.RestoreSmsByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4348,16 +11002,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "groupClients": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.GroupClientObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4365,20 +11010,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListSmssByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "types.ListSmssByIDsRespond": {
+        "types.RestoreUnanswerdCallByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4386,16 +11018,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "smss": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.SmsObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4403,7 +11026,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListSmssRespond": {
+        "types.RestoreUserByIDRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4411,16 +11034,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "smss": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.SmsObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4428,20 +11042,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListUnanswerdCallsByIDsRequest": {
-            "type": "object",
-            "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "types.ListUnanswerdCallsByIDsRespond": {
+        "types.RevokeClientsCredentialRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4449,16 +11050,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "data": {
-                    "description": "return data",
-                    "type": "object",
-                    "properties": {
-                        "unanswerdCalls": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.UnanswerdCallObjDetail"
-                            }
-                        }
-                    }
+                    "description": "return data"
                 },
                 "msg": {
                     "description": "return information description",
@@ -4466,7 +11058,7 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListUnanswerdCallsRespond": {
+        "types.RotateClientsCredentialRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4477,11 +11069,9 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "unanswerdCalls": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.UnanswerdCallObjDetail"
-                            }
+                        "credential": {
+                            "description": "the new credential, the previous one is no longer valid",
+                            "type": "string"
                         }
                     }
                 },
@@ -4491,20 +11081,28 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListUsersByIDsRequest": {
+        "types.SetClientsStatusRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "ids": {
-                    "description": "id list",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "integer"
-                    }
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "status": {
+                    "description": "a disabled or quarantined client is not routed calls and its device apis are rejected",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "disabled",
+                        "quarantined"
+                    ]
                 }
             }
         },
-        "types.ListUsersByIDsRespond": {
+        "types.SetClientsStatusRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4515,11 +11113,8 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "users": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.UserObjDetail"
-                            }
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
                         }
                     }
                 },
@@ -4529,7 +11124,43 @@ const docTemplate = `{
                 }
             }
         },
-        "types.ListUsersRespond": {
+        "types.SetLabelsRequest": {
+            "type": "object",
+            "required": [
+                "labels"
+            ],
+            "properties": {
+                "labels": {
+                    "description": "replace all the labels of the record, {} removes them",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.SetReleaseChannelClientsRequest": {
+            "type": "object",
+            "required": [
+                "machineCodes"
+            ],
+            "properties": {
+                "channel": {
+                    "description": "empty removes the assignment, the clients are then placed by the client percent of the channels",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "machineCodes": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.SetReleaseChannelClientsRespond": {
             "type": "object",
             "properties": {
                 "code": {
@@ -4540,11 +11171,9 @@ const docTemplate = `{
                     "description": "return data",
                     "type": "object",
                     "properties": {
-                        "users": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.UserObjDetail"
-                            }
+                        "changed": {
+                            "description": "number of clients whose channel changed",
+                            "type": "integer"
                         }
                     }
                 },
@@ -4554,27 +11183,48 @@ const docTemplate = `{
                 }
             }
         },
-        "types.Params": {
+        "types.SimObjDetail": {
             "type": "object",
             "properties": {
-                "columns": {
-                    "description": "query conditions",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.Column"
-                    }
+                "carrier": {
+                    "type": "string"
                 },
-                "page": {
-                    "description": "page number, starting from page 0",
+                "clientId": {
+                    "description": "0 if the sim is not in a device",
                     "type": "integer"
                 },
-                "size": {
-                    "description": "lines per page",
+                "createdAt": {
+                    "type": "string"
+                },
+                "duplicateNumber": {
+                    "description": "true if the phone number is also on a sim in another device",
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "iccid": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "convert to string id",
+                    "type": "string"
+                },
+                "phoneNumber": {
+                    "type": "string"
+                },
+                "slotIndex": {
                     "type": "integer"
                 },
-                "sort": {
-                    "description": "sorted fields, multi-column sorting separated by commas",
+                "status": {
+                    "description": "active, suspended or cancelled",
+                    "type": "string"
+                },
+                "updatedAt": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -4593,6 +11243,10 @@ const docTemplate = `{
                 "date": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
                 "id": {
                     "description": "convert to string id",
                     "type": "string"
@@ -4600,11 +11254,68 @@ const docTemplate = `{
                 "machineCode": {
                     "type": "string"
                 },
+                "simId": {
+                    "description": "0 if unknown",
+                    "type": "integer"
+                },
                 "smsType": {
                     "type": "string"
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.StartCallRecordingRequest": {
+            "type": "object",
+            "required": [
+                "checksum",
+                "codec",
+                "size"
+            ],
+            "properties": {
+                "checksum": {
+                    "description": "hex sha256 of the audio, the upload is rejected when the audio does not match",
+                    "type": "string"
+                },
+                "codec": {
+                    "description": "codec of the audio, e.g. amr, aac, opus",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "duration": {
+                    "description": "milliseconds of audio",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "size": {
+                    "description": "bytes of the audio",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.StartCallRecordingRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callRecording": {
+                            "$ref": "#/definitions/types.CallRecordingObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
                 }
             }
         },
@@ -4617,6 +11328,10 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
                 "id": {
                     "description": "convert to string id",
                     "type": "string"
@@ -4626,6 +11341,9 @@ const docTemplate = `{
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -4645,8 +11363,15 @@ const docTemplate = `{
                 "mobileNumber": {
                     "type": "string"
                 },
+                "payload": {
+                    "description": "replaces the payload, if the instruction is changed and the payload is empty the instruction has no payload",
+                    "type": "object"
+                },
                 "requestMachineCode": {
                     "type": "string"
+                },
+                "simId": {
+                    "type": "integer"
                 }
             }
         },
@@ -4793,6 +11518,50 @@ const docTemplate = `{
                 }
             }
         },
+        "types.UpdateSimByIDRequest": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "uint64 id",
+                    "type": "integer"
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "cancelled"
+                    ]
+                }
+            }
+        },
+        "types.UpdateSimByIDRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data"
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
         "types.UpdateSmsByIDRequest": {
             "type": "object",
             "properties": {
@@ -4812,6 +11581,9 @@ const docTemplate = `{
                 "machineCode": {
                     "type": "string"
                 },
+                "simId": {
+                    "type": "integer"
+                },
                 "smsType": {
                     "type": "string"
                 }
@@ -4892,12 +11664,172 @@ const docTemplate = `{
                 }
             }
         },
+        "types.UploadCallRecordingRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "callRecording": {
+                            "$ref": "#/definitions/types.CallRecordingObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.UploadReleaseRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "release": {
+                            "$ref": "#/definitions/types.ReleaseObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.UpsertClientsMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "androidVersion": {
+                    "type": "string",
+                    "maxLength": 16
+                },
+                "appVersion": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "appVersionCode": {
+                    "description": "versionCode of the app, if empty the X-App-Version-Code header is used",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "batteryLevel": {
+                    "description": "percent",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "carrier": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "deviceModel": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "isCharging": {
+                    "type": "boolean"
+                },
+                "machineCode": {
+                    "description": "if empty, the machine code of the device credential is used",
+                    "type": "string"
+                },
+                "signalStrength": {
+                    "description": "dBm",
+                    "type": "integer"
+                },
+                "simSlotCount": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.UpsertClientsMetadataRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "clients": {
+                            "$ref": "#/definitions/types.ClientsObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
+        "types.UpsertReleaseChannelRequest": {
+            "type": "object",
+            "properties": {
+                "clientPercent": {
+                    "description": "percentage of the unassigned clients that are in the channel, the channels share at most 100",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "releaseId": {
+                    "description": "release offered by the channel, the replaced release is still offered to the clients outside the rollout, 0 removes the release",
+                    "type": "integer"
+                },
+                "rolloutPercent": {
+                    "description": "percentage of the clients of the channel that are offered the release, a new channel has 100",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "types.UpsertReleaseChannelRespond": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "return code",
+                    "type": "integer"
+                },
+                "data": {
+                    "description": "return data",
+                    "type": "object",
+                    "properties": {
+                        "channel": {
+                            "$ref": "#/definitions/types.ReleaseChannelObjDetail"
+                        }
+                    }
+                },
+                "msg": {
+                    "description": "return information description",
+                    "type": "string"
+                }
+            }
+        },
         "types.UserObjDetail": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "description": "only set for records in the trash",
+                    "type": "string"
+                },
                 "id": {
                     "description": "convert to string id",
                     "type": "string"
@@ -4907,6 +11839,9 @@ const docTemplate = `{
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         }
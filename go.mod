@@ -6,6 +6,7 @@ require (
 	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/gin-gonic/gin v1.9.1
 	github.com/go-redis/redis/v8 v8.11.5
+	github.com/gorilla/websocket v1.5.1
 	github.com/jinzhu/copier v0.3.5
 	github.com/stretchr/testify v1.8.4
 	github.com/swaggo/files v0.0.0-20220728132757-551d4a08d97a
@@ -13,8 +14,9 @@ require (
 	github.com/swaggo/swag v1.8.12
 	github.com/zhufuyi/sponge v1.8.3
 	golang.org/x/sync v0.5.0
+	gorm.io/driver/postgres v1.5.4
 	gorm.io/gorm v1.25.5
-
+	gorm.io/plugin/dbresolver v1.4.7
 )
 
 require (
@@ -154,7 +156,5 @@ require (
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gorm.io/driver/mysql v1.5.1 // indirect
-	gorm.io/driver/postgres v1.5.4 // indirect
 	gorm.io/driver/sqlite v1.5.4 // indirect
-	gorm.io/plugin/dbresolver v1.4.7 // indirect
 )
@@ -141,4 +141,14 @@ func TestNewCallHistoryCache(t *testing.T) {
 		CType: "redis",
 	})
 	assert.NotNil(t, c)
+
+	// the memory caches of the same cache type are shared
+	cacheType := &model.CacheType{CType: "memory"}
+	c = NewCallHistoryCache(cacheType)
+	assert.Same(t, c.(*entityCache[model.CallHistory]).cache, NewCallHistoryCache(cacheType).(*entityCache[model.CallHistory]).cache)
+	assert.NotSame(t, c.(*entityCache[model.CallHistory]).cache, NewCallHistoryCache(&model.CacheType{CType: "memory"}).(*entityCache[model.CallHistory]).cache)
+	c = NewCallHistoryCache(&model.CacheType{
+		CType: "redis",
+	})
+	assert.NotNil(t, c)
 }
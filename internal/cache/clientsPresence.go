@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"caller/internal/model"
+)
+
+// clientsPresenceKey redis sorted set of machine codes scored by the unix milliseconds of their last heartbeat
+const clientsPresenceKey = "clients:presence"
+
+// ClientsPresenceCache the time of the last heartbeat of each client
+type ClientsPresenceCache interface {
+	Touch(ctx context.Context, machineCode string, lastSeenAt time.Time) error
+	MultiGet(ctx context.Context, machineCodes []string) (map[string]time.Time, error)
+	GetSeenSince(ctx context.Context, since time.Time) ([]string, error)
+}
+
+// NewClientsPresenceCache new a presence cache, presence is kept in memory if the cache type is not redis
+func NewClientsPresenceCache(cacheType *model.CacheType) ClientsPresenceCache {
+	if strings.ToLower(cacheType.CType) == "redis" {
+		return &clientsPresenceRedis{rdb: cacheType.Rdb}
+	}
+	return memoryClientsPresence
+}
+
+type clientsPresenceRedis struct {
+	rdb *redis.Client
+}
+
+// Touch set the last heartbeat time of a client
+func (c *clientsPresenceRedis) Touch(ctx context.Context, machineCode string, lastSeenAt time.Time) error {
+	return c.rdb.ZAdd(ctx, clientsPresenceKey, &redis.Z{
+		Score:  float64(lastSeenAt.UnixMilli()),
+		Member: machineCode,
+	}).Err()
+}
+
+// MultiGet get the last heartbeat time of clients, the clients never seen are not in the map
+func (c *clientsPresenceRedis) MultiGet(ctx context.Context, machineCodes []string) (map[string]time.Time, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(machineCodes))
+	for i, machineCode := range machineCodes {
+		cmds[i] = pipe.ZScore(ctx, clientsPresenceKey, machineCode)
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	retMap := make(map[string]time.Time, len(machineCodes))
+	for i, cmd := range cmds {
+		score, err := cmd.Result()
+		if err != nil {
+			continue // redis.Nil, never seen
+		}
+		retMap[machineCodes[i]] = time.UnixMilli(int64(score))
+	}
+	return retMap, nil
+}
+
+// GetSeenSince get the machine codes of the clients whose last heartbeat is not before since
+func (c *clientsPresenceRedis) GetSeenSince(ctx context.Context, since time.Time) ([]string, error) {
+	return c.rdb.ZRangeByScore(ctx, clientsPresenceKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+}
+
+// memoryClientsPresence shared by every handler of the process
+var memoryClientsPresence = &clientsPresenceMemory{lastSeen: map[string]time.Time{}}
+
+type clientsPresenceMemory struct {
+	mu       sync.RWMutex
+	lastSeen map[string]time.Time
+}
+
+// Touch set the last heartbeat time of a client
+func (c *clientsPresenceMemory) Touch(_ context.Context, machineCode string, lastSeenAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastSeen[machineCode] = lastSeenAt
+	return nil
+}
+
+// MultiGet get the last heartbeat time of clients, the clients never seen are not in the map
+func (c *clientsPresenceMemory) MultiGet(_ context.Context, machineCodes []string) (map[string]time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	retMap := make(map[string]time.Time, len(machineCodes))
+	for _, machineCode := range machineCodes {
+		if lastSeenAt, ok := c.lastSeen[machineCode]; ok {
+			retMap[machineCode] = lastSeenAt
+		}
+	}
+	return retMap, nil
+}
+
+// GetSeenSince get the machine codes of the clients whose last heartbeat is not before since
+func (c *clientsPresenceMemory) GetSeenSince(_ context.Context, since time.Time) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	machineCodes := []string{}
+	for machineCode, lastSeenAt := range c.lastSeen {
+		if !lastSeenAt.Before(since) {
+			machineCodes = append(machineCodes, machineCode)
+		}
+	}
+	return machineCodes, nil
+}
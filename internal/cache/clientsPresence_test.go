@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/gotest"
+
+	"caller/internal/model"
+)
+
+func testClientsPresence(t *testing.T, c ClientsPresenceCache) {
+	ctx := context.Background()
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	assert.NoError(t, c.Touch(ctx, "m1", now.Add(-time.Hour)))
+	assert.NoError(t, c.Touch(ctx, "m2", now.Add(-time.Minute)))
+	assert.NoError(t, c.Touch(ctx, "m3", now))
+
+	lastSeen, err := c.MultiGet(ctx, []string{"m1", "m3", "never seen"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"m1": now.Add(-time.Hour), "m3": now}, lastSeen)
+
+	machineCodes, err := c.GetSeenSince(ctx, now.Add(-2*time.Minute))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"m2", "m3"}, machineCodes)
+
+	// a new heartbeat replaces the previous one
+	assert.NoError(t, c.Touch(ctx, "m1", now))
+	machineCodes, err = c.GetSeenSince(ctx, now)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"m1", "m3"}, machineCodes)
+}
+
+func TestClientsPresenceCache_Redis(t *testing.T) {
+	c := gotest.NewCache(map[string]interface{}{})
+	defer c.Close()
+	testClientsPresence(t, NewClientsPresenceCache(&model.CacheType{CType: "redis", Rdb: c.RedisClient}))
+}
+
+func TestClientsPresenceCache_Memory(t *testing.T) {
+	testClientsPresence(t, &clientsPresenceMemory{lastSeen: map[string]time.Time{}})
+	assert.Equal(t, memoryClientsPresence, NewClientsPresenceCache(&model.CacheType{CType: "memory"}))
+	assert.Equal(t, memoryClientsPresence, NewClientsPresenceCache(&model.CacheType{}))
+}
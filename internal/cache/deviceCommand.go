@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+
+	"caller/internal/model"
+)
+
+// deviceCommandChannelPrefix redis channel that is published to when commands are queued for a device,
+// the machine code of the device follows the prefix
+const deviceCommandChannelPrefix = "device:commands:"
+
+// DeviceCommandNotifier wake up the requests waiting for the commands of a device
+type DeviceCommandNotifier interface {
+	// Notify tell the waiting requests that commands were queued for the device
+	Notify(ctx context.Context, machineCode string) error
+	// Subscribe receive a value on the channel when commands are queued for the device,
+	// several notifications may be merged into one, call cancel when no longer waiting.
+	Subscribe(machineCode string) (ch <-chan struct{}, cancel func())
+}
+
+// NewDeviceCommandNotifier new a notifier, notifications only reach the requests of this process
+// if the cache type is not redis
+func NewDeviceCommandNotifier(cacheType *model.CacheType) DeviceCommandNotifier {
+	if strings.ToLower(cacheType.CType) == "redis" {
+		return &deviceCommandNotifierRedis{rdb: cacheType.Rdb, local: newDeviceCommandNotifierMemory()}
+	}
+	return memoryDeviceCommandNotifier
+}
+
+// deviceCommandNotifierRedis publish notifications to redis, so the requests waiting on every replica are woken up,
+// one pattern subscription of the process forwards them to the local waiting requests.
+type deviceCommandNotifierRedis struct {
+	rdb   *redis.Client
+	local *deviceCommandNotifierMemory
+	once  sync.Once
+}
+
+// Notify tell the waiting requests that commands were queued for the device
+func (n *deviceCommandNotifierRedis) Notify(ctx context.Context, machineCode string) error {
+	return n.rdb.Publish(ctx, deviceCommandChannelPrefix+machineCode, "").Err()
+}
+
+// Subscribe receive a value on the channel when commands are queued for the device
+func (n *deviceCommandNotifierRedis) Subscribe(machineCode string) (<-chan struct{}, func()) {
+	n.once.Do(func() {
+		pubSub := n.rdb.PSubscribe(context.Background(), deviceCommandChannelPrefix+"*")
+		go func() {
+			for msg := range pubSub.Channel() {
+				_ = n.local.Notify(context.Background(), strings.TrimPrefix(msg.Channel, deviceCommandChannelPrefix))
+			}
+		}()
+	})
+	return n.local.Subscribe(machineCode)
+}
+
+// memoryDeviceCommandNotifier shared by every handler of the process
+var memoryDeviceCommandNotifier = newDeviceCommandNotifierMemory()
+
+type deviceCommandNotifierMemory struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func newDeviceCommandNotifierMemory() *deviceCommandNotifierMemory {
+	return &deviceCommandNotifierMemory{subscribers: map[string]map[chan struct{}]struct{}{}}
+}
+
+// Notify tell the waiting requests that commands were queued for the device
+func (n *deviceCommandNotifierMemory) Notify(_ context.Context, machineCode string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subscribers[machineCode] {
+		select {
+		case ch <- struct{}{}:
+		default: // a notification is already waiting to be received
+		}
+	}
+	return nil
+}
+
+// Subscribe receive a value on the channel when commands are queued for the device
+func (n *deviceCommandNotifierMemory) Subscribe(machineCode string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.subscribers[machineCode] == nil {
+		n.subscribers[machineCode] = map[chan struct{}]struct{}{}
+	}
+	n.subscribers[machineCode][ch] = struct{}{}
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subscribers[machineCode], ch)
+		if len(n.subscribers[machineCode]) == 0 {
+			delete(n.subscribers, machineCode)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/gotest"
+
+	"caller/internal/model"
+)
+
+func testDeviceCommandNotifier(t *testing.T, n DeviceCommandNotifier) {
+	ctx := context.Background()
+	ch1, cancel1 := n.Subscribe("m1")
+	ch2, cancel2 := n.Subscribe("m2")
+	defer cancel2()
+
+	// the notifications of the subscription are merged
+	assert.NoError(t, n.Notify(ctx, "m1"))
+	assert.NoError(t, n.Notify(ctx, "m1"))
+	select {
+	case <-ch1:
+	case <-time.After(time.Second):
+		t.Fatal("m1 was not notified")
+	}
+	select {
+	case <-ch2:
+		t.Fatal("m2 was notified")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// canceled subscriptions are not notified
+	cancel1()
+	assert.NoError(t, n.Notify(ctx, "m1"))
+	select {
+	case <-ch1:
+		t.Fatal("canceled subscription was notified")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDeviceCommandNotifier_Redis(t *testing.T) {
+	c := gotest.NewCache(map[string]interface{}{})
+	defer c.Close()
+	n := NewDeviceCommandNotifier(&model.CacheType{CType: "redis", Rdb: c.RedisClient})
+
+	// wait for the pattern subscription of the process
+	_, cancel := n.Subscribe("m0")
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+
+	testDeviceCommandNotifier(t, n)
+}
+
+func TestDeviceCommandNotifier_Memory(t *testing.T) {
+	testDeviceCommandNotifier(t, newDeviceCommandNotifierMemory())
+	assert.Equal(t, memoryDeviceCommandNotifier, NewDeviceCommandNotifier(&model.CacheType{CType: "memory"}))
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/cache"
+	"github.com/zhufuyi/sponge/pkg/encoding"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/model"
+)
+
+// EntityCache cache interface of the records of a table, T is a table struct in model, the key is the record id
+type EntityCache[T any] interface {
+	Set(ctx context.Context, id uint64, data *T, duration time.Duration) error
+	Get(ctx context.Context, id uint64) (*T, error)
+	MultiGet(ctx context.Context, ids []uint64) (map[uint64]*T, error)
+	MultiSet(ctx context.Context, data []*T, duration time.Duration) error
+	Del(ctx context.Context, id uint64) error
+	SetCacheWithNotFound(ctx context.Context, id uint64) error
+}
+
+// entityCache define a cache struct
+type entityCache[T any] struct {
+	prefixKey string // cache prefix key, must end with a colon
+	cache     cache.Cache
+}
+
+// newEntityCache new a cache, return nil if the cache type is not memory or redis
+func newEntityCache[T any](cacheType *model.CacheType, prefixKey string) *entityCache[T] {
+	jsonEncoding := encoding.JSONEncoding{}
+	cachePrefix := ""
+	newObject := func() interface{} {
+		return new(T)
+	}
+
+	cType := strings.ToLower(cacheType.CType)
+	switch cType {
+	case "redis":
+		c := cache.NewRedisCache(cacheType.Rdb, cachePrefix, jsonEncoding, newObject)
+		return &entityCache[T]{prefixKey: prefixKey, cache: c}
+	case "memory":
+		c := getMemoryCache(cacheType, prefixKey, func() cache.Cache {
+			return cache.NewMemoryCache(cachePrefix, jsonEncoding, newObject)
+		})
+		return &entityCache[T]{prefixKey: prefixKey, cache: c}
+	}
+
+	return nil // no cache
+}
+
+// memoryCaches the memory caches of each cache type and prefix key, a memory cache is only seen by the process
+// that holds it, so the daos of a table share one to see the deletes of each other, as they do with redis
+var memoryCaches = struct {
+	sync.Mutex
+	m map[memoryCacheKey]cache.Cache
+}{m: map[memoryCacheKey]cache.Cache{}}
+
+type memoryCacheKey struct {
+	cacheType *model.CacheType
+	prefixKey string
+}
+
+// getMemoryCache get the memory cache of the cache type and prefix key, it is created by newCache the first time
+func getMemoryCache(cacheType *model.CacheType, prefixKey string, newCache func() cache.Cache) cache.Cache {
+	memoryCaches.Lock()
+	defer memoryCaches.Unlock()
+	key := memoryCacheKey{cacheType: cacheType, prefixKey: prefixKey}
+	c, ok := memoryCaches.m[key]
+	if !ok {
+		c = newCache()
+		memoryCaches.m[key] = c
+	}
+	return c
+}
+
+// GetCacheKey cache key
+func (c *entityCache[T]) GetCacheKey(id uint64) string {
+	return c.prefixKey + utils.Uint64ToStr(id)
+}
+
+// Set write to cache
+func (c *entityCache[T]) Set(ctx context.Context, id uint64, data *T, duration time.Duration) error {
+	if data == nil || id == 0 {
+		return nil
+	}
+	cacheKey := c.GetCacheKey(id)
+	err := c.cache.Set(ctx, cacheKey, data, duration)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get cache value
+func (c *entityCache[T]) Get(ctx context.Context, id uint64) (*T, error) {
+	var data *T
+	cacheKey := c.GetCacheKey(id)
+	err := c.cache.Get(ctx, cacheKey, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// MultiSet multiple set cache
+func (c *entityCache[T]) MultiSet(ctx context.Context, data []*T, duration time.Duration) error {
+	valMap := make(map[string]interface{})
+	for _, v := range data {
+		cacheKey := c.GetCacheKey(model.GetID(v))
+		valMap[cacheKey] = v
+	}
+
+	err := c.cache.MultiSet(ctx, valMap, duration)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MultiGet multiple get cache, return key in map is id value
+func (c *entityCache[T]) MultiGet(ctx context.Context, ids []uint64) (map[uint64]*T, error) {
+	var keys []string
+	for _, v := range ids {
+		cacheKey := c.GetCacheKey(v)
+		keys = append(keys, cacheKey)
+	}
+
+	itemMap := make(map[string]*T)
+	err := c.cache.MultiGet(ctx, keys, itemMap)
+	if err != nil {
+		return nil, err
+	}
+
+	retMap := make(map[uint64]*T)
+	for _, id := range ids {
+		val, ok := itemMap[c.GetCacheKey(id)]
+		if ok {
+			retMap[id] = val
+		}
+	}
+
+	return retMap, nil
+}
+
+// Del delete cache
+func (c *entityCache[T]) Del(ctx context.Context, id uint64) error {
+	cacheKey := c.GetCacheKey(id)
+	err := c.cache.Del(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetCacheWithNotFound set empty cache
+func (c *entityCache[T]) SetCacheWithNotFound(ctx context.Context, id uint64) error {
+	cacheKey := c.GetCacheKey(id)
+	err := c.cache.SetCacheWithNotFound(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -32,6 +32,7 @@ type Config struct {
 	App        App          `yaml:"app" json:"app"`
 	Consul     Consul       `yaml:"consul" json:"consul"`
 	Database   Database     `yaml:"database" json:"database"`
+	Dial       Dial         `yaml:"dial" json:"dial"`
 	Etcd       Etcd         `yaml:"etcd" json:"etcd"`
 	Grpc       Grpc         `yaml:"grpc" json:"grpc"`
 	GrpcClient []GrpcClient `yaml:"grpcClient" json:"grpcClient"`
@@ -39,7 +40,11 @@ type Config struct {
 	Jaeger     Jaeger       `yaml:"jaeger" json:"jaeger"`
 	Logger     Logger       `yaml:"logger" json:"logger"`
 	NacosRd    NacosRd      `yaml:"nacosRd" json:"nacosRd"`
+	Recording  Recording    `yaml:"recording" json:"recording"`
 	Redis      Redis        `yaml:"redis" json:"redis"`
+	Release    Release      `yaml:"release" json:"release"`
+	Retention  Retention    `yaml:"retention" json:"retention"`
+	Scheduler  Scheduler    `yaml:"scheduler" json:"scheduler"`
 }
 
 type Consul struct {
@@ -78,7 +83,11 @@ type ServerSecure struct {
 
 type App struct {
 	CacheType             string  `yaml:"cacheType" json:"cacheType"`
+	ClientOfflineTimeout  int     `yaml:"clientOfflineTimeout" json:"clientOfflineTimeout"`
+	CursorSecret          string  `yaml:"cursorSecret" json:"cursorSecret"`
+	DeviceCommandTTL      int     `yaml:"deviceCommandTTL" json:"deviceCommandTTL"`
 	EnableCircuitBreaker  bool    `yaml:"enableCircuitBreaker" json:"enableCircuitBreaker"`
+	EnableDeviceAuth      bool    `yaml:"enableDeviceAuth" json:"enableDeviceAuth"`
 	EnableHTTPProfile     bool    `yaml:"enableHTTPProfile" json:"enableHTTPProfile"`
 	EnableLimit           bool    `yaml:"enableLimit" json:"enableLimit"`
 	EnableMetrics         bool    `yaml:"enableMetrics" json:"enableMetrics"`
@@ -86,6 +95,8 @@ type App struct {
 	EnableTrace           bool    `yaml:"enableTrace" json:"enableTrace"`
 	Env                   string  `yaml:"env" json:"env"`
 	Host                  string  `yaml:"host" json:"host"`
+	IPMassChangeThreshold int     `yaml:"ipMassChangeThreshold" json:"ipMassChangeThreshold"`
+	IPMassChangeWindow    int     `yaml:"ipMassChangeWindow" json:"ipMassChangeWindow"`
 	Name                  string  `yaml:"name" json:"name"`
 	RegistryDiscoveryType string  `yaml:"registryDiscoveryType" json:"registryDiscoveryType"`
 	TracingSamplingRate   float64 `yaml:"tracingSamplingRate" json:"tracingSamplingRate"`
@@ -122,11 +133,51 @@ type Mysql struct {
 }
 
 type Postgresql struct {
-	ConnMaxLifetime int    `yaml:"connMaxLifetime" json:"connMaxLifetime"`
-	Dsn             string `yaml:"dsn" json:"dsn"`
-	EnableLog       bool   `yaml:"enableLog" json:"enableLog"`
-	MaxIdleConns    int    `yaml:"maxIdleConns" json:"maxIdleConns"`
-	MaxOpenConns    int    `yaml:"maxOpenConns" json:"maxOpenConns"`
+	ConnMaxLifetime int      `yaml:"connMaxLifetime" json:"connMaxLifetime"`
+	Dsn             string   `yaml:"dsn" json:"dsn"`
+	EnableLog       bool     `yaml:"enableLog" json:"enableLog"`
+	MastersDsn      []string `yaml:"mastersDsn" json:"mastersDsn"`
+	MaxIdleConns    int      `yaml:"maxIdleConns" json:"maxIdleConns"`
+	MaxOpenConns    int      `yaml:"maxOpenConns" json:"maxOpenConns"`
+	SlavesDsn       []string `yaml:"slavesDsn" json:"slavesDsn"`
+}
+
+type Retention struct {
+	BatchInterval int              `yaml:"batchInterval" json:"batchInterval"`
+	BatchSize     int              `yaml:"batchSize" json:"batchSize"`
+	Enable        bool             `yaml:"enable" json:"enable"`
+	Interval      int              `yaml:"interval" json:"interval"`
+	Tables        []RetentionTable `yaml:"tables" json:"tables"`
+}
+
+type Scheduler struct {
+	BatchSize int  `yaml:"batchSize" json:"batchSize"`
+	Enable    bool `yaml:"enable" json:"enable"`
+	Interval  int  `yaml:"interval" json:"interval"`
+}
+
+type RetentionTable struct {
+	HardDelete bool   `yaml:"hardDelete" json:"hardDelete"`
+	MaxAge     int    `yaml:"maxAge" json:"maxAge"`
+	Name       string `yaml:"name" json:"name"`
+}
+
+type Dial struct {
+	StickyWindow int    `yaml:"stickyWindow" json:"stickyWindow"`
+	Strategy     string `yaml:"strategy" json:"strategy"`
+}
+
+type Recording struct {
+	Driver      string `yaml:"driver" json:"driver"`
+	MaxFileSize int    `yaml:"maxFileSize" json:"maxFileSize"`
+	StorageDir  string `yaml:"storageDir" json:"storageDir"`
+}
+
+type Release struct {
+	DownloadURL    string `yaml:"downloadURL" json:"downloadURL"`
+	MaxFileSize    int    `yaml:"maxFileSize" json:"maxFileSize"`
+	MinVersionCode int    `yaml:"minVersionCode" json:"minVersionCode"`
+	StorageDir     string `yaml:"storageDir" json:"storageDir"`
 }
 
 type Redis struct {
@@ -137,11 +188,11 @@ type Redis struct {
 }
 
 type Database struct {
-	Driver     string  `yaml:"driver" json:"driver"`
-	Mongodb    Mongodb `yaml:"mongodb" json:"mongodb"`
-	Mysql      Mysql   `yaml:"mysql" json:"mysql"`
-	Postgresql Mysql   `yaml:"postgresql" json:"postgresql"`
-	Sqlite     Sqlite  `yaml:"sqlite" json:"sqlite"`
+	Driver     string     `yaml:"driver" json:"driver"`
+	Mongodb    Mongodb    `yaml:"mongodb" json:"mongodb"`
+	Mysql      Mysql      `yaml:"mysql" json:"mysql"`
+	Postgresql Postgresql `yaml:"postgresql" json:"postgresql"`
+	Sqlite     Sqlite     `yaml:"sqlite" json:"sqlite"`
 }
 
 type Mongodb struct {
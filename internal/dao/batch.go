@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// createBatchSize the number of records inserted by one statement
+const createBatchSize = 50
+
+// CreateBatch create records in one transaction with CreateInBatches, if atomic is true a failure rolls back
+// all the records and is returned as the error, otherwise the failing records are skipped, the others are
+// committed and the error of each record is returned in the slice, which has the same length as records.
+func (r *Repository[T]) CreateBatch(ctx context.Context, records []*T, atomic bool) ([]error, error) {
+	return r.createBatch(ctx, records, atomic, nil)
+}
+
+// recordCreated handle a record after it is inserted, in the transaction that inserts it
+type recordCreated[T any] func(tx *gorm.DB, record *T) error
+
+// createBatch the same as CreateBatch, afterCreate is called for each record in the same transaction if it is not nil,
+// an error of afterCreate fails the record the same way as an error of its insert
+func (r *Repository[T]) createBatch(ctx context.Context, records []*T, atomic bool, afterCreate recordCreated[T]) ([]error, error) {
+	errs := make([]error, len(records))
+	if len(records) == 0 {
+		return errs, nil
+	}
+
+	// the ids assigned by a rolled back statement are not used, keep the original ones to restore them
+	ids := make([]uint64, len(records))
+	for i, record := range records {
+		ids[i] = model.GetID(record)
+	}
+
+	createAll := func(tx *gorm.DB) error {
+		err := tx.CreateInBatches(records, createBatchSize).Error
+		if err != nil || afterCreate == nil {
+			return err
+		}
+		for _, record := range records {
+			if err = afterCreate(tx, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	create := func(tx *gorm.DB, record *T) error {
+		err := tx.Create(record).Error
+		if err != nil || afterCreate == nil {
+			return err
+		}
+		return afterCreate(tx, record)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if atomic {
+			return createAll(tx)
+		}
+
+		// a failed statement aborts the transaction on some databases, so roll back to a savepoint
+		// and insert the records one by one to find out which ones fail
+		if err := tx.SavePoint("batch").Error; err != nil {
+			return err
+		}
+		if err := createAll(tx); err == nil {
+			return nil
+		}
+		if err := tx.RollbackTo("batch").Error; err != nil {
+			return err
+		}
+
+		for i, record := range records {
+			setID(record, ids[i])
+			if err := tx.SavePoint("record").Error; err != nil {
+				return err
+			}
+			if errs[i] = create(tx, record); errs[i] != nil {
+				setID(record, ids[i])
+				if err := tx.RollbackTo("record").Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i, record := range records {
+			setID(record, ids[i])
+		}
+		return errs, err
+	}
+
+	return errs, nil
+}
+
+// setID set the id of a table struct that embeds ggorm.Model
+func setID(table interface{}, id uint64) {
+	v := reflect.Indirect(reflect.ValueOf(table))
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	if field := v.FieldByName("ID"); field.IsValid() && field.CanSet() && field.Kind() == reflect.Uint64 {
+		field.SetUint(id)
+	}
+}
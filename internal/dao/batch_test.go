@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+
+	"caller/internal/model"
+)
+
+func TestRepository_CreateBatch(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewSmsDao(db, nil)
+
+	records := []*model.Sms{{MachineCode: "m1"}, {MachineCode: "m2"}}
+	errs, err := d.CreateBatch(ctx, records, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.NotZero(t, records[0].ID)
+	assert.NotZero(t, records[1].ID)
+
+	errs, err = d.CreateBatch(ctx, nil, true)
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	// a duplicate id makes the record fail
+	firstID := records[0].ID
+	duplicate := &model.Sms{MachineCode: "m4"}
+	duplicate.ID = firstID
+
+	// all or nothing
+	errs, err = d.CreateBatch(ctx, []*model.Sms{{MachineCode: "m3"}, duplicate}, true)
+	assert.Error(t, err)
+	assert.Len(t, errs, 2)
+	_, total, err := d.GetByColumns(ctx, &query.Params{Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	// partial success
+	records = []*model.Sms{{MachineCode: "m3"}, duplicate, {MachineCode: "m5"}}
+	errs, err = d.CreateBatch(ctx, records, false)
+	assert.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+	assert.NoError(t, errs[2])
+	assert.Equal(t, firstID, duplicate.ID) // the id set by the caller is kept
+	got, err := d.GetByID(ctx, records[2].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "m5", got.MachineCode)
+	_, total, err = d.GetByColumns(ctx, &query.Params{Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+}
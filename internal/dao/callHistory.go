@@ -2,16 +2,12 @@ package dao
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"encoding/json"
 	"time"
 
-	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
-	cacheBase "github.com/zhufuyi/sponge/pkg/cache"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
-	"github.com/zhufuyi/sponge/pkg/utils"
 
 	"caller/internal/cache"
 	"caller/internal/model"
@@ -22,8 +18,10 @@ var _ CallHistoryDao = (*callHistoryDao)(nil)
 // CallHistoryDao defining the dao interface
 type CallHistoryDao interface {
 	Create(ctx context.Context, table *model.CallHistory) error
-	DeleteByID(ctx context.Context, id uint64) error
+	CreateBatch(ctx context.Context, tables []*model.CallHistory, atomic bool) ([]error, error)
+	DeleteByID(ctx context.Context, id uint64, version uint64) error
 	UpdateByID(ctx context.Context, table *model.CallHistory) error
+	PatchByID(ctx context.Context, id uint64, version uint64, patch map[string]json.RawMessage) error
 	GetByID(ctx context.Context, id uint64) (*model.CallHistory, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.CallHistory, int64, error)
 
@@ -31,70 +29,48 @@ type CallHistoryDao interface {
 	GetByCondition(ctx context.Context, condition *query.Conditions) (*model.CallHistory, error)
 	GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.CallHistory, error)
 	GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.CallHistory, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.CallHistory, string, error)
+
+	GetDeletedByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.CallHistory, string, error)
+	RestoreByID(ctx context.Context, id uint64) error
+	PurgeByID(ctx context.Context, id uint64) error
+	DeleteCreatedBefore(ctx context.Context, before time.Time, limit int, hard bool) (int64, error)
+
+	Transit(ctx context.Context, id uint64, transition *CallTransition) (*model.CallHistory, error)
+	ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*model.CallHistory, error)
+	Reschedule(ctx context.Context, id uint64, at time.Time) (*model.CallHistory, error)
+
+	GetLastClientOfGroup(ctx context.Context, groupCallID uint64) (string, error)
+	GetLastCallTimes(ctx context.Context, machineCodes []string) (map[string]time.Time, error)
+	GetLastClientOfCallee(ctx context.Context, mobileNumber string, machineCodes []string, since time.Time) (string, error)
+	CreateByGroup(ctx context.Context, groupCallID uint64, choose func(history CallHistoryDao) (*model.CallHistory, error)) (*model.CallHistory, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.CallHistory) (uint64, error)
-	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64, version uint64) error
 	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.CallHistory) error
 }
 
 type callHistoryDao struct {
-	db    *gorm.DB
-	cache cache.CallHistoryCache // if nil, the cache is not used.
-	sfg   *singleflight.Group    // if cache is nil, the sfg is not used.
+	*Repository[model.CallHistory]
 }
 
 // NewCallHistoryDao creating the dao interface
 func NewCallHistoryDao(db *gorm.DB, xCache cache.CallHistoryCache) CallHistoryDao {
-	if xCache == nil {
-		return &callHistoryDao{db: db}
-	}
 	return &callHistoryDao{
-		db:    db,
-		cache: xCache,
-		sfg:   new(singleflight.Group),
-	}
-}
-
-func (d *callHistoryDao) deleteCache(ctx context.Context, id uint64) error {
-	if d.cache != nil {
-		return d.cache.Del(ctx, id)
+		Repository: NewRepository[model.CallHistory](db, xCache, cache.CallHistoryExpireTime, updateCallHistoryColumns, callHistoryReadOnlyColumns, callHistorySortColumns...),
 	}
-	return nil
 }
 
-// Create a record, insert the record and the id value is written back to the table
-func (d *callHistoryDao) Create(ctx context.Context, table *model.CallHistory) error {
-	return d.db.WithContext(ctx).Create(table).Error
-}
-
-// DeleteByID delete a record by id
-func (d *callHistoryDao) DeleteByID(ctx context.Context, id uint64) error {
-	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.CallHistory{}).Error
-	if err != nil {
-		return err
-	}
+// callHistoryReadOnlyColumns the columns that cannot be patched, the state of a call only moves through Transit
+// and a scheduled call is only moved by Reschedule
+var callHistoryReadOnlyColumns = []string{"state", "state_updated_at", "dispatched_at", "ringing_at", "answered_at",
+	"ended_at", "talk_duration", "failure_reason", "scheduled_at"}
 
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
-}
-
-// UpdateByID update a record by id
-func (d *callHistoryDao) UpdateByID(ctx context.Context, table *model.CallHistory) error {
-	err := d.updateDataByID(ctx, d.db, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
-}
-
-func (d *callHistoryDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.CallHistory) error {
-	if table.ID < 1 {
-		return errors.New("id cannot be 0")
-	}
+// callHistorySortColumns the columns besides id, created_at and updated_at that records can be paged by
+var callHistorySortColumns = []string{"request_machine_code", "client_machine_code", "mobile_number"}
 
+// updateCallHistoryColumns the columns of a record to update, empty values are not updated
+func updateCallHistoryColumns(table *model.CallHistory) map[string]interface{} {
 	update := map[string]interface{}{}
 
 	if table.RequestMachineCode != "" {
@@ -107,286 +83,13 @@ func (d *callHistoryDao) updateDataByID(ctx context.Context, db *gorm.DB, table
 		update["mobile_number"] = table.MobileNumber
 	}
 	if table.Instruction != "" {
+		// the payload belongs to the instruction, it is replaced with it
 		update["instruction"] = table.Instruction
+		update["payload"] = table.Payload
 	}
-
-	return db.WithContext(ctx).Model(table).Updates(update).Error
-}
-
-// GetByID get a record by id
-func (d *callHistoryDao) GetByID(ctx context.Context, id uint64) (*model.CallHistory, error) {
-	// no cache
-	if d.cache == nil {
-		record := &model.CallHistory{}
-		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
-		return record, err
-	}
-
-	// get from cache or database
-	record, err := d.cache.Get(ctx, id)
-	if err == nil {
-		return record, nil
-	}
-
-	if errors.Is(err, model.ErrCacheNotFound) {
-		// for the same id, prevent high concurrent simultaneous access to database
-		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
-			table := &model.CallHistory{}
-			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
-			if err != nil {
-				// if data is empty, set not found cache to prevent cache penetration, default expiration time 10 minutes
-				if errors.Is(err, model.ErrRecordNotFound) {
-					err = d.cache.SetCacheWithNotFound(ctx, id)
-					if err != nil {
-						return nil, err
-					}
-					return nil, model.ErrRecordNotFound
-				}
-				return nil, err
-			}
-			// set cache
-			err = d.cache.Set(ctx, id, table, cache.CallHistoryExpireTime)
-			if err != nil {
-				return nil, fmt.Errorf("cache.Set error: %v, id=%d", err, id)
-			}
-			return table, nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		table, ok := val.(*model.CallHistory)
-		if !ok {
-			return nil, model.ErrRecordNotFound
-		}
-		return table, nil
-	} else if errors.Is(err, cacheBase.ErrPlaceholder) {
-		return nil, model.ErrRecordNotFound
-	}
-
-	// fail fast, if cache error return, don't request to db
-	return nil, err
-}
-
-// GetByColumns get paging records by column information,
-// Note: query performance degrades when table rows are very large because of the use of offset.
-//
-// params includes paging parameters and query parameters
-// paging parameters (required):
-//
-//	page: page number, starting from 0
-//	size: lines per page
-//	sort: sort fields, default is id backwards, you can add - sign before the field to indicate reverse order, no - sign to indicate ascending order, multiple fields separated by comma
-//
-// query parameters (not required):
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: search for a male over 20 years of age
-//
-//	params = &query.Params{
-//	    Page: 0,
-//	    Size: 20,
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Exp: ">",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *callHistoryDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.CallHistory, int64, error) {
-	queryStr, args, err := params.ConvertToGormConditions()
-	if err != nil {
-		return nil, 0, errors.New("query params error: " + err.Error())
-	}
-
-	var total int64
-	if params.Sort != "ignore count" { // determine if count is required
-		err = d.db.WithContext(ctx).Model(&model.CallHistory{}).Select([]string{"id"}).Where(queryStr, args...).Count(&total).Error
-		if err != nil {
-			return nil, 0, err
-		}
-		if total == 0 {
-			return nil, total, nil
-		}
-	}
-
-	records := []*model.CallHistory{}
-	order, limit, offset := params.ConvertToPage()
-	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return records, total, err
-}
-
-// DeleteByIDs delete records by batch id
-func (d *callHistoryDao) DeleteByIDs(ctx context.Context, ids []uint64) error {
-	err := d.db.WithContext(ctx).Where("id IN (?)", ids).Delete(&model.CallHistory{}).Error
-	if err != nil {
-		return err
+	if table.SimID != 0 {
+		update["sim_id"] = table.SimID
 	}
 
-	// delete cache
-	for _, id := range ids {
-		_ = d.deleteCache(ctx, id)
-	}
-
-	return nil
-}
-
-// GetByCondition get a record by condition
-// query conditions:
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: find a male aged 20
-//
-//	condition = &query.Conditions{
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *callHistoryDao) GetByCondition(ctx context.Context, c *query.Conditions) (*model.CallHistory, error) {
-	queryStr, args, err := c.ConvertToGorm()
-	if err != nil {
-		return nil, err
-	}
-
-	table := &model.CallHistory{}
-	err = d.db.WithContext(ctx).Where(queryStr, args...).First(table).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return table, nil
-}
-
-// GetByIDs get records by batch id
-func (d *callHistoryDao) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.CallHistory, error) {
-	// no cache
-	if d.cache == nil {
-		var records []*model.CallHistory
-		err := d.db.WithContext(ctx).Where("id IN (?)", ids).Find(&records).Error
-		if err != nil {
-			return nil, err
-		}
-		itemMap := make(map[uint64]*model.CallHistory)
-		for _, record := range records {
-			itemMap[record.ID] = record
-		}
-		return itemMap, nil
-	}
-
-	// get form cache or database
-	itemMap, err := d.cache.MultiGet(ctx, ids)
-	if err != nil {
-		return nil, err
-	}
-
-	var missedIDs []uint64
-	for _, id := range ids {
-		_, ok := itemMap[id]
-		if !ok {
-			missedIDs = append(missedIDs, id)
-			continue
-		}
-	}
-
-	// get missed data
-	if len(missedIDs) > 0 {
-		// find the id of an active placeholder, i.e. an id that does not exist in database
-		var realMissedIDs []uint64
-		for _, id := range missedIDs {
-			_, err = d.cache.Get(ctx, id)
-			if errors.Is(err, cacheBase.ErrPlaceholder) {
-				continue
-			}
-			realMissedIDs = append(realMissedIDs, id)
-		}
-
-		if len(realMissedIDs) > 0 {
-			var missedData []*model.CallHistory
-			err = d.db.WithContext(ctx).Where("id IN (?)", realMissedIDs).Find(&missedData).Error
-			if err != nil {
-				return nil, err
-			}
-
-			if len(missedData) > 0 {
-				for _, data := range missedData {
-					itemMap[data.ID] = data
-				}
-				err = d.cache.MultiSet(ctx, missedData, cache.CallHistoryExpireTime)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				for _, id := range realMissedIDs {
-					_ = d.cache.SetCacheWithNotFound(ctx, id)
-				}
-			}
-		}
-	}
-
-	return itemMap, nil
-}
-
-// GetByLastID get paging records by last id and limit
-func (d *callHistoryDao) GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.CallHistory, error) {
-	page := query.NewPage(0, limit, sort)
-
-	records := []*model.CallHistory{}
-	err := d.db.WithContext(ctx).Order(page.Sort()).Limit(page.Size()).Where("id < ?", lastID).Find(&records).Error
-	if err != nil {
-		return nil, err
-	}
-	return records, nil
-}
-
-// CreateByTx create a record in the database using the provided transaction
-func (d *callHistoryDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.CallHistory) (uint64, error) {
-	err := tx.WithContext(ctx).Create(table).Error
-	return table.ID, err
-}
-
-// DeleteByTx delete a record by id in the database using the provided transaction
-func (d *callHistoryDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
-	update := map[string]interface{}{
-		"deleted_at": time.Now(),
-	}
-	err := tx.WithContext(ctx).Model(&model.CallHistory{}).Where("id = ?", id).Updates(update).Error
-	if err != nil {
-		return err
-	}
-
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
-}
-
-// UpdateByTx update a record by id in the database using the provided transaction
-func (d *callHistoryDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.CallHistory) error {
-	err := d.updateDataByID(ctx, tx, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
+	return update
 }
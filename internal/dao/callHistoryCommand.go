@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// Create create a call and queue its instruction for the client device in one transaction,
+// the instruction of a scheduled call is queued when the call is released.
+func (d *callHistoryDao) Create(ctx context.Context, table *model.CallHistory) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createCall(tx, table)
+	})
+}
+
+// CreateBatch the same as Repository.CreateBatch, the instruction of each call is queued with the call,
+// a call whose instruction cannot be queued fails.
+func (d *callHistoryDao) CreateBatch(ctx context.Context, tables []*model.CallHistory, atomic bool) ([]error, error) {
+	return d.createBatch(ctx, tables, atomic, queueCreatedCall)
+}
+
+// CreateByTx create a call and queue its instruction in the transaction tx
+func (d *callHistoryDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.CallHistory) (uint64, error) {
+	err := createCall(tx.WithContext(ctx), table)
+	return table.ID, err
+}
+
+func createCall(tx *gorm.DB, call *model.CallHistory) error {
+	err := tx.Create(call).Error
+	if err != nil {
+		return err
+	}
+	return queueCreatedCall(tx, call)
+}
+
+// queueCreatedCall queue the instruction of a call that was just created, unless the call is scheduled
+func queueCreatedCall(tx *gorm.DB, call *model.CallHistory) error {
+	if call.State == model.CallStateScheduled {
+		return nil
+	}
+	return queueCommand(tx, call)
+}
+
+// queueCommand queue the instruction of a call for the client device, nothing is queued if the instruction or
+// the client machine code is empty. return model.ErrClientsDisabled or model.ErrClientsQuarantined if the client
+// is not active, the instruction would never be delivered.
+func queueCommand(tx *gorm.DB, call *model.CallHistory) error {
+	if call.Instruction == "" || call.ClientMachineCode == "" {
+		return nil
+	}
+
+	var statuses []string
+	err := tx.Model(&model.Clients{}).
+		Where("machine_code = ? AND status <> ?", call.ClientMachineCode, model.ClientsStatusActive).
+		Limit(1).Pluck("status", &statuses).Error
+	if err != nil {
+		return err
+	}
+	if len(statuses) > 0 {
+		if statuses[0] == model.ClientsStatusQuarantined {
+			return model.ErrClientsQuarantined
+		}
+		return model.ErrClientsDisabled
+	}
+
+	return tx.Create(&model.DeviceCommand{
+		MachineCode:   call.ClientMachineCode,
+		CallHistoryID: call.ID,
+		Instruction:   call.Instruction,
+		Payload:       call.Payload,
+		MobileNumber:  call.MobileNumber,
+		State:         model.DeviceCommandPending,
+		ExpiresAt:     time.Now().Add(model.DeviceCommandTTL),
+	}).Error
+}
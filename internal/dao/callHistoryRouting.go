@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// CreateByGroup create the call that choose returns for a group, the dials of the same group are serialized
+// by locking the group, so that choose reads the calls created by the dials before it. choose reads the
+// call history through the dao it is given, which is bound to the transaction. the instruction of the call
+// is queued in the same transaction.
+func (d *callHistoryDao) CreateByGroup(ctx context.Context, groupCallID uint64,
+	choose func(history CallHistoryDao) (*model.CallHistory, error)) (*model.CallHistory, error) {
+	var call *model.CallHistory
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the write locks the row of the group on every database, sqlite locks the whole database for it,
+		// the affected rows are not checked, mysql does not count a row whose value is unchanged
+		err := tx.Model(&model.GroupCall{}).Where("id = ?", groupCallID).UpdateColumn("id", gorm.Expr("id")).Error
+		if err != nil {
+			return err
+		}
+		var ids []uint64
+		err = tx.Model(&model.GroupCall{}).Where("id = ?", groupCallID).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return model.ErrRecordNotFound
+		}
+
+		repository := *d.Repository
+		repository.db = tx
+		call, err = choose(&callHistoryDao{Repository: &repository})
+		if err != nil {
+			return err
+		}
+		return createCall(tx, call)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// GetLastClientOfGroup get the machine code of the client of the last call dialed by the group, empty if there is none
+func (d *callHistoryDao) GetLastClientOfGroup(ctx context.Context, groupCallID uint64) (string, error) {
+	var machineCodes []string
+	err := d.db.WithContext(ctx).Model(&model.CallHistory{}).
+		Where("group_call_id = ?", groupCallID).
+		Order("id DESC").Limit(1).Pluck("client_machine_code", &machineCodes).Error
+	if err != nil || len(machineCodes) == 0 {
+		return "", err
+	}
+	return machineCodes[0], nil
+}
+
+// GetLastCallTimes get the time of the last call of each client, the clients that never called are not in the map
+func (d *callHistoryDao) GetLastCallTimes(ctx context.Context, machineCodes []string) (map[string]time.Time, error) {
+	itemMap := make(map[string]time.Time)
+	if len(machineCodes) == 0 {
+		return itemMap, nil
+	}
+
+	var rows []struct {
+		ClientMachineCode string
+		ID                uint64
+	}
+	// the last call is found by its id, the ids grow with the time of the calls, MAX of a time is a string in sqlite
+	err := d.db.WithContext(ctx).Model(&model.CallHistory{}).
+		Select("client_machine_code, MAX(id) AS id").
+		Where("client_machine_code IN (?)", machineCodes).
+		Group("client_machine_code").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return itemMap, nil
+	}
+
+	ids := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	records := []*model.CallHistory{}
+	err = d.db.WithContext(ctx).Select("id, client_machine_code, created_at").Where("id IN (?)", ids).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		itemMap[record.ClientMachineCode] = record.CreatedAt
+	}
+	return itemMap, nil
+}
+
+// GetLastClientOfCallee get the machine code of the client that last called the mobile number since a time,
+// only the clients in machineCodes are considered, empty if none of them did
+func (d *callHistoryDao) GetLastClientOfCallee(ctx context.Context, mobileNumber string, machineCodes []string, since time.Time) (string, error) {
+	if len(machineCodes) == 0 {
+		return "", nil
+	}
+
+	var found []string
+	err := d.db.WithContext(ctx).Model(&model.CallHistory{}).
+		Where("mobile_number = ? AND client_machine_code IN (?) AND created_at >= ?", mobileNumber, machineCodes, since).
+		Order("id DESC").Limit(1).Pluck("client_machine_code", &found).Error
+	if err != nil || len(found) == 0 {
+		return "", err
+	}
+	return found[0], nil
+}
@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_callHistoryDao_routing(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallHistoryDao(db, nil)
+
+	machineCode, err := d.GetLastClientOfGroup(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "", machineCode)
+
+	calls := []*model.CallHistory{
+		{ClientMachineCode: "m1", MobileNumber: "13900000001", GroupCallID: 1},
+		{ClientMachineCode: "m2", MobileNumber: "13900000002", GroupCallID: 1},
+		{ClientMachineCode: "m1", MobileNumber: "13900000002"},
+		{ClientMachineCode: "m3", MobileNumber: "13900000001", GroupCallID: 2},
+	}
+	for i, call := range calls {
+		assert.NoError(t, d.Create(ctx, call))
+		createdAt := time.Date(2024, 1, 1, i, 0, 0, 0, time.UTC)
+		assert.NoError(t, db.Model(&model.CallHistory{}).Where("id = ?", call.ID).Update("created_at", createdAt).Error)
+	}
+
+	// the last call of a group
+	machineCode, err = d.GetLastClientOfGroup(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "m2", machineCode)
+
+	// the last call of each client, whichever group dialed it
+	itemMap, err := d.GetLastCallTimes(ctx, []string{"m1", "m2", "m4"})
+	assert.NoError(t, err)
+	assert.Len(t, itemMap, 2)
+	assert.Equal(t, 2, itemMap["m1"].UTC().Hour())
+	assert.Equal(t, 1, itemMap["m2"].UTC().Hour())
+	itemMap, err = d.GetLastCallTimes(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, itemMap, 0)
+
+	// the last client of a callee among the candidates and within the window
+	machineCode, err = d.GetLastClientOfCallee(ctx, "13900000001", []string{"m1", "m2"}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", machineCode)
+	machineCode, err = d.GetLastClientOfCallee(ctx, "13900000002", []string{"m1", "m2"}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", machineCode)
+	machineCode, err = d.GetLastClientOfCallee(ctx, "13900000001", []string{"m1", "m2"}, time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "", machineCode)
+}
+
+func Test_callHistoryDao_CreateByGroup(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallHistoryDao(db, nil)
+	assert.NoError(t, NewGroupCallDao(db, nil).Create(ctx, &model.GroupCall{GroupNumber: "1001"}))
+
+	// every dial of the group reads the client of the dial before it, the clients alternate
+	chooseNext := func(history CallHistoryDao) (*model.CallHistory, error) {
+		last, err := history.GetLastClientOfGroup(ctx, 1)
+		if err != nil {
+			return nil, err
+		}
+		machineCode := "m1"
+		if last == "m1" {
+			machineCode = "m2"
+		}
+		return &model.CallHistory{ClientMachineCode: machineCode, MobileNumber: "13900000001", GroupCallID: 1}, nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call, err := d.CreateByGroup(ctx, 1, chooseNext)
+			assert.NoError(t, err)
+			assert.NotZero(t, call.ID)
+		}()
+	}
+	wg.Wait()
+	var counts []struct {
+		ClientMachineCode string
+		Total             int
+	}
+	err := db.Model(&model.CallHistory{}).Select("client_machine_code, COUNT(*) AS total").
+		Group("client_machine_code").Order("client_machine_code").Scan(&counts).Error
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	for _, count := range counts {
+		assert.Equal(t, 5, count.Total, count.ClientMachineCode)
+	}
+
+	// nothing is created when no client is chosen or the group does not exist
+	errChoose := errors.New("no client")
+	_, err = d.CreateByGroup(ctx, 1, func(CallHistoryDao) (*model.CallHistory, error) { return nil, errChoose })
+	assert.ErrorIs(t, err, errChoose)
+	_, err = d.CreateByGroup(ctx, 2, chooseNext)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	var total int64
+	assert.NoError(t, db.Model(&model.CallHistory{}).Count(&total).Error)
+	assert.Equal(t, int64(10), total)
+}
+
+func Test_groupCallDao_GetByGroupNumber(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewGroupCallDao(db, nil)
+
+	for _, groupNumber := range []string{"1001", "1002", "1001"} {
+		assert.NoError(t, d.Create(ctx, &model.GroupCall{GroupNumber: groupNumber}))
+	}
+
+	record, err := d.GetByGroupNumber(ctx, "1001")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), record.ID)
+	assert.NoError(t, d.DeleteByID(ctx, 1, 0))
+	record, err = d.GetByGroupNumber(ctx, "1001")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), record.ID)
+	_, err = d.GetByGroupNumber(ctx, "1003")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
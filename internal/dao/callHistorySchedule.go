@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ErrCallNotScheduled the call is not scheduled, it has been requested or cancelled
+var ErrCallNotScheduled = errors.New("call is not scheduled")
+
+// ReleaseDue request the scheduled calls that are due at now, at most limit of them, the earliest first, and return
+// the calls released. a call is requested and its instruction is queued in one transaction that only changes the call
+// if it is still scheduled, so when several replicas release the calls at the same time each call is requested once.
+// the call of a client that is disabled or quarantined fails, its instruction would never be delivered.
+func (d *callHistoryDao) ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*model.CallHistory, error) {
+	calls := []*model.CallHistory{}
+	err := d.db.WithContext(ctx).Where("state = ? AND scheduled_at <= ?", model.CallStateScheduled, now).
+		Order("scheduled_at ASC, id ASC").Limit(limit).Find(&calls).Error
+	if err != nil {
+		return nil, err
+	}
+
+	released := make([]*model.CallHistory, 0, len(calls))
+	for _, call := range calls {
+		ok, err := d.release(ctx, call, now)
+		if err != nil {
+			return released, err
+		}
+		if ok {
+			released = append(released, call)
+		}
+	}
+	return released, nil
+}
+
+// release request a scheduled call, return false if it was released by another replica or changed since it was read,
+// a call that was changed and is still due is released by the next run.
+func (d *callHistoryDao) release(ctx context.Context, call *model.CallHistory, now time.Time) (bool, error) {
+	released := false
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.CallHistory{}).
+			Where("id = ? AND state = ? AND "+versionColumn+" = ?", call.ID, model.CallStateScheduled, call.Version).
+			Updates(map[string]interface{}{
+				"state":            model.CallStateRequested,
+				"state_updated_at": now,
+				versionColumn:      gorm.Expr(versionColumn + " + 1"),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		released = true
+		err := queueCommand(tx, call)
+		if errors.Is(err, model.ErrClientsDisabled) || errors.Is(err, model.ErrClientsQuarantined) {
+			// the instruction would never be delivered, the call fails instead of waiting for the client
+			call.State = model.CallStateFailed
+			call.EndedAt = &now
+			call.FailureReason = err.Error()
+			return tx.Model(&model.CallHistory{}).Where("id = ?", call.ID).Updates(map[string]interface{}{
+				"state":          call.State,
+				"ended_at":       now,
+				"failure_reason": call.FailureReason,
+			}).Error
+		}
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if !released {
+		return false, nil
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, call.ID)
+
+	if call.State == model.CallStateScheduled {
+		call.State = model.CallStateRequested
+	}
+	call.StateUpdatedAt = &now
+	call.Version++
+	return true, nil
+}
+
+// Reschedule move a scheduled call to another time and return the call after the change, a time that is not in the
+// future requests the call at the next run of the scheduler. return ErrCallNotScheduled if the call is not scheduled.
+func (d *callHistoryDao) Reschedule(ctx context.Context, id uint64, at time.Time) (*model.CallHistory, error) {
+	result := d.db.WithContext(ctx).Model(&model.CallHistory{}).
+		Where("id = ? AND state = ?", id, model.CallStateScheduled).
+		Updates(map[string]interface{}{
+			"scheduled_at": at,
+			versionColumn:  gorm.Expr(versionColumn + " + 1"),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	call := &model.CallHistory{}
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(call).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCallNotScheduled
+	}
+	return call, nil
+}
@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_callHistoryDao_ReleaseDue(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallHistoryDao(db, nil)
+	commandDao := NewDeviceCommandDao(db)
+
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-time.Minute), now.Add(-2 * time.Minute), now.Add(time.Hour)} {
+		scheduledAt := at
+		assert.NoError(t, d.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000",
+			Instruction: "call", State: model.CallStateScheduled, ScheduledAt: &scheduledAt}))
+	}
+
+	// the instruction of a scheduled call is not queued when it is created
+	commands, err := commandDao.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 0)
+
+	// the due calls are requested, the earliest first
+	calls, err := d.ReleaseDue(ctx, now, 1)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	assert.Equal(t, uint64(2), calls[0].ID)
+	assert.Equal(t, model.CallStateRequested, calls[0].State)
+	calls, err = d.ReleaseDue(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	assert.Equal(t, uint64(1), calls[0].ID)
+	commands, err = commandDao.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 2)
+
+	// a released call is not released again
+	calls, err = d.ReleaseDue(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 0)
+
+	// a call changed since it was read is left to the next run
+	record, err := d.GetByID(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateScheduled, record.State)
+	record.Version++
+	ok, err := d.(*callHistoryDao).release(ctx, record, now)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// reschedule
+	record, err = d.Reschedule(ctx, 3, now.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), record.Version)
+	calls, err = d.ReleaseDue(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	_, err = d.Reschedule(ctx, 3, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrCallNotScheduled)
+	_, err = d.Reschedule(ctx, 100, now.Add(time.Hour))
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// the call of a client that is not active fails when it is due
+	assert.NoError(t, NewClientsDao(db, nil).Create(ctx, &model.Clients{MachineCode: "m2", Status: model.ClientsStatusDisabled}))
+	scheduledAt := now.Add(-time.Second)
+	assert.NoError(t, d.Create(ctx, &model.CallHistory{ClientMachineCode: "m2", MobileNumber: "13800000000",
+		Instruction: "call", State: model.CallStateScheduled, ScheduledAt: &scheduledAt}))
+	calls, err = d.ReleaseDue(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	assert.Equal(t, model.CallStateFailed, calls[0].State)
+	record, err = d.GetByID(ctx, calls[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateFailed, record.State)
+	assert.Equal(t, model.ErrClientsDisabled.Error(), record.FailureReason)
+	assert.NotNil(t, record.EndedAt)
+	commands, err = commandDao.Deliver(ctx, "m2", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 0)
+}
@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ErrIllegalCallTransition the call cannot move from its state to the new state, e.g. ended before dispatched
+var ErrIllegalCallTransition = errors.New("illegal call state transition")
+
+// CallTransition a change of the state of a call
+type CallTransition struct {
+	State         string
+	At            time.Time // time of the change
+	TalkDuration  int       // seconds, if 0 it is the time from answered to the final state
+	FailureReason string    // only kept for failed and busy
+}
+
+// columns the columns of a call to update for the transition, call is the call before the change
+func (t *CallTransition) columns(call *model.CallHistory) map[string]interface{} {
+	columns := map[string]interface{}{
+		"state":            t.State,
+		"state_updated_at": t.At,
+	}
+
+	switch t.State {
+	case model.CallStateDispatched:
+		columns["dispatched_at"] = t.At
+	case model.CallStateRinging:
+		columns["ringing_at"] = t.At
+	case model.CallStateAnswered:
+		columns["answered_at"] = t.At
+	default:
+		columns["ended_at"] = t.At
+		if call.AnsweredAt != nil {
+			talkDuration := t.TalkDuration
+			if talkDuration == 0 && t.At.After(*call.AnsweredAt) {
+				talkDuration = int(t.At.Sub(*call.AnsweredAt) / time.Second)
+			}
+			columns["talk_duration"] = talkDuration
+		}
+		if t.State != model.CallStateEnded {
+			columns["failure_reason"] = t.FailureReason
+		}
+	}
+
+	return columns
+}
+
+// Transit move a call to the state of the transition and return the call after the change, reporting the state
+// of the call again changes nothing. return ErrIllegalCallTransition if the call cannot move to the state.
+func (d *callHistoryDao) Transit(ctx context.Context, id uint64, transition *CallTransition) (*model.CallHistory, error) {
+	// the states only move forward, so the loop ends when the call reaches the state or a state it cannot leave for it
+	for {
+		call := &model.CallHistory{}
+		err := d.db.WithContext(ctx).Where("id = ?", id).First(call).Error
+		if err != nil {
+			return nil, err
+		}
+		if call.State == transition.State {
+			return call, nil
+		}
+		if !model.CanTransitCallState(call.State, transition.State) {
+			return nil, fmt.Errorf("%w: %s to %s", ErrIllegalCallTransition, call.State, transition.State)
+		}
+
+		columns := transition.columns(call)
+		columns[versionColumn] = gorm.Expr(versionColumn + " + 1")
+		// the state in the condition makes the update fail if another transition came first, it is then checked again
+		result := d.db.WithContext(ctx).Model(&model.CallHistory{}).
+			Where("id = ? AND state = ?", id, call.State).Updates(columns)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		// delete cache
+		_ = d.deleteCache(ctx, id)
+
+		if result.RowsAffected == 1 {
+			call = &model.CallHistory{}
+			err = d.db.WithContext(ctx).Where("id = ?", id).First(call).Error
+			if err != nil {
+				return nil, err
+			}
+			return call, nil
+		}
+	}
+}
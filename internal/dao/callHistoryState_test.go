@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_callHistoryDao_Transit(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallHistoryDao(db, nil)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, d.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000"}))
+	}
+	record, err := d.GetByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateRequested, record.State)
+
+	// a call that is answered and ended
+	now := time.Now()
+	record, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateDispatched, At: now})
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateDispatched, record.State)
+	assert.NotNil(t, record.DispatchedAt)
+	assert.Equal(t, uint64(2), record.Version)
+	record, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateDispatched, At: now.Add(time.Second)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), record.Version)
+	_, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateRinging, At: now.Add(time.Second)})
+	assert.NoError(t, err)
+	_, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateAnswered, At: now.Add(2 * time.Second)})
+	assert.NoError(t, err)
+	record, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateEnded, At: now.Add(32 * time.Second), FailureReason: "ignored"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateEnded, record.State)
+	assert.Equal(t, 30, record.TalkDuration)
+	assert.Equal(t, "", record.FailureReason)
+	assert.NotNil(t, record.RingingAt)
+	assert.NotNil(t, record.AnsweredAt)
+	assert.NotNil(t, record.EndedAt)
+
+	// a final state cannot be left
+	_, err = d.Transit(ctx, 1, &CallTransition{State: model.CallStateFailed, At: now})
+	assert.ErrorIs(t, err, ErrIllegalCallTransition)
+
+	// a call cannot end before it is dispatched
+	_, err = d.Transit(ctx, 2, &CallTransition{State: model.CallStateEnded, At: now})
+	assert.ErrorIs(t, err, ErrIllegalCallTransition)
+
+	// a busy call is not answered, the talk duration reported by the device is kept only for answered calls
+	_, err = d.Transit(ctx, 3, &CallTransition{State: model.CallStateDispatched, At: now})
+	assert.NoError(t, err)
+	record, err = d.Transit(ctx, 3, &CallTransition{State: model.CallStateBusy, At: now, TalkDuration: 10, FailureReason: "line busy"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, record.TalkDuration)
+	assert.Equal(t, "line busy", record.FailureReason)
+
+	_, err = d.Transit(ctx, 100, &CallTransition{State: model.CallStateDispatched, At: now})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func Test_CanTransitCallState(t *testing.T) {
+	assert.True(t, model.CanTransitCallState(model.CallStateRequested, model.CallStateDispatched))
+	assert.True(t, model.CanTransitCallState(model.CallStateDispatched, model.CallStateAnswered))
+	assert.False(t, model.CanTransitCallState(model.CallStateRequested, model.CallStateEnded))
+	assert.False(t, model.CanTransitCallState(model.CallStateAnswered, model.CallStateBusy))
+	assert.False(t, model.CanTransitCallState(model.CallStateEnded, model.CallStateFailed))
+	assert.True(t, model.IsFinalCallState(model.CallStateBusy))
+	assert.False(t, model.IsFinalCallState(model.CallStateAnswered))
+}
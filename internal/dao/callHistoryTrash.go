@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"context"
+	"time"
+)
+
+// PurgeByID permanently delete a call in the trash with its recording and the audio of the recording,
+// return model.ErrRecordNotFound if the call is not in the trash
+func (d *callHistoryDao) PurgeByID(ctx context.Context, id uint64) error {
+	return d.purgeByID(ctx, id, deleteRecordingsOfCalls)
+}
+
+// DeleteCreatedBefore the same as Repository.DeleteCreatedBefore, hard deletes remove the recordings
+// of the calls and their audio too, the recordings of the soft deleted calls are kept until the calls are purged
+func (d *callHistoryDao) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int, hard bool) (int64, error) {
+	if !hard {
+		return d.deleteCreatedBefore(ctx, before, limit, hard, nil)
+	}
+	return d.deleteCreatedBefore(ctx, before, limit, hard, deleteRecordingsOfCalls)
+}
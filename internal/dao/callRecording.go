@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+	"caller/internal/storage"
+)
+
+// recordingStorage the storage of the audio of the recordings, the audio is deleted with its call
+var recordingStorage storage.Storage = storage.NewLocal("recordings")
+
+// SetRecordingStorage set the storage of the audio of the recordings, every replica must use the same storage
+func SetRecordingStorage(s storage.Storage) {
+	recordingStorage = s
+}
+
+// GetRecordingStorage get the storage of the audio of the recordings
+func GetRecordingStorage() storage.Storage {
+	return recordingStorage
+}
+
+var _ CallRecordingDao = (*callRecordingDao)(nil)
+
+// CallRecordingDao defining the dao interface
+type CallRecordingDao interface {
+	GetByID(ctx context.Context, id uint64) (*model.CallRecording, error)
+	GetByCallHistoryID(ctx context.Context, callHistoryID uint64) (*model.CallRecording, error)
+	Start(ctx context.Context, recording *model.CallRecording) (*model.CallRecording, error)
+	Complete(ctx context.Context, id uint64, version uint64) (*model.CallRecording, error)
+}
+
+type callRecordingDao struct {
+	*Repository[model.CallRecording]
+}
+
+// NewCallRecordingDao creating the dao interface, the recordings are not cached
+func NewCallRecordingDao(db *gorm.DB) CallRecordingDao {
+	return &callRecordingDao{
+		Repository: NewRepository[model.CallRecording](db, nil, 0, nil, nil),
+	}
+}
+
+// GetByCallHistoryID get the recording of a call
+func (d *callRecordingDao) GetByCallHistoryID(ctx context.Context, callHistoryID uint64) (*model.CallRecording, error) {
+	record := &model.CallRecording{}
+	err := d.db.WithContext(ctx).Where("call_history_id = ?", callHistoryID).First(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Start create the recording of a call to upload, or replace the recording of the call that has not been uploaded yet,
+// the version of the recording to replace must be set. return the recording after the change.
+func (d *callRecordingDao) Start(ctx context.Context, recording *model.CallRecording) (*model.CallRecording, error) {
+	recording.Status = model.CallRecordingStatusUploading
+	recording.UploadedAt = nil
+	if recording.ID == 0 {
+		err := d.Create(ctx, recording)
+		if err != nil {
+			return nil, err
+		}
+		return d.GetByID(ctx, recording.ID)
+	}
+
+	err := d.updateColumnsByID(ctx, d.db, recording.ID, recording.Version, map[string]interface{}{
+		"file_key":    recording.FileKey,
+		"codec":       recording.Codec,
+		"duration":    recording.Duration,
+		"size":        recording.Size,
+		"checksum":    recording.Checksum,
+		"status":      recording.Status,
+		"uploaded_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.GetByID(ctx, recording.ID)
+}
+
+// Complete mark the upload of a recording as complete, return the recording after the change
+func (d *callRecordingDao) Complete(ctx context.Context, id uint64, version uint64) (*model.CallRecording, error) {
+	err := d.updateColumnsByID(ctx, d.db, id, version, map[string]interface{}{
+		"status":      model.CallRecordingStatusReady,
+		"uploaded_at": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.GetByID(ctx, id)
+}
+
+// deleteRecordingsOfCalls permanently delete the recordings of the calls and their audio in the transaction
+// that deletes the calls, the transaction is rolled back if the audio cannot be deleted, so that it is not left behind
+func deleteRecordingsOfCalls(tx *gorm.DB, callHistoryIDs []uint64) error {
+	recordings := []*model.CallRecording{}
+	err := tx.Unscoped().Select("id, file_key").Where("call_history_id IN (?)", callHistoryIDs).Find(&recordings).Error
+	if err != nil || len(recordings) == 0 {
+		return err
+	}
+
+	ids := make([]uint64, 0, len(recordings))
+	for _, recording := range recordings {
+		ids = append(ids, recording.ID)
+	}
+	err = tx.Unscoped().Where("id IN (?)", ids).Delete(&model.CallRecording{}).Error
+	if err != nil {
+		return err
+	}
+	for _, recording := range recordings {
+		err = recordingStorage.Delete(tx.Statement.Context, recording.FileKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
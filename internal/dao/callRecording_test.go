@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+	"caller/internal/storage"
+)
+
+func Test_callRecordingDao(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallRecordingDao(db)
+
+	_, err := d.GetByCallHistoryID(ctx, 1)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// start an upload
+	recording, err := d.Start(ctx, &model.CallRecording{CallHistoryID: 1, FileKey: "1/a", Codec: "amr", Duration: 1000, Size: 10, Checksum: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallRecordingStatusUploading, recording.Status)
+	assert.Equal(t, uint64(1), recording.Version)
+
+	// replace the audio
+	recording.FileKey, recording.Size, recording.Checksum = "1/b", 20, "b"
+	recording, err = d.Start(ctx, recording)
+	assert.NoError(t, err)
+	assert.Equal(t, "1/b", recording.FileKey)
+	assert.Equal(t, int64(20), recording.Size)
+	assert.Equal(t, uint64(2), recording.Version)
+	recording.Version = 1
+	_, err = d.Start(ctx, recording)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	// complete the upload
+	_, err = d.Complete(ctx, recording.ID, 1)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	recording, err = d.Complete(ctx, recording.ID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallRecordingStatusReady, recording.Status)
+	assert.NotNil(t, recording.UploadedAt)
+	recording, err = d.GetByCallHistoryID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", recording.Checksum)
+	_, err = d.Complete(ctx, 100, 0)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func Test_callHistoryDao_deleteRecordings(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewCallHistoryDao(db, nil)
+	recordingDao := NewCallRecordingDao(db)
+	defaultStorage := GetRecordingStorage()
+	defer SetRecordingStorage(defaultStorage)
+	objects := storage.NewLocal(t.TempDir())
+	SetRecordingStorage(objects)
+
+	old := time.Now().Add(-48 * time.Hour)
+	for i := 1; i <= 3; i++ {
+		call := &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000"}
+		call.CreatedAt = old
+		assert.NoError(t, d.Create(ctx, call))
+		fileKey := fmt.Sprintf("%d/a", call.ID)
+		_, err := recordingDao.Start(ctx, &model.CallRecording{CallHistoryID: call.ID, FileKey: fileKey, Codec: "amr", Checksum: "a"})
+		assert.NoError(t, err)
+		_, err = objects.Append(ctx, fileKey, 0, strings.NewReader("audio"))
+		assert.NoError(t, err)
+	}
+
+	// the recording of a purged call is deleted with its audio
+	assert.NoError(t, d.DeleteByID(ctx, 1, 0))
+	assert.NoError(t, d.PurgeByID(ctx, 1))
+	_, err := recordingDao.GetByCallHistoryID(ctx, 1)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = objects.Size(ctx, "1/a")
+	assert.ErrorIs(t, err, storage.ErrNotExist)
+	assert.ErrorIs(t, d.PurgeByID(ctx, 2), model.ErrRecordNotFound)
+
+	// the recordings of the soft deleted calls are kept, the hard deleted ones are not
+	n, err := d.DeleteCreatedBefore(ctx, time.Now(), 10, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	_, err = recordingDao.GetByCallHistoryID(ctx, 2)
+	assert.NoError(t, err)
+	n, err = d.DeleteCreatedBefore(ctx, time.Now(), 10, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	for _, id := range []uint64{2, 3} {
+		_, err = recordingDao.GetByCallHistoryID(ctx, id)
+		assert.ErrorIs(t, err, model.ErrRecordNotFound)
+		_, err = objects.Size(ctx, fmt.Sprintf("%d/a", id))
+		assert.ErrorIs(t, err, storage.ErrNotExist)
+	}
+}
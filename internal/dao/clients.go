@@ -2,18 +2,14 @@ package dao
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"time"
+	"encoding/json"
 
-	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
-	cacheBase "github.com/zhufuyi/sponge/pkg/cache"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
-	"github.com/zhufuyi/sponge/pkg/utils"
 
 	"caller/internal/cache"
+	"caller/internal/labels"
 	"caller/internal/model"
 )
 
@@ -22,8 +18,10 @@ var _ ClientsDao = (*clientsDao)(nil)
 // ClientsDao defining the dao interface
 type ClientsDao interface {
 	Create(ctx context.Context, table *model.Clients) error
-	DeleteByID(ctx context.Context, id uint64) error
+	CreateBatch(ctx context.Context, tables []*model.Clients, atomic bool) ([]error, error)
+	DeleteByID(ctx context.Context, id uint64, version uint64) error
 	UpdateByID(ctx context.Context, table *model.Clients) error
+	PatchByID(ctx context.Context, id uint64, version uint64, patch map[string]json.RawMessage) error
 	GetByID(ctx context.Context, id uint64) (*model.Clients, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.Clients, int64, error)
 
@@ -31,70 +29,66 @@ type ClientsDao interface {
 	GetByCondition(ctx context.Context, condition *query.Conditions) (*model.Clients, error)
 	GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Clients, error)
 	GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.Clients, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.Clients, string, error)
+
+	GetDeletedByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.Clients, string, error)
+	RestoreByID(ctx context.Context, id uint64) error
+	PurgeByID(ctx context.Context, id uint64) error
+
+	GetByColumnsWithScopes(ctx context.Context, params *query.Params, scopes ...Scope) ([]*model.Clients, int64, error)
+	GetByCursorWithScopes(ctx context.Context, cursor string, limit int, sort string, scopes ...Scope) ([]*model.Clients, string, error)
+
+	GetLabels(ctx context.Context, id uint64) (map[string]string, error)
+	GetLabelsByIDs(ctx context.Context, ids []uint64) (map[uint64]map[string]string, error)
+	SetLabels(ctx context.Context, id uint64, values map[string]string) (map[string]string, error)
+	PatchLabels(ctx context.Context, id uint64, patch map[string]*string) (map[string]string, error)
+	LabelScope(selector labels.Selector) Scope
+
+	GetByMachineCode(ctx context.Context, machineCode string) (*model.Clients, error)
+	GetByColumnsWithMachineCodes(ctx context.Context, params *query.Params, machineCodes []string, exclude bool) ([]*model.Clients, int64, error)
+	GetByCursorWithMachineCodes(ctx context.Context, cursor string, limit int, sort string, machineCodes []string, exclude bool) ([]*model.Clients, string, error)
+
+	CreateEnrollmentCode(ctx context.Context, code *model.EnrollmentCode) error
+	Enroll(ctx context.Context, codeHash string, machineCode string, ipAddress string, credentialHash string) (*model.Clients, error)
+	SetCredential(ctx context.Context, id uint64, credentialHash string) error
+	UpsertMetadata(ctx context.Context, machineCode string, metadata *ClientsMetadata) (*model.Clients, error)
+	GetIPHistoryByCursor(ctx context.Context, clientID uint64, cursor string, limit int, flagged bool) ([]*model.ClientIPHistory, string, error)
+	SetReleaseChannel(ctx context.Context, machineCodes []string, channel string) (int64, error)
+	SetStatus(ctx context.Context, id uint64, version uint64, status string, reason string, operator string) (*model.Clients, error)
+	GetMembersByGroupID(ctx context.Context, groupID int, activeOnly bool) ([]*model.Clients, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Clients) (uint64, error)
-	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64, version uint64) error
 	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Clients) error
 }
 
 type clientsDao struct {
-	db    *gorm.DB
-	cache cache.ClientsCache  // if nil, the cache is not used.
-	sfg   *singleflight.Group // if cache is nil, the sfg is not used.
+	*Repository[model.Clients]
+	*labelRepository[model.Clients]
+	ipHistory *Repository[model.ClientIPHistory] // the ip changes of the clients, it is not cached
 }
 
 // NewClientsDao creating the dao interface
 func NewClientsDao(db *gorm.DB, xCache cache.ClientsCache) ClientsDao {
-	if xCache == nil {
-		return &clientsDao{db: db}
-	}
 	return &clientsDao{
-		db:    db,
-		cache: xCache,
-		sfg:   new(singleflight.Group),
-	}
-}
-
-func (d *clientsDao) deleteCache(ctx context.Context, id uint64) error {
-	if d.cache != nil {
-		return d.cache.Del(ctx, id)
-	}
-	return nil
-}
-
-// Create a record, insert the record and the id value is written back to the table
-func (d *clientsDao) Create(ctx context.Context, table *model.Clients) error {
-	return d.db.WithContext(ctx).Create(table).Error
-}
-
-// DeleteByID delete a record by id
-func (d *clientsDao) DeleteByID(ctx context.Context, id uint64) error {
-	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Clients{}).Error
-	if err != nil {
-		return err
+		Repository:      NewRepository[model.Clients](db, xCache, cache.ClientsExpireTime, updateClientsColumns, clientsReadOnlyColumns, clientsSortColumns...),
+		labelRepository: newLabelRepository[model.Clients](db),
+		ipHistory:       NewRepository[model.ClientIPHistory](db, nil, 0, nil, nil),
 	}
-
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
 }
 
-// UpdateByID update a record by id
-func (d *clientsDao) UpdateByID(ctx context.Context, table *model.Clients) error {
-	err := d.updateDataByID(ctx, d.db, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
-}
+// clientsReadOnlyColumns the columns that cannot be patched, the credential and the metadata are maintained by the
+// device apis, the status only changes through SetStatus, which validates it and records the operator
+var clientsReadOnlyColumns = []string{"credential_hash", "credential_issued_at", "metadata_updated_at",
+	"status", "status_reason", "status_operator", "status_updated_at"}
 
-func (d *clientsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.Clients) error {
-	if table.ID < 1 {
-		return errors.New("id cannot be 0")
-	}
+// clientsSortColumns the columns besides id, created_at and updated_at that records can be paged by,
+// app_version is not one of them, its versions such as 1.10.0 and 1.9.0 are not ordered as strings, app_version_code is
+var clientsSortColumns = []string{"machine_code", "device_model", "android_version", "app_version_code",
+	"battery_level", "is_charging", "signal_strength", "carrier", "sim_slot_count"}
 
+// updateClientsColumns the columns of a record to update, empty values are not updated
+func updateClientsColumns(table *model.Clients) map[string]interface{} {
 	update := map[string]interface{}{}
 
 	if table.MachineCode != "" {
@@ -104,283 +98,52 @@ func (d *clientsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *mod
 		update["ip_address"] = table.IPAddress
 	}
 
-	return db.WithContext(ctx).Model(table).Updates(update).Error
-}
-
-// GetByID get a record by id
-func (d *clientsDao) GetByID(ctx context.Context, id uint64) (*model.Clients, error) {
-	// no cache
-	if d.cache == nil {
-		record := &model.Clients{}
-		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
-		return record, err
-	}
-
-	// get from cache or database
-	record, err := d.cache.Get(ctx, id)
-	if err == nil {
-		return record, nil
-	}
-
-	if errors.Is(err, model.ErrCacheNotFound) {
-		// for the same id, prevent high concurrent simultaneous access to database
-		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
-			table := &model.Clients{}
-			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
-			if err != nil {
-				// if data is empty, set not found cache to prevent cache penetration, default expiration time 10 minutes
-				if errors.Is(err, model.ErrRecordNotFound) {
-					err = d.cache.SetCacheWithNotFound(ctx, id)
-					if err != nil {
-						return nil, err
-					}
-					return nil, model.ErrRecordNotFound
-				}
-				return nil, err
-			}
-			// set cache
-			err = d.cache.Set(ctx, id, table, cache.ClientsExpireTime)
-			if err != nil {
-				return nil, fmt.Errorf("cache.Set error: %v, id=%d", err, id)
-			}
-			return table, nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		table, ok := val.(*model.Clients)
-		if !ok {
-			return nil, model.ErrRecordNotFound
-		}
-		return table, nil
-	} else if errors.Is(err, cacheBase.ErrPlaceholder) {
-		return nil, model.ErrRecordNotFound
-	}
-
-	// fail fast, if cache error return, don't request to db
-	return nil, err
+	return update
 }
 
-// GetByColumns get paging records by column information,
-// Note: query performance degrades when table rows are very large because of the use of offset.
-//
-// params includes paging parameters and query parameters
-// paging parameters (required):
-//
-//	page: page number, starting from 0
-//	size: lines per page
-//	sort: sort fields, default is id backwards, you can add - sign before the field to indicate reverse order, no - sign to indicate ascending order, multiple fields separated by comma
-//
-// query parameters (not required):
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: search for a male over 20 years of age
-//
-//	params = &query.Params{
-//	    Page: 0,
-//	    Size: 20,
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Exp: ">",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *clientsDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.Clients, int64, error) {
-	queryStr, args, err := params.ConvertToGormConditions()
-	if err != nil {
-		return nil, 0, errors.New("query params error: " + err.Error())
-	}
-
-	var total int64
-	if params.Sort != "ignore count" { // determine if count is required
-		err = d.db.WithContext(ctx).Model(&model.Clients{}).Select([]string{"id"}).Where(queryStr, args...).Count(&total).Error
-		if err != nil {
-			return nil, 0, err
-		}
-		if total == 0 {
-			return nil, total, nil
-		}
-	}
-
-	records := []*model.Clients{}
-	order, limit, offset := params.ConvertToPage()
-	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
+// GetByMachineCode get the first record with the machine code
+func (d *clientsDao) GetByMachineCode(ctx context.Context, machineCode string) (*model.Clients, error) {
+	record := &model.Clients{}
+	err := d.db.WithContext(ctx).Where("machine_code = ?", machineCode).Order("id ASC").First(record).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	return records, total, err
+	return record, nil
 }
 
-// DeleteByIDs delete records by batch id
-func (d *clientsDao) DeleteByIDs(ctx context.Context, ids []uint64) error {
-	err := d.db.WithContext(ctx).Where("id IN (?)", ids).Delete(&model.Clients{}).Error
-	if err != nil {
-		return err
-	}
-
-	// delete cache
-	for _, id := range ids {
-		_ = d.deleteCache(ctx, id)
-	}
-
-	return nil
+// GetByColumnsWithMachineCodes the same as GetByColumns, the records are limited to the machine codes,
+// or to the other machine codes if exclude is true
+func (d *clientsDao) GetByColumnsWithMachineCodes(ctx context.Context, params *query.Params, machineCodes []string, exclude bool) ([]*model.Clients, int64, error) {
+	return d.getByColumns(ctx, params, MachineCodesScope(machineCodes, exclude))
 }
 
-// GetByCondition get a record by condition
-// query conditions:
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: find a male aged 20
-//
-//	condition = &query.Conditions{
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *clientsDao) GetByCondition(ctx context.Context, c *query.Conditions) (*model.Clients, error) {
-	queryStr, args, err := c.ConvertToGorm()
-	if err != nil {
-		return nil, err
-	}
-
-	table := &model.Clients{}
-	err = d.db.WithContext(ctx).Where(queryStr, args...).First(table).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return table, nil
+// GetByCursorWithMachineCodes the same as GetByCursor, the records are limited to the machine codes,
+// or to the other machine codes if exclude is true
+func (d *clientsDao) GetByCursorWithMachineCodes(ctx context.Context, cursor string, limit int, sort string, machineCodes []string, exclude bool) ([]*model.Clients, string, error) {
+	return d.getByCursor(d.withScopes(ctx, MachineCodesScope(machineCodes, exclude)), cursor, limit, sort, d.sortColumns)
 }
 
-// GetByIDs get records by batch id
-func (d *clientsDao) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.Clients, error) {
-	// no cache
-	if d.cache == nil {
-		var records []*model.Clients
-		err := d.db.WithContext(ctx).Where("id IN (?)", ids).Find(&records).Error
-		if err != nil {
-			return nil, err
-		}
-		itemMap := make(map[uint64]*model.Clients)
-		for _, record := range records {
-			itemMap[record.ID] = record
-		}
-		return itemMap, nil
-	}
-
-	// get form cache or database
-	itemMap, err := d.cache.MultiGet(ctx, ids)
-	if err != nil {
-		return nil, err
-	}
-
-	var missedIDs []uint64
-	for _, id := range ids {
-		_, ok := itemMap[id]
-		if !ok {
-			missedIDs = append(missedIDs, id)
-			continue
-		}
-	}
-
-	// get missed data
-	if len(missedIDs) > 0 {
-		// find the id of an active placeholder, i.e. an id that does not exist in database
-		var realMissedIDs []uint64
-		for _, id := range missedIDs {
-			_, err = d.cache.Get(ctx, id)
-			if errors.Is(err, cacheBase.ErrPlaceholder) {
-				continue
-			}
-			realMissedIDs = append(realMissedIDs, id)
+// MachineCodesScope limit the records to the machine codes, or to the other machine codes if exclude is true
+func MachineCodesScope(machineCodes []string, exclude bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case len(machineCodes) == 0 && exclude:
+			return db
+		case len(machineCodes) == 0:
+			return db.Where("1 = 0")
+		case exclude:
+			return db.Where("machine_code NOT IN (?)", machineCodes)
 		}
-
-		if len(realMissedIDs) > 0 {
-			var missedData []*model.Clients
-			err = d.db.WithContext(ctx).Where("id IN (?)", realMissedIDs).Find(&missedData).Error
-			if err != nil {
-				return nil, err
-			}
-
-			if len(missedData) > 0 {
-				for _, data := range missedData {
-					itemMap[data.ID] = data
-				}
-				err = d.cache.MultiSet(ctx, missedData, cache.ClientsExpireTime)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				for _, id := range realMissedIDs {
-					_ = d.cache.SetCacheWithNotFound(ctx, id)
-				}
-			}
-		}
-	}
-
-	return itemMap, nil
-}
-
-// GetByLastID get paging records by last id and limit
-func (d *clientsDao) GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.Clients, error) {
-	page := query.NewPage(0, limit, sort)
-
-	records := []*model.Clients{}
-	err := d.db.WithContext(ctx).Order(page.Sort()).Limit(page.Size()).Where("id < ?", lastID).Find(&records).Error
-	if err != nil {
-		return nil, err
+		return db.Where("machine_code IN (?)", machineCodes)
 	}
-	return records, nil
-}
-
-// CreateByTx create a record in the database using the provided transaction
-func (d *clientsDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Clients) (uint64, error) {
-	err := tx.WithContext(ctx).Create(table).Error
-	return table.ID, err
 }
 
-// DeleteByTx delete a record by id in the database using the provided transaction
-func (d *clientsDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
-	update := map[string]interface{}{
-		"deleted_at": time.Now(),
-	}
-	err := tx.WithContext(ctx).Model(&model.Clients{}).Where("id = ?", id).Updates(update).Error
+// PurgeByID permanently delete a soft deleted record by id and its labels,
+// return model.ErrRecordNotFound if the record is not in the trash
+func (d *clientsDao) PurgeByID(ctx context.Context, id uint64) error {
+	err := d.Repository.PurgeByID(ctx, id)
 	if err != nil {
 		return err
 	}
-
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
-}
-
-// UpdateByTx update a record by id in the database using the provided transaction
-func (d *clientsDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Clients) error {
-	err := d.updateDataByID(ctx, tx, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
+	return d.deleteLabels(ctx, id)
 }
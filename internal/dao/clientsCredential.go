@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ErrInvalidEnrollmentCode the enrollment code is unknown, already used, expired, issued for another device,
+// or not issued for the device that already has a credential
+var ErrInvalidEnrollmentCode = errors.New("invalid enrollment code")
+
+// CreateEnrollmentCode save an enrollment code, only the hash of the code is stored
+func (d *clientsDao) CreateEnrollmentCode(ctx context.Context, code *model.EnrollmentCode) error {
+	return d.db.WithContext(ctx).Create(code).Error
+}
+
+// Enroll use an enrollment code to issue a credential to the client of a machine code, the client is
+// created if it does not exist, the previous credential of the client is replaced. a client that has a
+// credential is only enrolled again with a code issued for its machine code, so a code issued for any
+// device cannot take over the credential of an enrolled device.
+func (d *clientsDao) Enroll(ctx context.Context, codeHash string, machineCode string, ipAddress string, credentialHash string) (*model.Clients, error) {
+	record := &model.Clients{}
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Where("machine_code = ?", machineCode).Order("id ASC").First(record).Error
+		if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+			return err
+		}
+		exists := err == nil
+
+		// the update is the check, so a code cannot be used twice by concurrent requests
+		codes := tx.Model(&model.EnrollmentCode{}).
+			Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", codeHash, now)
+		if record.CredentialHash != "" {
+			codes = codes.Where("machine_code = ?", machineCode)
+		} else {
+			codes = codes.Where("machine_code IS NULL OR machine_code = '' OR machine_code = ?", machineCode)
+		}
+		result := codes.Updates(map[string]interface{}{"used_at": now, "machine_code": machineCode})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidEnrollmentCode
+		}
+
+		if !exists {
+			record = &model.Clients{MachineCode: machineCode, IPAddress: ipAddress}
+			err = tx.Create(record).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		columns := map[string]interface{}{"credential_hash": credentialHash, "credential_issued_at": now}
+		if ipAddress != "" {
+			columns["ip_address"] = ipAddress
+		}
+		err = d.updateColumnsByID(ctx, tx, record.ID, 0, columns)
+		if err != nil {
+			return err
+		}
+		return d.recordIPChange(ctx, tx, &model.Clients{Model: record.Model, MachineCode: machineCode, IPAddress: ipAddress}, record.IPAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, record.ID)
+
+	return d.GetByID(ctx, record.ID)
+}
+
+// SetCredential replace the credential of a client, an empty credentialHash revokes the credential
+func (d *clientsDao) SetCredential(ctx context.Context, id uint64, credentialHash string) error {
+	columns := map[string]interface{}{"credential_hash": credentialHash, "credential_issued_at": nil}
+	if credentialHash != "" {
+		columns["credential_issued_at"] = time.Now()
+	}
+	err := d.updateColumnsByID(ctx, d.db, id, 0, columns)
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_clientsDao_Enroll(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, cache.NewClientsCache(&model.CacheType{CType: "memory"}))
+
+	for _, code := range []*model.EnrollmentCode{
+		{CodeHash: "any", ExpiresAt: time.Now().Add(time.Hour)},
+		{CodeHash: "m2 only", MachineCode: "m2", ExpiresAt: time.Now().Add(time.Hour)},
+		{CodeHash: "expired", ExpiresAt: time.Now().Add(-time.Second)},
+		{CodeHash: "again", MachineCode: "m1", ExpiresAt: time.Now().Add(time.Hour)},
+		{CodeHash: "takeover", ExpiresAt: time.Now().Add(time.Hour)},
+	} {
+		err := d.CreateEnrollmentCode(ctx, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a new client is created
+	record, err := d.Enroll(ctx, "any", "m1", "10.0.0.1", "hash1")
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", record.MachineCode)
+	assert.Equal(t, "hash1", record.CredentialHash)
+	assert.NotNil(t, record.CredentialIssuedAt)
+
+	// the code is used
+	_, err = d.Enroll(ctx, "any", "m1", "", "hash2")
+	assert.ErrorIs(t, err, ErrInvalidEnrollmentCode)
+	_, err = d.Enroll(ctx, "m2 only", "m1", "", "hash2")
+	assert.ErrorIs(t, err, ErrInvalidEnrollmentCode)
+	_, err = d.Enroll(ctx, "expired", "m1", "", "hash2")
+	assert.ErrorIs(t, err, ErrInvalidEnrollmentCode)
+	_, err = d.Enroll(ctx, "unknown", "m1", "", "hash2")
+	assert.ErrorIs(t, err, ErrInvalidEnrollmentCode)
+
+	// a code issued for any device cannot replace the credential of an enrolled client
+	_, err = d.Enroll(ctx, "takeover", "m1", "", "hash2")
+	assert.ErrorIs(t, err, ErrInvalidEnrollmentCode)
+
+	// an enrolled client gets a new credential with a code issued for it
+	enrolled, err := d.Enroll(ctx, "again", "m1", "", "hash3")
+	assert.NoError(t, err)
+	assert.Equal(t, record.ID, enrolled.ID)
+	assert.Equal(t, "hash3", enrolled.CredentialHash)
+	assert.Equal(t, "10.0.0.1", enrolled.IPAddress)
+
+	err = d.SetCredential(ctx, record.ID, "hash4")
+	assert.NoError(t, err)
+	got, err := d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash4", got.CredentialHash)
+
+	// revoke
+	err = d.SetCredential(ctx, record.ID, "")
+	assert.NoError(t, err)
+	got, err = d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, got.CredentialHash)
+	assert.Nil(t, got.CredentialIssuedAt)
+
+	// a client without a credential can be enrolled with a code issued for any device
+	enrolled, err = d.Enroll(ctx, "takeover", "m1", "", "hash5")
+	assert.NoError(t, err)
+	assert.Equal(t, "hash5", enrolled.CredentialHash)
+
+	err = d.SetCredential(ctx, record.ID+1, "")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
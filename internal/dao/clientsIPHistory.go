@@ -0,0 +1,160 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ipMassChange when at least threshold clients change their ip address within window the changes are flagged
+// as a mass change, e.g. the network of a phone farm was switched, a threshold of 0 disables the detection.
+var ipMassChange = struct {
+	threshold int
+	window    time.Duration
+}{threshold: 10, window: 5 * time.Minute}
+
+// SetIPMassChange set how many clients changing their ip address within window is a mass change,
+// a negative threshold disables the detection, a threshold or window of 0 keeps the default.
+func SetIPMassChange(threshold int, window time.Duration) {
+	if threshold < 0 {
+		ipMassChange.threshold = 0
+	} else if threshold > 0 {
+		ipMassChange.threshold = threshold
+	}
+	if window > 0 {
+		ipMassChange.window = window
+	}
+}
+
+// UpdateByID update a record by id, a change of the ip address is recorded in the ip history of the client
+func (d *clientsDao) UpdateByID(ctx context.Context, table *model.Clients) error {
+	if table.IPAddress == "" || table.ID < 1 {
+		return d.Repository.UpdateByID(ctx, table)
+	}
+
+	err := d.trackIPChange(ctx, table.ID, func(tx *gorm.DB) error {
+		return d.updateDataByID(ctx, tx, table)
+	})
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+// PatchByID apply a json merge patch to a record by id, a change of the ip address is recorded in the ip history of the client
+func (d *clientsDao) PatchByID(ctx context.Context, id uint64, version uint64, patch map[string]json.RawMessage) error {
+	ipAddress := ""
+	if value, ok := patch["ipAddress"]; ok {
+		_ = json.Unmarshal(value, &ipAddress)
+	}
+	if ipAddress == "" || id < 1 {
+		return d.Repository.PatchByID(ctx, id, version, patch)
+	}
+
+	err := d.trackIPChange(ctx, id, func(tx *gorm.DB) error {
+		return d.patchByID(ctx, tx, id, version, patch)
+	})
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return err
+}
+
+// GetIPHistoryByCursor get a page of the ip changes of a client after the cursor, and the cursor of the next page,
+// the latest changes come first, if flagged is true only the changes flagged as a subnet change or a mass change are returned.
+func (d *clientsDao) GetIPHistoryByCursor(ctx context.Context, clientID uint64, cursor string, limit int, flagged bool) ([]*model.ClientIPHistory, string, error) {
+	db := d.ipHistory.db.WithContext(ctx).Where("client_id = ?", clientID)
+	if flagged {
+		db = db.Where("subnet_changed = ? OR mass_change = ?", true, true)
+	}
+	return d.ipHistory.getByCursor(db, cursor, limit, "-id", d.ipHistory.sortColumns)
+}
+
+// trackIPChange run update in a transaction, if the ip address of the client is changed by update the change is recorded
+func (d *clientsDao) trackIPChange(ctx context.Context, id uint64, update func(tx *gorm.DB) error) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before := &model.Clients{}
+		err := tx.Select("id", "ip_address").Where("id = ?", id).First(before).Error
+		if err != nil {
+			return err
+		}
+
+		err = update(tx)
+		if err != nil {
+			return err
+		}
+
+		after := &model.Clients{}
+		err = tx.Select("id", "machine_code", "ip_address").Where("id = ?", id).First(after).Error
+		if err != nil {
+			return err
+		}
+		return d.recordIPChange(ctx, tx, after, before.IPAddress)
+	})
+}
+
+// recordIPChange add the change of the ip address of a client to its ip history, nothing is recorded if the
+// ip address was not changed or was cleared.
+func (d *clientsDao) recordIPChange(ctx context.Context, tx *gorm.DB, client *model.Clients, previousIP string) error {
+	if client.IPAddress == "" || client.IPAddress == previousIP {
+		return nil
+	}
+
+	record := &model.ClientIPHistory{
+		ClientID:      client.ID,
+		MachineCode:   client.MachineCode,
+		PreviousIP:    previousIP,
+		IPAddress:     client.IPAddress,
+		SubnetChanged: previousIP != "" && !sameSubnet(previousIP, client.IPAddress),
+	}
+
+	if ipMassChange.threshold > 0 {
+		since := time.Now().Add(-ipMassChange.window)
+		var moved int64
+		err := tx.WithContext(ctx).Model(&model.ClientIPHistory{}).
+			Where("created_at >= ? AND client_id <> ?", since, client.ID).
+			Distinct("client_id").Count(&moved).Error
+		if err != nil {
+			return err
+		}
+		if int(moved)+1 >= ipMassChange.threshold {
+			record.MassChange = true
+			// the changes before the threshold was reached belong to the same mass change
+			err = tx.WithContext(ctx).Model(&model.ClientIPHistory{}).
+				Where("created_at >= ? AND mass_change = ?", since, false).
+				Update("mass_change", true).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.WithContext(ctx).Create(record).Error
+}
+
+// sameSubnet whether two ip addresses are in the same /24 (ipv4) or /64 (ipv6) network,
+// addresses that cannot be parsed are only in the same network if they are equal.
+func sameSubnet(a string, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+
+	if v4A, v4B := ipA.To4(), ipB.To4(); v4A != nil || v4B != nil {
+		if v4A == nil || v4B == nil {
+			return false
+		}
+		mask := net.CIDRMask(24, 32)
+		return v4A.Mask(mask).Equal(v4B.Mask(mask))
+	}
+
+	mask := net.CIDRMask(64, 128)
+	return ipA.Mask(mask).Equal(ipB.Mask(mask))
+}
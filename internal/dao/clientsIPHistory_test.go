@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_clientsDao_GetIPHistoryByCursor(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, cache.NewClientsCache(&model.CacheType{CType: "memory"}))
+
+	record := &model.Clients{MachineCode: "m1", IPAddress: "10.0.0.1"}
+	assert.NoError(t, d.Create(ctx, record))
+
+	// same subnet
+	err := d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: record.ID}, IPAddress: "10.0.0.2"})
+	assert.NoError(t, err)
+	// another subnet
+	err = d.PatchByID(ctx, record.ID, 0, map[string]json.RawMessage{"ipAddress": json.RawMessage(`"10.1.0.1"`)})
+	assert.NoError(t, err)
+	// the ip address is not changed
+	err = d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: record.ID}, IPAddress: "10.1.0.1"})
+	assert.NoError(t, err)
+	// a failed update is not recorded
+	err = d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: record.ID}, IPAddress: "10.2.0.1", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	records, nextCursor, err := d.GetIPHistoryByCursor(ctx, record.ID, "", 1, false)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "10.0.0.2", records[0].PreviousIP)
+	assert.Equal(t, "10.1.0.1", records[0].IPAddress)
+	assert.Equal(t, "m1", records[0].MachineCode)
+	assert.True(t, records[0].SubnetChanged)
+	assert.NotEmpty(t, nextCursor)
+
+	records, nextCursor, err = d.GetIPHistoryByCursor(ctx, record.ID, nextCursor, 1, false)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "10.0.0.1", records[0].PreviousIP)
+	assert.False(t, records[0].SubnetChanged)
+	assert.Empty(t, nextCursor)
+
+	records, _, err = d.GetIPHistoryByCursor(ctx, record.ID, "", 10, true)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// an update of a client that does not exist
+	err = d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: 100}, IPAddress: "10.0.0.1"})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func Test_clientsDao_recordIPChange_massChange(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, nil)
+
+	defer SetIPMassChange(ipMassChange.threshold, ipMassChange.window)
+	SetIPMassChange(3, time.Minute)
+
+	ids := []uint64{}
+	for i := 1; i <= 3; i++ {
+		record := &model.Clients{MachineCode: fmt.Sprintf("m%d", i), IPAddress: fmt.Sprintf("10.0.0.%d", i)}
+		assert.NoError(t, d.Create(ctx, record))
+		ids = append(ids, record.ID)
+	}
+
+	for i, id := range ids {
+		err := d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: id}, IPAddress: fmt.Sprintf("10.0.0.%d", i+11)})
+		assert.NoError(t, err)
+
+		records, _, err := d.GetIPHistoryByCursor(ctx, ids[0], "", 10, true)
+		assert.NoError(t, err)
+		if i < 2 {
+			assert.Len(t, records, 0)
+		} else {
+			// the first change is flagged when the threshold is reached
+			assert.Len(t, records, 1)
+			assert.True(t, records[0].MassChange)
+		}
+	}
+
+	// disabled
+	SetIPMassChange(-1, 0)
+	err := d.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: ids[0]}, IPAddress: "10.0.0.100"})
+	assert.NoError(t, err)
+	records, _, err := d.GetIPHistoryByCursor(ctx, ids[0], "", 1, false)
+	assert.NoError(t, err)
+	assert.False(t, records[0].MassChange)
+}
+
+func Test_sameSubnet(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"10.0.0.1", "10.0.0.254", true},
+		{"10.0.0.1", "10.0.1.1", false},
+		{"2001:db8::1", "2001:db8::ffff", true},
+		{"2001:db8::1", "2001:db8:0:1::1", false},
+		{"10.0.0.1", "2001:db8::1", false},
+		{"::ffff:10.0.0.1", "10.0.0.2", true},
+		{"unknown", "unknown", true},
+		{"unknown", "10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sameSubnet(tt.a, tt.b), tt.a+" "+tt.b)
+	}
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ClientsMetadata the metadata reported by a device, nil fields are not changed
+type ClientsMetadata struct {
+	DeviceModel    *string
+	AndroidVersion *string
+	AppVersion     *string
+	AppVersionCode *int
+	BatteryLevel   *int
+	IsCharging     *bool
+	SignalStrength *int
+	Carrier        *string
+	SimSlotCount   *int
+}
+
+// columns the columns to update, the key is the column name
+func (m *ClientsMetadata) columns() map[string]interface{} {
+	columns := map[string]interface{}{}
+
+	if m.DeviceModel != nil {
+		columns["device_model"] = *m.DeviceModel
+	}
+	if m.AndroidVersion != nil {
+		columns["android_version"] = *m.AndroidVersion
+	}
+	if m.AppVersion != nil {
+		columns["app_version"] = *m.AppVersion
+	}
+	if m.AppVersionCode != nil {
+		columns["app_version_code"] = *m.AppVersionCode
+	}
+	if m.BatteryLevel != nil {
+		columns["battery_level"] = *m.BatteryLevel
+	}
+	if m.IsCharging != nil {
+		columns["is_charging"] = *m.IsCharging
+	}
+	if m.SignalStrength != nil {
+		columns["signal_strength"] = *m.SignalStrength
+	}
+	if m.Carrier != nil {
+		columns["carrier"] = *m.Carrier
+	}
+	if m.SimSlotCount != nil {
+		columns["sim_slot_count"] = *m.SimSlotCount
+	}
+
+	return columns
+}
+
+// apply set the fields of a new record
+func (m *ClientsMetadata) apply(record *model.Clients) {
+	if m.DeviceModel != nil {
+		record.DeviceModel = *m.DeviceModel
+	}
+	if m.AndroidVersion != nil {
+		record.AndroidVersion = *m.AndroidVersion
+	}
+	if m.AppVersion != nil {
+		record.AppVersion = *m.AppVersion
+	}
+	if m.AppVersionCode != nil {
+		record.AppVersionCode = *m.AppVersionCode
+	}
+	if m.BatteryLevel != nil {
+		record.BatteryLevel = *m.BatteryLevel
+	}
+	if m.IsCharging != nil {
+		record.IsCharging = *m.IsCharging
+	}
+	if m.SignalStrength != nil {
+		record.SignalStrength = *m.SignalStrength
+	}
+	if m.Carrier != nil {
+		record.Carrier = *m.Carrier
+	}
+	if m.SimSlotCount != nil {
+		record.SimSlotCount = *m.SimSlotCount
+	}
+}
+
+// UpsertMetadata update the metadata of the client with the machine code, the client is created if it does not exist
+func (d *clientsDao) UpsertMetadata(ctx context.Context, machineCode string, metadata *ClientsMetadata) (*model.Clients, error) {
+	record := &model.Clients{}
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Where("machine_code = ?", machineCode).Order("id ASC").First(record).Error
+		if errors.Is(err, model.ErrRecordNotFound) {
+			record = &model.Clients{MachineCode: machineCode, MetadataUpdatedAt: &now}
+			metadata.apply(record)
+			return tx.Create(record).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		columns := metadata.columns()
+		columns["metadata_updated_at"] = now
+		return d.updateColumnsByID(ctx, tx, record.ID, 0, columns)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, record.ID)
+
+	return d.GetByID(ctx, record.ID)
+}
@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_clientsDao_UpsertMetadata(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, cache.NewClientsCache(&model.CacheType{CType: "memory"}))
+
+	appVersion, batteryLevel, isCharging := "1.2.0", 80, true
+	record, err := d.UpsertMetadata(ctx, "m1", &ClientsMetadata{AppVersion: &appVersion, BatteryLevel: &batteryLevel, IsCharging: &isCharging})
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", record.MachineCode)
+	assert.Equal(t, "1.2.0", record.AppVersion)
+	assert.Equal(t, 80, record.BatteryLevel)
+	assert.True(t, record.IsCharging)
+	assert.Equal(t, uint64(1), record.Version)
+	assert.NotNil(t, record.MetadataUpdatedAt)
+
+	// nil fields are not changed, false and 0 are
+	batteryLevel, isCharging = 15, false
+	updated, err := d.UpsertMetadata(ctx, "m1", &ClientsMetadata{BatteryLevel: &batteryLevel, IsCharging: &isCharging})
+	assert.NoError(t, err)
+	assert.Equal(t, record.ID, updated.ID)
+	assert.Equal(t, "1.2.0", updated.AppVersion)
+	assert.Equal(t, 15, updated.BatteryLevel)
+	assert.False(t, updated.IsCharging)
+
+	_, err = d.UpsertMetadata(ctx, "m2", &ClientsMetadata{BatteryLevel: &batteryLevel})
+	assert.NoError(t, err)
+
+	// find the low battery devices
+	records, total, err := d.GetByColumns(ctx, &query.Params{Size: 10, Sort: "battery_level", Columns: []query.Column{
+		{Name: "battery_level", Exp: "<", Value: 20},
+		{Name: "battery_level", Exp: ">", Value: 0},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, records, 2)
+
+	records, _, err = d.GetByCursor(ctx, "", 10, "machine_code")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", records[0].AppVersion)
+	_, _, err = d.GetByCursor(ctx, "", 10, "-app_version")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+
+	// find the outdated devices by the version code, 1.10.0 is newer than 1.9.0
+	for machineCode, versionCode := range map[string]int{"m1": 110, "m2": 19} {
+		_, err = d.UpsertMetadata(ctx, machineCode, &ClientsMetadata{AppVersionCode: &versionCode})
+		assert.NoError(t, err)
+	}
+	records, _, err = d.GetByCursor(ctx, "", 10, "-app_version_code")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{110, 19}, []int{records[0].AppVersionCode, records[1].AppVersionCode})
+	records, total, err = d.GetByColumns(ctx, &query.Params{Size: 10, Columns: []query.Column{
+		{Name: "app_version_code", Exp: "<", Value: 100},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "m2", records[0].MachineCode)
+}
@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// SetReleaseChannel assign the clients with the machine codes to a release channel, an empty channel removes
+// the assignment, return the number of clients that are changed.
+func (d *clientsDao) SetReleaseChannel(ctx context.Context, machineCodes []string, channel string) (int64, error) {
+	if len(machineCodes) == 0 {
+		return 0, nil
+	}
+
+	var ids []uint64
+	db := d.db.WithContext(ctx)
+	err := db.Model(&model.Clients{}).Where("machine_code IN (?) AND release_channel <> ?", machineCodes, channel).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := db.Model(&model.Clients{}).Where("id IN (?)", ids).
+		Updates(map[string]interface{}{"release_channel": channel, versionColumn: gorm.Expr(versionColumn + " + 1")})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// delete cache
+	for _, id := range ids {
+		_ = d.deleteCache(ctx, id)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"caller/internal/model"
+)
+
+// SetStatus set the status of a client with the reason and the operator who set it, if version is not 0
+// the status is only set when the version of the client matches, return the client after the change.
+func (d *clientsDao) SetStatus(ctx context.Context, id uint64, version uint64, status string, reason string, operator string) (*model.Clients, error) {
+	err := d.updateColumnsByID(ctx, d.db, id, version, map[string]interface{}{
+		"status":            status,
+		"status_reason":     reason,
+		"status_operator":   operator,
+		"status_updated_at": time.Now(),
+	})
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+	return d.GetByID(ctx, id)
+}
+
+// GetMembersByGroupID get the clients of a group through group_client, the oldest first,
+// if activeOnly is true the clients that are disabled or quarantined are skipped.
+func (d *clientsDao) GetMembersByGroupID(ctx context.Context, groupID int, activeOnly bool) ([]*model.Clients, error) {
+	db := d.db.WithContext(ctx).Model(&model.Clients{}).Select("clients.*").
+		Joins("JOIN group_client ON group_client.client_id = clients.id AND group_client.deleted_at IS NULL").
+		Where("group_client.group_id = ?", groupID)
+	if activeOnly {
+		db = db.Where("clients.status = ?", model.ClientsStatusActive)
+	}
+
+	records := []*model.Clients{}
+	err := db.Order("clients.id ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
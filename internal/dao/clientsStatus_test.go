@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_clientsDao_SetStatus(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, cache.NewClientsCache(&model.CacheType{CType: "memory"}))
+
+	record := &model.Clients{MachineCode: "m1"}
+	assert.NoError(t, d.Create(ctx, record))
+	// a new client is active
+	record, err := d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ClientsStatusActive, record.Status)
+	assert.True(t, record.IsActive())
+
+	record, err = d.SetStatus(ctx, record.ID, record.Version, model.ClientsStatusQuarantined, "calls fail", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ClientsStatusQuarantined, record.Status)
+	assert.Equal(t, "calls fail", record.StatusReason)
+	assert.Equal(t, "alice", record.StatusOperator)
+	assert.NotNil(t, record.StatusUpdatedAt)
+	assert.False(t, record.IsActive())
+
+	_, err = d.SetStatus(ctx, record.ID, 1, model.ClientsStatusActive, "", "bob")
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	_, err = d.SetStatus(ctx, 100, 0, model.ClientsStatusActive, "", "bob")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// the call history of a client that is not active is rejected, no command is queued
+	err = NewCallHistoryDao(db, nil).Create(ctx, &model.CallHistory{ClientMachineCode: "m1", Instruction: "call"})
+	assert.ErrorIs(t, err, model.ErrClientsQuarantined)
+	commands, err := NewDeviceCommandDao(db).Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 0)
+	// the calls of a batch are created with their commands, the call of a client that is not active fails
+	calls := []*model.CallHistory{
+		{ClientMachineCode: "m2", Instruction: "call"},
+		{ClientMachineCode: "m1", Instruction: "call"},
+	}
+	errs, err := NewCallHistoryDao(db, nil).CreateBatch(ctx, calls, false)
+	assert.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], model.ErrClientsQuarantined)
+	assert.Zero(t, calls[1].ID)
+	commands, err = NewDeviceCommandDao(db).Deliver(ctx, "m2", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.Equal(t, calls[0].ID, commands[0].CallHistoryID)
+	_, err = NewCallHistoryDao(db, nil).CreateBatch(ctx, calls[1:], true)
+	assert.ErrorIs(t, err, model.ErrClientsQuarantined)
+
+	_, err = d.SetStatus(ctx, record.ID, 0, model.ClientsStatusActive, "", "bob")
+	assert.NoError(t, err)
+	assert.NoError(t, NewCallHistoryDao(db, nil).Create(ctx, &model.CallHistory{ClientMachineCode: "m1", Instruction: "call"}))
+	commands, err = NewDeviceCommandDao(db).Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+}
+
+func Test_clientsDao_GetMembersByGroupID(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, nil)
+	groupClientDao := NewGroupClientDao(db, nil)
+
+	for i, machineCode := range []string{"m1", "m2", "m3"} {
+		record := &model.Clients{MachineCode: machineCode}
+		assert.NoError(t, d.Create(ctx, record))
+		assert.NoError(t, groupClientDao.Create(ctx, &model.GroupClient{GroupID: 1, ClientID: int(record.ID)}))
+		if i == 1 {
+			_, err := d.SetStatus(ctx, record.ID, 0, model.ClientsStatusDisabled, "", "alice")
+			assert.NoError(t, err)
+		}
+	}
+	// a client of another group
+	assert.NoError(t, groupClientDao.Create(ctx, &model.GroupClient{GroupID: 2, ClientID: 1}))
+	// a deleted membership
+	other := &model.Clients{MachineCode: "m4"}
+	assert.NoError(t, d.Create(ctx, other))
+	membership := &model.GroupClient{GroupID: 1, ClientID: int(other.ID)}
+	assert.NoError(t, groupClientDao.Create(ctx, membership))
+	assert.NoError(t, groupClientDao.DeleteByID(ctx, membership.ID, 0))
+
+	records, err := d.GetMembersByGroupID(ctx, 1, true)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "m1", records[0].MachineCode)
+		assert.Equal(t, "m3", records[1].MachineCode)
+		assert.WithinDuration(t, time.Now(), records[0].CreatedAt, time.Minute)
+	}
+
+	records, err = d.GetMembersByGroupID(ctx, 1, false)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	records, err = d.GetMembersByGroupID(ctx, 3, false)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
@@ -2,11 +2,13 @@ package dao
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/zhufuyi/sponge/pkg/ggorm"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
 	"github.com/zhufuyi/sponge/pkg/gotest"
 	"github.com/zhufuyi/sponge/pkg/utils"
@@ -54,6 +56,22 @@ func Test_clientsDao_Create(t *testing.T) {
 	}
 }
 
+func Test_clientsDao_CreateBatch(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	d.SQLMock.ExpectCommit()
+
+	errs, err := d.IDao.(ClientsDao).CreateBatch(d.Ctx, []*model.Clients{{}, {}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, errs, 2)
+}
+
 func Test_clientsDao_DeleteByID(t *testing.T) {
 	d := newClientsDao()
 	defer d.Close()
@@ -66,13 +84,13 @@ func Test_clientsDao_DeleteByID(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(ClientsDao).DeleteByID(d.Ctx, testData.ID)
+	err := d.IDao.(ClientsDao).DeleteByID(d.Ctx, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// zero id error
-	err = d.IDao.(ClientsDao).DeleteByID(d.Ctx, 0)
+	err = d.IDao.(ClientsDao).DeleteByID(d.Ctx, 0, 0)
 	assert.Error(t, err)
 }
 
@@ -98,6 +116,30 @@ func Test_clientsDao_UpdateByID(t *testing.T) {
 
 }
 
+func Test_clientsDao_PatchByID(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Clients)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ClientsDao).PatchByID(d.Ctx, testData.ID, 0, map[string]json.RawMessage{"ipAddress": json.RawMessage("null")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read-only field error
+	err = d.IDao.(ClientsDao).PatchByID(d.Ctx, testData.ID, 0, map[string]json.RawMessage{"id": json.RawMessage("1")})
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	// zero id error
+	err = d.IDao.(ClientsDao).PatchByID(d.Ctx, 0, 0, nil)
+	assert.Error(t, err)
+}
+
 func Test_clientsDao_GetByID(t *testing.T) {
 	d := newClientsDao()
 	defer d.Close()
@@ -190,7 +232,7 @@ func Test_clientsDao_DeleteByIDs(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(ClientsDao).DeleteByID(d.Ctx, testData.ID)
+	err := d.IDao.(ClientsDao).DeleteByID(d.Ctx, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -296,6 +338,113 @@ func Test_clientsDao_GetByLastID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_clientsDao_GetByCursor(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Clients)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+		AddRow(testData.ID, testData.CreatedAt, testData.UpdatedAt)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	_, nextCursor, err := d.IDao.(ClientsDao).GetByCursor(d.Ctx, "", 10, "-created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, nextCursor)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(ClientsDao).GetByCursor(d.Ctx, "", 10, "unknown-column")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+	_, _, err = d.IDao.(ClientsDao).GetByCursor(d.Ctx, "invalid", 10, "")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func Test_clientsDao_GetDeletedByCursor(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Clients)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at"}).
+		AddRow(testData.ID, testData.CreatedAt, testData.UpdatedAt, testData.UpdatedAt)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	records, nextCursor, err := d.IDao.(ClientsDao).GetDeletedByCursor(d.Ctx, "", 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 1)
+	assert.Empty(t, nextCursor)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(ClientsDao).GetDeletedByCursor(d.Ctx, "", 10, "unknown-column")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func Test_clientsDao_RestoreByID(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Clients)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ClientsDao).RestoreByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not in the trash error
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d.SQLMock.ExpectCommit()
+
+	err = d.IDao.(ClientsDao).RestoreByID(d.Ctx, testData.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func Test_clientsDao_PurgeByID(t *testing.T) {
+	d := newClientsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Clients)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+	// the labels of the record
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("DELETE FROM `resource_label` .*").
+		WithArgs("clients", testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ClientsDao).PurgeByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not in the trash error
+	err = d.IDao.(ClientsDao).PurgeByID(d.Ctx, 111)
+	assert.Error(t, err)
+}
+
 func Test_clientsDao_CreateByTx(t *testing.T) {
 	d := newClientsDao()
 	defer d.Close()
@@ -325,7 +474,7 @@ func Test_clientsDao_DeleteByTx(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(ClientsDao).DeleteByTx(d.Ctx, d.DB, testData.ID)
+	err := d.IDao.(ClientsDao).DeleteByTx(d.Ctx, d.DB, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -347,3 +496,54 @@ func Test_clientsDao_UpdateByTx(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_clientsDao_WithMachineCodes(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, nil)
+
+	for _, code := range []string{"m1", "m2", "m3"} {
+		err := d.Create(ctx, &model.Clients{MachineCode: code})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record, err := d.GetByMachineCode(ctx, "m2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), record.ID)
+	_, err = d.GetByMachineCode(ctx, "m4")
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	machineCodes := func(records []*model.Clients) []string {
+		codes := []string{}
+		for _, record := range records {
+			codes = append(codes, record.MachineCode)
+		}
+		return codes
+	}
+	params := &query.Params{Size: 10, Sort: "machine_code"}
+
+	records, total, err := d.GetByColumnsWithMachineCodes(ctx, params, []string{"m1", "m3"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"m1", "m3"}, machineCodes(records))
+	records, total, err = d.GetByColumnsWithMachineCodes(ctx, params, []string{"m1", "m3"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"m2"}, machineCodes(records))
+	_, total, err = d.GetByColumnsWithMachineCodes(ctx, params, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	records, nextCursor, err := d.GetByCursorWithMachineCodes(ctx, "", 1, "machine_code", []string{"m1", "m3"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"m1"}, machineCodes(records))
+	records, _, err = d.GetByCursorWithMachineCodes(ctx, nextCursor, 1, "", []string{"m1", "m3"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"m3"}, machineCodes(records))
+	records, _, err = d.GetByCursorWithMachineCodes(ctx, "", 10, "machine_code", nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, machineCodes(records))
+}
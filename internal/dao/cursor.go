@@ -0,0 +1,220 @@
+package dao
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	// ErrInvalidCursor the cursor is malformed, its signature does not match or it was issued for another sort
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidSort the sort contains a column that is not allowed for paging
+	ErrInvalidSort = errors.New("invalid sort")
+)
+
+// commonSortColumns the columns of ggorm.Model that every table can be paged by
+var commonSortColumns = []string{"id", "created_at", "updated_at"}
+
+// cursorSecret the key used to sign cursors, a random key is used until SetCursorSecret is called,
+// in that case cursors become invalid when the service restarts.
+var cursorSecret = func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}()
+
+// SetCursorSecret set the key used to sign cursors, every replica of the service must use the same key,
+// an empty secret is ignored.
+func SetCursorSecret(secret string) {
+	if secret != "" {
+		cursorSecret = []byte(secret)
+	}
+}
+
+// sortKey a column of the sort and its direction
+type sortKey struct {
+	column string
+	desc   bool
+}
+
+// parseSort parse a sort such as "-created_at,id" into sort keys, only allowed columns can be used,
+// id is appended as the last key when it is missing so that the order of the records is total.
+// the default sort is id descending.
+func parseSort(sort string, allowed map[string]bool) ([]sortKey, error) {
+	sort = strings.ReplaceAll(sort, " ", "")
+	if sort == "" {
+		return []sortKey{{column: "id", desc: true}}, nil
+	}
+
+	var keys []sortKey
+	seen := map[string]bool{}
+	for _, name := range strings.Split(sort, ",") {
+		key := sortKey{column: name}
+		if strings.HasPrefix(name, "-") {
+			key = sortKey{column: name[1:], desc: true}
+		}
+		if !allowed[key.column] || seen[key.column] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, sort)
+		}
+		seen[key.column] = true
+		keys = append(keys, key)
+	}
+
+	if !seen["id"] {
+		keys = append(keys, sortKey{column: "id", desc: keys[0].desc})
+	}
+
+	return keys, nil
+}
+
+// sortString the canonical form of sort keys, e.g. "-created_at,-id"
+func sortString(keys []sortKey) string {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			names = append(names, "-"+key.column)
+		} else {
+			names = append(names, key.column)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// sortOrder the ORDER BY clause of sort keys
+func sortOrder(keys []sortKey) string {
+	orders := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			orders = append(orders, key.column+" DESC")
+		} else {
+			orders = append(orders, key.column+" ASC")
+		}
+	}
+	return strings.Join(orders, ", ")
+}
+
+// seekCondition the condition that selects the records after the given sort values, for the keys (a, -b, id)
+// it is: a > ? OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+func seekCondition(keys []sortKey, values []interface{}) (string, []interface{}) {
+	var ors []string
+	var args []interface{}
+	for i, key := range keys {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, keys[j].column+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if key.desc {
+			op = " < ?"
+		}
+		ands = append(ands, key.column+op)
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return strings.Join(ors, " OR "), args
+}
+
+// cursor the position of the last record of a page, it contains the sort and the values of the sort columns
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// encodeCursor encode the sort values of a record into an opaque token, the token is signed so that
+// clients cannot forge it to inject values of other types
+func encodeCursor(sch *schema.Schema, keys []sortKey, record interface{}) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(record))
+	c := cursor{Sort: sortString(keys)}
+	for _, key := range keys {
+		field := sch.LookUpField(key.column)
+		if field == nil {
+			return "", fmt.Errorf("%w: unknown column %s", ErrInvalidSort, key.column)
+		}
+		value, _ := field.ValueOf(context.Background(), rv)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, data)
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(encoded), nil
+}
+
+// decodeCursor verify a token and decode the sort values into the types of the columns
+func decodeCursor(sch *schema.Schema, keys []sortKey, token string) ([]interface{}, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(encoded))) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := cursor{}
+	if err = json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sortString(keys) || len(c.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: the cursor was issued for sort %q", ErrInvalidCursor, c.Sort)
+	}
+
+	values := make([]interface{}, 0, len(keys))
+	for i, key := range keys {
+		field := sch.LookUpField(key.column)
+		if field == nil {
+			return nil, fmt.Errorf("%w: unknown column %s", ErrInvalidSort, key.column)
+		}
+		value := reflect.New(field.FieldType)
+		if err = json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values = append(values, value.Elem().Interface())
+	}
+
+	return values, nil
+}
+
+// cursorSort the sort stored in a token, it is used when a client sends a cursor without the sort
+func cursorSort(token string) string {
+	encoded, _, _ := strings.Cut(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	c := cursor{}
+	_ = json.Unmarshal(payload, &c)
+	return c.Sort
+}
+
+func signCursor(encoded string) string {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseSchema parse the schema of a table struct, it is cached by gorm
+func parseSchema(db *gorm.DB, table interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(table); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
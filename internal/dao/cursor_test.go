@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_parseSort(t *testing.T) {
+	allowed := map[string]bool{"id": true, "created_at": true, "machine_code": true}
+
+	keys, err := parseSort("", allowed)
+	assert.NoError(t, err)
+	assert.Equal(t, "-id", sortString(keys))
+
+	keys, err = parseSort("-created_at", allowed)
+	assert.NoError(t, err)
+	assert.Equal(t, "-created_at,-id", sortString(keys))
+	assert.Equal(t, "created_at DESC, id DESC", sortOrder(keys))
+
+	keys, err = parseSort("machine_code, -id", allowed)
+	assert.NoError(t, err)
+	assert.Equal(t, "machine_code,-id", sortString(keys))
+
+	for _, sort := range []string{"unknown-column", "ip_address", "id,id", "-", "id;drop table clients"} {
+		_, err = parseSort(sort, allowed)
+		assert.ErrorIs(t, err, ErrInvalidSort, sort)
+	}
+}
+
+func Test_seekCondition(t *testing.T) {
+	keys := []sortKey{{column: "a"}, {column: "b", desc: true}, {column: "id"}}
+	queryStr, args := seekCondition(keys, []interface{}{1, 2, 3})
+	assert.Equal(t, "(a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)", queryStr)
+	assert.Equal(t, []interface{}{1, 1, 2, 1, 2, 3}, args)
+}
+
+func Test_encodeCursor(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	sch, err := parseSchema(db, &model.Clients{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := &model.Clients{MachineCode: "machine"}
+	record.ID = 1 << 40 // larger than MaxInt32
+	record.CreatedAt = time.Date(2023, 10, 1, 8, 30, 0, 123456000, time.UTC)
+	keys, _ := parseSort("-created_at,machine_code", map[string]bool{"id": true, "created_at": true, "machine_code": true})
+
+	token, err := encodeCursor(sch, keys, record)
+	assert.NoError(t, err)
+	assert.Equal(t, "-created_at,machine_code,-id", cursorSort(token))
+
+	values, err := decodeCursor(sch, keys, token)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{record.CreatedAt, "machine", record.ID}, values)
+
+	// tampered token
+	_, err = decodeCursor(sch, keys, "x"+token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = decodeCursor(sch, keys, "garbage")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	// the cursor was issued for another sort
+	otherKeys, _ := parseSort("", map[string]bool{"id": true})
+	_, err = decodeCursor(sch, otherKeys, token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	// a token signed with another secret
+	defer func(secret []byte) { cursorSecret = secret }(cursorSecret)
+	SetCursorSecret("another secret")
+	_, err = decodeCursor(sch, keys, token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestRepository_GetByCursor(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, nil)
+
+	// duplicate values of the sort column must not skip or repeat records across pages
+	for _, code := range []string{"b", "a", "b", "a", "b"} {
+		err := d.Create(ctx, &model.Clients{MachineCode: code})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var codes []string
+	var ids []uint64
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		records, nextCursor, err := d.GetByCursor(ctx, cursor, 2, "machine_code")
+		assert.NoError(t, err)
+		for _, record := range records {
+			codes = append(codes, record.MachineCode)
+			ids = append(ids, record.ID)
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	assert.Equal(t, []string{"a", "a", "b", "b", "b"}, codes)
+	assert.Equal(t, []uint64{2, 4, 1, 3, 5}, ids)
+
+	// the sort does not match the cursor
+	_, _, err := d.GetByCursor(ctx, cursor, 2, "-id")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, _, err = d.GetByCursor(ctx, "", 2, "ip_address")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+var _ DeviceCommandDao = (*deviceCommandDao)(nil)
+
+// DeviceCommandDao defining the dao interface
+type DeviceCommandDao interface {
+	Create(ctx context.Context, table *model.DeviceCommand) error
+	GetByID(ctx context.Context, id uint64) (*model.DeviceCommand, error)
+
+	Deliver(ctx context.Context, machineCode string, limit int) ([]*model.DeviceCommand, error)
+	Ack(ctx context.Context, machineCode string, id uint64) error
+	Redeliver(ctx context.Context, machineCode string, id uint64) error
+	GetByMachineCodeByCursor(ctx context.Context, machineCode string, state string, cursor string, limit int) ([]*model.DeviceCommand, string, error)
+}
+
+type deviceCommandDao struct {
+	*Repository[model.DeviceCommand]
+}
+
+// NewDeviceCommandDao creating the dao interface, the commands are not cached
+func NewDeviceCommandDao(db *gorm.DB) DeviceCommandDao {
+	return &deviceCommandDao{
+		Repository: NewRepository[model.DeviceCommand](db, nil, 0, nil, nil, "expires_at"),
+	}
+}
+
+// Deliver mark up to limit pending commands of a device as delivered and return them, the oldest first.
+// a command is only marked by one call, so concurrent calls never return the same command,
+// the pending commands that have expired are marked as expired.
+func (d *deviceCommandDao) Deliver(ctx context.Context, machineCode string, limit int) ([]*model.DeviceCommand, error) {
+	now := time.Now()
+	db := d.db.WithContext(ctx)
+
+	err := db.Model(&model.DeviceCommand{}).
+		Where("machine_code = ? AND state = ? AND expires_at <= ?", machineCode, model.DeviceCommandPending, now).
+		Update("state", model.DeviceCommandExpired).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	err = db.Model(&model.DeviceCommand{}).
+		Where("machine_code = ? AND state = ? AND expires_at > ?", machineCode, model.DeviceCommandPending, now).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	delivered := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		// the state in the condition makes the update the claim of the command
+		result := db.Model(&model.DeviceCommand{}).
+			Where("id = ? AND state = ?", id, model.DeviceCommandPending).
+			Updates(map[string]interface{}{"state": model.DeviceCommandDelivered, "delivered_at": now})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			delivered = append(delivered, id)
+		}
+	}
+
+	records := []*model.DeviceCommand{}
+	if len(delivered) == 0 {
+		return records, nil
+	}
+	err = db.Where("id IN (?)", delivered).Order("id ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Ack mark a delivered command of a device as acked, acking a command again is not an error,
+// return model.ErrRecordNotFound if the device has no such delivered command.
+func (d *deviceCommandDao) Ack(ctx context.Context, machineCode string, id uint64) error {
+	result := d.db.WithContext(ctx).Model(&model.DeviceCommand{}).
+		Where("id = ? AND machine_code = ? AND state = ?", id, machineCode, model.DeviceCommandDelivered).
+		Updates(map[string]interface{}{"state": model.DeviceCommandAcked, "acked_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	record := &model.DeviceCommand{}
+	err := d.db.WithContext(ctx).Select("id").
+		Where("id = ? AND machine_code = ? AND state = ?", id, machineCode, model.DeviceCommandAcked).
+		First(record).Error
+	return err
+}
+
+// Redeliver put a delivered command of a device that has not been acked back in the queue, so it is delivered
+// again, return model.ErrRecordNotFound if the device has no such delivered command.
+func (d *deviceCommandDao) Redeliver(ctx context.Context, machineCode string, id uint64) error {
+	result := d.db.WithContext(ctx).Model(&model.DeviceCommand{}).
+		Where("id = ? AND machine_code = ? AND state = ?", id, machineCode, model.DeviceCommandDelivered).
+		Updates(map[string]interface{}{"state": model.DeviceCommandPending, "delivered_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByMachineCodeByCursor get a page of the commands of a device after the cursor, and the cursor of the next page,
+// the latest commands come first, if state is not empty only the commands in the state are returned.
+func (d *deviceCommandDao) GetByMachineCodeByCursor(ctx context.Context, machineCode string, state string, cursor string, limit int) ([]*model.DeviceCommand, string, error) {
+	db := d.db.WithContext(ctx).Where("machine_code = ?", machineCode)
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
+	return d.getByCursor(db, cursor, limit, "-id", d.sortColumns)
+}
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_deviceCommandDao(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewDeviceCommandDao(db)
+
+	// the instruction of a call history is queued for the client device
+	callHistory := &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000", Instruction: "call"}
+	assert.NoError(t, NewCallHistoryDao(db, nil).Create(ctx, callHistory))
+	assert.NoError(t, NewCallHistoryDao(db, nil).Create(ctx, &model.CallHistory{ClientMachineCode: "m1"}))
+	assert.NoError(t, d.Create(ctx, &model.DeviceCommand{MachineCode: "m1", Instruction: "hangup",
+		State: model.DeviceCommandPending, ExpiresAt: time.Now().Add(time.Minute)}))
+	assert.NoError(t, d.Create(ctx, &model.DeviceCommand{MachineCode: "m1", Instruction: "late",
+		State: model.DeviceCommandPending, ExpiresAt: time.Now().Add(-time.Second)}))
+	assert.NoError(t, d.Create(ctx, &model.DeviceCommand{MachineCode: "m2", Instruction: "call",
+		State: model.DeviceCommandPending, ExpiresAt: time.Now().Add(time.Minute)}))
+
+	commands, err := d.Deliver(ctx, "m1", 1)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.Equal(t, callHistory.ID, commands[0].CallHistoryID)
+	assert.Equal(t, "call", commands[0].Instruction)
+	assert.Equal(t, "13800000000", commands[0].MobileNumber)
+	assert.Equal(t, model.DeviceCommandDelivered, commands[0].State)
+	assert.NotNil(t, commands[0].DeliveredAt)
+	first := commands[0].ID
+
+	// a command is delivered only once, the expired command is not delivered
+	commands, err = d.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.Equal(t, "hangup", commands[0].Instruction)
+	commands, err = d.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 0)
+
+	commands, _, err = d.GetByMachineCodeByCursor(ctx, "m1", model.DeviceCommandExpired, "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.Equal(t, "late", commands[0].Instruction)
+
+	// ack
+	assert.NoError(t, d.Ack(ctx, "m1", first))
+	assert.NoError(t, d.Ack(ctx, "m1", first))
+	command, err := d.GetByID(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DeviceCommandAcked, command.State)
+	assert.NotNil(t, command.AckedAt)
+	// the command of another device, or not delivered yet
+	assert.ErrorIs(t, d.Ack(ctx, "m2", first), model.ErrRecordNotFound)
+	commands, _, err = d.GetByMachineCodeByCursor(ctx, "m2", "", "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.ErrorIs(t, d.Ack(ctx, "m2", commands[0].ID), model.ErrRecordNotFound)
+
+	// a delivered command that is not acked is delivered again only after it is redelivered
+	commands, _, err = d.GetByMachineCodeByCursor(ctx, "m1", model.DeviceCommandDelivered, "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.ErrorIs(t, d.Redeliver(ctx, "m1", first), model.ErrRecordNotFound)
+	assert.ErrorIs(t, d.Redeliver(ctx, "m2", commands[0].ID), model.ErrRecordNotFound)
+	assert.NoError(t, d.Redeliver(ctx, "m1", commands[0].ID))
+	command, err = d.GetByID(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DeviceCommandAcked, command.State)
+	redelivered, err := d.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, redelivered, 1)
+	assert.Equal(t, commands[0].ID, redelivered[0].ID)
+
+	commands, nextCursor, err := d.GetByMachineCodeByCursor(ctx, "m1", "", "", 2)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 2)
+	assert.Equal(t, "late", commands[0].Instruction)
+	commands, nextCursor, err = d.GetByMachineCodeByCursor(ctx, "m1", "", nextCursor, 2)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 1)
+	assert.Empty(t, nextCursor)
+}
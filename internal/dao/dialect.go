@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const dialectPostgres = "postgres"
+
+// adaptQuery rewrites the conditions generated by query.Params so that they match the same rows
+// on every supported database. mysql compares strings with a case-insensitive collation by default,
+// postgresql LIKE is case-sensitive, so it is replaced with ILIKE.
+func adaptQuery(db *gorm.DB, queryStr string) string {
+	if db == nil || db.Dialector == nil {
+		return queryStr
+	}
+
+	if db.Dialector.Name() == dialectPostgres {
+		return strings.ReplaceAll(queryStr, " LIKE ", " ILIKE ")
+	}
+
+	return queryStr
+}
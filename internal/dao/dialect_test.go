@@ -0,0 +1,22 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_adaptQuery(t *testing.T) {
+	queryStr := "name LIKE ? AND age > ?"
+
+	assert.Equal(t, queryStr, adaptQuery(nil, queryStr))
+
+	d := newCallHistoryDao()
+	defer d.Close()
+	assert.Equal(t, queryStr, adaptQuery(d.DB, queryStr))
+
+	pgDB := &gorm.DB{Config: &gorm.Config{Dialector: postgres.New(postgres.Config{})}}
+	assert.Equal(t, "name ILIKE ? AND age > ?", adaptQuery(pgDB, queryStr))
+}
@@ -2,18 +2,14 @@ package dao
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"time"
+	"encoding/json"
 
-	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
-	cacheBase "github.com/zhufuyi/sponge/pkg/cache"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
-	"github.com/zhufuyi/sponge/pkg/utils"
 
 	"caller/internal/cache"
+	"caller/internal/labels"
 	"caller/internal/model"
 )
 
@@ -22,8 +18,10 @@ var _ GroupCallDao = (*groupCallDao)(nil)
 // GroupCallDao defining the dao interface
 type GroupCallDao interface {
 	Create(ctx context.Context, table *model.GroupCall) error
-	DeleteByID(ctx context.Context, id uint64) error
+	CreateBatch(ctx context.Context, tables []*model.GroupCall, atomic bool) ([]error, error)
+	DeleteByID(ctx context.Context, id uint64, version uint64) error
 	UpdateByID(ctx context.Context, table *model.GroupCall) error
+	PatchByID(ctx context.Context, id uint64, version uint64, patch map[string]json.RawMessage) error
 	GetByID(ctx context.Context, id uint64) (*model.GroupCall, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.GroupCall, int64, error)
 
@@ -31,70 +29,45 @@ type GroupCallDao interface {
 	GetByCondition(ctx context.Context, condition *query.Conditions) (*model.GroupCall, error)
 	GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.GroupCall, error)
 	GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.GroupCall, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.GroupCall, string, error)
+	GetByGroupNumber(ctx context.Context, groupNumber string) (*model.GroupCall, error)
+
+	GetDeletedByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.GroupCall, string, error)
+	RestoreByID(ctx context.Context, id uint64) error
+	PurgeByID(ctx context.Context, id uint64) error
+
+	GetByColumnsWithScopes(ctx context.Context, params *query.Params, scopes ...Scope) ([]*model.GroupCall, int64, error)
+	GetByCursorWithScopes(ctx context.Context, cursor string, limit int, sort string, scopes ...Scope) ([]*model.GroupCall, string, error)
+
+	GetLabels(ctx context.Context, id uint64) (map[string]string, error)
+	GetLabelsByIDs(ctx context.Context, ids []uint64) (map[uint64]map[string]string, error)
+	SetLabels(ctx context.Context, id uint64, values map[string]string) (map[string]string, error)
+	PatchLabels(ctx context.Context, id uint64, patch map[string]*string) (map[string]string, error)
+	LabelScope(selector labels.Selector) Scope
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.GroupCall) (uint64, error)
-	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64, version uint64) error
 	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.GroupCall) error
 }
 
 type groupCallDao struct {
-	db    *gorm.DB
-	cache cache.GroupCallCache // if nil, the cache is not used.
-	sfg   *singleflight.Group  // if cache is nil, the sfg is not used.
+	*Repository[model.GroupCall]
+	*labelRepository[model.GroupCall]
 }
 
 // NewGroupCallDao creating the dao interface
 func NewGroupCallDao(db *gorm.DB, xCache cache.GroupCallCache) GroupCallDao {
-	if xCache == nil {
-		return &groupCallDao{db: db}
-	}
 	return &groupCallDao{
-		db:    db,
-		cache: xCache,
-		sfg:   new(singleflight.Group),
+		Repository:      NewRepository[model.GroupCall](db, xCache, cache.GroupCallExpireTime, updateGroupCallColumns, nil, groupCallSortColumns...),
+		labelRepository: newLabelRepository[model.GroupCall](db),
 	}
 }
 
-func (d *groupCallDao) deleteCache(ctx context.Context, id uint64) error {
-	if d.cache != nil {
-		return d.cache.Del(ctx, id)
-	}
-	return nil
-}
-
-// Create a record, insert the record and the id value is written back to the table
-func (d *groupCallDao) Create(ctx context.Context, table *model.GroupCall) error {
-	return d.db.WithContext(ctx).Create(table).Error
-}
-
-// DeleteByID delete a record by id
-func (d *groupCallDao) DeleteByID(ctx context.Context, id uint64) error {
-	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.GroupCall{}).Error
-	if err != nil {
-		return err
-	}
-
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
-}
-
-// UpdateByID update a record by id
-func (d *groupCallDao) UpdateByID(ctx context.Context, table *model.GroupCall) error {
-	err := d.updateDataByID(ctx, d.db, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
-}
-
-func (d *groupCallDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.GroupCall) error {
-	if table.ID < 1 {
-		return errors.New("id cannot be 0")
-	}
+// groupCallSortColumns the columns besides id, created_at and updated_at that records can be paged by
+var groupCallSortColumns = []string{"group_number"}
 
+// updateGroupCallColumns the columns of a record to update, empty values are not updated
+func updateGroupCallColumns(table *model.GroupCall) map[string]interface{} {
 	update := map[string]interface{}{}
 
 	if table.GroupNumber != "" {
@@ -107,283 +80,25 @@ func (d *groupCallDao) updateDataByID(ctx context.Context, db *gorm.DB, table *m
 		update["transfer_client_id"] = table.TransferClientID
 	}
 
-	return db.WithContext(ctx).Model(table).Updates(update).Error
+	return update
 }
 
-// GetByID get a record by id
-func (d *groupCallDao) GetByID(ctx context.Context, id uint64) (*model.GroupCall, error) {
-	// no cache
-	if d.cache == nil {
-		record := &model.GroupCall{}
-		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
-		return record, err
-	}
-
-	// get from cache or database
-	record, err := d.cache.Get(ctx, id)
-	if err == nil {
-		return record, nil
-	}
-
-	if errors.Is(err, model.ErrCacheNotFound) {
-		// for the same id, prevent high concurrent simultaneous access to database
-		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
-			table := &model.GroupCall{}
-			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
-			if err != nil {
-				// if data is empty, set not found cache to prevent cache penetration, default expiration time 10 minutes
-				if errors.Is(err, model.ErrRecordNotFound) {
-					err = d.cache.SetCacheWithNotFound(ctx, id)
-					if err != nil {
-						return nil, err
-					}
-					return nil, model.ErrRecordNotFound
-				}
-				return nil, err
-			}
-			// set cache
-			err = d.cache.Set(ctx, id, table, cache.GroupCallExpireTime)
-			if err != nil {
-				return nil, fmt.Errorf("cache.Set error: %v, id=%d", err, id)
-			}
-			return table, nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		table, ok := val.(*model.GroupCall)
-		if !ok {
-			return nil, model.ErrRecordNotFound
-		}
-		return table, nil
-	} else if errors.Is(err, cacheBase.ErrPlaceholder) {
-		return nil, model.ErrRecordNotFound
-	}
-
-	// fail fast, if cache error return, don't request to db
-	return nil, err
-}
-
-// GetByColumns get paging records by column information,
-// Note: query performance degrades when table rows are very large because of the use of offset.
-//
-// params includes paging parameters and query parameters
-// paging parameters (required):
-//
-//	page: page number, starting from 0
-//	size: lines per page
-//	sort: sort fields, default is id backwards, you can add - sign before the field to indicate reverse order, no - sign to indicate ascending order, multiple fields separated by comma
-//
-// query parameters (not required):
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: search for a male over 20 years of age
-//
-//	params = &query.Params{
-//	    Page: 0,
-//	    Size: 20,
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Exp: ">",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *groupCallDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.GroupCall, int64, error) {
-	queryStr, args, err := params.ConvertToGormConditions()
-	if err != nil {
-		return nil, 0, errors.New("query params error: " + err.Error())
-	}
-
-	var total int64
-	if params.Sort != "ignore count" { // determine if count is required
-		err = d.db.WithContext(ctx).Model(&model.GroupCall{}).Select([]string{"id"}).Where(queryStr, args...).Count(&total).Error
-		if err != nil {
-			return nil, 0, err
-		}
-		if total == 0 {
-			return nil, total, nil
-		}
-	}
-
-	records := []*model.GroupCall{}
-	order, limit, offset := params.ConvertToPage()
-	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return records, total, err
-}
-
-// DeleteByIDs delete records by batch id
-func (d *groupCallDao) DeleteByIDs(ctx context.Context, ids []uint64) error {
-	err := d.db.WithContext(ctx).Where("id IN (?)", ids).Delete(&model.GroupCall{}).Error
-	if err != nil {
-		return err
-	}
-
-	// delete cache
-	for _, id := range ids {
-		_ = d.deleteCache(ctx, id)
-	}
-
-	return nil
-}
-
-// GetByCondition get a record by condition
-// query conditions:
-//
-//	name: column name
-//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
-//	value: column value, if exp=in, multiple values are separated by commas
-//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
-//
-// example: find a male aged 20
-//
-//	condition = &query.Conditions{
-//	    Columns: []query.Column{
-//		{
-//			Name:    "age",
-//			Value:   20,
-//		},
-//		{
-//			Name:  "gender",
-//			Value: "male",
-//		},
-//	}
-func (d *groupCallDao) GetByCondition(ctx context.Context, c *query.Conditions) (*model.GroupCall, error) {
-	queryStr, args, err := c.ConvertToGorm()
-	if err != nil {
-		return nil, err
-	}
-
-	table := &model.GroupCall{}
-	err = d.db.WithContext(ctx).Where(queryStr, args...).First(table).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return table, nil
-}
-
-// GetByIDs get records by batch id
-func (d *groupCallDao) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*model.GroupCall, error) {
-	// no cache
-	if d.cache == nil {
-		var records []*model.GroupCall
-		err := d.db.WithContext(ctx).Where("id IN (?)", ids).Find(&records).Error
-		if err != nil {
-			return nil, err
-		}
-		itemMap := make(map[uint64]*model.GroupCall)
-		for _, record := range records {
-			itemMap[record.ID] = record
-		}
-		return itemMap, nil
-	}
-
-	// get form cache or database
-	itemMap, err := d.cache.MultiGet(ctx, ids)
-	if err != nil {
-		return nil, err
-	}
-
-	var missedIDs []uint64
-	for _, id := range ids {
-		_, ok := itemMap[id]
-		if !ok {
-			missedIDs = append(missedIDs, id)
-			continue
-		}
-	}
-
-	// get missed data
-	if len(missedIDs) > 0 {
-		// find the id of an active placeholder, i.e. an id that does not exist in database
-		var realMissedIDs []uint64
-		for _, id := range missedIDs {
-			_, err = d.cache.Get(ctx, id)
-			if errors.Is(err, cacheBase.ErrPlaceholder) {
-				continue
-			}
-			realMissedIDs = append(realMissedIDs, id)
-		}
-
-		if len(realMissedIDs) > 0 {
-			var missedData []*model.GroupCall
-			err = d.db.WithContext(ctx).Where("id IN (?)", realMissedIDs).Find(&missedData).Error
-			if err != nil {
-				return nil, err
-			}
-
-			if len(missedData) > 0 {
-				for _, data := range missedData {
-					itemMap[data.ID] = data
-				}
-				err = d.cache.MultiSet(ctx, missedData, cache.GroupCallExpireTime)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				for _, id := range realMissedIDs {
-					_ = d.cache.SetCacheWithNotFound(ctx, id)
-				}
-			}
-		}
-	}
-
-	return itemMap, nil
-}
-
-// GetByLastID get paging records by last id and limit
-func (d *groupCallDao) GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*model.GroupCall, error) {
-	page := query.NewPage(0, limit, sort)
-
-	records := []*model.GroupCall{}
-	err := d.db.WithContext(ctx).Order(page.Sort()).Limit(page.Size()).Where("id < ?", lastID).Find(&records).Error
+// GetByGroupNumber get a group by its number, if several groups have the number the oldest one is returned
+func (d *groupCallDao) GetByGroupNumber(ctx context.Context, groupNumber string) (*model.GroupCall, error) {
+	record := &model.GroupCall{}
+	err := d.db.WithContext(ctx).Where("group_number = ?", groupNumber).Order("id ASC").First(record).Error
 	if err != nil {
 		return nil, err
 	}
-	return records, nil
-}
-
-// CreateByTx create a record in the database using the provided transaction
-func (d *groupCallDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.GroupCall) (uint64, error) {
-	err := tx.WithContext(ctx).Create(table).Error
-	return table.ID, err
+	return record, nil
 }
 
-// DeleteByTx delete a record by id in the database using the provided transaction
-func (d *groupCallDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
-	update := map[string]interface{}{
-		"deleted_at": time.Now(),
-	}
-	err := tx.WithContext(ctx).Model(&model.GroupCall{}).Where("id = ?", id).Updates(update).Error
+// PurgeByID permanently delete a soft deleted record by id and its labels,
+// return model.ErrRecordNotFound if the record is not in the trash
+func (d *groupCallDao) PurgeByID(ctx context.Context, id uint64) error {
+	err := d.Repository.PurgeByID(ctx, id)
 	if err != nil {
 		return err
 	}
-
-	// delete cache
-	_ = d.deleteCache(ctx, id)
-
-	return nil
-}
-
-// UpdateByTx update a record by id in the database using the provided transaction
-func (d *groupCallDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.GroupCall) error {
-	err := d.updateDataByID(ctx, tx, table)
-
-	// delete cache
-	_ = d.deleteCache(ctx, table.ID)
-
-	return err
+	return d.deleteLabels(ctx, id)
 }
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -54,6 +55,22 @@ func Test_groupClientDao_Create(t *testing.T) {
 	}
 }
 
+func Test_groupClientDao_CreateBatch(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	d.SQLMock.ExpectCommit()
+
+	errs, err := d.IDao.(GroupClientDao).CreateBatch(d.Ctx, []*model.GroupClient{{}, {}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, errs, 2)
+}
+
 func Test_groupClientDao_DeleteByID(t *testing.T) {
 	d := newGroupClientDao()
 	defer d.Close()
@@ -66,13 +83,13 @@ func Test_groupClientDao_DeleteByID(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(GroupClientDao).DeleteByID(d.Ctx, testData.ID)
+	err := d.IDao.(GroupClientDao).DeleteByID(d.Ctx, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// zero id error
-	err = d.IDao.(GroupClientDao).DeleteByID(d.Ctx, 0)
+	err = d.IDao.(GroupClientDao).DeleteByID(d.Ctx, 0, 0)
 	assert.Error(t, err)
 }
 
@@ -98,6 +115,30 @@ func Test_groupClientDao_UpdateByID(t *testing.T) {
 
 }
 
+func Test_groupClientDao_PatchByID(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+	testData := d.TestData.(*model.GroupClient)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(GroupClientDao).PatchByID(d.Ctx, testData.ID, 0, map[string]json.RawMessage{"clientId": json.RawMessage("null")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read-only field error
+	err = d.IDao.(GroupClientDao).PatchByID(d.Ctx, testData.ID, 0, map[string]json.RawMessage{"id": json.RawMessage("1")})
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	// zero id error
+	err = d.IDao.(GroupClientDao).PatchByID(d.Ctx, 0, 0, nil)
+	assert.Error(t, err)
+}
+
 func Test_groupClientDao_GetByID(t *testing.T) {
 	d := newGroupClientDao()
 	defer d.Close()
@@ -190,7 +231,7 @@ func Test_groupClientDao_DeleteByIDs(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(GroupClientDao).DeleteByID(d.Ctx, testData.ID)
+	err := d.IDao.(GroupClientDao).DeleteByID(d.Ctx, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -296,6 +337,107 @@ func Test_groupClientDao_GetByLastID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_groupClientDao_GetByCursor(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+	testData := d.TestData.(*model.GroupClient)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+		AddRow(testData.ID, testData.CreatedAt, testData.UpdatedAt)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	_, nextCursor, err := d.IDao.(GroupClientDao).GetByCursor(d.Ctx, "", 10, "-created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, nextCursor)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(GroupClientDao).GetByCursor(d.Ctx, "", 10, "unknown-column")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+	_, _, err = d.IDao.(GroupClientDao).GetByCursor(d.Ctx, "invalid", 10, "")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func Test_groupClientDao_GetDeletedByCursor(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+	testData := d.TestData.(*model.GroupClient)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at"}).
+		AddRow(testData.ID, testData.CreatedAt, testData.UpdatedAt, testData.UpdatedAt)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	records, nextCursor, err := d.IDao.(GroupClientDao).GetDeletedByCursor(d.Ctx, "", 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 1)
+	assert.Empty(t, nextCursor)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(GroupClientDao).GetDeletedByCursor(d.Ctx, "", 10, "unknown-column")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func Test_groupClientDao_RestoreByID(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+	testData := d.TestData.(*model.GroupClient)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(GroupClientDao).RestoreByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not in the trash error
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d.SQLMock.ExpectCommit()
+
+	err = d.IDao.(GroupClientDao).RestoreByID(d.Ctx, testData.ID)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+}
+
+func Test_groupClientDao_PurgeByID(t *testing.T) {
+	d := newGroupClientDao()
+	defer d.Close()
+	testData := d.TestData.(*model.GroupClient)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(GroupClientDao).PurgeByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not in the trash error
+	err = d.IDao.(GroupClientDao).PurgeByID(d.Ctx, 111)
+	assert.Error(t, err)
+}
+
 func Test_groupClientDao_CreateByTx(t *testing.T) {
 	d := newGroupClientDao()
 	defer d.Close()
@@ -325,7 +467,7 @@ func Test_groupClientDao_DeleteByTx(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	d.SQLMock.ExpectCommit()
 
-	err := d.IDao.(GroupClientDao).DeleteByTx(d.Ctx, d.DB, testData.ID)
+	err := d.IDao.(GroupClientDao).DeleteByTx(d.Ctx, d.DB, testData.ID, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
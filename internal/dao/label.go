@@ -0,0 +1,188 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"caller/internal/labels"
+	"caller/internal/model"
+)
+
+// labelRepository the key/value labels of the records of a table, T is the table struct,
+// the labels of every table are stored in resource_label and are removed when the record is purged.
+type labelRepository[T any] struct {
+	labelDB  *gorm.DB
+	resource string // table name of T
+}
+
+func newLabelRepository[T any](db *gorm.DB) *labelRepository[T] {
+	resource := ""
+	if table, ok := any(new(T)).(interface{ TableName() string }); ok {
+		resource = table.TableName()
+	}
+	return &labelRepository[T]{labelDB: db, resource: resource}
+}
+
+// GetLabels get the labels of a record, return model.ErrRecordNotFound if the record does not exist
+func (r *labelRepository[T]) GetLabels(ctx context.Context, id uint64) (map[string]string, error) {
+	err := r.checkRecord(ctx, r.labelDB, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.getLabels(ctx, r.labelDB, id)
+}
+
+// GetLabelsByIDs get the labels of the records by batch id, the records without labels are not in the map
+func (r *labelRepository[T]) GetLabelsByIDs(ctx context.Context, ids []uint64) (map[uint64]map[string]string, error) {
+	itemMap := make(map[uint64]map[string]string)
+	if len(ids) == 0 {
+		return itemMap, nil
+	}
+
+	var records []*model.ResourceLabel
+	err := r.labelDB.WithContext(ctx).Where("resource = ? AND resource_id IN (?)", r.resource, ids).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if itemMap[record.ResourceID] == nil {
+			itemMap[record.ResourceID] = map[string]string{}
+		}
+		itemMap[record.ResourceID][record.Key] = record.Value
+	}
+	return itemMap, nil
+}
+
+// SetLabels replace the labels of a record, return model.ErrRecordNotFound if the record does not exist
+func (r *labelRepository[T]) SetLabels(ctx context.Context, id uint64, values map[string]string) (map[string]string, error) {
+	err := labels.Validate(values)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.labelDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := r.checkRecord(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("resource = ? AND resource_id = ?", r.resource, id).Delete(&model.ResourceLabel{}).Error
+		if err != nil {
+			return err
+		}
+		return r.createLabels(tx, id, values)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getLabels(ctx, r.labelDB, id)
+}
+
+// PatchLabels merge the labels into the labels of a record, a nil value removes the label,
+// return model.ErrRecordNotFound if the record does not exist
+func (r *labelRepository[T]) PatchLabels(ctx context.Context, id uint64, patch map[string]*string) (map[string]string, error) {
+	keys := make([]string, 0, len(patch))
+	values := map[string]string{}
+	for key, value := range patch {
+		err := labels.ValidateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	err := labels.Validate(values)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.labelDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := r.checkRecord(ctx, tx, id)
+		if err != nil || len(keys) == 0 {
+			return err
+		}
+		err = tx.Unscoped().Where("resource = ? AND resource_id = ? AND label_key IN (?)", r.resource, id, keys).
+			Delete(&model.ResourceLabel{}).Error
+		if err != nil {
+			return err
+		}
+		err = r.createLabels(tx, id, values)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		err = tx.Model(&model.ResourceLabel{}).Where("resource = ? AND resource_id = ?", r.resource, id).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total > labels.MaxLabels {
+			return labels.ErrTooMany
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getLabels(ctx, r.labelDB, id)
+}
+
+// LabelScope limit the records to the ones whose labels match the selector, an empty selector does not limit the records
+func (r *labelRepository[T]) LabelScope(selector labels.Selector) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, requirement := range selector {
+			sub := db.Session(&gorm.Session{NewDB: true}).Model(&model.ResourceLabel{}).Select("1").
+				Where("resource_label.resource = ? AND resource_label.resource_id = "+r.resource+".id AND resource_label.label_key = ?",
+					r.resource, requirement.Key)
+			switch requirement.Operator {
+			case labels.Equals, labels.NotEquals, labels.In, labels.NotIn:
+				sub = sub.Where("resource_label.label_value IN (?)", requirement.Values)
+			}
+
+			switch requirement.Operator {
+			case labels.NotEquals, labels.NotIn, labels.DoesNotExist:
+				db = db.Where("NOT EXISTS (?)", sub)
+			default:
+				db = db.Where("EXISTS (?)", sub)
+			}
+		}
+		return db
+	}
+}
+
+// deleteLabels delete all the labels of a record
+func (r *labelRepository[T]) deleteLabels(ctx context.Context, id uint64) error {
+	return r.labelDB.WithContext(ctx).Unscoped().Where("resource = ? AND resource_id = ?", r.resource, id).
+		Delete(&model.ResourceLabel{}).Error
+}
+
+func (r *labelRepository[T]) getLabels(ctx context.Context, db *gorm.DB, id uint64) (map[string]string, error) {
+	var records []*model.ResourceLabel
+	err := db.WithContext(ctx).Where("resource = ? AND resource_id = ?", r.resource, id).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(records))
+	for _, record := range records {
+		values[record.Key] = record.Value
+	}
+	return values, nil
+}
+
+func (r *labelRepository[T]) createLabels(tx *gorm.DB, id uint64, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	records := make([]*model.ResourceLabel, 0, len(values))
+	for key, value := range values {
+		records = append(records, &model.ResourceLabel{Resource: r.resource, ResourceID: id, Key: key, Value: value})
+	}
+	return tx.Create(&records).Error
+}
+
+// checkRecord return model.ErrRecordNotFound if the record does not exist or is in the trash
+func (r *labelRepository[T]) checkRecord(ctx context.Context, db *gorm.DB, id uint64) error {
+	return db.WithContext(ctx).Select("id").Where("id = ?", id).First(new(T)).Error
+}
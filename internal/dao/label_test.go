@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+
+	"caller/internal/labels"
+	"caller/internal/model"
+)
+
+func Test_labelRepository(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, nil)
+
+	for _, machineCode := range []string{"m1", "m2", "m3"} {
+		assert.NoError(t, d.Create(ctx, &model.Clients{MachineCode: machineCode}))
+	}
+
+	values, err := d.SetLabels(ctx, 1, map[string]string{"site": "shanghai", "carrier": "cmcc"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"site": "shanghai", "carrier": "cmcc"}, values)
+	_, err = d.SetLabels(ctx, 2, map[string]string{"site": "shanghai", "carrier": "unicom"})
+	assert.NoError(t, err)
+	_, err = d.SetLabels(ctx, 3, map[string]string{"site": "beijing"})
+	assert.NoError(t, err)
+
+	// replace
+	values, err = d.SetLabels(ctx, 3, map[string]string{"site": "beijing", "customer": "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"site": "beijing", "customer": "acme"}, values)
+
+	// patch, nil removes a label
+	customer := "acme"
+	values, err = d.PatchLabels(ctx, 1, map[string]*string{"carrier": nil, "customer": &customer})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"site": "shanghai", "customer": "acme"}, values)
+	values, err = d.PatchLabels(ctx, 1, map[string]*string{"carrier": nil, "customer": &customer})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"site": "shanghai", "customer": "acme"}, values)
+
+	values, err = d.GetLabels(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"site": "shanghai", "carrier": "unicom"}, values)
+	itemMap, err := d.GetLabelsByIDs(ctx, []uint64{1, 2, 100})
+	assert.NoError(t, err)
+	assert.Len(t, itemMap, 2)
+	assert.Equal(t, "unicom", itemMap[2]["carrier"])
+
+	// errors
+	_, err = d.GetLabels(ctx, 100)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = d.SetLabels(ctx, 100, map[string]string{"site": "shanghai"})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = d.PatchLabels(ctx, 100, map[string]*string{"site": nil})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = d.SetLabels(ctx, 1, map[string]string{"site=": "shanghai"})
+	assert.ErrorIs(t, err, labels.ErrInvalidKey)
+	tooMany := map[string]*string{}
+	for i := 0; i < labels.MaxLabels; i++ {
+		key := "k" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		tooMany[key] = &customer
+	}
+	_, err = d.PatchLabels(ctx, 1, tooMany)
+	assert.ErrorIs(t, err, labels.ErrTooMany)
+	values, err = d.GetLabels(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+
+	// select by labels
+	tests := []struct {
+		selector string
+		want     []uint64
+	}{
+		{"", []uint64{1, 2, 3}},
+		{"site=shanghai,carrier!=cmcc", []uint64{1, 2}},
+		{"site=shanghai,carrier=unicom", []uint64{2}},
+		{"site in (beijing,shenzhen)", []uint64{3}},
+		{"site notin (beijing)", []uint64{1, 2}},
+		{"customer", []uint64{1, 3}},
+		{"!customer", []uint64{2}},
+		{"retired", nil},
+	}
+	for _, tt := range tests {
+		selector, err := labels.Parse(tt.selector)
+		assert.NoError(t, err, tt.selector)
+
+		records, total, err := d.GetByColumnsWithScopes(ctx, &query.Params{Size: 10, Sort: "id"}, d.LabelScope(selector))
+		assert.NoError(t, err, tt.selector)
+		assert.Equal(t, int64(len(tt.want)), total, tt.selector)
+		var ids []uint64
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		assert.Equal(t, tt.want, ids, tt.selector)
+
+		records, _, err = d.GetByCursorWithScopes(ctx, "", 10, "id", d.LabelScope(selector), MachineCodesScope([]string{"m1", "m2"}, false))
+		assert.NoError(t, err, tt.selector)
+		assert.LessOrEqual(t, len(records), 2, tt.selector)
+		for _, record := range records {
+			assert.Contains(t, tt.want, record.ID, tt.selector)
+		}
+	}
+
+	// the labels of another resource are not mixed up
+	groupCallDao := NewGroupCallDao(db, nil)
+	assert.NoError(t, groupCallDao.Create(ctx, &model.GroupCall{GroupNumber: "1"}))
+	_, err = groupCallDao.SetLabels(ctx, 1, map[string]string{"site": "guangzhou"})
+	assert.NoError(t, err)
+	values, err = d.GetLabels(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "shanghai", values["site"])
+
+	// the labels are deleted with a purged record
+	assert.NoError(t, d.DeleteByID(ctx, 1, 0))
+	assert.NoError(t, d.PurgeByID(ctx, 1))
+	itemMap, err = d.GetLabelsByIDs(ctx, []uint64{1})
+	assert.NoError(t, err)
+	assert.Len(t, itemMap, 0)
+	values, err = groupCallDao.GetLabels(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, values, 1)
+}
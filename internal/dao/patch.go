@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidPatch the patch contains a field that is unknown or read-only, or a value of the wrong type
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// commonReadOnlyColumns the columns of ggorm.Model and the version, which are maintained by the service
+// and cannot be patched in any table
+var commonReadOnlyColumns = []string{"id", "created_at", "updated_at", "deleted_at", versionColumn}
+
+// writableFields the fields of a table that can be patched, the key is the json name of the field
+func writableFields(sch *schema.Schema, readOnlyColumns map[string]bool) map[string]*schema.Field {
+	fields := map[string]*schema.Field{}
+	for _, field := range sch.Fields {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.DBName == "" || readOnlyColumns[field.DBName] || name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// patchColumns convert a json merge patch (RFC 7396) into the columns to update, null or "" sets
+// the column to the zero value of the field. the read-only columns cannot be patched.
+func patchColumns(sch *schema.Schema, readOnlyColumns map[string]bool, patch map[string]json.RawMessage) (map[string]interface{}, error) {
+	fields := writableFields(sch, readOnlyColumns)
+
+	columns := map[string]interface{}{}
+	for name, raw := range patch {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", ErrInvalidPatch, name)
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if string(raw) == "null" || string(raw) == `""` {
+			columns[field.DBName] = reflect.Zero(field.FieldType).Interface()
+			continue
+		}
+
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: field %q: %v", ErrInvalidPatch, name, err)
+		}
+		columns[field.DBName] = value.Elem().Interface()
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_patchColumns(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	sch, err := parseSchema(db, &model.GroupCall{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readOnlyColumns := NewGroupCallDao(db, nil).(*groupCallDao).readOnlyColumns
+	fields := writableFields(sch, readOnlyColumns)
+	assert.Len(t, fields, 3)
+	assert.Contains(t, fields, "transferClientId")
+	assert.NotContains(t, fields, "createdAt")
+
+	columns, err := patchColumns(sch, readOnlyColumns, map[string]json.RawMessage{
+		"groupNumber":      json.RawMessage(`"0001"`),
+		"phoneNumber":      json.RawMessage(`""`),
+		"transferClientId": json.RawMessage(`null`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"group_number": "0001", "phone_number": "", "transfer_client_id": ""}, columns)
+
+	sch, err = parseSchema(db, &model.GroupClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnlyColumns = NewGroupClientDao(db, nil).(*groupClientDao).readOnlyColumns
+	columns, err = patchColumns(sch, readOnlyColumns, map[string]json.RawMessage{"groupId": json.RawMessage(`2`), "clientId": json.RawMessage(`""`)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"group_id": 2, "client_id": 0}, columns)
+
+	// the status of a client is only set by SetStatus
+	sch, err = parseSchema(db, &model.Clients{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"status", "statusReason", "statusOperator", "statusUpdatedAt"} {
+		_, err = patchColumns(sch, NewClientsDao(db, nil).(*clientsDao).readOnlyColumns, map[string]json.RawMessage{name: json.RawMessage(`"disabled"`)})
+		assert.ErrorIs(t, err, ErrInvalidPatch, name)
+	}
+	// the columns are only read-only in the tables that declare them
+	fields = writableFields(sch, readOnlyColumns)
+	assert.Contains(t, fields, "status")
+	assert.NotContains(t, fields, "version")
+
+	sch, err = parseSchema(db, &model.GroupClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, patch := range []map[string]json.RawMessage{
+		{"id": json.RawMessage(`1`)},
+		{"updatedAt": json.RawMessage(`null`)},
+		{"unknown": json.RawMessage(`1`)},
+		{"group_id": json.RawMessage(`1`)}, // column names are not accepted, only json names
+		{"groupId": json.RawMessage(`"one"`)},
+		{"groupId": json.RawMessage(`{"a":1}`)},
+	} {
+		_, err = patchColumns(sch, readOnlyColumns, patch)
+		assert.ErrorIs(t, err, ErrInvalidPatch, patch)
+	}
+}
+
+func TestRepository_PatchByID(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewGroupCallDao(db, cache.NewGroupCallCache(&model.CacheType{CType: "memory"}))
+
+	record := &model.GroupCall{GroupNumber: "0001", PhoneNumber: "13800000000", TransferClientID: "client"}
+	err := d.Create(ctx, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.GetByID(ctx, record.ID) // fill the cache
+	assert.NoError(t, err)
+
+	err = d.PatchByID(ctx, record.ID, 0, map[string]json.RawMessage{"transferClientId": json.RawMessage("null")})
+	assert.NoError(t, err)
+	patched, err := d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", patched.TransferClientID)
+	assert.Equal(t, "0001", patched.GroupNumber)
+
+	// an empty patch changes nothing
+	err = d.PatchByID(ctx, record.ID, 0, map[string]json.RawMessage{})
+	assert.NoError(t, err)
+
+	err = d.PatchByID(ctx, record.ID+1, 0, map[string]json.RawMessage{"phoneNumber": json.RawMessage(`""`)})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	err = d.PatchByID(ctx, record.ID+1, 0, nil)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// zero values cannot be set with UpdateByID but can with PatchByID
+	groupClientDao := NewGroupClientDao(db, nil)
+	groupClient := &model.GroupClient{GroupID: 1, ClientID: 2}
+	err = groupClientDao.Create(ctx, groupClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = groupClientDao.PatchByID(ctx, groupClient.ID, 0, map[string]json.RawMessage{"clientId": json.RawMessage("null")})
+	assert.NoError(t, err)
+	patchedGroupClient, err := groupClientDao.GetByID(ctx, groupClient.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, patchedGroupClient.ClientID)
+	assert.Equal(t, 1, patchedGroupClient.GroupID)
+}
@@ -0,0 +1,199 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// ErrReleaseClientPercent the client percents of the channels add up to more than 100
+var ErrReleaseClientPercent = errors.New("client percent of the channels exceeds 100")
+
+var _ ReleaseDao = (*releaseDao)(nil)
+
+// ReleaseDao defining the dao interface
+type ReleaseDao interface {
+	Create(ctx context.Context, table *model.AppRelease) error
+	GetByID(ctx context.Context, id uint64) (*model.AppRelease, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.AppRelease, string, error)
+	GetByVersionCode(ctx context.Context, versionCode int) (*model.AppRelease, error)
+
+	GetChannels(ctx context.Context) ([]*model.ReleaseChannel, error)
+	GetChannelByName(ctx context.Context, name string) (*model.ReleaseChannel, error)
+	UpsertChannel(ctx context.Context, name string, update *ReleaseChannelUpdate) (*model.ReleaseChannel, error)
+	GetTarget(ctx context.Context, machineCode string, assignedChannel string) (*model.ReleaseChannel, *model.AppRelease, error)
+}
+
+// ReleaseChannelUpdate the settings of a channel, nil fields are not changed
+type ReleaseChannelUpdate struct {
+	ReleaseID      *uint64
+	RolloutPercent *int
+	ClientPercent  *int
+}
+
+// apply set the fields of a channel, the release that is replaced becomes the previous release
+func (u *ReleaseChannelUpdate) apply(channel *model.ReleaseChannel) {
+	if u.ReleaseID != nil && *u.ReleaseID != channel.ReleaseID {
+		channel.PreviousReleaseID = channel.ReleaseID
+		channel.ReleaseID = *u.ReleaseID
+	}
+	if u.RolloutPercent != nil {
+		channel.RolloutPercent = *u.RolloutPercent
+	}
+	if u.ClientPercent != nil {
+		channel.ClientPercent = *u.ClientPercent
+	}
+}
+
+type releaseDao struct {
+	*Repository[model.AppRelease]
+	channels *Repository[model.ReleaseChannel] // the channels are few and read by every update check, they are not cached
+}
+
+// NewReleaseDao creating the dao interface, the releases are not cached
+func NewReleaseDao(db *gorm.DB) ReleaseDao {
+	return &releaseDao{
+		Repository: NewRepository[model.AppRelease](db, nil, 0, nil, nil, "version_code"),
+		channels:   NewRepository[model.ReleaseChannel](db, nil, 0, nil, nil),
+	}
+}
+
+// GetByVersionCode get a release by its version code
+func (d *releaseDao) GetByVersionCode(ctx context.Context, versionCode int) (*model.AppRelease, error) {
+	record := &model.AppRelease{}
+	err := d.db.WithContext(ctx).Where("version_code = ?", versionCode).First(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetChannels get all the channels, the oldest first
+func (d *releaseDao) GetChannels(ctx context.Context) ([]*model.ReleaseChannel, error) {
+	records := []*model.ReleaseChannel{}
+	err := d.channels.db.WithContext(ctx).Order("id ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetChannelByName get a channel by its name
+func (d *releaseDao) GetChannelByName(ctx context.Context, name string) (*model.ReleaseChannel, error) {
+	record := &model.ReleaseChannel{}
+	err := d.channels.db.WithContext(ctx).Where("name = ?", name).First(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// UpsertChannel create a channel or update its settings, a new channel offers its release to all its clients,
+// return ErrReleaseClientPercent if the client percents of the channels would add up to more than 100.
+func (d *releaseDao) UpsertChannel(ctx context.Context, name string, update *ReleaseChannelUpdate) (*model.ReleaseChannel, error) {
+	channel := &model.ReleaseChannel{}
+	err := d.channels.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("name = ?", name).First(channel).Error
+		if errors.Is(err, model.ErrRecordNotFound) {
+			channel = &model.ReleaseChannel{Name: name, RolloutPercent: 100}
+		} else if err != nil {
+			return err
+		}
+		update.apply(channel)
+
+		if channel.ClientPercent > 0 {
+			var others int64
+			err = tx.Model(&model.ReleaseChannel{}).Where("name <> ?", name).
+				Select("COALESCE(SUM(client_percent), 0)").Scan(&others).Error
+			if err != nil {
+				return err
+			}
+			if others+int64(channel.ClientPercent) > 100 {
+				return ErrReleaseClientPercent
+			}
+		}
+
+		return tx.Save(channel).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// GetTarget get the channel of a client and the release offered to it, the client is in the assigned channel if it exists,
+// otherwise in the client percent of a channel or in the default channel. the channel is nil if there is none,
+// the release is nil if the channel offers no release to the client.
+func (d *releaseDao) GetTarget(ctx context.Context, machineCode string, assignedChannel string) (*model.ReleaseChannel, *model.AppRelease, error) {
+	channels, err := d.GetChannels(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	channel := pickReleaseChannel(channels, assignedChannel, machineCode)
+	if channel == nil {
+		return nil, nil, nil
+	}
+	releaseID := pickReleaseID(channel, machineCode)
+	if releaseID == 0 {
+		return channel, nil, nil
+	}
+
+	release, err := d.GetByID(ctx, releaseID)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			return channel, nil, nil
+		}
+		return nil, nil, err
+	}
+	return channel, release, nil
+}
+
+// releaseBucket map a machine code to one of 100 buckets, the salt keeps the buckets of different choices independent
+func releaseBucket(salt string, machineCode string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(salt))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(machineCode))
+	return int(h.Sum32() % 100)
+}
+
+// pickReleaseChannel the channel of a client, the channels with a client percent share the buckets in their order
+func pickReleaseChannel(channels []*model.ReleaseChannel, assignedChannel string, machineCode string) *model.ReleaseChannel {
+	var defaultChannel *model.ReleaseChannel
+	for _, channel := range channels {
+		if assignedChannel != "" && channel.Name == assignedChannel {
+			return channel
+		}
+		if channel.Name == model.DefaultReleaseChannel {
+			defaultChannel = channel
+		}
+	}
+
+	bucket := releaseBucket("channel", machineCode)
+	lower := 0
+	for _, channel := range channels {
+		if channel.ClientPercent <= 0 {
+			continue
+		}
+		if bucket < lower+channel.ClientPercent {
+			return channel
+		}
+		lower += channel.ClientPercent
+	}
+	return defaultChannel
+}
+
+// pickReleaseID the release of a channel offered to a client, the clients outside the rollout are offered the previous release,
+// the buckets depend on the release, so each release is rolled out to a different sample of the clients.
+func pickReleaseID(channel *model.ReleaseChannel, machineCode string) uint64 {
+	if channel.RolloutPercent >= 100 ||
+		releaseBucket(strconv.FormatUint(channel.ReleaseID, 10), machineCode) < channel.RolloutPercent {
+		return channel.ReleaseID
+	}
+	return channel.PreviousReleaseID
+}
@@ -0,0 +1,177 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func Test_releaseDao(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewReleaseDao(db)
+
+	v1 := &model.AppRelease{VersionCode: 1, VersionName: "1.0.0", FileName: "a.apk", Checksum: "a"}
+	v2 := &model.AppRelease{VersionCode: 2, VersionName: "1.1.0", FileName: "b.apk", Checksum: "b"}
+	assert.NoError(t, d.Create(ctx, v1))
+	assert.NoError(t, d.Create(ctx, v2))
+	// the version code is unique
+	assert.Error(t, d.Create(ctx, &model.AppRelease{VersionCode: 2, VersionName: "x", FileName: "c.apk", Checksum: "c"}))
+
+	record, err := d.GetByVersionCode(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, v2.ID, record.ID)
+	_, err = d.GetByVersionCode(ctx, 3)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	records, _, err := d.GetByCursor(ctx, "", 10, "-version_code")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].VersionCode)
+
+	// no channel
+	channel, release, err := d.GetTarget(ctx, "m1", "")
+	assert.NoError(t, err)
+	assert.Nil(t, channel)
+	assert.Nil(t, release)
+
+	channel, err = d.UpsertChannel(ctx, model.DefaultReleaseChannel, &ReleaseChannelUpdate{ReleaseID: &v1.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, channel.RolloutPercent)
+	assert.Equal(t, v1.ID, channel.ReleaseID)
+	assert.Equal(t, uint64(0), channel.PreviousReleaseID)
+
+	// the replaced release becomes the previous release
+	rollout := 0
+	channel, err = d.UpsertChannel(ctx, model.DefaultReleaseChannel, &ReleaseChannelUpdate{ReleaseID: &v2.ID, RolloutPercent: &rollout})
+	assert.NoError(t, err)
+	assert.Equal(t, v2.ID, channel.ReleaseID)
+	assert.Equal(t, v1.ID, channel.PreviousReleaseID)
+	channel, release, err = d.GetTarget(ctx, "m1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.DefaultReleaseChannel, channel.Name)
+	assert.Equal(t, v1.ID, release.ID)
+
+	rollout = 100
+	_, err = d.UpsertChannel(ctx, model.DefaultReleaseChannel, &ReleaseChannelUpdate{RolloutPercent: &rollout})
+	assert.NoError(t, err)
+	_, release, err = d.GetTarget(ctx, "m1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, v2.ID, release.ID)
+
+	// a channel without release
+	percent := 60
+	_, err = d.UpsertChannel(ctx, "beta", &ReleaseChannelUpdate{ClientPercent: &percent})
+	assert.NoError(t, err)
+	channel, release, err = d.GetTarget(ctx, "m1", "beta")
+	assert.NoError(t, err)
+	assert.Equal(t, "beta", channel.Name)
+	assert.Nil(t, release)
+
+	percent = 50
+	_, err = d.UpsertChannel(ctx, "alpha", &ReleaseChannelUpdate{ClientPercent: &percent})
+	assert.ErrorIs(t, err, ErrReleaseClientPercent)
+	percent = 40
+	_, err = d.UpsertChannel(ctx, "alpha", &ReleaseChannelUpdate{ClientPercent: &percent})
+	assert.NoError(t, err)
+
+	channels, err := d.GetChannels(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, channels, 3)
+	channel, err = d.GetChannelByName(ctx, "alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, 40, channel.ClientPercent)
+}
+
+func Test_pickReleaseChannel(t *testing.T) {
+	stable := &model.ReleaseChannel{Name: model.DefaultReleaseChannel}
+	beta := &model.ReleaseChannel{Name: "beta", ClientPercent: 30}
+	channels := []*model.ReleaseChannel{stable, beta}
+
+	// an assigned client is in its channel
+	assert.Equal(t, stable, pickReleaseChannel(channels, model.DefaultReleaseChannel, "m1"))
+	assert.Equal(t, beta, pickReleaseChannel(channels, "beta", "m1"))
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		machineCode := fmt.Sprintf("m%d", i)
+		channel := pickReleaseChannel(channels, "", machineCode)
+		counts[channel.Name]++
+		// the choice is stable, a channel that does not exist is ignored
+		assert.Equal(t, channel, pickReleaseChannel(channels, "gone", machineCode))
+	}
+	assert.InDelta(t, 300, counts["beta"], 60)
+	assert.InDelta(t, 700, counts[model.DefaultReleaseChannel], 60)
+
+	// no default channel
+	assert.Nil(t, pickReleaseChannel([]*model.ReleaseChannel{{Name: "beta"}}, "", "m1"))
+}
+
+func Test_pickReleaseID(t *testing.T) {
+	channel := &model.ReleaseChannel{ReleaseID: 2, PreviousReleaseID: 1, RolloutPercent: 20}
+	offered := 0
+	for i := 0; i < 1000; i++ {
+		if pickReleaseID(channel, fmt.Sprintf("m%d", i)) == 2 {
+			offered++
+		}
+	}
+	assert.InDelta(t, 200, offered, 60)
+
+	// a client in the rollout stays in it when the rollout grows
+	for i := 0; i < 100; i++ {
+		machineCode := fmt.Sprintf("m%d", i)
+		if pickReleaseID(channel, machineCode) == 2 {
+			assert.Equal(t, uint64(2), pickReleaseID(&model.ReleaseChannel{ReleaseID: 2, PreviousReleaseID: 1, RolloutPercent: 50}, machineCode))
+		}
+	}
+
+	channel.RolloutPercent = 100
+	assert.Equal(t, uint64(2), pickReleaseID(channel, "m1"))
+}
+
+func Test_clientsDao_SetReleaseChannel(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewClientsDao(db, cache.NewClientsCache(&model.CacheType{CType: "memory"}))
+
+	c1 := &model.Clients{MachineCode: "m1"}
+	c2 := &model.Clients{MachineCode: "m2"}
+	assert.NoError(t, d.Create(ctx, c1))
+	assert.NoError(t, d.Create(ctx, c2))
+	// cache the client
+	_, err := d.GetByID(ctx, c1.ID)
+	assert.NoError(t, err)
+
+	n, err := d.SetReleaseChannel(ctx, []string{"m1", "m2", "m3"}, "beta")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	record, err := d.GetByID(ctx, c1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "beta", record.ReleaseChannel)
+	assert.Equal(t, uint64(2), record.Version)
+
+	// unchanged clients are not counted
+	n, err = d.SetReleaseChannel(ctx, []string{"m1"}, "beta")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	n, err = d.SetReleaseChannel(ctx, []string{"m1"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	record, err = d.GetByMachineCode(ctx, "m1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", record.ReleaseChannel)
+
+	n, err = d.SetReleaseChannel(ctx, nil, "beta")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
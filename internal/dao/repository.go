@@ -0,0 +1,564 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+
+	cacheBase "github.com/zhufuyi/sponge/pkg/cache"
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+// ErrVersionMismatch the version of the record is not the expected version, it was changed by another request
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// versionColumn the column that is increased by every update, see updateColumnsByID
+const versionColumn = "version"
+
+// UpdateColumnsFunc returns the columns to update for a record and their values,
+// fields that are not to be updated are left out, e.g. empty strings.
+type UpdateColumnsFunc[T any] func(table *T) map[string]interface{}
+
+// Repository is the generic dao of a table, T is a table struct in model that embeds ggorm.Model.
+// the dao of each table embeds a Repository and only declares which columns are updated.
+type Repository[T any] struct {
+	db              *gorm.DB
+	cache           cache.EntityCache[T] // if nil, the cache is not used.
+	sfg             *singleflight.Group  // if cache is nil, the sfg is not used.
+	expireTime      time.Duration        // cache expire time
+	updateColumns   UpdateColumnsFunc[T]
+	sortColumns     map[string]bool // columns that records can be paged by with a cursor
+	readOnlyColumns map[string]bool // columns that cannot be patched, they only change through the methods of the dao
+}
+
+// NewRepository creating a generic dao, readOnlyColumns are the columns besides id, the times and the version
+// that cannot be patched, sortColumns are the columns besides id, created_at and updated_at that the records
+// can be paged by with a cursor, indexed columns should be preferred.
+func NewRepository[T any](db *gorm.DB, xCache cache.EntityCache[T], expireTime time.Duration,
+	updateColumns UpdateColumnsFunc[T], readOnlyColumns []string, sortColumns ...string) *Repository[T] {
+	r := &Repository[T]{
+		db:              db,
+		expireTime:      expireTime,
+		updateColumns:   updateColumns,
+		sortColumns:     map[string]bool{},
+		readOnlyColumns: map[string]bool{},
+	}
+	for _, column := range commonSortColumns {
+		r.sortColumns[column] = true
+	}
+	for _, column := range sortColumns {
+		r.sortColumns[column] = true
+	}
+	for _, column := range commonReadOnlyColumns {
+		r.readOnlyColumns[column] = true
+	}
+	for _, column := range readOnlyColumns {
+		r.readOnlyColumns[column] = true
+	}
+	if xCache != nil {
+		r.cache = xCache
+		r.sfg = new(singleflight.Group)
+	}
+	return r
+}
+
+func (r *Repository[T]) deleteCache(ctx context.Context, id uint64) error {
+	if r.cache != nil {
+		return r.cache.Del(ctx, id)
+	}
+	return nil
+}
+
+// Create a record, insert the record and the id value is written back to the table
+func (r *Repository[T]) Create(ctx context.Context, table *T) error {
+	return r.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a record by id, if version is not 0 the record is only deleted when its version matches
+func (r *Repository[T]) DeleteByID(ctx context.Context, id uint64, version uint64) error {
+	return r.deleteByID(ctx, r.db, id, version)
+}
+
+func (r *Repository[T]) deleteByID(ctx context.Context, db *gorm.DB, id uint64, version uint64) error {
+	query := db.WithContext(ctx).Where("id = ?", id)
+	if version > 0 {
+		query = query.Where(versionColumn+" = ?", version)
+	}
+	// soft delete, the deleted_at value is generated by gorm so that it suits every database driver
+	result := query.Delete(new(T))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// delete cache
+	_ = r.deleteCache(ctx, id)
+
+	if result.RowsAffected == 0 && version > 0 {
+		return r.checkVersion(ctx, db, id)
+	}
+	return nil
+}
+
+// UpdateByID update a record by id, if the version of the table is not 0 the record is only updated
+// when its version matches, the version is increased by every update.
+func (r *Repository[T]) UpdateByID(ctx context.Context, table *T) error {
+	err := r.updateDataByID(ctx, r.db, table)
+
+	// delete cache
+	_ = r.deleteCache(ctx, model.GetID(table))
+
+	return err
+}
+
+// PatchByID apply a json merge patch (RFC 7396) to a record by id, the keys of the patch are the json names
+// of the fields, only the fields in the patch are updated and null or "" clears a field.
+// if version is not 0 the record is only updated when its version matches.
+func (r *Repository[T]) PatchByID(ctx context.Context, id uint64, version uint64, patch map[string]json.RawMessage) error {
+	err := r.patchByID(ctx, r.db, id, version, patch)
+
+	// delete cache
+	_ = r.deleteCache(ctx, id)
+
+	return err
+}
+
+func (r *Repository[T]) patchByID(ctx context.Context, db *gorm.DB, id uint64, version uint64, patch map[string]json.RawMessage) error {
+	if id < 1 {
+		return errors.New("id cannot be 0")
+	}
+	sch, err := parseSchema(r.db, new(T))
+	if err != nil {
+		return err
+	}
+	columns, err := patchColumns(sch, r.readOnlyColumns, patch)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		// nothing to update, check whether the record exists
+		return db.WithContext(ctx).Select("id").Where("id = ?", id).First(new(T)).Error
+	}
+
+	return r.updateColumnsByID(ctx, db, id, version, columns)
+}
+
+func (r *Repository[T]) updateDataByID(ctx context.Context, db *gorm.DB, table *T) error {
+	if model.GetID(table) < 1 {
+		return errors.New("id cannot be 0")
+	}
+
+	update := r.updateColumns(table)
+
+	return r.updateColumnsByID(ctx, db, model.GetID(table), model.GetVersion(table), update)
+}
+
+// updateColumnsByID update the columns of a record by id and increase its version, if version is not 0
+// the record is only updated when its version matches.
+// every table has the version column for optimistic locking: a client sends back the version of the record it read,
+// and a record that was changed meanwhile is not updated, ErrVersionMismatch is returned instead of overwriting
+// the change, so the version is increased by every update, whichever method of the dao makes it.
+func (r *Repository[T]) updateColumnsByID(ctx context.Context, db *gorm.DB, id uint64, version uint64, columns map[string]interface{}) error {
+	columns[versionColumn] = gorm.Expr(versionColumn + " + 1")
+	query := db.WithContext(ctx).Model(new(T)).Where("id = ?", id)
+	if version > 0 {
+		query = query.Where(versionColumn+" = ?", version)
+	}
+	result := query.Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.checkVersion(ctx, db, id)
+	}
+	return nil
+}
+
+// checkVersion find out why no record was changed, return model.ErrRecordNotFound if the record does not exist,
+// otherwise return ErrVersionMismatch.
+func (r *Repository[T]) checkVersion(ctx context.Context, db *gorm.DB, id uint64) error {
+	err := db.WithContext(ctx).Select("id").Where("id = ?", id).First(new(T)).Error
+	if err != nil {
+		return err
+	}
+	return ErrVersionMismatch
+}
+
+// GetByID get a record by id
+func (r *Repository[T]) GetByID(ctx context.Context, id uint64) (*T, error) {
+	// no cache
+	if r.cache == nil {
+		record := new(T)
+		err := r.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+		return record, err
+	}
+
+	// get from cache or database
+	record, err := r.cache.Get(ctx, id)
+	if err == nil {
+		return record, nil
+	}
+
+	if errors.Is(err, model.ErrCacheNotFound) {
+		// for the same id, prevent high concurrent simultaneous access to database
+		val, err, _ := r.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
+			table := new(T)
+			err = r.db.WithContext(ctx).Where("id = ?", id).First(table).Error
+			if err != nil {
+				// if data is empty, set not found cache to prevent cache penetration, default expiration time 10 minutes
+				if errors.Is(err, model.ErrRecordNotFound) {
+					err = r.cache.SetCacheWithNotFound(ctx, id)
+					if err != nil {
+						return nil, err
+					}
+					return nil, model.ErrRecordNotFound
+				}
+				return nil, err
+			}
+			// set cache
+			err = r.cache.Set(ctx, id, table, r.expireTime)
+			if err != nil {
+				return nil, fmt.Errorf("cache.Set error: %v, id=%d", err, id)
+			}
+			return table, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		table, ok := val.(*T)
+		if !ok {
+			return nil, model.ErrRecordNotFound
+		}
+		return table, nil
+	} else if errors.Is(err, cacheBase.ErrPlaceholder) {
+		return nil, model.ErrRecordNotFound
+	}
+
+	// fail fast, if cache error return, don't request to db
+	return nil, err
+}
+
+// GetByColumns get paging records by column information,
+// Note: query performance degrades when table rows are very large because of the use of offset.
+//
+// params includes paging parameters and query parameters
+// paging parameters (required):
+//
+//	page: page number, starting from 0
+//	size: lines per page
+//	sort: sort fields, default is id backwards, you can add - sign before the field to indicate reverse order, no - sign to indicate ascending order, multiple fields separated by comma
+//
+// query parameters (not required):
+//
+//	name: column name
+//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
+//	value: column value, if exp=in, multiple values are separated by commas
+//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
+//
+// example: search for a male over 20 years of age
+//
+//	params = &query.Params{
+//	    Page: 0,
+//	    Size: 20,
+//	    Columns: []query.Column{
+//		{
+//			Name:    "age",
+//			Exp: ">",
+//			Value:   20,
+//		},
+//		{
+//			Name:  "gender",
+//			Value: "male",
+//		},
+//	}
+func (r *Repository[T]) GetByColumns(ctx context.Context, params *query.Params) ([]*T, int64, error) {
+	return r.getByColumns(ctx, params)
+}
+
+// getByColumns get paging records by column information, the scopes add conditions to the queries
+func (r *Repository[T]) getByColumns(ctx context.Context, params *query.Params, scopes ...func(*gorm.DB) *gorm.DB) ([]*T, int64, error) {
+	queryStr, args, err := params.ConvertToGormConditions()
+	if err != nil {
+		return nil, 0, errors.New("query params error: " + err.Error())
+	}
+	queryStr = adaptQuery(r.db, queryStr)
+	db := r.withScopes(ctx, scopes...)
+
+	var total int64
+	if params.Sort != "ignore count" { // determine if count is required
+		err = db.Model(new(T)).Select([]string{"id"}).Where(queryStr, args...).Count(&total).Error
+		if err != nil {
+			return nil, 0, err
+		}
+		if total == 0 {
+			return nil, total, nil
+		}
+	}
+
+	records := []*T{}
+	order, limit, offset := params.ConvertToPage()
+	err = db.Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, err
+}
+
+// DeleteByIDs delete records by batch id
+func (r *Repository[T]) DeleteByIDs(ctx context.Context, ids []uint64) error {
+	err := r.db.WithContext(ctx).Where("id IN (?)", ids).Delete(new(T)).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	for _, id := range ids {
+		_ = r.deleteCache(ctx, id)
+	}
+
+	return nil
+}
+
+// GetByCondition get a record by condition
+// query conditions:
+//
+//	name: column name
+//	exp: expressions, which default is "=",  support =, !=, >, >=, <, <=, like, in
+//	value: column value, if exp=in, multiple values are separated by commas
+//	logic: logical type, defaults to and when value is null, only &(and), ||(or)
+//
+// example: find a male aged 20
+//
+//	condition = &query.Conditions{
+//	    Columns: []query.Column{
+//		{
+//			Name:    "age",
+//			Value:   20,
+//		},
+//		{
+//			Name:  "gender",
+//			Value: "male",
+//		},
+//	}
+func (r *Repository[T]) GetByCondition(ctx context.Context, c *query.Conditions) (*T, error) {
+	queryStr, args, err := c.ConvertToGorm()
+	if err != nil {
+		return nil, err
+	}
+	queryStr = adaptQuery(r.db, queryStr)
+
+	table := new(T)
+	err = r.db.WithContext(ctx).Where(queryStr, args...).First(table).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// GetByIDs get records by batch id
+func (r *Repository[T]) GetByIDs(ctx context.Context, ids []uint64) (map[uint64]*T, error) {
+	// no cache
+	if r.cache == nil {
+		var records []*T
+		err := r.db.WithContext(ctx).Where("id IN (?)", ids).Find(&records).Error
+		if err != nil {
+			return nil, err
+		}
+		itemMap := make(map[uint64]*T)
+		for _, record := range records {
+			itemMap[model.GetID(record)] = record
+		}
+		return itemMap, nil
+	}
+
+	// get form cache or database
+	itemMap, err := r.cache.MultiGet(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var missedIDs []uint64
+	for _, id := range ids {
+		_, ok := itemMap[id]
+		if !ok {
+			missedIDs = append(missedIDs, id)
+			continue
+		}
+	}
+
+	// get missed data
+	if len(missedIDs) > 0 {
+		// find the id of an active placeholder, i.e. an id that does not exist in database
+		var realMissedIDs []uint64
+		for _, id := range missedIDs {
+			_, err = r.cache.Get(ctx, id)
+			if errors.Is(err, cacheBase.ErrPlaceholder) {
+				continue
+			}
+			realMissedIDs = append(realMissedIDs, id)
+		}
+
+		if len(realMissedIDs) > 0 {
+			var missedData []*T
+			err = r.db.WithContext(ctx).Where("id IN (?)", realMissedIDs).Find(&missedData).Error
+			if err != nil {
+				return nil, err
+			}
+
+			if len(missedData) > 0 {
+				for _, data := range missedData {
+					itemMap[model.GetID(data)] = data
+				}
+				err = r.cache.MultiSet(ctx, missedData, r.expireTime)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				for _, id := range realMissedIDs {
+					_ = r.cache.SetCacheWithNotFound(ctx, id)
+				}
+			}
+		}
+	}
+
+	return itemMap, nil
+}
+
+// GetByLastID get paging records by last id and limit, sort can only be id or -id,
+// lastID 0 means the first page.
+//
+// Deprecated: use GetByCursor, which pages by any allowed column.
+func (r *Repository[T]) GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*T, error) {
+	keys, err := parseSort(sort, map[string]bool{"id": true})
+	if err != nil {
+		return nil, err
+	}
+	page := query.NewPage(0, limit, "")
+
+	db := r.db.WithContext(ctx).Order(sortOrder(keys)).Limit(page.Size())
+	if lastID > 0 {
+		queryStr, args := seekCondition(keys, []interface{}{lastID})
+		db = db.Where(queryStr, args...)
+	}
+
+	records := []*T{}
+	err = db.Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetByCursor get a page of records after the cursor, and the cursor of the next page.
+//
+//	cursor: the opaque nextCursor returned with the previous page, empty means the first page
+//	limit: lines per page
+//	sort: allowed column names separated by comma, the "-" sign before a column name indicates reverse order,
+//	      default is -id, if empty the sort of the cursor is used, otherwise it must match the cursor
+//
+// the next cursor is empty when there are no more records.
+func (r *Repository[T]) GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*T, string, error) {
+	return r.getByCursor(r.db.WithContext(ctx), cursor, limit, sort, r.sortColumns)
+}
+
+// Scope adds conditions to the queries of a list of records, e.g. to select the records by their labels
+type Scope = func(*gorm.DB) *gorm.DB
+
+// GetByColumnsWithScopes the same as GetByColumns, the records are limited by the scopes
+func (r *Repository[T]) GetByColumnsWithScopes(ctx context.Context, params *query.Params, scopes ...Scope) ([]*T, int64, error) {
+	return r.getByColumns(ctx, params, scopes...)
+}
+
+// GetByCursorWithScopes the same as GetByCursor, the records are limited by the scopes
+func (r *Repository[T]) GetByCursorWithScopes(ctx context.Context, cursor string, limit int, sort string, scopes ...Scope) ([]*T, string, error) {
+	return r.getByCursor(r.withScopes(ctx, scopes...), cursor, limit, sort, r.sortColumns)
+}
+
+// withScopes get a db with the conditions of the scopes that can be shared by several queries
+func (r *Repository[T]) withScopes(ctx context.Context, scopes ...func(*gorm.DB) *gorm.DB) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if len(scopes) == 0 {
+		return db
+	}
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+	return db.Session(&gorm.Session{})
+}
+
+// getByCursor get a page of the records selected by db after the cursor, sort can only use the allowed columns
+func (r *Repository[T]) getByCursor(db *gorm.DB, cursor string, limit int, sort string, allowed map[string]bool) ([]*T, string, error) {
+	if sort == "" && cursor != "" {
+		sort = cursorSort(cursor)
+	}
+	keys, err := parseSort(sort, allowed)
+	if err != nil {
+		return nil, "", err
+	}
+	sch, err := parseSchema(r.db, new(T))
+	if err != nil {
+		return nil, "", err
+	}
+	page := query.NewPage(0, limit, "")
+
+	// query one more record to know whether there is a next page
+	db = db.Order(sortOrder(keys)).Limit(page.Size() + 1)
+	if cursor != "" {
+		values, err := decodeCursor(sch, keys, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		queryStr, args := seekCondition(keys, values)
+		db = db.Where(queryStr, args...)
+	}
+
+	records := []*T{}
+	err = db.Find(&records).Error
+	if err != nil {
+		return nil, "", err
+	}
+	if len(records) <= page.Size() {
+		return records, "", nil
+	}
+
+	records = records[:page.Size()]
+	nextCursor, err := encodeCursor(sch, keys, records[len(records)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	return records, nextCursor, nil
+}
+
+// CreateByTx create a record in the database using the provided transaction
+func (r *Repository[T]) CreateByTx(ctx context.Context, tx *gorm.DB, table *T) (uint64, error) {
+	err := tx.WithContext(ctx).Create(table).Error
+	return model.GetID(table), err
+}
+
+// DeleteByTx delete a record by id in the database using the provided transaction,
+// if version is not 0 the record is only deleted when its version matches
+func (r *Repository[T]) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64, version uint64) error {
+	return r.deleteByID(ctx, tx, id, version)
+}
+
+// UpdateByTx update a record by id in the database using the provided transaction,
+// the version of the table is checked the same way as UpdateByID
+func (r *Repository[T]) UpdateByTx(ctx context.Context, tx *gorm.DB, table *T) error {
+	err := r.updateDataByID(ctx, tx, table)
+
+	// delete cache
+	_ = r.deleteCache(ctx, model.GetID(table))
+
+	return err
+}
@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func TestRepositoryWithMemoryCache(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	xCache := cache.NewClientsCache(&model.CacheType{CType: "memory"})
+	r := NewRepository[model.Clients](db, xCache, cache.ClientsExpireTime, updateClientsColumns, clientsReadOnlyColumns)
+
+	record := &model.Clients{MachineCode: "m1", IPAddress: "10.0.0.1"}
+	err := r.Create(ctx, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", got.MachineCode)
+
+	// updates invalidate the cache
+	err = r.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: record.ID}, IPAddress: "10.0.0.2"})
+	assert.NoError(t, err)
+	got, err = r.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", got.IPAddress)
+	assert.Equal(t, "m1", got.MachineCode)
+
+	// missing ids get a not found placeholder
+	_, err = r.GetByID(ctx, 100)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = r.GetByID(ctx, 100)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	record2 := &model.Clients{MachineCode: "m2"}
+	id2, err := r.CreateByTx(ctx, db, record2)
+	assert.NoError(t, err)
+	assert.Equal(t, record2.ID, id2)
+
+	records, err := r.GetByIDs(ctx, []uint64{record.ID, id2, 100, 101})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "m2", records[id2].MachineCode)
+
+	err = r.DeleteByIDs(ctx, []uint64{record.ID, id2})
+	assert.NoError(t, err)
+	records, err = r.GetByIDs(ctx, []uint64{record.ID, id2})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	err = r.UpdateByID(ctx, &model.Clients{})
+	assert.Error(t, err)
+}
+
+func TestRepositoryVersion(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewGroupCallDao(db, nil)
+
+	record := &model.GroupCall{GroupNumber: "0001", TransferClientID: "client"}
+	err := d.Create(ctx, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1), record.Version)
+
+	// every update increases the version
+	err = d.UpdateByID(ctx, &model.GroupCall{Model: ggorm.Model{ID: record.ID}, PhoneNumber: "13800000000"})
+	assert.NoError(t, err)
+	got, err := d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), got.Version)
+
+	// another operator edits the version they read first
+	err = d.UpdateByID(ctx, &model.GroupCall{Model: ggorm.Model{ID: record.ID}, PhoneNumber: "13900000000", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	err = d.PatchByID(ctx, record.ID, 1, map[string]json.RawMessage{"transferClientId": json.RawMessage("null")})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	err = d.UpdateByTx(ctx, db, &model.GroupCall{Model: ggorm.Model{ID: record.ID}, PhoneNumber: "13900000000", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	got, err = d.GetByID(ctx, record.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "13800000000", got.PhoneNumber)
+	assert.Equal(t, "client", got.TransferClientID)
+
+	err = d.PatchByID(ctx, record.ID, 2, map[string]json.RawMessage{"transferClientId": json.RawMessage("null")})
+	assert.NoError(t, err)
+	err = d.UpdateByID(ctx, &model.GroupCall{Model: ggorm.Model{ID: record.ID + 1}, Version: 1})
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// deletes are checked the same way
+	err = d.DeleteByID(ctx, record.ID, 2)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	err = d.DeleteByTx(ctx, db, record.ID, 2)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	err = d.DeleteByTx(ctx, db, record.ID, 3)
+	assert.NoError(t, err)
+	err = d.DeleteByID(ctx, record.ID, 3)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	err = d.DeleteByID(ctx, record.ID, 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeleteCreatedBefore delete at most limit records created before the given time, the oldest first,
+// hard deletes remove the records permanently including those already in the trash, otherwise the
+// records are soft deleted, return the number of records deleted.
+func (r *Repository[T]) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int, hard bool) (int64, error) {
+	return r.deleteCreatedBefore(ctx, before, limit, hard, nil)
+}
+
+// deleteCreatedBefore the same as DeleteCreatedBefore, the dependents of the records are deleted
+// in the same transaction if deleteDependents is not nil
+func (r *Repository[T]) deleteCreatedBefore(ctx context.Context, before time.Time, limit int, hard bool,
+	deleteDependents dependentsDeleter) (int64, error) {
+	session := func(db *gorm.DB) *gorm.DB {
+		if hard {
+			return db.Unscoped()
+		}
+		return db
+	}
+
+	var ids []uint64
+	err := session(r.db.WithContext(ctx)).Model(new(T)).Where("created_at < ?", before).Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	var deleted int64
+	remove := func(tx *gorm.DB) error {
+		result := session(tx).Where("id IN (?)", ids).Delete(new(T))
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleteDependents != nil {
+			return deleteDependents(tx, ids)
+		}
+		return nil
+	}
+	if deleteDependents == nil {
+		err = remove(r.db.WithContext(ctx))
+	} else {
+		err = r.db.WithContext(ctx).Transaction(remove)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		_ = r.deleteCache(ctx, id)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func TestRepository_DeleteCreatedBefore(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewSmsDao(db, cache.NewSmsCache(&model.CacheType{CType: "memory"}))
+
+	now := time.Now()
+	for _, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, 36 * time.Hour, time.Hour} {
+		record := &model.Sms{MachineCode: "m1"}
+		record.CreatedAt = now.Add(-age)
+		err := d.Create(ctx, record)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := now.Add(-24 * time.Hour)
+
+	// soft deletes in batches, the oldest first
+	n, err := d.DeleteCreatedBefore(ctx, before, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	_, err = d.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	_, err = d.GetByID(ctx, 3)
+	assert.NoError(t, err)
+
+	n, err = d.DeleteCreatedBefore(ctx, before, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = d.DeleteCreatedBefore(ctx, before, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	records, _, err := d.GetDeletedByCursor(ctx, "", 10, "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	// hard deletes also empty the trash
+	n, err = d.DeleteCreatedBefore(ctx, before, 10, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	records, _, err = d.GetDeletedByCursor(ctx, "", 10, "")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	_, err = d.GetByID(ctx, 4)
+	assert.NoError(t, err)
+}
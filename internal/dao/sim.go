@@ -0,0 +1,272 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+
+	"caller/internal/model"
+)
+
+var _ SimDao = (*simDao)(nil)
+
+// SimDao defining the dao interface
+type SimDao interface {
+	Create(ctx context.Context, table *model.Sim) error
+	DeleteByID(ctx context.Context, id uint64, version uint64) error
+	UpdateByID(ctx context.Context, table *model.Sim) error
+	GetByID(ctx context.Context, id uint64) (*model.Sim, error)
+	GetByColumns(ctx context.Context, params *query.Params) ([]*model.Sim, int64, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*model.Sim, string, error)
+
+	GetByICCID(ctx context.Context, iccid string) (*model.Sim, error)
+	GetByClientID(ctx context.Context, clientID uint64) ([]*model.Sim, error)
+	GetIDBySlot(ctx context.Context, machineCode string, slotIndex int) (uint64, error)
+	GetIDsBySlot(ctx context.Context, machineCodes []string, slotIndex int) (map[string]uint64, error)
+	IsInDevice(ctx context.Context, id uint64, machineCode string) (bool, error)
+	Bind(ctx context.Context, id uint64, version uint64, clientID uint64, slotIndex int) (*model.Sim, error)
+	Report(ctx context.Context, clientID uint64, sims []*model.Sim) ([]*model.Sim, error)
+	GetDuplicateNumbers(ctx context.Context, phoneNumbers []string) (map[string]bool, error)
+	GetDuplicates(ctx context.Context) ([]*model.Sim, error)
+}
+
+type simDao struct {
+	*Repository[model.Sim]
+}
+
+// NewSimDao creating the dao interface, the sims are not cached
+func NewSimDao(db *gorm.DB) SimDao {
+	return &simDao{
+		Repository: NewRepository[model.Sim](db, nil, 0, updateSimColumns, nil, simSortColumns...),
+	}
+}
+
+// simSortColumns the columns besides id, created_at and updated_at that records can be paged by
+var simSortColumns = []string{"iccid", "phone_number", "expires_at"}
+
+// updateSimColumns the columns of a record to update, empty values are not updated,
+// the client and the slot of a sim are only changed by Bind and Report.
+func updateSimColumns(table *model.Sim) map[string]interface{} {
+	update := map[string]interface{}{}
+
+	if table.PhoneNumber != "" {
+		update["phone_number"] = table.PhoneNumber
+	}
+	if table.Carrier != "" {
+		update["carrier"] = table.Carrier
+	}
+	if table.Status != "" {
+		update["status"] = table.Status
+	}
+	if table.ExpiresAt != nil {
+		update["expires_at"] = table.ExpiresAt
+	}
+
+	return update
+}
+
+// GetByICCID get a sim by its iccid, including a sim in the trash, an iccid cannot be reused by a new sim
+func (d *simDao) GetByICCID(ctx context.Context, iccid string) (*model.Sim, error) {
+	record := &model.Sim{}
+	err := d.db.WithContext(ctx).Unscoped().Where("iccid = ?", iccid).First(record).Error
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetByClientID get the sims in a client device, ordered by slot
+func (d *simDao) GetByClientID(ctx context.Context, clientID uint64) ([]*model.Sim, error) {
+	records := []*model.Sim{}
+	err := d.db.WithContext(ctx).Where("client_id = ?", clientID).Order("slot_index ASC, id ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetIDBySlot get the id of the sim in a slot of a client device, return 0 if the slot is empty or the client does not exist
+func (d *simDao) GetIDBySlot(ctx context.Context, machineCode string, slotIndex int) (uint64, error) {
+	var ids []uint64
+	err := d.db.WithContext(ctx).Model(&model.Sim{}).
+		Joins("JOIN clients ON clients.id = sim.client_id AND clients.deleted_at IS NULL").
+		Where("clients.machine_code = ? AND sim.slot_index = ?", machineCode, slotIndex).
+		Order("sim.id DESC").Limit(1).Pluck("sim.id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// GetIDsBySlot get the id of the sim in a slot of each client device, the devices whose slot is empty are not in the map
+func (d *simDao) GetIDsBySlot(ctx context.Context, machineCodes []string, slotIndex int) (map[string]uint64, error) {
+	itemMap := make(map[string]uint64)
+	if len(machineCodes) == 0 {
+		return itemMap, nil
+	}
+
+	var rows []struct {
+		MachineCode string
+		ID          uint64
+	}
+	err := d.db.WithContext(ctx).Model(&model.Sim{}).Select("clients.machine_code, sim.id").
+		Joins("JOIN clients ON clients.id = sim.client_id AND clients.deleted_at IS NULL").
+		Where("clients.machine_code IN (?) AND sim.slot_index = ?", machineCodes, slotIndex).
+		Order("sim.id ASC").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		itemMap[row.MachineCode] = row.ID // the last sim of a slot, the same as GetIDBySlot
+	}
+	return itemMap, nil
+}
+
+// IsInDevice check that a sim exists and is in the device of the client with the machine code
+func (d *simDao) IsInDevice(ctx context.Context, id uint64, machineCode string) (bool, error) {
+	var total int64
+	err := d.db.WithContext(ctx).Model(&model.Sim{}).
+		Joins("JOIN clients ON clients.id = sim.client_id AND clients.deleted_at IS NULL").
+		Where("sim.id = ? AND clients.machine_code = ?", id, machineCode).Count(&total).Error
+	if err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// Bind put a sim in a slot of a client device, the sim that was in the slot is unbound, a client id of 0 unbinds the sim.
+// if version is not 0 the sim is only bound when its version matches, return the sim after the change.
+func (d *simDao) Bind(ctx context.Context, id uint64, version uint64, clientID uint64, slotIndex int) (*model.Sim, error) {
+	if clientID == 0 {
+		slotIndex = 0
+	}
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if clientID != 0 {
+			err := vacateSimSlots(tx.Where("client_id = ? AND slot_index = ? AND id <> ?", clientID, slotIndex, id))
+			if err != nil {
+				return err
+			}
+		}
+		return d.updateColumnsByID(ctx, tx, id, version, map[string]interface{}{
+			"client_id":  clientID,
+			"slot_index": slotIndex,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.GetByID(ctx, id)
+}
+
+// Report replace the sims in a client device with the sims reported by the device, the sims are matched by iccid,
+// an unknown sim is created and a sim in the trash is restored. the phone number and the carrier of a known sim
+// are only changed if they are reported, the sims of the client that were not reported are unbound.
+// return the sims in the device after the change.
+func (d *simDao) Report(ctx context.Context, clientID uint64, sims []*model.Sim) ([]*model.Sim, error) {
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := make([]uint64, 0, len(sims))
+		for _, sim := range sims {
+			record := &model.Sim{}
+			err := tx.Unscoped().Select("id").Where("iccid = ?", sim.ICCID).First(record).Error
+			if errors.Is(err, model.ErrRecordNotFound) {
+				record = &model.Sim{
+					ICCID:       sim.ICCID,
+					PhoneNumber: sim.PhoneNumber,
+					Carrier:     sim.Carrier,
+					ClientID:    clientID,
+					SlotIndex:   sim.SlotIndex,
+					Status:      model.SimStatusActive,
+				}
+				err = tx.Create(record).Error
+				if err != nil {
+					return err
+				}
+				ids = append(ids, record.ID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			columns := map[string]interface{}{
+				"client_id":   clientID,
+				"slot_index":  sim.SlotIndex,
+				"deleted_at":  nil,
+				versionColumn: gorm.Expr(versionColumn + " + 1"),
+			}
+			if sim.PhoneNumber != "" {
+				columns["phone_number"] = sim.PhoneNumber
+			}
+			if sim.Carrier != "" {
+				columns["carrier"] = sim.Carrier
+			}
+			err = tx.Unscoped().Model(&model.Sim{}).Where("id = ?", record.ID).Updates(columns).Error
+			if err != nil {
+				return err
+			}
+			ids = append(ids, record.ID)
+		}
+
+		// the sims that are no longer in the device
+		db := tx.Where("client_id = ?", clientID)
+		if len(ids) > 0 {
+			db = db.Where("id NOT IN (?)", ids)
+		}
+		return vacateSimSlots(db)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.GetByClientID(ctx, clientID)
+}
+
+// GetDuplicateNumbers check which of the phone numbers are on the sims of two or more client devices
+func (d *simDao) GetDuplicateNumbers(ctx context.Context, phoneNumbers []string) (map[string]bool, error) {
+	itemMap := make(map[string]bool)
+	if len(phoneNumbers) == 0 {
+		return itemMap, nil
+	}
+
+	var duplicates []string
+	err := d.duplicateNumbers(ctx).Where("phone_number IN (?)", phoneNumbers).Pluck("phone_number", &duplicates).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, phoneNumber := range duplicates {
+		itemMap[phoneNumber] = true
+	}
+	return itemMap, nil
+}
+
+// GetDuplicates get the sims whose phone number is also on a sim in another client device,
+// ordered by phone number, so the sims sharing a number are next to each other
+func (d *simDao) GetDuplicates(ctx context.Context) ([]*model.Sim, error) {
+	records := []*model.Sim{}
+	err := d.db.WithContext(ctx).
+		Where("client_id <> 0 AND status <> ? AND phone_number IN (?)", model.SimStatusCancelled, d.duplicateNumbers(ctx)).
+		Order("phone_number ASC, client_id ASC, slot_index ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// duplicateNumbers the phone numbers on the sims of two or more client devices, a cancelled sim does not count,
+// its number may have been given to another sim by the carrier
+func (d *simDao) duplicateNumbers(ctx context.Context) *gorm.DB {
+	return d.db.WithContext(ctx).Model(&model.Sim{}).Select("phone_number").
+		Where("client_id <> 0 AND phone_number <> '' AND status <> ?", model.SimStatusCancelled).
+		Group("phone_number").Having("COUNT(DISTINCT client_id) > 1")
+}
+
+// vacateSimSlots unbind the sims matched by the conditions of db
+func vacateSimSlots(db *gorm.DB) error {
+	return db.Model(&model.Sim{}).Updates(map[string]interface{}{
+		"client_id":   0,
+		"slot_index":  0,
+		versionColumn: gorm.Expr(versionColumn + " + 1"),
+	}).Error
+}
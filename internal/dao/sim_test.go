@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/model"
+)
+
+func Test_simDao(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewSimDao(db)
+	clientsDao := NewClientsDao(db, nil)
+
+	for _, machineCode := range []string{"m1", "m2"} {
+		assert.NoError(t, clientsDao.Create(ctx, &model.Clients{MachineCode: machineCode}))
+	}
+
+	// the device reports its sims, unknown sims are created
+	sims, err := d.Report(ctx, 1, []*model.Sim{
+		{ICCID: "8986000000000000001", PhoneNumber: "13800000001", Carrier: "cmcc", SlotIndex: 0},
+		{ICCID: "8986000000000000002", PhoneNumber: "13800000002", SlotIndex: 1},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sims, 2)
+	assert.Equal(t, "8986000000000000001", sims[0].ICCID)
+	assert.Equal(t, model.SimStatusActive, sims[0].Status)
+	assert.Equal(t, 1, sims[1].SlotIndex)
+
+	// a sim moved to another device keeps its number and carrier if they are not reported
+	sims, err = d.Report(ctx, 2, []*model.Sim{{ICCID: "8986000000000000001", SlotIndex: 1}})
+	assert.NoError(t, err)
+	assert.Len(t, sims, 1)
+	assert.Equal(t, "13800000001", sims[0].PhoneNumber)
+	assert.Equal(t, "cmcc", sims[0].Carrier)
+	sims, err = d.GetByClientID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, sims, 1)
+
+	// a device without sims unbinds all of its sims
+	sims, err = d.Report(ctx, 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sims, 0)
+	sim, err := d.GetByICCID(ctx, "8986000000000000002")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), sim.ClientID)
+
+	// bind unbinds the sim that was in the slot
+	assert.NoError(t, d.Create(ctx, &model.Sim{ICCID: "8986000000000000003", PhoneNumber: "13800000001", Status: model.SimStatusActive}))
+	sim, err = d.Bind(ctx, 3, 0, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), sim.ClientID)
+	sims, err = d.GetByClientID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, sims, 1)
+	assert.Equal(t, "8986000000000000003", sims[0].ICCID)
+	_, err = d.Bind(ctx, 3, 1, 2, 0)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	_, err = d.Bind(ctx, 100, 0, 2, 0)
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// the same number in two devices
+	_, err = d.Bind(ctx, 1, 0, 1, 0)
+	assert.NoError(t, err)
+	itemMap, err := d.GetDuplicateNumbers(ctx, []string{"13800000001", "13800000002"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"13800000001": true}, itemMap)
+	sims, err = d.GetDuplicates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, sims, 2)
+	assert.Equal(t, []uint64{1, 2}, []uint64{sims[0].ClientID, sims[1].ClientID})
+
+	// a cancelled sim does not count
+	assert.NoError(t, d.UpdateByID(ctx, &model.Sim{Model: ggorm.Model{ID: 3}, Status: model.SimStatusCancelled}))
+	sims, err = d.GetDuplicates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, sims, 0)
+
+	// unbind
+	sim, err = d.Bind(ctx, 1, 0, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), sim.ClientID)
+	assert.Equal(t, 0, sim.SlotIndex)
+
+	// a sim in the trash is restored when it is reported
+	assert.NoError(t, d.DeleteByID(ctx, 2, 0))
+	sims, err = d.Report(ctx, 1, []*model.Sim{{ICCID: "8986000000000000002", SlotIndex: 0}})
+	assert.NoError(t, err)
+	assert.Len(t, sims, 1)
+	assert.Equal(t, uint64(2), sims[0].ID)
+}
+
+func Test_simDao_GetIDBySlot(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewSimDao(db)
+
+	assert.NoError(t, NewClientsDao(db, nil).Create(ctx, &model.Clients{MachineCode: "m1"}))
+	_, err := d.Report(ctx, 1, []*model.Sim{
+		{ICCID: "8986000000000000001", SlotIndex: 0},
+		{ICCID: "8986000000000000002", SlotIndex: 1},
+	})
+	assert.NoError(t, err)
+
+	id, err := d.GetIDBySlot(ctx, "m1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), id)
+	id, err = d.GetIDBySlot(ctx, "m1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), id)
+	id, err = d.GetIDBySlot(ctx, "m2", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), id)
+
+	ids, err := d.GetIDsBySlot(ctx, []string{"m1", "m2"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"m1": 2}, ids)
+	ids, err = d.GetIDsBySlot(ctx, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 0)
+
+	ok, err := d.IsInDevice(ctx, 2, "m1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = d.IsInDevice(ctx, 2, "m2")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = d.IsInDevice(ctx, 100, "m1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,171 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+
+	"caller/internal/migration"
+	"caller/internal/model"
+)
+
+// the methods shared by all dao interfaces, used to run the same checks against a real database
+type commonDao[T any] interface {
+	Create(ctx context.Context, table *T) error
+	DeleteByID(ctx context.Context, id uint64, version uint64) error
+	UpdateByID(ctx context.Context, table *T) error
+	GetByID(ctx context.Context, id uint64) (*T, error)
+	GetByColumns(ctx context.Context, params *query.Params) ([]*T, int64, error)
+	GetByLastID(ctx context.Context, lastID uint64, limit int, sort string) ([]*T, error)
+	GetByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*T, string, error)
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64, version uint64) error
+}
+
+func newSqliteDB(t *testing.T) *gorm.DB {
+	db, err := ggorm.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	m, err := migration.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Up(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// tag returns a letter that is unique for each record, so that like queries match exactly one record
+func tag(i int) string {
+	return string(rune('a' + i))
+}
+
+func testCommonDao[T any](t *testing.T, db *gorm.DB, d commonDao[T], newRecord func(i int) *T, getID func(*T) uint64, likeColumn string, likeValue interface{}) {
+	ctx := context.Background()
+
+	var ids []uint64
+	for i := 1; i <= 3; i++ {
+		record := newRecord(i)
+		err := d.Create(ctx, record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, getID(record))
+	}
+
+	record, err := d.GetByID(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.Equal(t, ids[0], getID(record))
+
+	err = d.UpdateByID(ctx, record)
+	assert.NoError(t, err)
+
+	records, total, err := d.GetByColumns(ctx, &query.Params{
+		Page: 0,
+		Size: 10,
+		Columns: []query.Column{
+			{Name: likeColumn, Exp: query.Like, Value: likeValue},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, records, 1)
+
+	records, err = d.GetByLastID(ctx, ids[2], 10, "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	records, err = d.GetByLastID(ctx, ids[0], 10, "id")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// page through the records by created_at, the second page uses the sort of the cursor
+	records, cursor, err := d.GetByCursor(ctx, "", 2, "-created_at")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.NotEmpty(t, cursor)
+	nextRecords, cursor, err := d.GetByCursor(ctx, cursor, 2, "")
+	assert.NoError(t, err)
+	assert.Empty(t, cursor)
+	var pagedIDs []uint64
+	for _, v := range append(records, nextRecords...) {
+		pagedIDs = append(pagedIDs, getID(v))
+	}
+	assert.Equal(t, []uint64{ids[2], ids[1], ids[0]}, pagedIDs)
+
+	err = d.DeleteByTx(ctx, db, ids[1], 0)
+	assert.NoError(t, err)
+	_, err = d.GetByID(ctx, ids[1])
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	err = d.DeleteByID(ctx, ids[2], 0)
+	assert.NoError(t, err)
+	_, total, err = d.GetByColumns(ctx, &query.Params{Page: 0, Size: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestDaoWithSqlite(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+
+	testCommonDao[model.CallHistory](t, db, NewCallHistoryDao(db, nil),
+		func(i int) *model.CallHistory {
+			return &model.CallHistory{ClientMachineCode: "client", MobileNumber: "1380000000" + tag(i), Instruction: "dial"}
+		},
+		func(v *model.CallHistory) uint64 { return v.ID }, "mobile_number", tag(1))
+
+	testCommonDao[model.Clients](t, db, NewClientsDao(db, nil),
+		func(i int) *model.Clients {
+			return &model.Clients{MachineCode: "machine" + tag(i), IPAddress: "10.0.0.1"}
+		},
+		func(v *model.Clients) uint64 { return v.ID }, "machine_code", tag(1))
+
+	testCommonDao[model.Distribution](t, db, NewDistributionDao(db, nil),
+		func(i int) *model.Distribution {
+			return &model.Distribution{UserID: i, GroupCallID: i}
+		},
+		func(v *model.Distribution) uint64 { return v.ID }, "user_id", 1)
+
+	testCommonDao[model.GroupCall](t, db, NewGroupCallDao(db, nil),
+		func(i int) *model.GroupCall {
+			return &model.GroupCall{GroupNumber: "g" + tag(i), PhoneNumber: "13800000000"}
+		},
+		func(v *model.GroupCall) uint64 { return v.ID }, "group_number", tag(1))
+
+	testCommonDao[model.GroupClient](t, db, NewGroupClientDao(db, nil),
+		func(i int) *model.GroupClient {
+			return &model.GroupClient{GroupID: i, ClientID: i}
+		},
+		func(v *model.GroupClient) uint64 { return v.ID }, "group_id", 1)
+
+	testCommonDao[model.Sms](t, db, NewSmsDao(db, nil),
+		func(i int) *model.Sms {
+			return &model.Sms{MachineCode: "machine" + tag(i), Body: "hello"}
+		},
+		func(v *model.Sms) uint64 { return v.ID }, "machine_code", tag(1))
+
+	testCommonDao[model.UnanswerdCall](t, db, NewUnanswerdCallDao(db, nil),
+		func(i int) *model.UnanswerdCall {
+			return &model.UnanswerdCall{ClientMachineCode: "machine" + tag(i)}
+		},
+		func(v *model.UnanswerdCall) uint64 { return v.ID }, "client_machine_code", tag(1))
+
+	testCommonDao[model.User](t, db, NewUserDao(db, nil),
+		func(i int) *model.User {
+			return &model.User{MachineCode: "machine" + tag(i)}
+		},
+		func(v *model.User) uint64 { return v.ID }, "machine_code", tag(1))
+}
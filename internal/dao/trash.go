@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"caller/internal/model"
+)
+
+// deletedAtColumn the column set by soft deletes, records with a deleted_at value are in the trash
+const deletedAtColumn = "deleted_at"
+
+// GetDeletedByCursor get a page of soft deleted records after the cursor, and the cursor of the next page,
+// deleted_at can be used in sort besides the columns allowed by GetByCursor, the default sort is -deleted_at.
+func (r *Repository[T]) GetDeletedByCursor(ctx context.Context, cursor string, limit int, sort string) ([]*T, string, error) {
+	if sort == "" && cursor == "" {
+		sort = "-" + deletedAtColumn
+	}
+	allowed := map[string]bool{deletedAtColumn: true}
+	for column := range r.sortColumns {
+		allowed[column] = true
+	}
+
+	db := r.db.WithContext(ctx).Unscoped().Where(deletedAtColumn + " IS NOT NULL")
+	return r.getByCursor(db, cursor, limit, sort, allowed)
+}
+
+// RestoreByID restore a soft deleted record by id, return model.ErrRecordNotFound if the record is not in the trash
+func (r *Repository[T]) RestoreByID(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(new(T)).
+		Where("id = ? AND "+deletedAtColumn+" IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			deletedAtColumn: nil,
+			versionColumn:   gorm.Expr(versionColumn + " + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// delete the not found placeholder of the record in cache
+	_ = r.deleteCache(ctx, id)
+
+	if result.RowsAffected == 0 {
+		return model.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeByID permanently delete a soft deleted record by id, return model.ErrRecordNotFound if the record is not in the trash
+func (r *Repository[T]) PurgeByID(ctx context.Context, id uint64) error {
+	return r.purgeByID(ctx, id, nil)
+}
+
+// dependentsDeleter delete the records that belong to the records with the ids, in the transaction that deletes them
+type dependentsDeleter = func(tx *gorm.DB, ids []uint64) error
+
+// purgeByID the same as PurgeByID, the dependents of the record are deleted in the same transaction if deleteDependents is not nil
+func (r *Repository[T]) purgeByID(ctx context.Context, id uint64, deleteDependents dependentsDeleter) error {
+	purge := func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ? AND "+deletedAtColumn+" IS NOT NULL", id).Delete(new(T))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrRecordNotFound
+		}
+		if deleteDependents != nil {
+			return deleteDependents(tx, []uint64{id})
+		}
+		return nil
+	}
+
+	var err error
+	if deleteDependents == nil {
+		err = purge(r.db.WithContext(ctx))
+	} else {
+		err = r.db.WithContext(ctx).Transaction(purge)
+	}
+
+	// delete the not found placeholder of the record in cache
+	_ = r.deleteCache(ctx, id)
+
+	return err
+}
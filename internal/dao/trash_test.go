@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/model"
+)
+
+func TestRepositoryTrash(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+	d := NewGroupClientDao(db, cache.NewGroupClientCache(&model.CacheType{CType: "memory"}))
+
+	var ids []uint64
+	for i := 1; i <= 3; i++ {
+		record := &model.GroupClient{GroupID: 1, ClientID: i}
+		err := d.Create(ctx, record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	records, cursor, err := d.GetDeletedByCursor(ctx, "", 10, "")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Empty(t, cursor)
+
+	for _, id := range ids {
+		err = d.DeleteByID(ctx, id, 0)
+		assert.NoError(t, err)
+		_, err = d.GetByID(ctx, id) // caches a not found placeholder
+		assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	}
+
+	// the most recently deleted records come first
+	records, cursor, err = d.GetDeletedByCursor(ctx, "", 2, "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, ids[2], records[0].ID)
+	assert.True(t, records[0].DeletedAt.Valid)
+	records, cursor, err = d.GetDeletedByCursor(ctx, cursor, 2, "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, ids[0], records[0].ID)
+	assert.Empty(t, cursor)
+
+	// restore clears the placeholder, so the record can be read again
+	err = d.RestoreByID(ctx, ids[0])
+	assert.NoError(t, err)
+	record, err := d.GetByID(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, record.ClientID)
+	assert.Equal(t, uint64(2), record.Version)
+	err = d.RestoreByID(ctx, ids[0])
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	// only records in the trash can be purged
+	err = d.PurgeByID(ctx, ids[0])
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+	err = d.PurgeByID(ctx, ids[1])
+	assert.NoError(t, err)
+	var count int64
+	db.Unscoped().Model(&model.GroupClient{}).Where("id = ?", ids[1]).Count(&count)
+	assert.Equal(t, int64(0), count)
+	err = d.RestoreByID(ctx, ids[1])
+	assert.ErrorIs(t, err, model.ErrRecordNotFound)
+
+	records, _, err = d.GetDeletedByCursor(ctx, "", 10, "id")
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, ids[2], records[0].ID)
+}
@@ -21,6 +21,11 @@ var (
 	ErrGetByConditionCallHistory = errcode.NewError(callHistoryBaseCode+7, "failed to get "+callHistoryName+" details by conditions")
 	ErrListByIDsCallHistory      = errcode.NewError(callHistoryBaseCode+8, "failed to list by batch ids "+callHistoryName)
 	ErrListByLastIDCallHistory   = errcode.NewError(callHistoryBaseCode+9, "failed to list by last id "+callHistoryName)
+	ErrReportStateCallHistory    = errcode.NewError(callHistoryBaseCode+10, "failed to report state of "+callHistoryName)
+	ErrIllegalStateCallHistory   = errcode.NewError(callHistoryBaseCode+11, "illegal state transition of "+callHistoryName)
+	ErrCancelCallHistory         = errcode.NewError(callHistoryBaseCode+12, "failed to cancel "+callHistoryName)
+	ErrRescheduleCallHistory     = errcode.NewError(callHistoryBaseCode+13, "failed to reschedule "+callHistoryName)
+	ErrNotScheduledCallHistory   = errcode.NewError(callHistoryBaseCode+14, "only a scheduled "+callHistoryName+" can be cancelled or rescheduled")
 
 	// error codes are globally unique, adding 1 to the previous error code
 )
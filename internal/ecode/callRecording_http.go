@@ -0,0 +1,22 @@
+package ecode
+
+import (
+	"github.com/zhufuyi/sponge/pkg/errcode"
+)
+
+// callRecording business-level http error codes.
+// the callRecordingNO value range is 1~100, if the same error code is used, it will cause panic.
+var (
+	callRecordingNO       = 78
+	callRecordingName     = "callRecording"
+	callRecordingBaseCode = errcode.HCode(callRecordingNO)
+
+	ErrStartCallRecording    = errcode.NewError(callRecordingBaseCode+1, "failed to start the upload of "+callRecordingName)
+	ErrUploadCallRecording   = errcode.NewError(callRecordingBaseCode+2, "failed to upload "+callRecordingName)
+	ErrOffsetCallRecording   = errcode.NewError(callRecordingBaseCode+3, "upload offset does not match the uploaded size of "+callRecordingName)
+	ErrChecksumCallRecording = errcode.NewError(callRecordingBaseCode+4, "checksum of the "+callRecordingName+" does not match, upload it again")
+	ErrUploadedCallRecording = errcode.NewError(callRecordingBaseCode+5, callRecordingName+" has been uploaded")
+	ErrGetCallRecording      = errcode.NewError(callRecordingBaseCode+6, "failed to get "+callRecordingName+" details")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
@@ -21,6 +21,10 @@ var (
 	ErrGetByConditionClients = errcode.NewError(clientsBaseCode+7, "failed to get "+clientsName+" details by conditions")
 	ErrListByIDsClients      = errcode.NewError(clientsBaseCode+8, "failed to list by batch ids "+clientsName)
 	ErrListByLastIDClients   = errcode.NewError(clientsBaseCode+9, "failed to list by last id "+clientsName)
+	ErrListIPHistoryClients  = errcode.NewError(clientsBaseCode+10, "failed to list ip history of "+clientsName)
+	ErrSetStatusClients      = errcode.NewError(clientsBaseCode+11, "failed to set status of "+clientsName)
+	ErrDisabledClients       = errcode.NewError(clientsBaseCode+12, clientsName+" is disabled")
+	ErrQuarantinedClients    = errcode.NewError(clientsBaseCode+13, clientsName+" is quarantined")
 
 	// error codes are globally unique, adding 1 to the previous error code
 )
@@ -0,0 +1,21 @@
+package ecode
+
+import (
+	"github.com/zhufuyi/sponge/pkg/errcode"
+)
+
+// deviceCommand business-level http error codes.
+// the deviceCommandNO value range is 1~100, if the same error code is used, it will cause panic.
+var (
+	deviceCommandNO       = 74
+	deviceCommandName     = "deviceCommand"
+	deviceCommandBaseCode = errcode.HCode(deviceCommandNO)
+
+	ErrCreateDeviceCommand    = errcode.NewError(deviceCommandBaseCode+1, "failed to create "+deviceCommandName)
+	ErrPollDeviceCommand      = errcode.NewError(deviceCommandBaseCode+2, "failed to poll "+deviceCommandName)
+	ErrAckDeviceCommand       = errcode.NewError(deviceCommandBaseCode+3, "failed to ack "+deviceCommandName)
+	ErrListDeviceCommand      = errcode.NewError(deviceCommandBaseCode+4, "failed to list of "+deviceCommandName)
+	ErrRedeliverDeviceCommand = errcode.NewError(deviceCommandBaseCode+5, "failed to redeliver "+deviceCommandName)
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
@@ -21,6 +21,8 @@ var (
 	ErrGetByConditionGroupCall = errcode.NewError(groupCallBaseCode+7, "failed to get "+groupCallName+" details by conditions")
 	ErrListByIDsGroupCall      = errcode.NewError(groupCallBaseCode+8, "failed to list by batch ids "+groupCallName)
 	ErrListByLastIDGroupCall   = errcode.NewError(groupCallBaseCode+9, "failed to list by last id "+groupCallName)
+	ErrDialGroupCall           = errcode.NewError(groupCallBaseCode+10, "failed to dial by "+groupCallName)
+	ErrNoClientGroupCall       = errcode.NewError(groupCallBaseCode+11, "no eligible client in "+groupCallName)
 
 	// error codes are globally unique, adding 1 to the previous error code
 )
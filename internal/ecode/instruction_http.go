@@ -0,0 +1,22 @@
+package ecode
+
+import (
+	"github.com/zhufuyi/sponge/pkg/errcode"
+)
+
+// instruction business-level http error codes.
+// the instructionNO value range is 1~100, if the same error code is used, it will cause panic.
+var (
+	instructionNO       = 77
+	instructionName     = "instruction"
+	instructionBaseCode = errcode.HCode(instructionNO)
+
+	ErrUnknownInstruction      = errcode.NewError(instructionBaseCode+1, "unknown "+instructionName)
+	ErrMobileNumberInstruction = errcode.NewError(instructionBaseCode+2, "mobile number is required by the "+instructionName)
+	ErrPayloadInstruction      = errcode.NewError(instructionBaseCode+3, "invalid payload of "+instructionName)
+	ErrTargetInstruction       = errcode.NewError(instructionBaseCode+4, "invalid transfer target of "+instructionName)
+	ErrDigitsInstruction       = errcode.NewError(instructionBaseCode+5, "invalid dtmf digits of "+instructionName)
+	ErrMutedInstruction        = errcode.NewError(instructionBaseCode+6, "muted is required by the "+instructionName)
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
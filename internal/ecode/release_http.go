@@ -0,0 +1,26 @@
+package ecode
+
+import (
+	"github.com/zhufuyi/sponge/pkg/errcode"
+)
+
+// release business-level http error codes.
+// the releaseNO value range is 1~100, if the same error code is used, it will cause panic.
+var (
+	releaseNO       = 75
+	releaseName     = "release"
+	releaseBaseCode = errcode.HCode(releaseNO)
+
+	ErrUploadRelease            = errcode.NewError(releaseBaseCode+1, "failed to upload "+releaseName)
+	ErrGetByIDRelease           = errcode.NewError(releaseBaseCode+2, "failed to get "+releaseName+" details")
+	ErrListRelease              = errcode.NewError(releaseBaseCode+3, "failed to list of "+releaseName)
+	ErrChecksumRelease          = errcode.NewError(releaseBaseCode+4, "checksum of the "+releaseName+" file does not match")
+	ErrUpsertReleaseChannel     = errcode.NewError(releaseBaseCode+5, "failed to save the "+releaseName+" channel")
+	ErrClientPercentRelease     = errcode.NewError(releaseBaseCode+6, "client percent of the "+releaseName+" channels exceeds 100")
+	ErrListReleaseChannel       = errcode.NewError(releaseBaseCode+7, "failed to list of "+releaseName+" channels")
+	ErrSetReleaseChannelClients = errcode.NewError(releaseBaseCode+8, "failed to assign the clients to the "+releaseName+" channel")
+	ErrCheckUpdateRelease       = errcode.NewError(releaseBaseCode+9, "failed to check the update of the app")
+	ErrAppVersionUnsupported    = errcode.NewError(releaseBaseCode+10, "app version is no longer supported, update the app")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
@@ -0,0 +1,25 @@
+package ecode
+
+import (
+	"github.com/zhufuyi/sponge/pkg/errcode"
+)
+
+// sim business-level http error codes.
+// the simNO value range is 1~100, if the same error code is used, it will cause panic.
+var (
+	simNO       = 76
+	simName     = "sim"
+	simBaseCode = errcode.HCode(simNO)
+
+	ErrCreateSim     = errcode.NewError(simBaseCode+1, "failed to create "+simName)
+	ErrDeleteByIDSim = errcode.NewError(simBaseCode+2, "failed to delete "+simName)
+	ErrUpdateByIDSim = errcode.NewError(simBaseCode+3, "failed to update "+simName)
+	ErrGetByIDSim    = errcode.NewError(simBaseCode+4, "failed to get "+simName+" details")
+	ErrListSim       = errcode.NewError(simBaseCode+5, "failed to list of "+simName)
+
+	ErrListByLastIDSim = errcode.NewError(simBaseCode+6, "failed to list by last id "+simName)
+	ErrBindSim         = errcode.NewError(simBaseCode+7, "failed to bind "+simName)
+	ErrReportSim       = errcode.NewError(simBaseCode+8, "failed to report "+simName)
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
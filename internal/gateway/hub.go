@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrHubClosed the hub is shut down, no session can be added
+var ErrHubClosed = errors.New("hub is closed")
+
+// DefaultHub the sessions of the devices connected to the http server of the process
+var DefaultHub = NewHub()
+
+// Hub the registry of the sessions keyed by the machine code of the device, a device has at most one session
+type Hub struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	wg       sync.WaitGroup // the sessions that have not been removed
+	closed   bool
+}
+
+// NewHub new a hub
+func NewHub() *Hub {
+	return &Hub{sessions: map[string]*Session{}}
+}
+
+// Add register a session, the previous session of the device is closed
+func (h *Hub) Add(s *Session) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrHubClosed
+	}
+	if previous, ok := h.sessions[s.MachineCode]; ok {
+		previous.Close(websocket.ClosePolicyViolation, "replaced by a new connection")
+	}
+	h.sessions[s.MachineCode] = s
+	h.wg.Add(1)
+	return nil
+}
+
+// Remove unregister a session after it ended, every added session must be removed once
+func (h *Hub) Remove(s *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions[s.MachineCode] == s {
+		delete(h.sessions, s.MachineCode)
+	}
+	h.wg.Done()
+}
+
+// Get the session of a device, nil if the device is not connected
+func (h *Hub) Get(machineCode string) *Session {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sessions[machineCode]
+}
+
+// Len the number of connected devices
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+// Shutdown close every session and wait until they are removed or ctx is done, no session can be added afterwards
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for _, s := range h.sessions {
+		s.Close(websocket.CloseGoingAway, "server shutdown")
+	}
+	h.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
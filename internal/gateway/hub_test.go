@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer a server that registers a session for each connection, the messages read are sent back
+func newTestServer(t *testing.T, hub *Hub) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		s := NewSession(conn, r.URL.Query().Get("machineCode"))
+		if err = hub.Add(s); err != nil {
+			_ = conn.Close()
+			return
+		}
+		defer hub.Remove(s)
+		s.Run(func(data []byte) { _ = s.Send(map[string]string{"echo": string(data)}) }, func() {})
+	}))
+}
+
+func dial(t *testing.T, server *httptest.Server, machineCode string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/?machineCode=" + machineCode
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met")
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	server := newTestServer(t, hub)
+	defer server.Close()
+
+	conn := dial(t, server, "m1")
+	defer conn.Close()
+	waitFor(t, func() bool { return hub.Len() == 1 })
+
+	// messages are read and sent
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	msg := map[string]string{}
+	assert.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "hello", msg["echo"])
+
+	// push
+	assert.NoError(t, hub.Get("m1").Send(map[string]string{"type": "push"}))
+	assert.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "push", msg["type"])
+	assert.Nil(t, hub.Get("m2"))
+
+	// a new connection of the device replaces the previous one
+	conn2 := dial(t, server, "m1")
+	defer conn2.Close()
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+	waitFor(t, func() bool { return hub.Len() == 1 })
+
+	// a connection closed by the device is removed
+	conn3 := dial(t, server, "m3")
+	waitFor(t, func() bool { return hub.Len() == 2 })
+	_ = conn3.Close()
+	waitFor(t, func() bool { return hub.Len() == 1 })
+
+	// shutdown drains the connections
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, hub.Shutdown(ctx))
+	_, _, err = conn2.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	assert.Equal(t, 0, hub.Len())
+	assert.Equal(t, ErrHubClosed, hub.Add(&Session{MachineCode: "m4"}))
+}
+
+func TestSession_Send(t *testing.T) {
+	s := &Session{send: make(chan []byte, 1), done: make(chan struct{})}
+	assert.NoError(t, s.Send("a"))
+	assert.Equal(t, ErrSendBufferFull, s.Send("b"))
+	assert.Equal(t, ErrSessionClosed, s.Send("c"))
+	assert.Error(t, s.Send(func() {}))
+}
@@ -0,0 +1,146 @@
+// Package gateway keeps the websocket connections of the devices, so messages can be pushed to them.
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait time allowed to write a message to the device
+	writeWait = 10 * time.Second
+	// pongWait a connection is dead if nothing is read from the device for this long
+	pongWait = 60 * time.Second
+	// pingPeriod send pings to the device at this period, it must be less than pongWait
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize the largest message read from the device
+	maxMessageSize = 64 << 10
+	// sendBufferSize the messages waiting to be written, a device that does not keep up is disconnected
+	sendBufferSize = 64
+)
+
+var (
+	// ErrSessionClosed the session is closed, nothing can be sent
+	ErrSessionClosed = errors.New("session is closed")
+	// ErrSendBufferFull the device does not read the messages fast enough, the session is closed
+	ErrSendBufferFull = errors.New("send buffer is full")
+)
+
+// Session the websocket connection of a device
+type Session struct {
+	MachineCode string
+
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	closeMsg  []byte // close frame written before the connection is closed
+}
+
+// NewSession new a session of the connection of a device
+func NewSession(conn *websocket.Conn, machineCode string) *Session {
+	return &Session{
+		MachineCode: machineCode,
+		conn:        conn,
+		send:        make(chan []byte, sendBufferSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// Send queue a message for the device, v is encoded as json
+func (s *Session) Send(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-s.done:
+		return ErrSessionClosed
+	default:
+	}
+	select {
+	case s.send <- data:
+		return nil
+	default:
+		s.Close(websocket.CloseTryAgainLater, "too many messages")
+		return ErrSendBufferFull
+	}
+}
+
+// Done closed when the session is closed
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close close the session with a close code and reason, the queued messages are not written
+func (s *Session) Close(code int, text string) {
+	s.closeOnce.Do(func() {
+		s.closeMsg = websocket.FormatCloseMessage(code, text)
+		close(s.done)
+	})
+}
+
+// Run read the messages of the device until the connection is closed, onMessage is called with each message
+// and onAlive each time the device answers a ping, they are called on the goroutine of Run.
+func (s *Session) Run(onMessage func(data []byte), onAlive func()) {
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		s.writeLoop()
+	}()
+
+	s.readLoop(onMessage, onAlive)
+	s.Close(websocket.CloseNormalClosure, "")
+	<-writerDone
+}
+
+func (s *Session) readLoop(onMessage func(data []byte), onAlive func()) {
+	s.conn.SetReadLimit(maxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		onAlive()
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			// closed by the device, dead, or closed by the writer
+			return
+		}
+		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+		onMessage(data)
+	}
+}
+
+func (s *Session) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = s.conn.Close()
+	}()
+
+	for {
+		select {
+		case data := <-s.send:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				s.Close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				s.Close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-s.done:
+			_ = s.conn.WriteControl(websocket.CloseMessage, s.closeMsg, time.Now().Add(writeWait))
+			return
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"caller/internal/types"
+)
+
+// newCreateBatchData summarize the results of a batch create, a record is created if it has an id
+func newCreateBatchData(results []types.CreateBatchResult) *types.CreateBatchData {
+	data := &types.CreateBatchData{Results: results}
+	for _, result := range results {
+		if result.ID > 0 {
+			data.Created++
+		}
+	}
+	data.Failed = len(results) - data.Created
+	return data
+}
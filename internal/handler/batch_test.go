@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"caller/internal/types"
+)
+
+func Test_newCreateBatchData(t *testing.T) {
+	data := newCreateBatchData([]types.CreateBatchResult{
+		{Index: 0, ID: 1},
+		{Index: 1, Error: "invalid"},
+		{Index: 2}, // valid but not created because of another record
+	})
+	assert.Equal(t, 1, data.Created)
+	assert.Equal(t, 2, data.Failed)
+	assert.Len(t, data.Results, 3)
+}
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
+	"github.com/zhufuyi/sponge/pkg/errcode"
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
 	"github.com/zhufuyi/sponge/pkg/gin/response"
 	"github.com/zhufuyi/sponge/pkg/logger"
@@ -15,6 +20,7 @@ import (
 	"caller/internal/cache"
 	"caller/internal/dao"
 	"caller/internal/ecode"
+	"caller/internal/instruction"
 	"caller/internal/model"
 	"caller/internal/types"
 )
@@ -24,8 +30,10 @@ var _ CallHistoryHandler = (*callHistoryHandler)(nil)
 // CallHistoryHandler defining the handler interface
 type CallHistoryHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,10 +41,22 @@ type CallHistoryHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
+
+	ReportState(c *gin.Context)
+
+	Cancel(c *gin.Context)
+	Reschedule(c *gin.Context)
 }
 
 type callHistoryHandler struct {
-	iDao dao.CallHistoryDao
+	iDao         dao.CallHistoryDao
+	simDao       dao.SimDao                  // find and check the sim of the client device
+	recordingDao dao.CallRecordingDao        // the recording shown in the detail of a call
+	notifier     cache.DeviceCommandNotifier // wake up the polls of the devices the instructions are queued for
 }
 
 // NewCallHistoryHandler creating the handler interface
@@ -46,12 +66,15 @@ func NewCallHistoryHandler() CallHistoryHandler {
 			model.GetDB(),
 			cache.NewCallHistoryCache(model.GetCacheType()),
 		),
+		simDao:       dao.NewSimDao(model.GetDB()),
+		recordingDao: dao.NewCallRecordingDao(model.GetDB()),
+		notifier:     cache.NewDeviceCommandNotifier(model.GetCacheType()),
 	}
 }
 
 // Create a record
 // @Summary create callHistory
-// @Description submit information to create callHistory
+// @Description submit information to create callHistory, the instruction and its payload are validated, see GET /api/v1/instructions. with scheduledAt or scheduleIn in the future the call is scheduled, its instruction is sent to the client device at that time
 // @Tags callHistory
 // @accept json
 // @Produce json
@@ -68,6 +91,13 @@ func (h *callHistoryHandler) Create(c *gin.Context) {
 		return
 	}
 
+	payload, err := checkInstruction(form.Instruction, form.MobileNumber, form.Payload, true)
+	if err != nil {
+		logger.Warn("checkInstruction error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, instructionError(err))
+		return
+	}
+
 	callHistory := &model.CallHistory{}
 	err = copier.Copy(callHistory, form)
 	if err != nil {
@@ -75,18 +105,136 @@ func (h *callHistoryHandler) Create(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	callHistory.Payload = payload
+	err = scheduleCallHistory(callHistory, form.ScheduledAt, form.ScheduleIn)
+	if err != nil {
+		logger.Warn("scheduleCallHistory error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
 
 	ctx := middleware.WrapCtx(c)
+	callHistory.SimID, err = getSimID(ctx, h.simDao, form.ClientMachineCode, form.SimID, form.SimSlot)
+	if err != nil {
+		if isSimError(err) {
+			logger.Warn("getSimID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else {
+			logger.Error("getSimID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
 	err = h.iDao.Create(ctx, callHistory)
 	if err != nil {
+		if e := inactiveClientsError(err); e != nil {
+			logger.Warn("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, e)
+			return
+		}
 		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
+	h.notifyCommands(c, callHistory)
 
 	response.Success(c, gin.H{"id": callHistory.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create callHistorys in batch
+// @Description submit at most 100 callHistorys in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param data body types.CreateCallHistorysBatchRequest true "callHistory information"
+// @Success 200 {object} types.CreateCallHistorysBatchRespond{}
+// @Router /api/v1/callHistory/batch [post]
+// @Security BearerAuth
+func (h *callHistoryHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateCallHistorysBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.CallHistory, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		payload, err := checkInstruction(form.Records[i].Instruction, form.Records[i].MobileNumber, form.Records[i].Payload, true)
+		if err != nil {
+			results[i].Error = instructionError(err).Msg()
+			continue
+		}
+		record := &model.CallHistory{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateCallHistory.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		record.Payload = payload
+		record.SimID, err = getSimID(ctx, h.simDao, form.Records[i].ClientMachineCode, form.Records[i].SimID, form.Records[i].SimSlot)
+		if err != nil {
+			if isSimError(err) {
+				results[i].Error = err.Error()
+			} else {
+				logger.Error("getSimID error", logger.Err(err), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+				results[i].Error = ecode.ErrCreateCallHistory.Msg()
+			}
+			continue
+		}
+		err = scheduleCallHistory(record, form.Records[i].ScheduledAt, form.Records[i].ScheduleIn)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		if e := inactiveClientsError(err); e != nil {
+			logger.Warn("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+			response.Error(c, e)
+			return
+		}
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateCallHistory.Msg()
+			if e := inactiveClientsError(errs[j]); e != nil {
+				results[i].Error = e.Msg()
+			}
+			continue
+		}
+		results[i].ID = records[j].ID
+		h.notifyCommands(c, records[j])
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete callHistory
 // @Description delete callHistory by id
@@ -94,6 +242,7 @@ func (h *callHistoryHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteCallHistoryByIDRespond{}
 // @Router /api/v1/callHistory/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +253,25 @@ func (h *callHistoryHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +285,7 @@ func (h *callHistoryHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateCallHistoryByIDRequest true "callHistory information"
 // @Success 200 {object} types.UpdateCallHistoryByIDRespond{}
 // @Router /api/v1/callHistory/{id} [put]
@@ -133,6 +297,12 @@ func (h *callHistoryHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateCallHistoryByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +319,86 @@ func (h *callHistoryHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	callHistory.Version = version
+	callHistory.Payload = ""
 
 	ctx := middleware.WrapCtx(c)
+	if form.Instruction != "" || !instruction.IsEmptyPayload(form.Payload) {
+		callHistory.Instruction, callHistory.Payload, isAbort = h.checkChangedInstruction(c, id, form.Instruction, form.MobileNumber, form.Payload)
+		if isAbort {
+			return
+		}
+	}
 	err = h.iDao.UpdateByID(ctx, callHistory)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch callHistory
+// @Description update the callHistory fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchCallHistoryByIDRequest true "callHistory fields to update"
+// @Success 200 {object} types.PatchCallHistoryByIDRespond{}
+// @Router /api/v1/callHistory/{id} [patch]
+// @Security BearerAuth
+func (h *callHistoryHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if h.patchInstruction(c, id, patch) {
+		return
+	}
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +413,7 @@ func (h *callHistoryHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetCallHistoryByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/callHistory/{id} [get]
 // @Security BearerAuth
 func (h *callHistoryHandler) GetByID(c *gin.Context) {
@@ -199,7 +444,10 @@ func (h *callHistoryHandler) GetByID(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
+	data.Payload = payloadJSON(callHistory.Payload)
+	data.Recording = h.getRecording(c, id)
 
+	setETag(c, callHistory.Version)
 	response.Success(c, gin.H{"callHistory": data})
 }
 
@@ -318,6 +566,7 @@ func (h *callHistoryHandler) GetByCondition(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(callHistory.ID)
+	data.Payload = payloadJSON(callHistory.Payload)
 
 	response.Success(c, gin.H{"callHistory": data})
 }
@@ -366,23 +615,65 @@ func (h *callHistoryHandler) ListByIDs(c *gin.Context) {
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of callHistorys by last id and limit
-// @Description list of callHistorys by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of callHistorys by cursor and limit
+// @Description list of callHistorys by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags callHistory
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListCallHistorysRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, request_machine_code, client_machine_code, mobile_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListCallHistorysByCursorRespond{}
 // @Router /api/v1/callHistory/list [get]
 // @Security BearerAuth
 func (h *callHistoryHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	callHistorys, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertCallHistorys(callHistorys)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDCallHistory)
+		return
 	}
+
+	response.Success(c, gin.H{
+		"callHistorys": data,
+		"nextCursor":   nextCursor,
+	})
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted callHistorys by cursor and limit
+// @Description list of deleted callHistorys by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, request_machine_code, client_machine_code, mobile_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListCallHistorysByCursorRespond{}
+// @Router /api/v1/callHistory/trash [get]
+// @Security BearerAuth
+func (h *callHistoryHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +681,14 @@ func (h *callHistoryHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	callHistorys, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	callHistorys, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -405,7 +701,247 @@ func (h *callHistoryHandler) ListByLastID(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"callHistorys": data,
+		"nextCursor":   nextCursor,
+	})
+}
+
+// RestoreByID restore a deleted record by id
+// @Summary restore callHistory
+// @Description restore a deleted callHistory by id
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreCallHistoryByIDRespond{}
+// @Router /api/v1/callHistory/{id}/restore [post]
+// @Security BearerAuth
+func (h *callHistoryHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge callHistory
+// @Description permanently delete a callHistory in the trash by id with its recording, it cannot be restored afterwards
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeCallHistoryByIDRespond{}
+// @Router /api/v1/callHistory/trash/{id} [delete]
+// @Security BearerAuth
+func (h *callHistoryHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// ReportState report a change of the state of a call
+// @Summary report the state of a call
+// @Description the client device reports a change of the state of a call, a call moves from requested to dispatched when its command is delivered, then to ringing, answered and one of the final states ended, failed or busy. reporting the current state again is not an error, a change the call cannot make, e.g. ended before dispatched, is rejected
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id"
+// @Param data body types.ReportCallHistoryStateRequest true "state of the call"
+// @Success 200 {object} types.ReportCallHistoryStateRespond{}
+// @Router /api/v1/devices/{machineCode}/callHistory/{id}/state [post]
+// @Security BearerAuth
+func (h *callHistoryHandler) ReportState(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form := &types.ReportCallHistoryStateRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, isAbort = getDeviceCallHistory(c, h.iDao, id, machineCode)
+	if isAbort {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	at := time.Now()
+	if form.At != nil && form.At.Before(at) {
+		at = *form.At
+	}
+	callHistory, err := h.iDao.Transit(ctx, id, &dao.CallTransition{
+		State:         form.State,
+		At:            at,
+		TalkDuration:  form.TalkDuration,
+		FailureReason: form.FailureReason,
+	})
+	if err != nil {
+		if errors.Is(err, dao.ErrIllegalCallTransition) {
+			logger.Warn("Transit error", logger.Err(err), logger.Any("id", id), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrIllegalStateCallHistory)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Transit not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Transit error", logger.Err(err), logger.Any("id", id), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertCallHistory(callHistory)
+	if err != nil {
+		response.Error(c, ecode.ErrReportStateCallHistory)
+		return
+	}
+
+	response.Success(c, gin.H{"callHistory": data})
+}
+
+// Cancel cancel a scheduled call
+// @Summary cancel a scheduled call
+// @Description cancel a call that is scheduled, its instruction is never sent to the client device. cancelling a cancelled call again is not an error, a call that has been requested cannot be cancelled
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.CancelCallHistoryRespond{}
+// @Router /api/v1/callHistory/{id}/cancel [post]
+// @Security BearerAuth
+func (h *callHistoryHandler) Cancel(c *gin.Context) {
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	callHistory, err := h.iDao.Transit(ctx, id, &dao.CallTransition{
+		State: model.CallStateCancelled,
+		At:    time.Now(),
 	})
+	if err != nil {
+		if errors.Is(err, dao.ErrIllegalCallTransition) {
+			logger.Warn("Transit error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrNotScheduledCallHistory)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Transit not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Transit error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertCallHistory(callHistory)
+	if err != nil {
+		response.Error(c, ecode.ErrCancelCallHistory)
+		return
+	}
+
+	response.Success(c, gin.H{"callHistory": data})
+}
+
+// Reschedule move a scheduled call to another time
+// @Summary reschedule a scheduled call
+// @Description change the time the instruction of a scheduled call is sent to the client device, a time that is not in the future sends it at the next run of the scheduler. a call that has been requested or cancelled cannot be rescheduled
+// @Tags callHistory
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.RescheduleCallHistoryRequest true "time of the call"
+// @Success 200 {object} types.RescheduleCallHistoryRespond{}
+// @Router /api/v1/callHistory/{id}/schedule [put]
+// @Security BearerAuth
+func (h *callHistoryHandler) Reschedule(c *gin.Context) {
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form := &types.RescheduleCallHistoryRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	scheduledAt, err := getScheduledAt(form.ScheduledAt, form.ScheduleIn)
+	if err == nil && scheduledAt == nil {
+		err = errors.New("scheduledAt or scheduleIn is required")
+	}
+	if err != nil {
+		logger.Warn("getScheduledAt error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	callHistory, err := h.iDao.Reschedule(ctx, id, *scheduledAt)
+	if err != nil {
+		if errors.Is(err, dao.ErrCallNotScheduled) {
+			logger.Warn("Reschedule error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrNotScheduledCallHistory)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Reschedule not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Reschedule error", logger.Err(err), logger.Any("id", id), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertCallHistory(callHistory)
+	if err != nil {
+		response.Error(c, ecode.ErrRescheduleCallHistory)
+		return
+	}
+
+	response.Success(c, gin.H{"callHistory": data})
 }
 
 func getCallHistoryIDFromPath(c *gin.Context) (string, uint64, bool) {
@@ -419,6 +955,43 @@ func getCallHistoryIDFromPath(c *gin.Context) (string, uint64, bool) {
 	return idStr, id, false
 }
 
+// getRecording get the recording of a call, nil if the call has no recording or it cannot be read
+func (h *callHistoryHandler) getRecording(c *gin.Context, id uint64) *types.CallRecordingObjDetail {
+	recording, err := h.recordingDao.GetByCallHistoryID(middleware.WrapCtx(c), id)
+	if err != nil {
+		if !errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByCallHistoryID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		}
+		return nil
+	}
+	data, err := convertCallRecording(recording)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// getDeviceCallHistory get the call of the id placed by the client device, isAbort is true if the respond has been written,
+// the call of another device is not found
+func getDeviceCallHistory(c *gin.Context, d dao.CallHistoryDao, id uint64, machineCode string) (*model.CallHistory, bool) {
+	callHistory, err := d.GetByID(middleware.WrapCtx(c), id)
+	if err == nil && callHistory.ClientMachineCode != machineCode {
+		// the call of another device is not disclosed
+		err = model.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	return callHistory, false
+}
+
 func convertCallHistory(callHistory *model.CallHistory) (*types.CallHistoryObjDetail, error) {
 	data := &types.CallHistoryObjDetail{}
 	err := copier.Copy(data, callHistory)
@@ -427,6 +1000,11 @@ func convertCallHistory(callHistory *model.CallHistory) (*types.CallHistoryObjDe
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(callHistory.ID)
+	data.Payload = payloadJSON(callHistory.Payload)
+	if callHistory.DeletedAt.Valid {
+		deletedAt := callHistory.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
@@ -442,3 +1020,165 @@ func convertCallHistorys(fromValues []*model.CallHistory) ([]*types.CallHistoryO
 
 	return toValues, nil
 }
+
+// getScheduledAt get the time of a call from the scheduledAt or the scheduleIn of a request, nil if neither is set,
+// return an error if both are set or scheduleIn is not a positive duration
+func getScheduledAt(scheduledAt *time.Time, scheduleIn string) (*time.Time, error) {
+	if scheduleIn == "" {
+		return scheduledAt, nil
+	}
+	if scheduledAt != nil {
+		return nil, errors.New("scheduledAt and scheduleIn cannot be used together")
+	}
+	d, err := time.ParseDuration(scheduleIn)
+	if err != nil {
+		return nil, err
+	}
+	if d <= 0 {
+		return nil, errors.New("scheduleIn must be a positive duration")
+	}
+	at := time.Now().Add(d)
+	return &at, nil
+}
+
+// scheduleCallHistory schedule a call that is about to be created if its time is in the future,
+// the instruction of a scheduled call is queued by the scheduler at that time
+func scheduleCallHistory(callHistory *model.CallHistory, scheduledAt *time.Time, scheduleIn string) error {
+	at, err := getScheduledAt(scheduledAt, scheduleIn)
+	if err != nil {
+		return err
+	}
+	if at == nil || !at.After(time.Now()) {
+		// the call is requested now
+		callHistory.ScheduledAt = nil
+		return nil
+	}
+	callHistory.State = model.CallStateScheduled
+	callHistory.ScheduledAt = at
+	return nil
+}
+
+// inactiveClientsError the error code of a call that is rejected because its client is disabled or quarantined,
+// nil if err has another cause
+func inactiveClientsError(err error) *errcode.Error {
+	switch {
+	case errors.Is(err, model.ErrClientsQuarantined):
+		return ecode.ErrQuarantinedClients
+	case errors.Is(err, model.ErrClientsDisabled):
+		return ecode.ErrDisabledClients
+	}
+	return nil
+}
+
+// notifyCommands wake up the polls of the client device if a command was queued for the instruction of the call history
+func (h *callHistoryHandler) notifyCommands(c *gin.Context, callHistory *model.CallHistory) {
+	if callHistory.Instruction == "" || callHistory.ClientMachineCode == "" || callHistory.State == model.CallStateScheduled {
+		return
+	}
+	notifyDeviceCommand(c, h.notifier, callHistory.ClientMachineCode)
+}
+
+// checkChangedInstruction validate the instruction of a call history after an update, the empty instruction and
+// mobile number of the update are the ones of the record, and the payload of the record is kept if the instruction
+// is not changed and the update has no payload. return the instruction and the normalized payload to store.
+func (h *callHistoryHandler) checkChangedInstruction(c *gin.Context, id uint64, name string, mobileNumber string,
+	payload json.RawMessage) (string, string, bool) {
+	callHistory, err := h.iDao.GetByID(middleware.WrapCtx(c), id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return "", "", true
+	}
+
+	if name == "" {
+		name = callHistory.Instruction
+	}
+	if mobileNumber == "" {
+		mobileNumber = callHistory.MobileNumber
+	}
+	if instruction.IsEmptyPayload(payload) && name == callHistory.Instruction {
+		payload = payloadJSON(callHistory.Payload)
+	}
+	normalized, err := checkInstruction(name, mobileNumber, payload, true)
+	if err != nil {
+		logger.Warn("checkInstruction error", logger.Err(err), logger.Any("id", id), logger.String("instruction", name), middleware.GCtxRequestIDField(c))
+		response.Error(c, instructionError(err))
+		return "", "", true
+	}
+	return name, normalized, false
+}
+
+// patchInstruction validate the instruction and the payload of a patch, they are checked again when the patch changes
+// the mobile number, which an instruction may require. the payload in the patch is replaced with the normalized payload
+// as a json string, the column it is stored in. a patch that changes the instruction without a payload clears the
+// payload. isAbort is true if the patch is rejected.
+func (h *callHistoryHandler) patchInstruction(c *gin.Context, id uint64, patch map[string]json.RawMessage) bool {
+	rawName, hasName := patch["instruction"]
+	rawPayload, hasPayload := patch["payload"]
+	_, hasMobileNumber := patch["mobileNumber"]
+	if !hasName && !hasPayload && !hasMobileNumber {
+		return false
+	}
+
+	var name, mobileNumber *string
+	if hasName {
+		name = new(string)
+		if err := unmarshalPatchValue(rawName, name); err != nil {
+			logger.Warn("instruction of patch error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return true
+		}
+	}
+	if rawMobileNumber, ok := patch["mobileNumber"]; ok {
+		mobileNumber = new(string)
+		if err := unmarshalPatchValue(rawMobileNumber, mobileNumber); err != nil {
+			logger.Warn("mobileNumber of patch error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return true
+		}
+	}
+
+	callHistory, err := h.iDao.GetByID(middleware.WrapCtx(c), id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return true
+	}
+	if name == nil {
+		name = &callHistory.Instruction
+	}
+	if mobileNumber == nil {
+		mobileNumber = &callHistory.MobileNumber
+	}
+	if !hasPayload && *name == callHistory.Instruction {
+		rawPayload = payloadJSON(callHistory.Payload)
+	}
+
+	payload, err := checkInstruction(*name, *mobileNumber, rawPayload, true)
+	if err != nil {
+		logger.Warn("checkInstruction error", logger.Err(err), logger.Any("id", id), logger.String("instruction", *name), middleware.GCtxRequestIDField(c))
+		response.Error(c, instructionError(err))
+		return true
+	}
+	patch["instruction"], _ = json.Marshal(*name)
+	patch["payload"], _ = json.Marshal(payload)
+	return false
+}
+
+// unmarshalPatchValue decode the value of a field of a json merge patch, null is the zero value
+func unmarshalPatchValue(raw json.RawMessage, v interface{}) error {
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/zhufuyi/sponge/pkg/ggorm"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
 	"github.com/zhufuyi/sponge/pkg/gohttp"
 	"github.com/zhufuyi/sponge/pkg/gotest"
@@ -16,6 +20,7 @@ import (
 
 	"caller/internal/cache"
 	"caller/internal/dao"
+	"caller/internal/ecode"
 	"caller/internal/model"
 	"caller/internal/types"
 )
@@ -40,7 +45,11 @@ func newCallHistoryHandler() *gotest.Handler {
 
 	// init mock handler
 	h := gotest.NewHandler(d, testData)
-	h.IHandler = &callHistoryHandler{iDao: d.IDao.(dao.CallHistoryDao)}
+	h.IHandler = &callHistoryHandler{
+		iDao:         d.IDao.(dao.CallHistoryDao),
+		recordingDao: dao.NewCallRecordingDao(d.DB),
+		notifier:     cache.NewDeviceCommandNotifier(&model.CacheType{}),
+	}
 	iHandler := h.IHandler.(CallHistoryHandler)
 
 	testFns := []gotest.RouterInfo{
@@ -50,6 +59,12 @@ func newCallHistoryHandler() *gotest.Handler {
 			Path:        "/callHistory",
 			HandlerFunc: iHandler.Create,
 		},
+		{
+			FuncName:    "CreateBatch",
+			Method:      http.MethodPost,
+			Path:        "/callHistory/batch",
+			HandlerFunc: iHandler.CreateBatch,
+		},
 		{
 			FuncName:    "DeleteByID",
 			Method:      http.MethodDelete,
@@ -62,6 +77,12 @@ func newCallHistoryHandler() *gotest.Handler {
 			Path:        "/callHistory/:id",
 			HandlerFunc: iHandler.UpdateByID,
 		},
+		{
+			FuncName:    "PatchByID",
+			Method:      http.MethodPatch,
+			Path:        "/callHistory/:id",
+			HandlerFunc: iHandler.PatchByID,
+		},
 		{
 			FuncName:    "GetByID",
 			Method:      http.MethodGet,
@@ -98,6 +119,24 @@ func newCallHistoryHandler() *gotest.Handler {
 			Path:        "/callHistory/list",
 			HandlerFunc: iHandler.ListByLastID,
 		},
+		{
+			FuncName:    "ListTrash",
+			Method:      http.MethodGet,
+			Path:        "/callHistory/trash",
+			HandlerFunc: iHandler.ListTrash,
+		},
+		{
+			FuncName:    "RestoreByID",
+			Method:      http.MethodPost,
+			Path:        "/callHistory/:id/restore",
+			HandlerFunc: iHandler.RestoreByID,
+		},
+		{
+			FuncName:    "PurgeByID",
+			Method:      http.MethodDelete,
+			Path:        "/callHistory/trash/:id",
+			HandlerFunc: iHandler.PurgeByID,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -129,15 +168,50 @@ func Test_callHistoryHandler_Create(t *testing.T) {
 
 }
 
+func Test_callHistoryHandler_CreateBatch(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := &types.CreateCallHistorysBatchRequest{
+		Atomic:  true,
+		Records: []types.CreateCallHistoryRequest{{}, {}},
+	}
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, result.Code)
+
+	// empty batch
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), &types.CreateCallHistorysBatchRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the whole transaction fails
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	assert.Error(t, err)
+}
+
 func Test_callHistoryHandler_DeleteByID(t *testing.T) {
 	h := newCallHistoryHandler()
 	defer h.Close()
 	testData := h.TestData.(*model.CallHistory)
-	expectedSQLForDeletion := "DELETE .*"
+	expectedSQLForDeletion := "UPDATE .*"
+	expectedArgsForDeletionTime := h.MockDao.AnyTime
 
 	h.MockDao.SQLMock.ExpectBegin()
 	h.MockDao.SQLMock.ExpectExec(expectedSQLForDeletion).
-		WithArgs(testData.ID). // adjusted for the amount of test data
+		WithArgs(expectedArgsForDeletionTime, testData.ID). // adjusted for the amount of test data
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
 	h.MockDao.SQLMock.ExpectCommit()
 
@@ -189,6 +263,79 @@ func Test_callHistoryHandler_UpdateByID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_callHistoryHandler_UpdateByIDWithIfMatch(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := &types.UpdateCallHistoryByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.CallHistory))
+
+	// the record has been changed by another request
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+
+	req := &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `"1"`)
+	req.SetJSONBody(testData)
+	resp, err := req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	_, _ = resp.ReadBody()
+
+	// invalid If-Match error test
+	req = &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `W/"1"`)
+	req.SetJSONBody(testData)
+	resp, err = req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &gohttp.StdResult{}
+	err = resp.BindJSON(result)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+}
+
+func Test_callHistoryHandler_PatchByID(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.CallHistory)
+
+	// the instruction of the record is checked with the patched mobile number
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"mobileNumber": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// read-only field error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+
+	// patch error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", 111), map[string]interface{}{"mobileNumber": nil})
+	assert.Error(t, err)
+}
+
 func Test_callHistoryHandler_GetByID(t *testing.T) {
 	h := newCallHistoryHandler()
 	defer h.Close()
@@ -384,6 +531,8 @@ func Test_callHistoryHandler_ListByLastID(t *testing.T) {
 	// error test
 	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"lastID": 0, "size": 10, "sort": "unknown-column"})
 	assert.Error(t, err)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"cursor": "invalid", "size": 10})
+	assert.Error(t, err)
 }
 
 func TestNewCallHistoryHandler(t *testing.T) {
@@ -392,3 +541,219 @@ func TestNewCallHistoryHandler(t *testing.T) {
 	}()
 	_ = NewCallHistoryHandler()
 }
+
+func Test_callHistoryHandler_ListTrash(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.CallHistory)
+
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"}).
+		AddRow(testData.ID, time.Now())
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// error test
+	err = gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"sort": "unknown-column"})
+	assert.Error(t, err)
+}
+
+func Test_callHistoryHandler_RestoreByID(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.CallHistory)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RestoreByID", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 0), nil)
+	assert.NoError(t, err)
+
+	// restore error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 111), nil)
+	assert.Error(t, err)
+}
+
+func Test_callHistoryHandler_PurgeByID(t *testing.T) {
+	h := newCallHistoryHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.CallHistory)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the call has no recording
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_key"}))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("PurgeByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 0))
+	assert.NoError(t, err)
+
+	// purge error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_callHistoryHandler_ReportState(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &callHistoryHandler{iDao: dao.NewCallHistoryDao(db, nil)}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/devices/:machineCode/callHistory/:id/state", h.ReportState)
+	assert.NoError(t, h.iDao.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000"}))
+
+	// a call cannot end before it is dispatched
+	result := doLabelsRequest(t, r, http.MethodPost, "/devices/m1/callHistory/1/state", `{"state":"ended"}`)
+	assert.Equal(t, ecode.ErrIllegalStateCallHistory.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/devices/m1/callHistory/1/state", `{"state":"requested"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/devices/m2/callHistory/1/state", `{"state":"dispatched"}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	for _, state := range []string{"dispatched", "ringing", "answered", "answered"} {
+		result = doLabelsRequest(t, r, http.MethodPost, "/devices/m1/callHistory/1/state", `{"state":"`+state+`"}`)
+		assert.Equal(t, 0, result.Code, result.Msg)
+	}
+	result = doLabelsRequest(t, r, http.MethodPost, "/devices/m1/callHistory/1/state", `{"state":"failed","talkDuration":12,"failureReason":"dropped"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	callHistory := result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+	assert.Equal(t, model.CallStateFailed, callHistory["state"])
+	assert.Equal(t, float64(12), callHistory["talkDuration"])
+	assert.Equal(t, "dropped", callHistory["failureReason"])
+	assert.NotNil(t, callHistory["endedAt"])
+}
+
+func Test_callHistoryHandler_Schedule(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+
+	h := &callHistoryHandler{iDao: dao.NewCallHistoryDao(db, nil), simDao: dao.NewSimDao(db), recordingDao: dao.NewCallRecordingDao(db)}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/callHistory", h.Create)
+	r.POST("/callHistory/batch", h.CreateBatch)
+	r.GET("/callHistory/:id", h.GetByID)
+	r.POST("/callHistory/:id/cancel", h.Cancel)
+	r.PUT("/callHistory/:id/schedule", h.Reschedule)
+
+	// a call in the future is scheduled, a call in the past is requested now
+	result := doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","mobileNumber":"13800000000","scheduleIn":"30m"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","scheduledAt":"2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","scheduleIn":"soon"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","scheduleIn":"1h","scheduledAt":"2030-01-01T00:00:00Z"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/batch", `{"records":[{"clientMachineCode":"m1","scheduleIn":"2h"},{"clientMachineCode":"m1","scheduleIn":"-1h"}]}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	results := result.Data.(map[string]interface{})["results"].([]interface{})
+	assert.Equal(t, float64(3), results[0].(map[string]interface{})["id"])
+	assert.NotEmpty(t, results[1].(map[string]interface{})["error"])
+
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/1", "")
+	callHistory := result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+	assert.Equal(t, model.CallStateScheduled, callHistory["state"])
+	assert.NotNil(t, callHistory["scheduledAt"])
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/2", "")
+	callHistory = result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+	assert.Equal(t, model.CallStateRequested, callHistory["state"])
+	assert.Nil(t, callHistory["scheduledAt"])
+
+	// reschedule
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/1/schedule", `{"scheduledAt":"2030-01-01T00:00:00Z"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	callHistory = result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+	assert.Contains(t, callHistory["scheduledAt"], "2030-01-01")
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/1/schedule", `{}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/2/schedule", `{"scheduleIn":"1h"}`)
+	assert.Equal(t, ecode.ErrNotScheduledCallHistory.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/100/schedule", `{"scheduleIn":"1h"}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	// cancel
+	for i := 0; i < 2; i++ {
+		result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/1/cancel", "")
+		assert.Equal(t, 0, result.Code, result.Msg)
+		callHistory = result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+		assert.Equal(t, model.CallStateCancelled, callHistory["state"])
+	}
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/1/schedule", `{"scheduleIn":"1h"}`)
+	assert.Equal(t, ecode.ErrNotScheduledCallHistory.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/2/cancel", "")
+	assert.Equal(t, ecode.ErrNotScheduledCallHistory.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/100/cancel", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+}
+
+func Test_callHistoryHandler_CreateInactiveClients(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &callHistoryHandler{iDao: dao.NewCallHistoryDao(db, nil), simDao: dao.NewSimDao(db),
+		notifier: cache.NewDeviceCommandNotifier(&model.CacheType{})}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/callHistory", h.Create)
+	r.POST("/callHistory/batch", h.CreateBatch)
+	clientsDao := dao.NewClientsDao(db, nil)
+	for machineCode, status := range map[string]string{"m1": model.ClientsStatusActive, "m2": model.ClientsStatusDisabled, "m3": model.ClientsStatusQuarantined} {
+		assert.NoError(t, clientsDao.Create(ctx, &model.Clients{MachineCode: machineCode, Status: status}))
+	}
+
+	// the instruction of a call would never be delivered to a client that is not active
+	result := doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13800000000","instruction":"dial"}`)
+	assert.Equal(t, ecode.ErrDisabledClients.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m3","mobileNumber":"13800000000","instruction":"dial"}`)
+	assert.Equal(t, ecode.ErrQuarantinedClients.Code(), result.Code)
+	// a call without an instruction is only recorded
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13800000000"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/batch",
+		`{"records":[{"clientMachineCode":"m1","mobileNumber":"13800000000","instruction":"dial"},{"clientMachineCode":"m3","mobileNumber":"13800000000","instruction":"dial"}]}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	results := result.Data.(map[string]interface{})["results"].([]interface{})
+	assert.NotEmpty(t, results[0].(map[string]interface{})["id"])
+	assert.Equal(t, ecode.ErrQuarantinedClients.Msg(), results[1].(map[string]interface{})["error"])
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/batch",
+		`{"atomic":true,"records":[{"clientMachineCode":"m1","mobileNumber":"13800000000","instruction":"dial"},{"clientMachineCode":"m2","mobileNumber":"13800000000","instruction":"dial"}]}`)
+	assert.Equal(t, ecode.ErrDisabledClients.Code(), result.Code)
+}
@@ -0,0 +1,444 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/storage"
+	"caller/internal/types"
+)
+
+// uploadOffsetHeader the header in which the device sends the offset of the chunk it uploads
+const uploadOffsetHeader = "Upload-Offset"
+
+// callRecordingContentTypes the content type of the audio of a codec
+var callRecordingContentTypes = map[string]string{
+	"aac":  "audio/aac",
+	"amr":  "audio/amr",
+	"m4a":  "audio/mp4",
+	"mp3":  "audio/mpeg",
+	"ogg":  "audio/ogg",
+	"opus": "audio/ogg",
+	"wav":  "audio/wav",
+}
+
+var _ CallRecordingHandler = (*callRecordingHandler)(nil)
+
+// CallRecordingHandler defining the handler interface
+type CallRecordingHandler interface {
+	Start(c *gin.Context)
+	GetUpload(c *gin.Context)
+	Upload(c *gin.Context)
+	Download(c *gin.Context)
+}
+
+type callRecordingHandler struct {
+	iDao           dao.CallRecordingDao
+	callHistoryDao dao.CallHistoryDao
+	storage        storage.Storage
+	maxFileSize    int64 // bytes
+}
+
+// SetRecordingStorage set the storage of the recordings by the recording config, it is called at startup
+// before the routers are created, so that a config with an unknown driver stops the service with an error.
+func SetRecordingStorage(cfg config.Recording) error {
+	if cfg.Driver == "" {
+		cfg.Driver = storage.Local
+	}
+	if cfg.StorageDir == "" {
+		cfg.StorageDir = "recordings"
+	}
+	s, err := storage.New(cfg.Driver, storage.WithDir(cfg.StorageDir))
+	if err != nil {
+		return fmt.Errorf("recording config: %w", err)
+	}
+	dao.SetRecordingStorage(s)
+	return nil
+}
+
+// NewCallRecordingHandler creating the handler interface
+func NewCallRecordingHandler() CallRecordingHandler {
+	h := &callRecordingHandler{
+		iDao: dao.NewCallRecordingDao(model.GetDB()),
+		callHistoryDao: dao.NewCallHistoryDao(
+			model.GetDB(),
+			cache.NewCallHistoryCache(model.GetCacheType()),
+		),
+		storage:     dao.GetRecordingStorage(),
+		maxFileSize: int64(config.Get().Recording.MaxFileSize) << 20,
+	}
+	if h.maxFileSize <= 0 {
+		h.maxFileSize = 100 << 20
+	}
+	return h
+}
+
+// Start start the upload of the recording of a call
+// @Summary start the upload of a call recording
+// @Description the client device starts to upload the recording of a call it placed, then uploads the audio in chunks.
+// @Description starting again with the same checksum resumes the upload from the received bytes, a different checksum
+// @Description replaces the audio that has not been uploaded completely. a recording that has been uploaded cannot be replaced
+// @Tags callRecording
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id of the call history"
+// @Param data body types.StartCallRecordingRequest true "recording information"
+// @Success 200 {object} types.StartCallRecordingRespond{}
+// @Router /api/v1/devices/{machineCode}/callHistory/{id}/recording [post]
+// @Security BearerAuth
+func (h *callRecordingHandler) Start(c *gin.Context) {
+	callHistory, isAbort := h.getDeviceCallHistory(c)
+	if isAbort {
+		return
+	}
+	form := &types.StartCallRecordingRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil || form.Size > h.maxFileSize {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.Checksum = strings.ToLower(form.Checksum)
+
+	ctx := middleware.WrapCtx(c)
+	recording, err := h.iDao.GetByCallHistoryID(ctx, callHistory.ID)
+	if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+		logger.Error("GetByCallHistoryID error", logger.Err(err), logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	if recording == nil {
+		recording = &model.CallRecording{CallHistoryID: callHistory.ID}
+	} else if recording.Status == model.CallRecordingStatusReady {
+		logger.Warn("recording has been uploaded", logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUploadedCallRecording)
+		return
+	} else if recording.Checksum == form.Checksum && recording.Size == form.Size && recording.Codec == form.Codec &&
+		recording.Duration == form.Duration {
+		// the same audio, resume the upload
+		h.respondUpload(c, recording)
+		return
+	} else {
+		// the audio that is replaced
+		err = h.storage.Delete(ctx, recording.FileKey)
+		if err != nil {
+			logger.Error("Delete recording error", logger.Err(err), logger.String("fileKey", recording.FileKey), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrStartCallRecording)
+			return
+		}
+	}
+
+	recording.FileKey = fmt.Sprintf("%d/%s", callHistory.ID, form.Checksum)
+	recording.Codec = form.Codec
+	recording.Duration = form.Duration
+	recording.Size = form.Size
+	recording.Checksum = form.Checksum
+	recording, err = h.iDao.Start(ctx, recording)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			// another upload of the call started at the same time
+			logger.Warn("Start version mismatch", logger.Err(err), logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrStartCallRecording)
+		} else {
+			logger.Error("Start error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	h.respondUpload(c, recording)
+}
+
+// GetUpload get the upload of the recording of a call
+// @Summary get the upload of a call recording
+// @Description the client device gets the status of the upload of a recording and the bytes received, to resume the upload from them
+// @Tags callRecording
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id of the call history"
+// @Produce json
+// @Success 200 {object} types.GetCallRecordingUploadRespond{}
+// @Router /api/v1/devices/{machineCode}/callHistory/{id}/recording [get]
+// @Security BearerAuth
+func (h *callRecordingHandler) GetUpload(c *gin.Context) {
+	callHistory, isAbort := h.getDeviceCallHistory(c)
+	if isAbort {
+		return
+	}
+	recording, isAbort := h.getRecording(c, callHistory.ID)
+	if isAbort {
+		return
+	}
+
+	h.respondUpload(c, recording)
+}
+
+// Upload upload a chunk of the recording of a call
+// @Summary upload a chunk of a call recording
+// @Description the client device uploads the next chunk of the audio in the body, the Upload-Offset header is the number of bytes
+// @Description received before, the received bytes are returned by the start and the get of the upload. when the last chunk is
+// @Description received the checksum of the audio is checked, if it does not match the audio is dropped and has to be uploaded again
+// @Tags callRecording
+// @accept application/octet-stream
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id of the call history"
+// @Param Upload-Offset header int true "bytes of the audio received before the chunk"
+// @Param data body string true "chunk of the audio"
+// @Success 200 {object} types.UploadCallRecordingRespond{}
+// @Router /api/v1/devices/{machineCode}/callHistory/{id}/recording/content [put]
+// @Security BearerAuth
+func (h *callRecordingHandler) Upload(c *gin.Context) {
+	callHistory, isAbort := h.getDeviceCallHistory(c)
+	if isAbort {
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		logger.Warn("invalid upload offset", logger.String(uploadOffsetHeader, c.GetHeader(uploadOffsetHeader)), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	recording, isAbort := h.getRecording(c, callHistory.ID)
+	if isAbort {
+		return
+	}
+	if recording.Status == model.CallRecordingStatusReady {
+		logger.Warn("recording has been uploaded", logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUploadedCallRecording)
+		return
+	}
+	if offset > recording.Size {
+		logger.Warn("upload offset exceeds the size", logger.Int64("offset", offset), logger.Int64("size", recording.Size), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, recording.Size-offset)
+	received, err := h.storage.Append(ctx, recording.FileKey, offset, body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, storage.ErrOffsetMismatch) {
+			logger.Warn("Append offset mismatch", logger.Int64("offset", offset), logger.Int64("received", received), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrOffsetCallRecording)
+		} else if errors.As(err, &maxBytesErr) {
+			logger.Warn("chunk exceeds the size", logger.Int64("offset", offset), logger.Int64("size", recording.Size), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else {
+			// the received bytes are kept, the device resumes from them
+			logger.Warn("Append error", logger.Err(err), logger.Int64("offset", offset), logger.Int64("received", received), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrUploadCallRecording)
+		}
+		return
+	}
+	if received < recording.Size {
+		h.respondUpload(c, recording)
+		return
+	}
+
+	checksum, err := h.checksum(c, recording.FileKey)
+	if err != nil {
+		logger.Error("checksum error", logger.Err(err), logger.String("fileKey", recording.FileKey), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUploadCallRecording)
+		return
+	}
+	if checksum != recording.Checksum {
+		logger.Warn("checksum does not match", logger.String("checksum", recording.Checksum), logger.String("computed", checksum), middleware.GCtxRequestIDField(c))
+		err = h.storage.Delete(ctx, recording.FileKey)
+		if err != nil {
+			logger.Error("Delete recording error", logger.Err(err), logger.String("fileKey", recording.FileKey), middleware.GCtxRequestIDField(c))
+		}
+		response.Error(c, ecode.ErrChecksumCallRecording)
+		return
+	}
+
+	recording, err = h.iDao.Complete(ctx, recording.ID, recording.Version)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			// the upload was restarted by the device meanwhile
+			logger.Warn("Complete version mismatch", logger.Err(err), logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrUploadCallRecording)
+		} else {
+			logger.Error("Complete error", logger.Err(err), logger.Any("callHistoryId", callHistory.ID), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	h.respondUpload(c, recording)
+}
+
+// Download download the recording of a call
+// @Summary download a call recording
+// @Description download the audio of the recording of a call that has been uploaded and not deleted, a range of the audio can be requested
+// @Description with the Range header. the X-Checksum-Sha256 header is the hex sha256 of the audio
+// @Tags callRecording
+// @Param id path string true "id of the call history"
+// @Param Range header string false "bytes of the audio to download, e.g. bytes=0-1023"
+// @Produce application/octet-stream
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Router /api/v1/callHistory/{id}/recording/download [get]
+// @Security BearerAuth
+func (h *callRecordingHandler) Download(c *gin.Context) {
+	idStr, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	// the recording of a deleted call cannot be downloaded
+	_, err := h.callHistoryDao.GetByID(middleware.WrapCtx(c), id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("callHistoryId", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("callHistoryId", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	recording, isAbort := h.getRecording(c, id)
+	if isAbort {
+		return
+	}
+	if recording.Status != model.CallRecordingStatusReady {
+		logger.Warn("recording has not been uploaded", logger.Any("callHistoryId", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.NotFound)
+		return
+	}
+
+	file, err := h.storage.Open(middleware.WrapCtx(c), recording.FileKey)
+	if err != nil {
+		logger.Error("audio of the recording is missing", logger.Err(err), logger.String("fileKey", recording.FileKey), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.NotFound)
+		return
+	}
+	defer file.Close() //nolint
+
+	contentType, ok := callRecordingContentTypes[strings.ToLower(recording.Codec)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="callHistory-%s.%s"`, idStr, recordingFileExt(recording.Codec)))
+	c.Header("X-Checksum-Sha256", recording.Checksum)
+	c.Header("ETag", `"`+recording.Checksum+`"`)
+	// serves the ranges and the conditional requests
+	http.ServeContent(c.Writer, c.Request, "", *recording.UploadedAt, file)
+}
+
+// getDeviceCallHistory get the call in the path placed by the device in the path, isAbort is true if the respond has been written
+func (h *callRecordingHandler) getDeviceCallHistory(c *gin.Context) (*model.CallHistory, bool) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return nil, true
+	}
+	_, id, isAbort := getCallHistoryIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return nil, true
+	}
+	return getDeviceCallHistory(c, h.callHistoryDao, id, machineCode)
+}
+
+// getRecording get the recording of a call, isAbort is true if the respond has been written
+func (h *callRecordingHandler) getRecording(c *gin.Context, callHistoryID uint64) (*model.CallRecording, bool) {
+	recording, err := h.iDao.GetByCallHistoryID(middleware.WrapCtx(c), callHistoryID)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByCallHistoryID not found", logger.Err(err), logger.Any("callHistoryId", callHistoryID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByCallHistoryID error", logger.Err(err), logger.Any("callHistoryId", callHistoryID), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	return recording, false
+}
+
+// respondUpload respond the recording with the bytes of the audio received
+func (h *callRecordingHandler) respondUpload(c *gin.Context, recording *model.CallRecording) {
+	received := recording.Size
+	if recording.Status != model.CallRecordingStatusReady {
+		var err error
+		received, err = h.storage.Size(middleware.WrapCtx(c), recording.FileKey)
+		if errors.Is(err, storage.ErrNotExist) {
+			received, err = 0, nil
+		}
+		if err != nil {
+			logger.Error("Size error", logger.Err(err), logger.String("fileKey", recording.FileKey), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+	}
+
+	data, err := convertCallRecording(recording)
+	if err != nil {
+		response.Error(c, ecode.ErrGetCallRecording)
+		return
+	}
+	data.Received = received
+	c.Header(uploadOffsetHeader, strconv.FormatInt(received, 10))
+	response.Success(c, gin.H{"callRecording": data})
+}
+
+// checksum compute the hex sha256 of an object of the storage
+func (h *callRecordingHandler) checksum(c *gin.Context, key string) (string, error) {
+	file, err := h.storage.Open(middleware.WrapCtx(c), key)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close() //nolint
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func convertCallRecording(recording *model.CallRecording) (*types.CallRecordingObjDetail, error) {
+	data := &types.CallRecordingObjDetail{}
+	err := copier.Copy(data, recording)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	data.ID = utils.Uint64ToStr(recording.ID)
+	if recording.Status == model.CallRecordingStatusReady {
+		data.DownloadURL = "/api/v1/callHistory/" + utils.Uint64ToStr(recording.CallHistoryID) + "/recording/download"
+	}
+	return data, nil
+}
+
+// recordingFileExt the extension of the file name of a downloaded recording, the codec if it is a known one
+func recordingFileExt(codec string) string {
+	codec = strings.ToLower(codec)
+	if _, ok := callRecordingContentTypes[codec]; ok {
+		return codec
+	}
+	return "bin"
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/gohttp"
+
+	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/storage"
+)
+
+func doRecordingUpload(t *testing.T, r *gin.Engine, path string, offset int, chunk string) *gohttp.StdResult {
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(chunk))
+	req.Header.Set(uploadOffsetHeader, strconv.Itoa(offset))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	result := &gohttp.StdResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result), w.Body.String())
+	return result
+}
+
+// uploadedRecording the recording in the data of a result
+func uploadedRecording(result *gohttp.StdResult) map[string]interface{} {
+	data, _ := result.Data.(map[string]interface{})
+	recording, _ := data["callRecording"].(map[string]interface{})
+	return recording
+}
+
+func Test_callRecordingHandler(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &callRecordingHandler{
+		iDao:           dao.NewCallRecordingDao(db),
+		callHistoryDao: dao.NewCallHistoryDao(db, nil),
+		storage:        storage.NewLocal(t.TempDir()),
+		maxFileSize:    1 << 20,
+	}
+	callHistory := &callHistoryHandler{iDao: h.callHistoryDao, recordingDao: h.iDao}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/callHistory/:id", callHistory.GetByID)
+	r.GET("/callHistory/:id/recording/download", h.Download)
+	r.POST("/devices/:machineCode/callHistory/:id/recording", h.Start)
+	r.GET("/devices/:machineCode/callHistory/:id/recording", h.GetUpload)
+	r.PUT("/devices/:machineCode/callHistory/:id/recording/content", h.Upload)
+
+	assert.NoError(t, h.callHistoryDao.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000"}))
+	audio := "#!AMR\n0123456789abcdefghij"
+	sum := sha256.Sum256([]byte(audio))
+	checksum := hex.EncodeToString(sum[:])
+	start := `{"codec":"amr","duration":1500,"size":` + strconv.Itoa(len(audio)) + `,"checksum":"` + checksum + `"}`
+	path := "/devices/m1/callHistory/1/recording"
+
+	// the call of another device and invalid recordings
+	result := doLabelsRequest(t, r, http.MethodPost, "/devices/m2/callHistory/1/recording", start)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, path, `{"codec":"amr","size":10,"checksum":"abc"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, path, `{"codec":"amr","size":2097152,"checksum":"`+checksum+`"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodGet, path, "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	// start and upload the first chunk
+	result = doLabelsRequest(t, r, http.MethodPost, path, start)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, model.CallRecordingStatusUploading, uploadedRecording(result)["status"])
+	assert.Nil(t, uploadedRecording(result)["received"])
+	result = doRecordingUpload(t, r, path+"/content", 0, audio[:10])
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, float64(10), uploadedRecording(result)["received"])
+	result = doRecordingUpload(t, r, path+"/content", 5, audio[5:])
+	assert.Equal(t, ecode.ErrOffsetCallRecording.Code(), result.Code)
+	result = doRecordingUpload(t, r, path+"/content", 10, audio[10:]+"extra")
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// the recording cannot be downloaded before the upload is complete
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/1/recording/download", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	// resume from the received bytes
+	result = doLabelsRequest(t, r, http.MethodPost, path, start)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	received := int(uploadedRecording(result)["received"].(float64))
+	assert.GreaterOrEqual(t, received, 10)
+	result = doLabelsRequest(t, r, http.MethodGet, path, "")
+	assert.Equal(t, float64(received), uploadedRecording(result)["received"])
+	result = doRecordingUpload(t, r, path+"/content", received, audio[received:])
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, model.CallRecordingStatusReady, uploadedRecording(result)["status"])
+	assert.Equal(t, float64(len(audio)), uploadedRecording(result)["received"])
+
+	// an uploaded recording cannot be replaced
+	result = doLabelsRequest(t, r, http.MethodPost, path, start)
+	assert.Equal(t, ecode.ErrUploadedCallRecording.Code(), result.Code)
+	result = doRecordingUpload(t, r, path+"/content", 0, audio)
+	assert.Equal(t, ecode.ErrUploadedCallRecording.Code(), result.Code)
+
+	// the recording is in the detail of the call
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/1", "")
+	assert.Equal(t, 0, result.Code, result.Msg)
+	recording := result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})["recording"].(map[string]interface{})
+	assert.Equal(t, "amr", recording["codec"])
+	assert.Equal(t, float64(1500), recording["duration"])
+	assert.Equal(t, float64(len(audio)), recording["size"])
+	assert.Equal(t, checksum, recording["checksum"])
+	assert.Equal(t, "/api/v1/callHistory/1/recording/download", recording["downloadUrl"])
+
+	// download the whole audio and a range of it
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callHistory/1/recording/download", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, audio, w.Body.String())
+	assert.Equal(t, "audio/amr", w.Header().Get("Content-Type"))
+	assert.Equal(t, checksum, w.Header().Get("X-Checksum-Sha256"))
+	req := httptest.NewRequest(http.MethodGet, "/callHistory/1/recording/download", nil)
+	req.Header.Set("Range", "bytes=6-9")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "0123", w.Body.String())
+	assert.Equal(t, "bytes 6-9/"+strconv.Itoa(len(audio)), w.Header().Get("Content-Range"))
+
+	// the recording of a deleted call
+	assert.NoError(t, h.callHistoryDao.DeleteByID(ctx, 1, 0))
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/1/recording/download", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/100/recording/download", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+}
+
+func Test_callRecordingHandler_checksum(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &callRecordingHandler{
+		iDao:           dao.NewCallRecordingDao(db),
+		callHistoryDao: dao.NewCallHistoryDao(db, nil),
+		storage:        storage.NewLocal(t.TempDir()),
+		maxFileSize:    1 << 20,
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/devices/:machineCode/callHistory/:id/recording", h.Start)
+	r.PUT("/devices/:machineCode/callHistory/:id/recording/content", h.Upload)
+
+	assert.NoError(t, h.callHistoryDao.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", MobileNumber: "13800000000"}))
+	path := "/devices/m1/callHistory/1/recording"
+	result := doLabelsRequest(t, r, http.MethodPost, path, `{"codec":"amr","size":4,"checksum":"`+strings.Repeat("0", 64)+`"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+
+	// the audio that does not match its checksum is dropped
+	result = doRecordingUpload(t, r, path+"/content", 0, "abcd")
+	assert.Equal(t, ecode.ErrChecksumCallRecording.Code(), result.Code)
+	size, err := h.storage.Size(ctx, "1/"+strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, storage.ErrNotExist)
+	assert.Equal(t, int64(0), size)
+
+	// another audio replaces the one that has not been uploaded
+	sum := sha256.Sum256([]byte("abcd"))
+	result = doLabelsRequest(t, r, http.MethodPost, path, `{"codec":"amr","size":4,"checksum":"`+hex.EncodeToString(sum[:])+`"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doRecordingUpload(t, r, path+"/content", 0, "abcd")
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, model.CallRecordingStatusReady, uploadedRecording(result)["status"])
+}
+
+func TestNewCallRecordingHandler(t *testing.T) {
+	defer func() {
+		recover()
+	}()
+	_ = NewCallRecordingHandler()
+}
+
+func TestSetRecordingStorage(t *testing.T) {
+	defaultStorage := dao.GetRecordingStorage()
+	defer dao.SetRecordingStorage(defaultStorage)
+
+	err := SetRecordingStorage(config.Recording{Driver: "s3"})
+	assert.ErrorIs(t, err, storage.ErrUnknownDriver)
+	assert.Equal(t, defaultStorage, dao.GetRecordingStorage())
+
+	err = SetRecordingStorage(config.Recording{StorageDir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.NotEqual(t, defaultStorage, dao.GetRecordingStorage())
+}
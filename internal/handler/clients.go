@@ -1,20 +1,29 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
 	"github.com/jinzhu/copier"
 
+	"github.com/zhufuyi/sponge/pkg/ggorm"
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
 	"github.com/zhufuyi/sponge/pkg/gin/response"
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"github.com/zhufuyi/sponge/pkg/utils"
 
 	"caller/internal/cache"
+	"caller/internal/config"
 	"caller/internal/dao"
 	"caller/internal/ecode"
+	"caller/internal/gateway"
 	"caller/internal/model"
 	"caller/internal/types"
 )
@@ -24,8 +33,17 @@ var _ ClientsHandler = (*clientsHandler)(nil)
 // ClientsHandler defining the handler interface
 type ClientsHandler interface {
 	Create(c *gin.Context)
+	Heartbeat(c *gin.Context)
+	UpsertMetadata(c *gin.Context)
+	IssueEnrollmentCode(c *gin.Context)
+	Enroll(c *gin.Context)
+	RotateCredential(c *gin.Context)
+	RevokeCredential(c *gin.Context)
+	SetStatus(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,10 +51,23 @@ type ClientsHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	ListIPHistory(c *gin.Context)
+
+	GetLabels(c *gin.Context)
+	SetLabels(c *gin.Context)
+	PatchLabels(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
 }
 
 type clientsHandler struct {
-	iDao dao.ClientsDao
+	iDao           dao.ClientsDao
+	presence       cache.ClientsPresenceCache
+	offlineTimeout time.Duration // a client is offline if its last heartbeat is older
+	hub            *gateway.Hub  // the websocket sessions of the devices
 }
 
 // NewClientsHandler creating the handler interface
@@ -46,6 +77,9 @@ func NewClientsHandler() ClientsHandler {
 			model.GetDB(),
 			cache.NewClientsCache(model.GetCacheType()),
 		),
+		presence:       cache.NewClientsPresenceCache(model.GetCacheType()),
+		offlineTimeout: getClientsOfflineTimeout(),
+		hub:            gateway.DefaultHub,
 	}
 }
 
@@ -87,6 +121,450 @@ func (h *clientsHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": clients.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create clientss in batch
+// @Description submit at most 100 clientss in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param data body types.CreateClientssBatchRequest true "clients information"
+// @Success 200 {object} types.CreateClientssBatchRespond{}
+// @Router /api/v1/clients/batch [post]
+// @Security BearerAuth
+func (h *clientsHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateClientssBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.Clients, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.Clients{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateClients.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateClients.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
+// Heartbeat report that a client is alive
+// @Summary clients heartbeat
+// @Description report that the client with the machine code is alive, the time of the heartbeat is kept as its presence and its ip address is updated
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param data body types.HeartbeatClientsRequest true "heartbeat information"
+// @Success 200 {object} types.HeartbeatClientsRespond{}
+// @Router /api/v1/clients/heartbeat [post]
+// @Security BearerAuth
+func (h *clientsHandler) Heartbeat(c *gin.Context) {
+	form := &types.HeartbeatClientsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.IPAddress == "" {
+		form.IPAddress = c.ClientIP()
+	}
+	if checkDeviceMachineCode(c, &form.MachineCode) {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.iDao.GetByMachineCode(ctx, form.MachineCode)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByMachineCode not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByMachineCode error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	if clients.IPAddress != form.IPAddress {
+		err = h.iDao.UpdateByID(ctx, &model.Clients{Model: ggorm.Model{ID: clients.ID}, IPAddress: form.IPAddress})
+		if err == nil {
+			clients, err = h.iDao.GetByID(ctx, clients.ID)
+		}
+		if err != nil {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+	}
+
+	err = h.presence.Touch(ctx, clients.MachineCode, time.Now())
+	if err != nil {
+		logger.Error("Touch presence error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertClients(clients)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDClients)
+		return
+	}
+	h.setPresence(c, data)
+
+	response.Success(c, gin.H{"clients": data})
+}
+
+// UpsertMetadata update the metadata reported by a device
+// @Summary upsert clients metadata
+// @Description update the model, versions, battery, signal, carrier and sim slots reported by a device, the client is created if the machine code does not exist. appVersionCode is taken from the X-App-Version-Code header if it is empty
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param data body types.UpsertClientsMetadataRequest true "clients metadata"
+// @Success 200 {object} types.UpsertClientsMetadataRespond{}
+// @Router /api/v1/clients/metadata [put]
+// @Security BearerAuth
+func (h *clientsHandler) UpsertMetadata(c *gin.Context) {
+	form := &types.UpsertClientsMetadataRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if checkDeviceMachineCode(c, &form.MachineCode) {
+		return
+	}
+	if form.MachineCode == "" {
+		logger.Warn("machine code is empty", middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	if form.AppVersionCode == nil {
+		if versionCode, ok := getAppVersionCode(c); ok && versionCode > 0 {
+			form.AppVersionCode = &versionCode
+		}
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.iDao.UpsertMetadata(ctx, form.MachineCode, newClientsMetadata(form))
+	if err != nil {
+		logger.Error("UpsertMetadata error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertClients(clients)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDClients)
+		return
+	}
+	h.setPresence(c, data)
+
+	response.Success(c, gin.H{"clients": data})
+}
+
+// IssueEnrollmentCode issue a one-time enrollment code
+// @Summary issue enrollment code
+// @Description issue a one-time code that a device exchanges for its credential, the code is only returned in this respond. a device that already has a credential can only use a code issued for its machine code
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param data body types.IssueClientsEnrollmentCodeRequest true "enrollment code information"
+// @Success 200 {object} types.IssueClientsEnrollmentCodeRespond{}
+// @Router /api/v1/clients/enrollmentCode [post]
+// @Security BearerAuth
+func (h *clientsHandler) IssueEnrollmentCode(c *gin.Context) {
+	form := &types.IssueClientsEnrollmentCodeRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	expiresIn := 24 * time.Hour
+	if form.ExpiresIn > 0 {
+		expiresIn = time.Duration(form.ExpiresIn) * time.Second
+	}
+
+	code, err := newEnrollmentCode()
+	if err != nil {
+		logger.Error("newEnrollmentCode error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	enrollmentCode := &model.EnrollmentCode{
+		CodeHash:    hashEnrollmentCode(code),
+		MachineCode: form.MachineCode,
+		ExpiresAt:   time.Now().Add(expiresIn),
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.CreateEnrollmentCode(ctx, enrollmentCode)
+	if err != nil {
+		logger.Error("CreateEnrollmentCode error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"enrollmentCode": code,
+		"expiresAt":      enrollmentCode.ExpiresAt,
+	})
+}
+
+// Enroll exchange an enrollment code for a device credential
+// @Summary enroll device
+// @Description exchange a one-time enrollment code and the machine code of a device for its credential, the client is created if it does not exist, a device that has a credential is only enrolled again with a code issued for its machine code, the credential is only returned in this respond
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param data body types.EnrollClientsRequest true "enrollment information"
+// @Success 200 {object} types.EnrollClientsRespond{}
+// @Router /api/v1/clients/enroll [post]
+func (h *clientsHandler) Enroll(c *gin.Context) {
+	form := &types.EnrollClientsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.IPAddress == "" {
+		form.IPAddress = c.ClientIP()
+	}
+
+	secret, secretHash, err := newDeviceCredential()
+	if err != nil {
+		logger.Error("newDeviceCredential error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.iDao.Enroll(ctx, hashEnrollmentCode(form.EnrollmentCode), form.MachineCode, form.IPAddress, secretHash)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidEnrollmentCode) {
+			logger.Warn("Enroll error", logger.Err(err), logger.String("machineCode", form.MachineCode), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.Forbidden.ToHTTPCode())
+		} else {
+			logger.Error("Enroll error", logger.Err(err), logger.String("machineCode", form.MachineCode), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertClients(clients)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDClients)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"clients":    data,
+		"credential": formatDeviceCredential(clients.ID, secret),
+	})
+}
+
+// RotateCredential replace the credential of a device
+// @Summary rotate device credential
+// @Description replace the credential of a device, the previous credential is no longer valid, a device can only rotate its own credential with device auth enabled
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RotateClientsCredentialRespond{}
+// @Router /api/v1/clients/{id}/credential/rotate [post]
+// @Security BearerAuth
+func (h *clientsHandler) RotateCredential(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	// only an authenticated device rotates its credential, the route is not authenticated when device auth is disabled
+	device := getDevice(c)
+	if device == nil {
+		logger.Warn("RotateCredential without device", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.Unauthorized.ToHTTPCode())
+		return
+	}
+	if device.ID != id {
+		logger.Warn("RotateCredential of another device", logger.Any("id", id), logger.Any("deviceID", device.ID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.Forbidden.ToHTTPCode())
+		return
+	}
+
+	secret, secretHash, err := newDeviceCredential()
+	if err != nil {
+		logger.Error("newDeviceCredential error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.SetCredential(ctx, id, secretHash)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("SetCredential not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("SetCredential error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c, gin.H{"credential": formatDeviceCredential(id, secret)})
+}
+
+// RevokeCredential revoke the credential of a device
+// @Summary revoke device credential
+// @Description revoke the credential of a device, the device has to be enrolled again with a new enrollment code
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RevokeClientsCredentialRespond{}
+// @Router /api/v1/clients/{id}/credential [delete]
+// @Security BearerAuth
+func (h *clientsHandler) RevokeCredential(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.SetCredential(ctx, id, "")
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("SetCredential not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("SetCredential error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// SetStatus set the status of a client
+// @Summary set clients status
+// @Description set a client active, disabled or quarantined with the reason, the operator is the user of the token, a client that is not active is not routed calls, is skipped in the members of its groups, and its device apis are rejected with a status error code, the websocket of the device is closed
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.SetClientsStatusRequest true "status information"
+// @Success 200 {object} types.SetClientsStatusRespond{}
+// @Router /api/v1/clients/{id}/status [put]
+// @Security BearerAuth
+func (h *clientsHandler) SetStatus(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.SetClientsStatusRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	operator := getOperator(c)
+	if operator == "" {
+		logger.Warn("SetStatus without operator", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.Unauthorized.ToHTTPCode())
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.iDao.SetStatus(ctx, id, version, form.Status, form.Reason, operator)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("SetStatus version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("SetStatus not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("SetStatus error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	logger.Info("client status set", logger.Any("id", id), logger.String("status", form.Status),
+		logger.String("reason", form.Reason), logger.String("operator", operator), middleware.GCtxRequestIDField(c))
+
+	// the connection of the device on this replica is closed, the device is rejected when it connects again
+	if !clients.IsActive() {
+		if session := h.hub.Get(clients.MachineCode); session != nil {
+			session.Close(websocket.ClosePolicyViolation, "client is "+clients.Status)
+		}
+	}
+
+	data, err := convertClients(clients)
+	if err != nil {
+		response.Error(c, ecode.ErrSetStatusClients)
+		return
+	}
+	h.setPresence(c, data)
+
+	setETag(c, clients.Version)
+	response.Success(c, gin.H{"clients": data})
+}
+
 // DeleteByID delete a record by id
 // @Summary delete clients
 // @Description delete clients by id
@@ -94,6 +572,7 @@ func (h *clientsHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteClientsByIDRespond{}
 // @Router /api/v1/clients/{id} [delete]
 // @Security BearerAuth
@@ -104,57 +583,142 @@ func (h *clientsHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.DeleteByID(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// UpdateByID update information by id
+// @Summary update clients
+// @Description update clients information by id
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.UpdateClientsByIDRequest true "clients information"
+// @Success 200 {object} types.UpdateClientsByIDRespond{}
+// @Router /api/v1/clients/{id} [put]
+// @Security BearerAuth
+func (h *clientsHandler) UpdateByID(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateClientsByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.ID = id
+
+	clients := &model.Clients{}
+	err = copier.Copy(clients, form)
+	if err != nil {
+		response.Error(c, ecode.ErrUpdateByIDClients)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	clients.Version = version
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err = h.iDao.UpdateByID(ctx, clients)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
 	response.Success(c)
 }
 
-// UpdateByID update information by id
-// @Summary update clients
-// @Description update clients information by id
+// PatchByID update the fields in the patch by id
+// @Summary patch clients
+// @Description update the clients fields present in a json merge patch (RFC 7396), null or "" clears a field
 // @Tags clients
 // @accept json
 // @Produce json
 // @Param id path string true "id"
-// @Param data body types.UpdateClientsByIDRequest true "clients information"
-// @Success 200 {object} types.UpdateClientsByIDRespond{}
-// @Router /api/v1/clients/{id} [put]
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchClientsByIDRequest true "clients fields to update"
+// @Success 200 {object} types.PatchClientsByIDRespond{}
+// @Router /api/v1/clients/{id} [patch]
 // @Security BearerAuth
-func (h *clientsHandler) UpdateByID(c *gin.Context) {
+func (h *clientsHandler) PatchByID(c *gin.Context) {
 	_, id, isAbort := getClientsIDFromPath(c)
 	if isAbort {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
 
-	form := &types.UpdateClientsByIDRequest{}
-	err := c.ShouldBindJSON(form)
-	if err != nil {
-		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
-	form.ID = id
 
-	clients := &model.Clients{}
-	err = copier.Copy(clients, form)
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
 	if err != nil {
-		response.Error(c, ecode.ErrUpdateByIDClients)
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
 		return
 	}
-	// Note: if copier.Copy cannot assign a value to a field, add it here
 
 	ctx := middleware.WrapCtx(c)
-	err = h.iDao.UpdateByID(ctx, clients)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +733,7 @@ func (h *clientsHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetClientsByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/clients/{id} [get]
 // @Security BearerAuth
 func (h *clientsHandler) GetByID(c *gin.Context) {
@@ -199,7 +764,10 @@ func (h *clientsHandler) GetByID(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
+	h.setPresence(c, data)
+	h.setLabels(c, data)
 
+	setETag(c, clients.Version)
 	response.Success(c, gin.H{"clients": data})
 }
 
@@ -209,7 +777,7 @@ func (h *clientsHandler) GetByID(c *gin.Context) {
 // @Tags clients
 // @accept json
 // @Produce json
-// @Param data body types.Params true "query parameters"
+// @Param data body types.LabelParams true "query parameters and label selector"
 // @Success 200 {object} types.ListClientssRespond{}
 // @Router /api/v1/clients/list [post]
 // @Security BearerAuth
@@ -222,8 +790,24 @@ func (h *clientsHandler) List(c *gin.Context) {
 		return
 	}
 
+	selector, isAbort := parseLabelSelector(c, form.LabelSelector)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	clientss, total, err := h.iDao.GetByColumns(ctx, &form.Params)
+	scopes := []dao.Scope{h.iDao.LabelScope(selector)}
+	if form.Online != nil {
+		machineCodes, err := h.getOnlineMachineCodes(ctx)
+		if err != nil {
+			logger.Error("getOnlineMachineCodes error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+		scopes = append(scopes, dao.MachineCodesScope(machineCodes, !*form.Online))
+	}
+	clientss, total, err := h.iDao.GetByColumnsWithScopes(ctx, &form.Params, scopes...)
 	if err != nil {
 		logger.Error("GetByColumns error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -235,6 +819,8 @@ func (h *clientsHandler) List(c *gin.Context) {
 		response.Error(c, ecode.ErrListClients)
 		return
 	}
+	h.setPresence(c, data...)
+	h.setLabels(c, data...)
 
 	response.Success(c, gin.H{
 		"clientss": data,
@@ -318,6 +904,8 @@ func (h *clientsHandler) GetByCondition(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(clients.ID)
+	h.setPresence(c, data)
+	h.setLabels(c, data)
 
 	response.Success(c, gin.H{"clients": data})
 }
@@ -360,29 +948,233 @@ func (h *clientsHandler) ListByIDs(c *gin.Context) {
 			clientss = append(clientss, record)
 		}
 	}
+	h.setPresence(c, clientss...)
+	h.setLabels(c, clientss...)
 
 	response.Success(c, gin.H{
 		"clientss": clientss,
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of clientss by last id and limit
-// @Description list of clientss by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of clientss by cursor and limit
+// @Description list of clientss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags clients
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListClientssRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, machine_code, device_model, android_version, app_version_code, battery_level, is_charging, signal_strength, carrier, sim_slot_count, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Param online query bool false "true: only online clients, false: only offline clients, empty: all clients"
+// @Param labelSelector query string false "select the clients by their labels, e.g. site=shanghai,carrier!=cmcc"
+// @Success 200 {object} types.ListClientssByCursorRespond{}
 // @Router /api/v1/clients/list [get]
 // @Security BearerAuth
 func (h *clientsHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+	onlineStr := c.Query("online")
+	online, err := strconv.ParseBool(onlineStr)
+	if err != nil && onlineStr != "" {
+		logger.Warn("ParseBool error", logger.Err(err), logger.String("online", onlineStr), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	selector, isAbort := parseLabelSelector(c, c.Query("labelSelector"))
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	scopes := []dao.Scope{h.iDao.LabelScope(selector)}
+	if onlineStr != "" {
+		machineCodes, err := h.getOnlineMachineCodes(ctx)
+		if err != nil {
+			logger.Error("getOnlineMachineCodes error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+		scopes = append(scopes, dao.MachineCodesScope(machineCodes, !online))
+	}
+	clientss, nextCursor, err := h.iDao.GetByCursorWithScopes(ctx, cursor, limit, sort, scopes...)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertClientss(clientss)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDClients)
+		return
+	}
+	h.setPresence(c, data...)
+	h.setLabels(c, data...)
+
+	response.Success(c, gin.H{
+		"clientss":   data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// ListIPHistory get the ip changes of a clients by cursor and limit
+// @Summary list of ip changes of a clients
+// @Description list of ip changes of a clients by cursor and limit, the latest change comes first, changes to another subnet and changes of many clients at the same time are flagged
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param flagged query bool false "true: only the changes flagged as subnetChanged or massChange"
+// @Success 200 {object} types.ListClientsIPHistoryRespond{}
+// @Router /api/v1/clients/{id}/ip-history [get]
+// @Security BearerAuth
+func (h *clientsHandler) ListIPHistory(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	flagged := false
+	if v := c.Query("flagged"); v != "" {
+		var err error
+		flagged, err = strconv.ParseBool(v)
+		if err != nil {
+			logger.Warn("ParseBool error: ", logger.Err(err), logger.String("flagged", v), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	ctx := middleware.WrapCtx(c)
+	_, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	records, nextCursor, err := h.iDao.GetIPHistoryByCursor(ctx, id, cursor, limit, flagged)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) {
+			logger.Warn("GetIPHistoryByCursor error", logger.Err(err), logger.String("cursor", cursor), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetIPHistoryByCursor error", logger.Err(err), logger.Any("id", id), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertClientsIPHistories(records)
+	if err != nil {
+		response.Error(c, ecode.ErrListIPHistoryClients)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"ipHistory":  data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// GetLabels get the labels of a record
+// @Summary get the labels of clients
+// @Description get the key/value labels of clients by id
+// @Tags clients
+// @Param id path string true "id"
+// @Produce json
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/clients/{id}/labels [get]
+// @Security BearerAuth
+func (h *clientsHandler) GetLabels(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	serveGetLabels(c, h.iDao, id)
+}
+
+// SetLabels replace the labels of a record
+// @Summary set the labels of clients
+// @Description replace all the key/value labels of clients by id, a key starts and ends with a letter or digit and may have - _ . / in between, a value may be empty and may not have /, both have at most 63 characters, a clients has at most 64 labels
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.SetLabelsRequest true "labels"
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/clients/{id}/labels [put]
+// @Security BearerAuth
+func (h *clientsHandler) SetLabels(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	serveSetLabels(c, h.iDao, id)
+}
+
+// PatchLabels add, change or remove some labels of a record
+// @Summary patch the labels of clients
+// @Description add or change the labels in the request, a label with a null value is removed, the other labels of the clients are kept
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.PatchLabelsRequest true "labels"
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/clients/{id}/labels [patch]
+// @Security BearerAuth
+func (h *clientsHandler) PatchLabels(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
 	}
+
+	servePatchLabels(c, h.iDao, id)
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted clientss by cursor and limit
+// @Description list of deleted clientss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, machine_code, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListClientssByCursorRespond{}
+// @Router /api/v1/clients/trash [get]
+// @Security BearerAuth
+func (h *clientsHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +1182,14 @@ func (h *clientsHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	clientss, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	clientss, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -404,10 +1201,86 @@ func (h *clientsHandler) ListByLastID(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"clientss": data,
+		"clientss":   data,
+		"nextCursor": nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore clients
+// @Description restore a deleted clients by id
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreClientsByIDRespond{}
+// @Router /api/v1/clients/{id}/restore [post]
+// @Security BearerAuth
+func (h *clientsHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge clients
+// @Description permanently delete a clients in the trash by id, it cannot be restored afterwards
+// @Tags clients
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeClientsByIDRespond{}
+// @Router /api/v1/clients/trash/{id} [delete]
+// @Security BearerAuth
+func (h *clientsHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// getOperator get the user authenticated by middleware.Auth, the name of the token or else its uid,
+// empty if the route does not use middleware.Auth
+func getOperator(c *gin.Context) string {
+	if name := c.GetString("name"); name != "" {
+		return name
+	}
+	return c.GetString("uid")
+}
+
 func getClientsIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -419,6 +1292,71 @@ func getClientsIDFromPath(c *gin.Context) (string, uint64, bool) {
 	return idStr, id, false
 }
 
+// setPresence set whether the clients are online from the time of their last heartbeat,
+// the clients are shown offline if the presence cannot be read
+func (h *clientsHandler) setPresence(c *gin.Context, clientss ...*types.ClientsObjDetail) {
+	if len(clientss) == 0 {
+		return
+	}
+	machineCodes := make([]string, 0, len(clientss))
+	for _, v := range clientss {
+		machineCodes = append(machineCodes, v.MachineCode)
+	}
+
+	lastSeen, err := h.presence.MultiGet(middleware.WrapCtx(c), machineCodes)
+	if err != nil {
+		logger.Warn("MultiGet presence error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return
+	}
+	onlineSince := time.Now().Add(-h.offlineTimeout)
+	for _, v := range clientss {
+		if lastSeenAt, ok := lastSeen[v.MachineCode]; ok {
+			v.LastSeenAt = &lastSeenAt
+			v.Online = !lastSeenAt.Before(onlineSince)
+		}
+	}
+}
+
+// setLabels set the labels of the clients, the clients are shown without labels if the labels cannot be read
+func (h *clientsHandler) setLabels(c *gin.Context, clientss ...*types.ClientsObjDetail) {
+	ids := make([]uint64, 0, len(clientss))
+	for _, v := range clientss {
+		ids = append(ids, utils.StrToUint64(v.ID))
+	}
+	itemMap := getLabelsByIDs(c, h.iDao, ids)
+	for _, v := range clientss {
+		v.Labels = itemMap[utils.StrToUint64(v.ID)]
+	}
+}
+
+// getOnlineMachineCodes get the machine codes of the clients whose last heartbeat is within the offline timeout
+func (h *clientsHandler) getOnlineMachineCodes(ctx context.Context) ([]string, error) {
+	return h.presence.GetSeenSince(ctx, time.Now().Add(-h.offlineTimeout))
+}
+
+// getClientsOfflineTimeout get the offline timeout in the config, default is 90 seconds
+func getClientsOfflineTimeout() time.Duration {
+	if timeout := config.Get().App.ClientOfflineTimeout; timeout > 0 {
+		return time.Duration(timeout) * time.Second
+	}
+	return 90 * time.Second
+}
+
+// newClientsMetadata the metadata to update from the request, the empty fields are not changed
+func newClientsMetadata(form *types.UpsertClientsMetadataRequest) *dao.ClientsMetadata {
+	return &dao.ClientsMetadata{
+		DeviceModel:    form.DeviceModel,
+		AndroidVersion: form.AndroidVersion,
+		AppVersion:     form.AppVersion,
+		AppVersionCode: form.AppVersionCode,
+		BatteryLevel:   form.BatteryLevel,
+		IsCharging:     form.IsCharging,
+		SignalStrength: form.SignalStrength,
+		Carrier:        form.Carrier,
+		SimSlotCount:   form.SimSlotCount,
+	}
+}
+
 func convertClients(clients *model.Clients) (*types.ClientsObjDetail, error) {
 	data := &types.ClientsObjDetail{}
 	err := copier.Copy(data, clients)
@@ -427,6 +1365,10 @@ func convertClients(clients *model.Clients) (*types.ClientsObjDetail, error) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(clients.ID)
+	if clients.DeletedAt.Valid {
+		deletedAt := clients.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
@@ -442,3 +1384,20 @@ func convertClientss(fromValues []*model.Clients) ([]*types.ClientsObjDetail, er
 
 	return toValues, nil
 }
+
+func convertClientsIPHistories(fromValues []*model.ClientIPHistory) ([]*types.ClientsIPHistoryObjDetail, error) {
+	toValues := []*types.ClientsIPHistoryObjDetail{}
+	for _, v := range fromValues {
+		data := &types.ClientsIPHistoryObjDetail{}
+		err := copier.Copy(data, v)
+		if err != nil {
+			return nil, err
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		data.ID = utils.Uint64ToStr(v.ID)
+		data.ClientID = utils.Uint64ToStr(v.ClientID)
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"github.com/stretchr/testify/assert"
 
@@ -16,6 +20,8 @@ import (
 
 	"caller/internal/cache"
 	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/gateway"
 	"caller/internal/model"
 	"caller/internal/types"
 )
@@ -40,8 +46,24 @@ func newClientsHandler() *gotest.Handler {
 
 	// init mock handler
 	h := gotest.NewHandler(d, testData)
-	h.IHandler = &clientsHandler{iDao: d.IDao.(dao.ClientsDao)}
+	h.IHandler = &clientsHandler{
+		iDao:           d.IDao.(dao.ClientsDao),
+		presence:       cache.NewClientsPresenceCache(&model.CacheType{CType: "redis", Rdb: c.RedisClient}),
+		offlineTimeout: time.Minute,
+		hub:            gateway.NewHub(),
+	}
 	iHandler := h.IHandler.(ClientsHandler)
+	// the device is set by DeviceAuth and the user by middleware.Auth in front of the routes
+	rotateCredential := func(c *gin.Context) {
+		device := &model.Clients{MachineCode: "m1"}
+		device.ID = testData.ID
+		c.Set(deviceCtxKey, device)
+		iHandler.RotateCredential(c)
+	}
+	setStatus := func(c *gin.Context) {
+		c.Set("name", "alice")
+		iHandler.SetStatus(c)
+	}
 
 	testFns := []gotest.RouterInfo{
 		{
@@ -50,6 +72,48 @@ func newClientsHandler() *gotest.Handler {
 			Path:        "/clients",
 			HandlerFunc: iHandler.Create,
 		},
+		{
+			FuncName:    "CreateBatch",
+			Method:      http.MethodPost,
+			Path:        "/clients/batch",
+			HandlerFunc: iHandler.CreateBatch,
+		},
+		{
+			FuncName:    "Heartbeat",
+			Method:      http.MethodPost,
+			Path:        "/clients/heartbeat",
+			HandlerFunc: iHandler.Heartbeat,
+		},
+		{
+			FuncName:    "UpsertMetadata",
+			Method:      http.MethodPut,
+			Path:        "/clients/metadata",
+			HandlerFunc: iHandler.UpsertMetadata,
+		},
+		{
+			FuncName:    "IssueEnrollmentCode",
+			Method:      http.MethodPost,
+			Path:        "/clients/enrollmentCode",
+			HandlerFunc: iHandler.IssueEnrollmentCode,
+		},
+		{
+			FuncName:    "Enroll",
+			Method:      http.MethodPost,
+			Path:        "/clients/enroll",
+			HandlerFunc: iHandler.Enroll,
+		},
+		{
+			FuncName:    "RotateCredential",
+			Method:      http.MethodPost,
+			Path:        "/clients/:id/credential/rotate",
+			HandlerFunc: rotateCredential,
+		},
+		{
+			FuncName:    "RevokeCredential",
+			Method:      http.MethodDelete,
+			Path:        "/clients/:id/credential",
+			HandlerFunc: iHandler.RevokeCredential,
+		},
 		{
 			FuncName:    "DeleteByID",
 			Method:      http.MethodDelete,
@@ -62,6 +126,18 @@ func newClientsHandler() *gotest.Handler {
 			Path:        "/clients/:id",
 			HandlerFunc: iHandler.UpdateByID,
 		},
+		{
+			FuncName:    "PatchByID",
+			Method:      http.MethodPatch,
+			Path:        "/clients/:id",
+			HandlerFunc: iHandler.PatchByID,
+		},
+		{
+			FuncName:    "SetStatus",
+			Method:      http.MethodPut,
+			Path:        "/clients/:id/status",
+			HandlerFunc: setStatus,
+		},
 		{
 			FuncName:    "GetByID",
 			Method:      http.MethodGet,
@@ -98,6 +174,30 @@ func newClientsHandler() *gotest.Handler {
 			Path:        "/clients/list",
 			HandlerFunc: iHandler.ListByLastID,
 		},
+		{
+			FuncName:    "ListIPHistory",
+			Method:      http.MethodGet,
+			Path:        "/clients/:id/ip-history",
+			HandlerFunc: iHandler.ListIPHistory,
+		},
+		{
+			FuncName:    "ListTrash",
+			Method:      http.MethodGet,
+			Path:        "/clients/trash",
+			HandlerFunc: iHandler.ListTrash,
+		},
+		{
+			FuncName:    "RestoreByID",
+			Method:      http.MethodPost,
+			Path:        "/clients/:id/restore",
+			HandlerFunc: iHandler.RestoreByID,
+		},
+		{
+			FuncName:    "PurgeByID",
+			Method:      http.MethodDelete,
+			Path:        "/clients/trash/:id",
+			HandlerFunc: iHandler.PurgeByID,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -129,6 +229,313 @@ func Test_clientsHandler_Create(t *testing.T) {
 
 }
 
+func Test_clientsHandler_CreateBatch(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := &types.CreateClientssBatchRequest{
+		Atomic:  true,
+		Records: []types.CreateClientsRequest{{}, {}},
+	}
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, result.Code)
+
+	// empty batch
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), &types.CreateClientssBatchRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the whole transaction fails
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	assert.Error(t, err)
+}
+
+func Test_clientsHandler_Heartbeat(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	rows := sqlmock.NewRows([]string{"id", "machine_code", "ip_address"}).
+		AddRow(testData.ID, "m1", "10.0.0.1")
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs("m1").
+		WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("Heartbeat"), &types.HeartbeatClientsRequest{MachineCode: "m1", IPAddress: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	clients := result.Data.(map[string]interface{})["clients"].(map[string]interface{})
+	assert.Equal(t, true, clients["online"])
+	assert.NotEmpty(t, clients["lastSeenAt"])
+
+	// the presence is used by the list apis
+	rows = sqlmock.NewRows([]string{"id", "machine_code"}).
+		AddRow(testData.ID, "m1")
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* machine_code IN .*").
+		WillReturnRows(rows)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"online": "true"})
+	assert.NoError(t, err)
+	clientss := result.Data.(map[string]interface{})["clientss"].([]interface{})
+	assert.Len(t, clientss, 1)
+	assert.Equal(t, true, clientss[0].(map[string]interface{})["online"])
+
+	rows = sqlmock.NewRows([]string{"id", "machine_code"})
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* machine_code NOT IN .*").
+		WillReturnRows(rows)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"online": "false"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"online": "sometimes"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// machine code is required
+	err = gohttp.Post(result, h.GetRequestURL("Heartbeat"), &types.HeartbeatClientsRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// unknown machine code
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs("m2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = gohttp.Post(result, h.GetRequestURL("Heartbeat"), &types.HeartbeatClientsRequest{MachineCode: "m2"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_clientsHandler_UpsertMetadata(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	appVersion, batteryLevel := "1.2.0", 15
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs("m1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code"}).AddRow(testData.ID, "m1"))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "app_version", "battery_level"}).
+			AddRow(testData.ID, "m1", appVersion, batteryLevel))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Put(result, h.GetRequestURL("UpsertMetadata"), &types.UpsertClientsMetadataRequest{
+		MachineCode: "m1", AppVersion: &appVersion, BatteryLevel: &batteryLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	clients := result.Data.(map[string]interface{})["clients"].(map[string]interface{})
+	assert.Equal(t, appVersion, clients["appVersion"])
+	assert.Equal(t, float64(batteryLevel), clients["batteryLevel"])
+
+	// the version code is taken from the header if the body has none
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs("m1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code"}).AddRow(testData.ID, "m1"))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*`app_version_code`=.*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "app_version_code"}).
+			AddRow(testData.ID, "m1", 120))
+	req, _ := http.NewRequest(http.MethodPut, h.GetRequestURL("UpsertMetadata"), strings.NewReader(`{"machineCode":"m1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(appVersionCodeHeader, "120")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+
+	// battery level is out of range
+	batteryLevel = 101
+	err = gohttp.Put(result, h.GetRequestURL("UpsertMetadata"), &types.UpsertClientsMetadataRequest{
+		MachineCode: "m1", BatteryLevel: &batteryLevel})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// machine code is required without a device credential
+	err = gohttp.Put(result, h.GetRequestURL("UpsertMetadata"), &types.UpsertClientsMetadataRequest{AppVersion: &appVersion})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// error
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Put(result, h.GetRequestURL("UpsertMetadata"), &types.UpsertClientsMetadataRequest{
+		MachineCode: "m1", AppVersion: &appVersion})
+	assert.Error(t, err)
+}
+
+func Test_clientsHandler_IssueEnrollmentCode(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("IssueEnrollmentCode"), &types.IssueClientsEnrollmentCodeRequest{MachineCode: "m1", ExpiresIn: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	assert.NotEmpty(t, result.Data.(map[string]interface{})["enrollmentCode"])
+
+	// expires in more than 30 days
+	err = gohttp.Post(result, h.GetRequestURL("IssueEnrollmentCode"), &types.IssueClientsEnrollmentCodeRequest{ExpiresIn: 31 * 86400})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_clientsHandler_Enroll(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code"}).AddRow(2, "m1"))
+	h.MockDao.SQLMock.ExpectExec("UPDATE `enrollment_code` .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE `clients` .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the ip address of the request is recorded in the ip history
+	h.MockDao.SQLMock.ExpectQuery("SELECT COUNT.*client_ip_history.*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO `client_ip_history` .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "credential_hash"}).AddRow(2, "m1", "hash"))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("Enroll"), &types.EnrollClientsRequest{EnrollmentCode: "ABCD-EFGH-JKLM", MachineCode: "m1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	credential := result.Data.(map[string]interface{})["credential"].(string)
+	id, _, ok := parseDeviceCredential(credential)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), id)
+
+	// the code is used or unknown
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "credential_hash"}).AddRow(2, "m1", "hash"))
+	h.MockDao.SQLMock.ExpectExec("UPDATE `enrollment_code` .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("Enroll"), &types.EnrollClientsRequest{EnrollmentCode: "ABCD-EFGH-JKLM", MachineCode: "m1"})
+	assert.Error(t, err)
+
+	// enrollment code is required
+	err = gohttp.Post(result, h.GetRequestURL("Enroll"), &types.EnrollClientsRequest{MachineCode: "m1"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_clientsHandler_RotateCredential(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RotateCredential", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	assert.NotEmpty(t, result.Data.(map[string]interface{})["credential"])
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RotateCredential", 0), nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the credential of another device, or without device auth
+	err = gohttp.Post(result, h.GetRequestURL("RotateCredential", 2), nil)
+	assert.Error(t, err)
+	r := gin.New()
+	r.POST("/clients/:id/credential/rotate", (&clientsHandler{}).RotateCredential)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/clients/1/credential/rotate", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func Test_clientsHandler_RevokeCredential(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("RevokeCredential", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// not found
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = gohttp.Delete(result, h.GetRequestURL("RevokeCredential", 111))
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+}
+
 func Test_clientsHandler_DeleteByID(t *testing.T) {
 	h := newClientsHandler()
 	defer h.Close()
@@ -190,6 +597,116 @@ func Test_clientsHandler_UpdateByID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_clientsHandler_UpdateByIDWithIfMatch(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := &types.UpdateClientsByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.Clients))
+
+	// the record has been changed by another request
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+
+	req := &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `"1"`)
+	req.SetJSONBody(testData)
+	resp, err := req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	_, _ = resp.ReadBody()
+
+	// invalid If-Match error test
+	req = &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `W/"1"`)
+	req.SetJSONBody(testData)
+	resp, err = req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &gohttp.StdResult{}
+	err = resp.BindJSON(result)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+}
+
+func Test_clientsHandler_PatchByID(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"ipAddress": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// read-only field error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+
+	// patch error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", 111), map[string]interface{}{"ipAddress": nil})
+	assert.Error(t, err)
+}
+
+func Test_clientsHandler_SetStatus(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "status", "status_operator"}).
+			AddRow(testData.ID, "m1", model.ClientsStatusDisabled, "alice"))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Put(result, h.GetRequestURL("SetStatus", testData.ID),
+		&types.SetClientsStatusRequest{Status: model.ClientsStatusDisabled, Reason: "lost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	clients := result.Data.(map[string]interface{})["clients"].(map[string]interface{})
+	assert.Equal(t, model.ClientsStatusDisabled, clients["status"])
+
+	// unknown status
+	err = gohttp.Put(result, h.GetRequestURL("SetStatus", testData.ID),
+		&types.SetClientsStatusRequest{Status: "lost"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// set status error test
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Put(result, h.GetRequestURL("SetStatus", testData.ID),
+		&types.SetClientsStatusRequest{Status: model.ClientsStatusActive})
+	assert.Error(t, err)
+}
+
 func Test_clientsHandler_GetByID(t *testing.T) {
 	h := newClientsHandler()
 	defer h.Close()
@@ -233,7 +750,7 @@ func Test_clientsHandler_List(t *testing.T) {
 	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
 
 	result := &gohttp.StdResult{}
-	err := gohttp.Post(result, h.GetRequestURL("List"), &types.ListClientssRequest{query.Params{
+	err := gohttp.Post(result, h.GetRequestURL("List"), &types.ListClientssRequest{Params: query.Params{
 		Page: 0,
 		Size: 10,
 		Sort: "ignore count", // ignore test count
@@ -250,7 +767,7 @@ func Test_clientsHandler_List(t *testing.T) {
 	assert.NoError(t, err)
 
 	// get error test
-	err = gohttp.Post(result, h.GetRequestURL("List"), &types.ListClientssRequest{query.Params{
+	err = gohttp.Post(result, h.GetRequestURL("List"), &types.ListClientssRequest{Params: query.Params{
 		Page: 0,
 		Size: 10,
 		Sort: "unknown-column",
@@ -385,6 +902,8 @@ func Test_clientsHandler_ListByLastID(t *testing.T) {
 	// error test
 	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"lastID": 0, "size": 10, "sort": "unknown-column"})
 	assert.Error(t, err)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"cursor": "invalid", "size": 10})
+	assert.Error(t, err)
 }
 
 func TestNewClientsHandler(t *testing.T) {
@@ -393,3 +912,133 @@ func TestNewClientsHandler(t *testing.T) {
 	}()
 	_ = NewClientsHandler()
 }
+
+func Test_clientsHandler_ListIPHistory(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code"}).AddRow(testData.ID, "m1"))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* `client_ip_history` .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "previous_ip", "ip_address", "subnet_changed"}).
+			AddRow(2, testData.ID, "10.0.0.1", "10.0.1.1", true))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListIPHistory", testData.ID), gohttp.KV{"flagged": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	ipHistory := result.Data.(map[string]interface{})["ipHistory"].([]interface{})
+	assert.Len(t, ipHistory, 1)
+	assert.Equal(t, "10.0.1.1", ipHistory[0].(map[string]interface{})["ipAddress"])
+	assert.Equal(t, true, ipHistory[0].(map[string]interface{})["subnetChanged"])
+
+	// invalid flagged error test
+	err = gohttp.Get(result, h.GetRequestURL("ListIPHistory", testData.ID), gohttp.KV{"flagged": "sometimes"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// zero id error test
+	err = gohttp.Get(result, h.GetRequestURL("ListIPHistory", 0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// not found
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(222).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = gohttp.Get(result, h.GetRequestURL("ListIPHistory", 222))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_clientsHandler_ListTrash(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"}).
+		AddRow(testData.ID, time.Now())
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// error test
+	err = gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"sort": "unknown-column"})
+	assert.Error(t, err)
+}
+
+func Test_clientsHandler_RestoreByID(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RestoreByID", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 0), nil)
+	assert.NoError(t, err)
+
+	// restore error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 111), nil)
+	assert.Error(t, err)
+}
+
+func Test_clientsHandler_PurgeByID(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Clients)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE FROM `resource_label` .*").
+		WithArgs("clients", testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("PurgeByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 0))
+	assert.NoError(t, err)
+
+	// purge error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 111))
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zhufuyi/sponge/pkg/errcode"
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+)
+
+const (
+	// deviceAuthScheme the devices send their credential in the header "Authorization: Device <credential>"
+	deviceAuthScheme = "Device"
+	// deviceCtxKey the key of the client authenticated by DeviceAuth in gin.Context
+	deviceCtxKey = "device"
+
+	// enrollmentCodeAlphabet the characters of enrollment codes, without the ones that are easily confused such as 0/O and 1/I
+	enrollmentCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	enrollmentCodeLength   = 12
+)
+
+// DeviceAuth authenticate the calls of the devices with the credential issued by enrollment,
+// the client is read without the cache, so that a revoked credential or a disabled client is rejected at once
+func DeviceAuth() gin.HandlerFunc {
+	return deviceAuth(dao.NewClientsDao(model.GetDB(), nil))
+}
+
+func deviceAuth(iDao dao.ClientsDao) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, credential, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		id, secret, ok := parseDeviceCredential(strings.TrimSpace(credential))
+		if !ok || !strings.EqualFold(scheme, deviceAuthScheme) {
+			logger.Warn("missing device credential", middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.Unauthorized.ToHTTPCode())
+			c.Abort()
+			return
+		}
+
+		clients, err := iDao.GetByID(middleware.WrapCtx(c), id)
+		if err != nil {
+			if errors.Is(err, model.ErrRecordNotFound) {
+				logger.Warn("device credential of unknown client", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.Unauthorized.ToHTTPCode())
+			} else {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			}
+			c.Abort()
+			return
+		}
+		if clients.CredentialHash == "" ||
+			subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(clients.CredentialHash)) != 1 {
+			logger.Warn("invalid or revoked device credential", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.Unauthorized.ToHTTPCode())
+			c.Abort()
+			return
+		}
+
+		if !clients.IsActive() {
+			logger.Warn("device is not active", logger.Any("id", id), logger.String("status", clients.Status), middleware.GCtxRequestIDField(c))
+			response.Error(c, clientsStatusError(clients.Status))
+			c.Abort()
+			return
+		}
+
+		c.Set(deviceCtxKey, clients)
+		c.Next()
+	}
+}
+
+// getDevice get the client authenticated by DeviceAuth, nil if the route does not use DeviceAuth
+func getDevice(c *gin.Context) *model.Clients {
+	if v, ok := c.Get(deviceCtxKey); ok {
+		if clients, ok := v.(*model.Clients); ok {
+			return clients
+		}
+	}
+	return nil
+}
+
+// checkDeviceMachineCode check that a machine code in a request is the one of the authenticated device,
+// an empty machine code is set to it, isAbort is true if the machine code belongs to another device.
+func checkDeviceMachineCode(c *gin.Context, machineCode *string) bool {
+	device := getDevice(c)
+	if device == nil {
+		return false
+	}
+	if *machineCode == "" {
+		*machineCode = device.MachineCode
+		return false
+	}
+	if *machineCode != device.MachineCode {
+		logger.Warn("machine code of another device", logger.String("machineCode", *machineCode),
+			logger.String("deviceMachineCode", device.MachineCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.Forbidden.ToHTTPCode())
+		return true
+	}
+	return false
+}
+
+// clientsStatusError the error code returned to a device that is not active
+func clientsStatusError(status string) *errcode.Error {
+	if status == model.ClientsStatusQuarantined {
+		return ecode.ErrQuarantinedClients
+	}
+	return ecode.ErrDisabledClients
+}
+
+// newDeviceCredential create the secret of a device credential and its hash, only the hash is stored,
+// the credential given to the device is "<client id>.<secret>", see formatDeviceCredential.
+func newDeviceCredential() (secret string, secretHash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret = base64.RawURLEncoding.EncodeToString(buf)
+	return secret, hashSecret(secret), nil
+}
+
+func formatDeviceCredential(id uint64, secret string) string {
+	return strconv.FormatUint(id, 10) + "." + secret
+}
+
+func parseDeviceCredential(credential string) (uint64, string, bool) {
+	idStr, secret, ok := strings.Cut(credential, ".")
+	if !ok || secret == "" {
+		return 0, "", false
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || id == 0 {
+		return 0, "", false
+	}
+	return id, secret, true
+}
+
+// newEnrollmentCode create a random enrollment code that is easy to type on a phone, such as "7KQ2-M9XD-4HPT"
+func newEnrollmentCode() (string, error) {
+	buf := make([]byte, enrollmentCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(enrollmentCodeAlphabet[int(b)%len(enrollmentCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// hashEnrollmentCode the hash of an enrollment code, case and separators are ignored
+func hashEnrollmentCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashSecret(code)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/gohttp"
+
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+)
+
+func Test_deviceCredential(t *testing.T) {
+	secret, secretHash, err := newDeviceCredential()
+	assert.NoError(t, err)
+	assert.Equal(t, hashSecret(secret), secretHash)
+	assert.Len(t, secretHash, 64)
+
+	id, parsedSecret, ok := parseDeviceCredential(formatDeviceCredential(12, secret))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(12), id)
+	assert.Equal(t, secret, parsedSecret)
+
+	for _, credential := range []string{"", "12", "12.", ".secret", "0.secret", "x.secret"} {
+		_, _, ok = parseDeviceCredential(credential)
+		assert.False(t, ok, credential)
+	}
+}
+
+func Test_newEnrollmentCode(t *testing.T) {
+	code, err := newEnrollmentCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, enrollmentCodeLength+2)
+	for _, r := range strings.ReplaceAll(code, "-", "") {
+		assert.Contains(t, enrollmentCodeAlphabet, string(r))
+	}
+
+	// case and separators are ignored
+	assert.Equal(t, hashEnrollmentCode("ABCD-EFGH-JKLM"), hashEnrollmentCode("abcd efgh jklm"))
+}
+
+func Test_deviceAuth(t *testing.T) {
+	h := newClientsHandler()
+	defer h.Close()
+
+	secret, secretHash, _ := newDeviceCredential()
+	r := gin.New()
+	r.POST("/device", deviceAuth(dao.NewClientsDao(h.MockDao.DB, nil)), func(c *gin.Context) {
+		machineCode := c.Query("machineCode")
+		if checkDeviceMachineCode(c, &machineCode) {
+			return
+		}
+		c.String(http.StatusOK, machineCode)
+	})
+	request := func(authorization string, machineCode ...string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		target := "/device"
+		if len(machineCode) > 0 {
+			target += "?machineCode=" + machineCode[0]
+		}
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// the client is read from the database by every call
+	for i := 0; i < 4; i++ {
+		rows := sqlmock.NewRows([]string{"id", "machine_code", "credential_hash"}).
+			AddRow(2, "m1", secretHash)
+		h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+			WithArgs(2).
+			WillReturnRows(rows)
+	}
+
+	// an empty machine code is set to the one of the device
+	w := request("Device " + formatDeviceCredential(2, secret))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "m1", w.Body.String())
+	w = request("Device "+formatDeviceCredential(2, secret), "m1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the machine code of another device is forbidden
+	w = request("Device "+formatDeviceCredential(2, secret), "m2")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = request("Device " + formatDeviceCredential(2, "wrong secret"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = request("Bearer " + formatDeviceCredential(2, secret))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = request("")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// revoked credential
+	rows := sqlmock.NewRows([]string{"id", "machine_code", "credential_hash"}).
+		AddRow(3, "m3", "")
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(3).
+		WillReturnRows(rows)
+	w = request("Device " + formatDeviceCredential(3, secret))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// disabled and quarantined devices get the error code of their status
+	for id, status := range map[int]string{4: model.ClientsStatusDisabled, 5: model.ClientsStatusQuarantined} {
+		rows = sqlmock.NewRows([]string{"id", "machine_code", "credential_hash", "status"}).
+			AddRow(id, "m4", secretHash, status)
+		h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+			WithArgs(id).
+			WillReturnRows(rows)
+		w = request("Device " + formatDeviceCredential(uint64(id), secret))
+		assert.Equal(t, http.StatusOK, w.Code)
+		result := &gohttp.StdResult{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+		assert.Equal(t, clientsStatusError(status).Code(), result.Code, status)
+	}
+	assert.Equal(t, ecode.ErrDisabledClients, clientsStatusError(model.ClientsStatusDisabled))
+	assert.Equal(t, ecode.ErrQuarantinedClients, clientsStatusError(model.ClientsStatusQuarantined))
+}
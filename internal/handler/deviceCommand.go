@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+const (
+	// deviceCommandMaxWait the longest time a poll waits for commands
+	deviceCommandMaxWait = 60 * time.Second
+	// deviceCommandRecheckInterval a waiting poll also checks the queue at this interval, in case a notification is lost
+	deviceCommandRecheckInterval = 5 * time.Second
+)
+
+var _ DeviceCommandHandler = (*deviceCommandHandler)(nil)
+
+// DeviceCommandHandler defining the handler interface
+type DeviceCommandHandler interface {
+	Create(c *gin.Context)
+	Poll(c *gin.Context)
+	Ack(c *gin.Context)
+	Redeliver(c *gin.Context)
+	List(c *gin.Context)
+}
+
+type deviceCommandHandler struct {
+	iDao           dao.DeviceCommandDao
+	clientsDao     dao.ClientsDao
+	callHistoryDao dao.CallHistoryDao // the calls of the delivered commands are dispatched
+	notifier       cache.DeviceCommandNotifier
+}
+
+// NewDeviceCommandHandler creating the handler interface
+func NewDeviceCommandHandler() DeviceCommandHandler {
+	return &deviceCommandHandler{
+		iDao: dao.NewDeviceCommandDao(model.GetDB()),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
+		callHistoryDao: dao.NewCallHistoryDao(
+			model.GetDB(),
+			cache.NewCallHistoryCache(model.GetCacheType()),
+		),
+		notifier: cache.NewDeviceCommandNotifier(model.GetCacheType()),
+	}
+}
+
+// Create queue a command for a device
+// @Summary queue a command for a device
+// @Description queue a command for a device, the device receives it from the poll api, the commands queued for call history are created with the call history. the instruction must be one of GET /api/v1/instructions and its payload is validated. a command cannot be queued for a disabled or quarantined client
+// @Tags deviceCommand
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param data body types.CreateDeviceCommandRequest true "command information"
+// @Success 200 {object} types.CreateDeviceCommandRespond{}
+// @Router /api/v1/devices/{machineCode}/commands [post]
+// @Security BearerAuth
+func (h *deviceCommandHandler) Create(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	form := &types.CreateDeviceCommandRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	payload, err := checkInstruction(form.Instruction, form.MobileNumber, form.Payload, false)
+	if err != nil {
+		logger.Warn("checkInstruction error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, instructionError(err))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.clientsDao.GetByMachineCode(ctx, machineCode)
+	if err != nil && !errors.Is(err, model.ErrRecordNotFound) {
+		logger.Error("GetByMachineCode error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	if clients != nil && !clients.IsActive() {
+		logger.Warn("client is not active", logger.String("machineCode", machineCode), logger.String("status", clients.Status), middleware.GCtxRequestIDField(c))
+		response.Error(c, clientsStatusError(clients.Status))
+		return
+	}
+
+	ttl := model.DeviceCommandTTL
+	if form.ExpiresIn > 0 {
+		ttl = time.Duration(form.ExpiresIn) * time.Second
+	}
+	command := &model.DeviceCommand{
+		MachineCode:  machineCode,
+		Instruction:  form.Instruction,
+		Payload:      payload,
+		MobileNumber: form.MobileNumber,
+		State:        model.DeviceCommandPending,
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+
+	err = h.iDao.Create(ctx, command)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	notifyDeviceCommand(c, h.notifier, machineCode)
+
+	response.Success(c, gin.H{"id": command.ID})
+}
+
+// Poll deliver the pending commands of a device, waiting for new commands if there are none
+// @Summary poll the commands of a device
+// @Description deliver the pending commands of the device, the oldest first, if there are none the request waits until a command is queued or the wait ends. a command is delivered only once, ack it after it is executed
+// @Tags deviceCommand
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param wait query string false "how long to wait for a command, e.g. 30s, at most 60s, 0 returns at once" default(0s)
+// @Param limit query int false "most commands in the respond" default(10)
+// @Success 200 {object} types.PollDeviceCommandsRespond{}
+// @Router /api/v1/devices/{machineCode}/commands [get]
+// @Security BearerAuth
+func (h *deviceCommandHandler) Poll(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	wait, err := parseDeviceCommandWait(c.Query("wait"))
+	if err != nil {
+		logger.Warn("parseDeviceCommandWait error: ", logger.Err(err), logger.String("wait", c.Query("wait")), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	// subscribe before checking the queue, so a command queued in between is not missed
+	notified, cancel := h.notifier.Subscribe(machineCode)
+	defer cancel()
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	recheck := time.NewTicker(deviceCommandRecheckInterval)
+	defer recheck.Stop()
+
+	ctx := middleware.WrapCtx(c)
+	for {
+		commands, err := h.iDao.Deliver(ctx, machineCode, limit)
+		if err != nil {
+			logger.Error("Deliver error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+		dispatchCalls(ctx, h.callHistoryDao, commands)
+		if len(commands) > 0 || wait == 0 {
+			data, err := convertDeviceCommands(commands)
+			if err != nil {
+				response.Error(c, ecode.ErrPollDeviceCommand)
+				return
+			}
+			response.Success(c, gin.H{"commands": data})
+			return
+		}
+
+		select {
+		case <-notified:
+		case <-recheck.C:
+		case <-deadline.C:
+			response.Success(c, gin.H{"commands": []*types.DeviceCommandObjDetail{}})
+			return
+		case <-c.Request.Context().Done():
+			// the device is gone, nothing is delivered
+			return
+		}
+	}
+}
+
+// Ack confirm that a delivered command was executed
+// @Summary ack a command
+// @Description confirm that a delivered command was executed by the device, acking a command again is not an error
+// @Tags deviceCommand
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id"
+// @Success 200 {object} types.AckDeviceCommandRespond{}
+// @Router /api/v1/devices/{machineCode}/commands/{id}/ack [post]
+// @Security BearerAuth
+func (h *deviceCommandHandler) Ack(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	id, err := utils.StrToUint64E(c.Param("id"))
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", c.Param("id")), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.Ack(ctx, machineCode, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Ack not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Ack error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// Redeliver put a delivered command that was not acked back in the queue
+// @Summary redeliver a command
+// @Description put a command that was delivered to the device but not acked back in the queue, it is delivered again on the next poll or push, if it has not expired. the device may have executed the command without acking it
+// @Tags deviceCommand
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param id path string true "id"
+// @Success 200 {object} types.RedeliverDeviceCommandRespond{}
+// @Router /api/v1/devices/{machineCode}/commands/{id}/redeliver [post]
+// @Security BearerAuth
+func (h *deviceCommandHandler) Redeliver(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	id, err := utils.StrToUint64E(c.Param("id"))
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", c.Param("id")), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.Redeliver(ctx, machineCode, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Redeliver not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Redeliver error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	notifyDeviceCommand(c, h.notifier, machineCode)
+
+	response.Success(c)
+}
+
+// List get the commands of a device by cursor and limit
+// @Summary list of the commands of a device
+// @Description list of the commands of a device by cursor and limit, the latest command comes first, pass the nextCursor of the respond to get the next page
+// @Tags deviceCommand
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param state query string false "pending, delivered, acked or expired, empty means every state"
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Success 200 {object} types.ListDeviceCommandsRespond{}
+// @Router /api/v1/devices/{machineCode}/commands/list [get]
+// @Security BearerAuth
+func (h *deviceCommandHandler) List(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	state := c.Query("state")
+	switch state {
+	case "", model.DeviceCommandPending, model.DeviceCommandDelivered, model.DeviceCommandAcked, model.DeviceCommandExpired:
+	default:
+		logger.Warn("unknown state", logger.String("state", state), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	ctx := middleware.WrapCtx(c)
+	commands, nextCursor, err := h.iDao.GetByMachineCodeByCursor(ctx, machineCode, state, cursor, limit)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) {
+			logger.Warn("GetByMachineCodeByCursor error", logger.Err(err), logger.String("cursor", cursor), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByMachineCodeByCursor error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertDeviceCommands(commands)
+	if err != nil {
+		response.Error(c, ecode.ErrListDeviceCommand)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"commands":   data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// notifyDeviceCommand wake up the polls of a device after commands were queued for it, if the notification fails
+// the polls still find the commands when they check the queue again
+func notifyDeviceCommand(c *gin.Context, notifier cache.DeviceCommandNotifier, machineCode string) {
+	err := notifier.Notify(middleware.WrapCtx(c), machineCode)
+	if err != nil {
+		logger.Warn("Notify device command error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+	}
+}
+
+// parseDeviceCommandWait parse the wait of a poll, a duration such as 30s or a number of seconds
+func parseDeviceCommandWait(str string) (time.Duration, error) {
+	if str == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(str)
+	if err != nil {
+		seconds, err := strconv.Atoi(str)
+		if err != nil {
+			return 0, err
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait < 0 {
+		return 0, errors.New("wait cannot be negative")
+	}
+	if wait > deviceCommandMaxWait {
+		wait = deviceCommandMaxWait
+	}
+	return wait, nil
+}
+
+func convertDeviceCommands(fromValues []*model.DeviceCommand) ([]*types.DeviceCommandObjDetail, error) {
+	toValues := []*types.DeviceCommandObjDetail{}
+	for _, v := range fromValues {
+		data := &types.DeviceCommandObjDetail{}
+		err := copier.Copy(data, v)
+		if err != nil {
+			return nil, err
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		data.ID = utils.Uint64ToStr(v.ID)
+		data.CallHistoryID = utils.Uint64ToStr(v.CallHistoryID)
+		data.Payload = payloadJSON(v.Payload)
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
+
+// dispatchCalls move the calls of the delivered commands to dispatched, a call that has already moved on,
+// e.g. the device reported it before the command, is left as it is
+func dispatchCalls(ctx context.Context, callHistoryDao dao.CallHistoryDao, commands []*model.DeviceCommand) {
+	for _, command := range commands {
+		if command.CallHistoryID == 0 {
+			continue
+		}
+		at := time.Now()
+		if command.DeliveredAt != nil {
+			at = *command.DeliveredAt
+		}
+		_, err := callHistoryDao.Transit(ctx, command.CallHistoryID, &dao.CallTransition{State: model.CallStateDispatched, At: at})
+		if err != nil && !errors.Is(err, dao.ErrIllegalCallTransition) && !errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Transit error", logger.Err(err), logger.Any("callHistoryID", command.CallHistoryID), logger.Any("commandID", command.ID))
+		}
+	}
+}
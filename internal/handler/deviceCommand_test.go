@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/gohttp"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+func newDeviceCommandHandler() *gotest.Handler {
+	testData := &model.DeviceCommand{}
+	testData.ID = 1
+
+	// init mock cache
+	c := gotest.NewCache(map[string]interface{}{})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = dao.NewDeviceCommandDao(d.DB)
+
+	// init mock handler
+	h := gotest.NewHandler(d, testData)
+	h.IHandler = &deviceCommandHandler{
+		iDao:           d.IDao.(dao.DeviceCommandDao),
+		clientsDao:     dao.NewClientsDao(d.DB, nil),
+		callHistoryDao: dao.NewCallHistoryDao(d.DB, nil),
+		notifier:       cache.NewDeviceCommandNotifier(&model.CacheType{CType: "redis", Rdb: c.RedisClient}),
+	}
+	iHandler := h.IHandler.(DeviceCommandHandler)
+
+	testFns := []gotest.RouterInfo{
+		{
+			FuncName:    "Create",
+			Method:      http.MethodPost,
+			Path:        "/devices/:machineCode/commands",
+			HandlerFunc: iHandler.Create,
+		},
+		{
+			FuncName:    "Poll",
+			Method:      http.MethodGet,
+			Path:        "/devices/:machineCode/commands",
+			HandlerFunc: iHandler.Poll,
+		},
+		{
+			FuncName:    "Ack",
+			Method:      http.MethodPost,
+			Path:        "/devices/:machineCode/commands/:id/ack",
+			HandlerFunc: iHandler.Ack,
+		},
+		{
+			FuncName:    "Redeliver",
+			Method:      http.MethodPost,
+			Path:        "/devices/:machineCode/commands/:id/redeliver",
+			HandlerFunc: iHandler.Redeliver,
+		},
+		{
+			FuncName:    "List",
+			Method:      http.MethodGet,
+			Path:        "/devices/:machineCode/commands/list",
+			HandlerFunc: iHandler.List,
+		},
+	}
+
+	h.GoRunHTTPServer(testFns)
+
+	time.Sleep(time.Millisecond * 200)
+	return h
+}
+
+// expectDeliver the queries of dao Deliver, ids are the pending commands that are delivered
+func expectDeliver(h *gotest.Handler, ids ...uint64) {
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE `device_command` .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	idRows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		idRows.AddRow(id)
+	}
+	h.MockDao.SQLMock.ExpectQuery("SELECT `id` FROM `device_command` .*").
+		WillReturnRows(idRows)
+	if len(ids) == 0 {
+		return
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "machine_code", "instruction", "state"})
+	for _, id := range ids {
+		h.MockDao.SQLMock.ExpectBegin()
+		h.MockDao.SQLMock.ExpectExec("UPDATE `device_command` .*").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		h.MockDao.SQLMock.ExpectCommit()
+		rows.AddRow(id, "m1", "call", model.DeviceCommandDelivered)
+	}
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `device_command` .*").
+		WillReturnRows(rows)
+}
+
+func Test_deviceCommandHandler_Create(t *testing.T) {
+	h := newDeviceCommandHandler()
+	defer h.Close()
+
+	// the client is unknown
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `clients` .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "dial", MobileNumber: "13800000000", ExpiresIn: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// the client is quarantined
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `clients` .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "status"}).AddRow(1, "m1", model.ClientsStatusQuarantined))
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "hangup"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrQuarantinedClients.Code(), result.Code)
+
+	// instruction is required
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the instruction and its payload are validated
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "call"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrUnknownInstruction.Code(), result.Code)
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "dial"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrMobileNumberInstruction.Code(), result.Code)
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "sendDTMF", Payload: []byte(`{"digits":"12x"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrDigitsInstruction.Code(), result.Code)
+
+	// create error test
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `clients` .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("Create", "m1"), &types.CreateDeviceCommandRequest{Instruction: "hangup"})
+	assert.Error(t, err)
+}
+
+func Test_deviceCommandHandler_Poll(t *testing.T) {
+	h := newDeviceCommandHandler()
+	defer h.Close()
+	notifier := h.IHandler.(*deviceCommandHandler).notifier
+
+	// pending commands are returned at once
+	expectDeliver(h, 3, 4)
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("Poll", "m1"), gohttp.KV{"wait": "30s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := result.Data.(map[string]interface{})["commands"].([]interface{})
+	assert.Len(t, commands, 2)
+	assert.Equal(t, "3", commands[0].(map[string]interface{})["id"])
+
+	// a waiting poll returns when a command is queued
+	expectDeliver(h)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		expectDeliver(h, 5)
+		_ = notifier.Notify(context.Background(), "m1")
+	}()
+	start := time.Now()
+	err = gohttp.Get(result, h.GetRequestURL("Poll", "m1"), gohttp.KV{"wait": "30"})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+	commands = result.Data.(map[string]interface{})["commands"].([]interface{})
+	assert.Len(t, commands, 1)
+	assert.Equal(t, "5", commands[0].(map[string]interface{})["id"])
+
+	// no command before the wait ends
+	expectDeliver(h)
+	start = time.Now()
+	err = gohttp.Get(result, h.GetRequestURL("Poll", "m1"), gohttp.KV{"wait": "300ms"})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+	assert.Len(t, result.Data.(map[string]interface{})["commands"], 0)
+
+	// invalid wait
+	err = gohttp.Get(result, h.GetRequestURL("Poll", "m1"), gohttp.KV{"wait": "soon"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// poll error test
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Get(result, h.GetRequestURL("Poll", "m1"))
+	assert.Error(t, err)
+}
+
+func Test_deviceCommandHandler_Ack(t *testing.T) {
+	h := newDeviceCommandHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("Ack", "m1", 3), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// not delivered to the device
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = gohttp.Post(result, h.GetRequestURL("Ack", "m1", 4), nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("Ack", "m1", 0), nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_deviceCommandHandler_Redeliver(t *testing.T) {
+	h := newDeviceCommandHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("Redeliver", "m1", 3), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// not delivered to the device, or acked
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = gohttp.Post(result, h.GetRequestURL("Redeliver", "m1", 4), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("Redeliver", "m1", 0), nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+}
+
+func Test_deviceCommandHandler_List(t *testing.T) {
+	h := newDeviceCommandHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "state"}).AddRow(3, "m1", model.DeviceCommandPending))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("List", "m1"), gohttp.KV{"state": model.DeviceCommandPending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	assert.Len(t, result.Data.(map[string]interface{})["commands"], 1)
+
+	// unknown state
+	err = gohttp.Get(result, h.GetRequestURL("List", "m1"), gohttp.KV{"state": "lost"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// invalid cursor
+	err = gohttp.Get(result, h.GetRequestURL("List", "m1"), gohttp.KV{"cursor": "invalid"})
+	assert.Error(t, err)
+}
+
+func Test_parseDeviceCommandWait(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"30s", 30 * time.Second, false},
+		{"15", 15 * time.Second, false},
+		{"10m", deviceCommandMaxWait, false},
+		{"-1s", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDeviceCommandWait(tt.str)
+		if tt.wantErr {
+			assert.Error(t, err, tt.str)
+			continue
+		}
+		assert.NoError(t, err, tt.str)
+		assert.Equal(t, tt.want, got, tt.str)
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/gateway"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+// deviceGatewayBatchSize the most commands pushed in one message
+const deviceGatewayBatchSize = 10
+
+// deviceUpgrader the devices are not browsers and authenticate with their credential, so any origin is accepted
+var deviceUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+var _ DeviceGatewayHandler = (*deviceGatewayHandler)(nil)
+
+// DeviceGatewayHandler defining the handler interface
+type DeviceGatewayHandler interface {
+	Connect(c *gin.Context)
+}
+
+type deviceGatewayHandler struct {
+	hub            *gateway.Hub
+	commandDao     dao.DeviceCommandDao
+	clientsDao     dao.ClientsDao
+	callHistoryDao dao.CallHistoryDao
+	notifier       cache.DeviceCommandNotifier
+	presence       cache.ClientsPresenceCache
+}
+
+// NewDeviceGatewayHandler creating the handler interface, the connections are kept in hub
+func NewDeviceGatewayHandler(hub *gateway.Hub) DeviceGatewayHandler {
+	return &deviceGatewayHandler{
+		hub:        hub,
+		commandDao: dao.NewDeviceCommandDao(model.GetDB()),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
+		callHistoryDao: dao.NewCallHistoryDao(
+			model.GetDB(),
+			cache.NewCallHistoryCache(model.GetCacheType()),
+		),
+		notifier: cache.NewDeviceCommandNotifier(model.GetCacheType()),
+		presence: cache.NewClientsPresenceCache(model.GetCacheType()),
+	}
+}
+
+// Connect upgrade the request of a device to a websocket, the commands queued for the device are pushed
+// as soon as they are queued, the device sends the acks of the commands and its status on the same connection.
+// @Summary device websocket
+// @Description connect a device with a websocket, see types.DeviceMessage for the json text messages. the pending commands are pushed on connect and when they are queued, a command is pushed only once, ack it after it is executed, a command that is not acked is pushed again only if an operator redelivers it. status messages update the metadata of the device. the device must answer pings, the connection is closed if it is silent for 60s
+// @Tags deviceCommand
+// @Param machineCode query string false "machine code of the device, if empty the machine code of the device credential is used"
+// @Success 101 {object} types.DeviceMessage{}
+// @Router /ws/device [get]
+// @Security BearerAuth
+func (h *deviceGatewayHandler) Connect(c *gin.Context) {
+	machineCode := c.Query("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	if machineCode == "" {
+		logger.Warn("machine code is empty", middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	conn, err := deviceUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader has responded with the error
+		logger.Warn("Upgrade error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		return
+	}
+	session := gateway.NewSession(conn, machineCode)
+	err = h.hub.Add(session)
+	if err != nil {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown"), time.Now().Add(time.Second))
+		_ = conn.Close()
+		return
+	}
+	defer h.hub.Remove(session)
+
+	// the request context ends when the handler returns, which is after the session
+	ctx := middleware.WrapCtx(c)
+	logger.Info("device connected", logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+	h.touch(ctx, machineCode)
+
+	// subscribe before the first delivery, so a command queued in between is not missed
+	notified, cancel := h.notifier.Subscribe(machineCode)
+	defer cancel()
+	pushDone := make(chan struct{})
+	go func() {
+		defer close(pushDone)
+		h.pushCommands(ctx, session, notified)
+	}()
+
+	session.Run(func(data []byte) {
+		h.handleMessage(ctx, session, data)
+	}, func() {
+		h.touch(ctx, machineCode)
+	})
+	<-pushDone
+	logger.Info("device disconnected", logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+}
+
+// pushCommands push the pending commands of the device now and each time commands are queued, until the session ends
+func (h *deviceGatewayHandler) pushCommands(ctx context.Context, session *gateway.Session, notified <-chan struct{}) {
+	recheck := time.NewTicker(deviceCommandRecheckInterval)
+	defer recheck.Stop()
+
+	for {
+		for {
+			// check the session first, the delivered commands of a closed session are lost until they are redelivered
+			select {
+			case <-session.Done():
+				return
+			default:
+			}
+			commands, err := h.commandDao.Deliver(ctx, session.MachineCode, deviceGatewayBatchSize)
+			if err != nil {
+				logger.Error("Deliver error", logger.Err(err), logger.String("machineCode", session.MachineCode))
+				break
+			}
+			if len(commands) == 0 {
+				break
+			}
+			dispatchCalls(ctx, h.callHistoryDao, commands)
+			data, err := convertDeviceCommands(commands)
+			if err != nil {
+				logger.Error("convertDeviceCommands error", logger.Err(err), logger.String("machineCode", session.MachineCode))
+				break
+			}
+			err = session.Send(&types.DeviceMessage{Type: types.DeviceMessageCommands, Commands: data})
+			if err != nil {
+				logger.Warn("Send commands error", logger.Err(err), logger.String("machineCode", session.MachineCode), logger.Any("commands", data))
+				return
+			}
+			if len(commands) < deviceGatewayBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-notified:
+		case <-recheck.C:
+		case <-session.Done():
+			return
+		}
+	}
+}
+
+// handleMessage handle a message of the device, an error message is sent back if it cannot be handled
+func (h *deviceGatewayHandler) handleMessage(ctx context.Context, session *gateway.Session, data []byte) {
+	h.touch(ctx, session.MachineCode)
+
+	msg := &types.DeviceMessage{}
+	err := json.Unmarshal(data, msg)
+	if err != nil {
+		h.sendError(session, "", "invalid message")
+		return
+	}
+
+	switch msg.Type {
+	case types.DeviceMessageAck:
+		id, err := utils.StrToUint64E(msg.ID)
+		if err != nil || id == 0 {
+			h.sendError(session, msg.ID, "invalid id")
+			return
+		}
+		err = h.commandDao.Ack(ctx, session.MachineCode, id)
+		if err != nil {
+			if errors.Is(err, model.ErrRecordNotFound) {
+				h.sendError(session, msg.ID, ecode.NotFound.Msg())
+			} else {
+				logger.Error("Ack error", logger.Err(err), logger.Any("id", id), logger.String("machineCode", session.MachineCode))
+				h.sendError(session, msg.ID, ecode.ErrAckDeviceCommand.Msg())
+			}
+			return
+		}
+		_ = session.Send(&types.DeviceMessage{Type: types.DeviceMessageAcked, ID: msg.ID})
+
+	case types.DeviceMessageStatus:
+		if msg.Status == nil {
+			h.sendError(session, "", "status is required")
+			return
+		}
+		err = binding.Validator.ValidateStruct(msg.Status)
+		if err != nil {
+			h.sendError(session, "", err.Error())
+			return
+		}
+		_, err = h.clientsDao.UpsertMetadata(ctx, session.MachineCode, newClientsMetadata(msg.Status))
+		if err != nil {
+			logger.Error("UpsertMetadata error", logger.Err(err), logger.String("machineCode", session.MachineCode))
+			h.sendError(session, "", ecode.InternalServerError.Msg())
+		}
+
+	default:
+		h.sendError(session, msg.ID, "unknown message type")
+	}
+}
+
+func (h *deviceGatewayHandler) sendError(session *gateway.Session, id string, msg string) {
+	_ = session.Send(&types.DeviceMessage{Type: types.DeviceMessageError, ID: id, Msg: msg})
+}
+
+// touch keep the device online while it is connected
+func (h *deviceGatewayHandler) touch(ctx context.Context, machineCode string) {
+	err := h.presence.Touch(ctx, machineCode, time.Now())
+	if err != nil {
+		logger.Warn("Touch presence error", logger.Err(err), logger.String("machineCode", machineCode))
+	}
+}
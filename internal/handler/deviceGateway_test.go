@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/gateway"
+	"caller/internal/migration"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+func newDeviceGatewaySqliteDB(t *testing.T) *gorm.DB {
+	db, err := ggorm.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	m, err := migration.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Up(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func readDeviceMessage(t *testing.T, conn *websocket.Conn) *types.DeviceMessage {
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msg := &types.DeviceMessage{}
+	err := conn.ReadJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func Test_deviceGatewayHandler_Connect(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	hub := gateway.NewHub()
+	h := &deviceGatewayHandler{
+		hub:            hub,
+		commandDao:     dao.NewDeviceCommandDao(db),
+		clientsDao:     dao.NewClientsDao(db, nil),
+		callHistoryDao: dao.NewCallHistoryDao(db, nil),
+		notifier:       cache.NewDeviceCommandNotifier(&model.CacheType{}),
+		presence:       cache.NewClientsPresenceCache(&model.CacheType{}),
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/ws/device", func(c *gin.Context) {
+		if machineCode := c.GetHeader("X-Device"); machineCode != "" {
+			c.Set(deviceCtxKey, &model.Clients{MachineCode: machineCode})
+		}
+	}, h.Connect)
+	server := httptest.NewServer(r)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/device"
+
+	// the command queued before the device connects is pushed on connect
+	assert.NoError(t, h.commandDao.Create(ctx, &model.DeviceCommand{MachineCode: "m1", Instruction: "call",
+		State: model.DeviceCommandPending, ExpiresAt: time.Now().Add(time.Minute)}))
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?machineCode=m1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	msg := readDeviceMessage(t, conn)
+	assert.Equal(t, types.DeviceMessageCommands, msg.Type)
+	assert.Len(t, msg.Commands, 1)
+	assert.Equal(t, "call", msg.Commands[0].Instruction)
+
+	// ack
+	assert.NoError(t, conn.WriteJSON(&types.DeviceMessage{Type: types.DeviceMessageAck, ID: msg.Commands[0].ID}))
+	msg = readDeviceMessage(t, conn)
+	assert.Equal(t, types.DeviceMessageAcked, msg.Type)
+	assert.NoError(t, conn.WriteJSON(&types.DeviceMessage{Type: types.DeviceMessageAck, ID: "100"}))
+	msg = readDeviceMessage(t, conn)
+	assert.Equal(t, types.DeviceMessageError, msg.Type)
+	assert.Equal(t, "100", msg.ID)
+
+	// the instruction of a new call history is pushed at once
+	start := time.Now()
+	assert.NoError(t, h.callHistoryDao.Create(ctx, &model.CallHistory{ClientMachineCode: "m1", Instruction: "hangup"}))
+	assert.NoError(t, h.notifier.Notify(ctx, "m1"))
+	msg = readDeviceMessage(t, conn)
+	assert.Equal(t, types.DeviceMessageCommands, msg.Type)
+	assert.Equal(t, "hangup", msg.Commands[0].Instruction)
+	assert.Less(t, time.Since(start), time.Second)
+	callHistory, err := h.callHistoryDao.GetByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.CallStateDispatched, callHistory.State)
+	assert.NotNil(t, callHistory.DispatchedAt)
+
+	// status
+	batteryLevel := 42
+	assert.NoError(t, conn.WriteJSON(&types.DeviceMessage{Type: types.DeviceMessageStatus,
+		Status: &types.UpsertClientsMetadataRequest{BatteryLevel: &batteryLevel}}))
+	batteryLevel = 101
+	assert.NoError(t, conn.WriteJSON(&types.DeviceMessage{Type: types.DeviceMessageStatus,
+		Status: &types.UpsertClientsMetadataRequest{BatteryLevel: &batteryLevel}}))
+	msg = readDeviceMessage(t, conn)
+	assert.Equal(t, types.DeviceMessageError, msg.Type)
+	clients, err := h.clientsDao.GetByMachineCode(ctx, "m1")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, clients.BatteryLevel)
+	lastSeen, err := h.presence.MultiGet(ctx, []string{"m1"})
+	assert.NoError(t, err)
+	assert.Contains(t, lastSeen, "m1")
+
+	// unknown messages
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	assert.Equal(t, types.DeviceMessageError, readDeviceMessage(t, conn).Type)
+	assert.NoError(t, conn.WriteJSON(&types.DeviceMessage{Type: "reboot"}))
+	assert.Equal(t, types.DeviceMessageError, readDeviceMessage(t, conn).Type)
+
+	// the machine code of another device
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?machineCode=m1", http.Header{"X-Device": []string{"m2"}})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	// machine code is required
+	_, resp, err = websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	_ = resp.Body.Close()
+
+	// the connections are drained on shutdown
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assert.NoError(t, hub.Shutdown(ctx))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
@@ -24,8 +26,10 @@ var _ DistributionHandler = (*distributionHandler)(nil)
 // DistributionHandler defining the handler interface
 type DistributionHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,6 +37,10 @@ type DistributionHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
 }
 
 type distributionHandler struct {
@@ -87,6 +95,70 @@ func (h *distributionHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": distribution.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create distributions in batch
+// @Description submit at most 100 distributions in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags distribution
+// @accept json
+// @Produce json
+// @Param data body types.CreateDistributionsBatchRequest true "distribution information"
+// @Success 200 {object} types.CreateDistributionsBatchRespond{}
+// @Router /api/v1/distribution/batch [post]
+// @Security BearerAuth
+func (h *distributionHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateDistributionsBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.Distribution, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.Distribution{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateDistribution.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateDistribution.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete distribution
 // @Description delete distribution by id
@@ -94,6 +166,7 @@ func (h *distributionHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteDistributionByIDRespond{}
 // @Router /api/v1/distribution/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +177,25 @@ func (h *distributionHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +209,7 @@ func (h *distributionHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateDistributionByIDRequest true "distribution information"
 // @Success 200 {object} types.UpdateDistributionByIDRespond{}
 // @Router /api/v1/distribution/{id} [put]
@@ -133,6 +221,12 @@ func (h *distributionHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateDistributionByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +243,76 @@ func (h *distributionHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	distribution.Version = version
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, distribution)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch distribution
+// @Description update the distribution fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags distribution
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchDistributionByIDRequest true "distribution fields to update"
+// @Success 200 {object} types.PatchDistributionByIDRespond{}
+// @Router /api/v1/distribution/{id} [patch]
+// @Security BearerAuth
+func (h *distributionHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getDistributionIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +327,7 @@ func (h *distributionHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetDistributionByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/distribution/{id} [get]
 // @Security BearerAuth
 func (h *distributionHandler) GetByID(c *gin.Context) {
@@ -200,6 +359,7 @@ func (h *distributionHandler) GetByID(c *gin.Context) {
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
 
+	setETag(c, distribution.Version)
 	response.Success(c, gin.H{"distribution": data})
 }
 
@@ -366,23 +526,65 @@ func (h *distributionHandler) ListByIDs(c *gin.Context) {
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of distributions by last id and limit
-// @Description list of distributions by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of distributions by cursor and limit
+// @Description list of distributions by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags distribution
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListDistributionsRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, user_id, group_call_id, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListDistributionsByCursorRespond{}
 // @Router /api/v1/distribution/list [get]
 // @Security BearerAuth
 func (h *distributionHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
 	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	distributions, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertDistributions(distributions)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDDistribution)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"distributions": data,
+		"nextCursor":    nextCursor,
+	})
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted distributions by cursor and limit
+// @Description list of deleted distributions by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags distribution
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, user_id, group_call_id, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListDistributionsByCursorRespond{}
+// @Router /api/v1/distribution/trash [get]
+// @Security BearerAuth
+func (h *distributionHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +592,14 @@ func (h *distributionHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	distributions, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	distributions, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -405,9 +612,76 @@ func (h *distributionHandler) ListByLastID(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"distributions": data,
+		"nextCursor":    nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore distribution
+// @Description restore a deleted distribution by id
+// @Tags distribution
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreDistributionByIDRespond{}
+// @Router /api/v1/distribution/{id}/restore [post]
+// @Security BearerAuth
+func (h *distributionHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getDistributionIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge distribution
+// @Description permanently delete a distribution in the trash by id, it cannot be restored afterwards
+// @Tags distribution
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeDistributionByIDRespond{}
+// @Router /api/v1/distribution/trash/{id} [delete]
+// @Security BearerAuth
+func (h *distributionHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getDistributionIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
 func getDistributionIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -427,6 +701,10 @@ func convertDistribution(distribution *model.Distribution) (*types.DistributionO
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(distribution.ID)
+	if distribution.DeletedAt.Valid {
+		deletedAt := distribution.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -50,6 +51,12 @@ func newDistributionHandler() *gotest.Handler {
 			Path:        "/distribution",
 			HandlerFunc: iHandler.Create,
 		},
+		{
+			FuncName:    "CreateBatch",
+			Method:      http.MethodPost,
+			Path:        "/distribution/batch",
+			HandlerFunc: iHandler.CreateBatch,
+		},
 		{
 			FuncName:    "DeleteByID",
 			Method:      http.MethodDelete,
@@ -62,6 +69,12 @@ func newDistributionHandler() *gotest.Handler {
 			Path:        "/distribution/:id",
 			HandlerFunc: iHandler.UpdateByID,
 		},
+		{
+			FuncName:    "PatchByID",
+			Method:      http.MethodPatch,
+			Path:        "/distribution/:id",
+			HandlerFunc: iHandler.PatchByID,
+		},
 		{
 			FuncName:    "GetByID",
 			Method:      http.MethodGet,
@@ -98,6 +111,24 @@ func newDistributionHandler() *gotest.Handler {
 			Path:        "/distribution/list",
 			HandlerFunc: iHandler.ListByLastID,
 		},
+		{
+			FuncName:    "ListTrash",
+			Method:      http.MethodGet,
+			Path:        "/distribution/trash",
+			HandlerFunc: iHandler.ListTrash,
+		},
+		{
+			FuncName:    "RestoreByID",
+			Method:      http.MethodPost,
+			Path:        "/distribution/:id/restore",
+			HandlerFunc: iHandler.RestoreByID,
+		},
+		{
+			FuncName:    "PurgeByID",
+			Method:      http.MethodDelete,
+			Path:        "/distribution/trash/:id",
+			HandlerFunc: iHandler.PurgeByID,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -129,6 +160,40 @@ func Test_distributionHandler_Create(t *testing.T) {
 
 }
 
+func Test_distributionHandler_CreateBatch(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := &types.CreateDistributionsBatchRequest{
+		Atomic:  true,
+		Records: []types.CreateDistributionRequest{{}, {}},
+	}
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, result.Code)
+
+	// empty batch
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), &types.CreateDistributionsBatchRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the whole transaction fails
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	assert.Error(t, err)
+}
+
 func Test_distributionHandler_DeleteByID(t *testing.T) {
 	h := newDistributionHandler()
 	defer h.Close()
@@ -190,6 +255,75 @@ func Test_distributionHandler_UpdateByID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_distributionHandler_UpdateByIDWithIfMatch(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := &types.UpdateDistributionByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.Distribution))
+
+	// the record has been changed by another request
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+
+	req := &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `"1"`)
+	req.SetJSONBody(testData)
+	resp, err := req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	_, _ = resp.ReadBody()
+
+	// invalid If-Match error test
+	req = &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `W/"1"`)
+	req.SetJSONBody(testData)
+	resp, err = req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &gohttp.StdResult{}
+	err = resp.BindJSON(result)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+}
+
+func Test_distributionHandler_PatchByID(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Distribution)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"groupCallId": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// read-only field error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+
+	// patch error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", 111), map[string]interface{}{"groupCallId": nil})
+	assert.Error(t, err)
+}
+
 func Test_distributionHandler_GetByID(t *testing.T) {
 	h := newDistributionHandler()
 	defer h.Close()
@@ -385,6 +519,8 @@ func Test_distributionHandler_ListByLastID(t *testing.T) {
 	// error test
 	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"lastID": 0, "size": 10, "sort": "unknown-column"})
 	assert.Error(t, err)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"cursor": "invalid", "size": 10})
+	assert.Error(t, err)
 }
 
 func TestNewDistributionHandler(t *testing.T) {
@@ -393,3 +529,84 @@ func TestNewDistributionHandler(t *testing.T) {
 	}()
 	_ = NewDistributionHandler()
 }
+
+func Test_distributionHandler_ListTrash(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Distribution)
+
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"}).
+		AddRow(testData.ID, time.Now())
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// error test
+	err = gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"sort": "unknown-column"})
+	assert.Error(t, err)
+}
+
+func Test_distributionHandler_RestoreByID(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Distribution)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RestoreByID", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 0), nil)
+	assert.NoError(t, err)
+
+	// restore error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 111), nil)
+	assert.Error(t, err)
+}
+
+func Test_distributionHandler_PurgeByID(t *testing.T) {
+	h := newDistributionHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.Distribution)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("PurgeByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 0))
+	assert.NoError(t, err)
+
+	// purge error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 111))
+	assert.Error(t, err)
+}
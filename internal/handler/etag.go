@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/logger"
+)
+
+// setETag set the ETag header of the response to the version of a record
+func setETag(c *gin.Context, version uint64) {
+	c.Header("ETag", `"`+strconv.FormatUint(version, 10)+`"`)
+}
+
+// getIfMatchVersion get the version in the If-Match header, 0 means that the header is absent or "*"
+// and the version is not checked, isAbort is true if the header is not a single ETag returned by GetByID.
+func getIfMatchVersion(c *gin.Context) (uint64, bool) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, false
+	}
+
+	tag := strings.TrimSuffix(strings.TrimPrefix(ifMatch, `"`), `"`)
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version == 0 || len(tag)+2 != len(ifMatch) {
+		logger.Warn("invalid If-Match header", logger.String("ifMatch", ifMatch), middleware.GCtxRequestIDField(c))
+		return 0, true
+	}
+
+	return version, false
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getIfMatchVersion(t *testing.T) {
+	testCases := []struct {
+		ifMatch string
+		version uint64
+		isAbort bool
+	}{
+		{ifMatch: "", version: 0},
+		{ifMatch: "*", version: 0},
+		{ifMatch: `"3"`, version: 3},
+		{ifMatch: ` "12" `, version: 12},
+		{ifMatch: "3", isAbort: true},
+		{ifMatch: `W/"3"`, isAbort: true},
+		{ifMatch: `"0"`, isAbort: true},
+		{ifMatch: `"1", "2"`, isAbort: true},
+		{ifMatch: `"abc"`, isAbort: true},
+	}
+	for _, tc := range testCases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+		c.Request.Header.Set("If-Match", tc.ifMatch)
+		version, isAbort := getIfMatchVersion(c)
+		assert.Equal(t, tc.version, version, tc.ifMatch)
+		assert.Equal(t, tc.isAbort, isAbort, tc.ifMatch)
+	}
+}
+
+func Test_setETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	setETag(c, 7)
+	assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
@@ -13,9 +17,13 @@ import (
 	"github.com/zhufuyi/sponge/pkg/utils"
 
 	"caller/internal/cache"
+	"caller/internal/config"
 	"caller/internal/dao"
 	"caller/internal/ecode"
+	"caller/internal/instruction"
+	"caller/internal/labels"
 	"caller/internal/model"
+	"caller/internal/routing"
 	"caller/internal/types"
 )
 
@@ -24,8 +32,10 @@ var _ GroupCallHandler = (*groupCallHandler)(nil)
 // GroupCallHandler defining the handler interface
 type GroupCallHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,20 +43,54 @@ type GroupCallHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	GetLabels(c *gin.Context)
+	SetLabels(c *gin.Context)
+	PatchLabels(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
+
+	Dial(c *gin.Context)
 }
 
 type groupCallHandler struct {
-	iDao dao.GroupCallDao
+	iDao           dao.GroupCallDao
+	clientsDao     dao.ClientsDao
+	callHistoryDao dao.CallHistoryDao
+	simDao         dao.SimDao
+	notifier       cache.DeviceCommandNotifier
+
+	strategy     string        // the strategy a dial chooses the client with if the request does not name one
+	stickyWindow time.Duration // how long a callee sticks to the client that called it
 }
 
 // NewGroupCallHandler creating the handler interface
 func NewGroupCallHandler() GroupCallHandler {
-	return &groupCallHandler{
+	cfg := config.Get().Dial
+	h := &groupCallHandler{
 		iDao: dao.NewGroupCallDao(
 			model.GetDB(),
 			cache.NewGroupCallCache(model.GetCacheType()),
 		),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
+		callHistoryDao: dao.NewCallHistoryDao(
+			model.GetDB(),
+			cache.NewCallHistoryCache(model.GetCacheType()),
+		),
+		simDao:       dao.NewSimDao(model.GetDB()),
+		notifier:     cache.NewDeviceCommandNotifier(model.GetCacheType()),
+		strategy:     cfg.Strategy,
+		stickyWindow: time.Duration(cfg.StickyWindow) * time.Minute,
+	}
+	if h.strategy == "" {
+		h.strategy = routing.RoundRobin
 	}
+	return h
 }
 
 // Create a record
@@ -87,6 +131,70 @@ func (h *groupCallHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": groupCall.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create groupCalls in batch
+// @Description submit at most 100 groupCalls in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param data body types.CreateGroupCallsBatchRequest true "groupCall information"
+// @Success 200 {object} types.CreateGroupCallsBatchRespond{}
+// @Router /api/v1/groupCall/batch [post]
+// @Security BearerAuth
+func (h *groupCallHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateGroupCallsBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.GroupCall, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.GroupCall{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateGroupCall.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateGroupCall.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete groupCall
 // @Description delete groupCall by id
@@ -94,6 +202,7 @@ func (h *groupCallHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteGroupCallByIDRespond{}
 // @Router /api/v1/groupCall/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +213,25 @@ func (h *groupCallHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +245,7 @@ func (h *groupCallHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateGroupCallByIDRequest true "groupCall information"
 // @Success 200 {object} types.UpdateGroupCallByIDRespond{}
 // @Router /api/v1/groupCall/{id} [put]
@@ -133,6 +257,12 @@ func (h *groupCallHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateGroupCallByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +279,76 @@ func (h *groupCallHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	groupCall.Version = version
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, groupCall)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch groupCall
+// @Description update the groupCall fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchGroupCallByIDRequest true "groupCall fields to update"
+// @Success 200 {object} types.PatchGroupCallByIDRespond{}
+// @Router /api/v1/groupCall/{id} [patch]
+// @Security BearerAuth
+func (h *groupCallHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +363,7 @@ func (h *groupCallHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetGroupCallByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/groupCall/{id} [get]
 // @Security BearerAuth
 func (h *groupCallHandler) GetByID(c *gin.Context) {
@@ -199,7 +394,9 @@ func (h *groupCallHandler) GetByID(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
+	h.setLabels(c, data)
 
+	setETag(c, groupCall.Version)
 	response.Success(c, gin.H{"groupCall": data})
 }
 
@@ -209,7 +406,7 @@ func (h *groupCallHandler) GetByID(c *gin.Context) {
 // @Tags groupCall
 // @accept json
 // @Produce json
-// @Param data body types.Params true "query parameters"
+// @Param data body types.LabelParams true "query parameters and label selector"
 // @Success 200 {object} types.ListGroupCallsRespond{}
 // @Router /api/v1/groupCall/list [post]
 // @Security BearerAuth
@@ -222,8 +419,14 @@ func (h *groupCallHandler) List(c *gin.Context) {
 		return
 	}
 
+	selector, isAbort := parseLabelSelector(c, form.LabelSelector)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	groupCalls, total, err := h.iDao.GetByColumns(ctx, &form.Params)
+	groupCalls, total, err := h.iDao.GetByColumnsWithScopes(ctx, &form.Params, h.iDao.LabelScope(selector))
 	if err != nil {
 		logger.Error("GetByColumns error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
@@ -235,6 +438,7 @@ func (h *groupCallHandler) List(c *gin.Context) {
 		response.Error(c, ecode.ErrListGroupCall)
 		return
 	}
+	h.setLabels(c, data...)
 
 	response.Success(c, gin.H{
 		"groupCalls": data,
@@ -318,6 +522,7 @@ func (h *groupCallHandler) GetByCondition(c *gin.Context) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(groupCall.ID)
+	h.setLabels(c, data)
 
 	response.Success(c, gin.H{"groupCall": data})
 }
@@ -360,29 +565,140 @@ func (h *groupCallHandler) ListByIDs(c *gin.Context) {
 			groupCalls = append(groupCalls, record)
 		}
 	}
+	h.setLabels(c, groupCalls...)
 
 	response.Success(c, gin.H{
 		"groupCalls": groupCalls,
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of groupCalls by last id and limit
-// @Description list of groupCalls by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of groupCalls by cursor and limit
+// @Description list of groupCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags groupCall
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListGroupCallsRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, group_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Param labelSelector query string false "select the groupCalls by their labels, e.g. site=shanghai,carrier!=cmcc"
+// @Success 200 {object} types.ListGroupCallsByCursorRespond{}
 // @Router /api/v1/groupCall/list [get]
 // @Security BearerAuth
 func (h *groupCallHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+	selector, isAbort := parseLabelSelector(c, c.Query("labelSelector"))
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	groupCalls, nextCursor, err := h.iDao.GetByCursorWithScopes(ctx, cursor, limit, sort, h.iDao.LabelScope(selector))
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertGroupCalls(groupCalls)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDGroupCall)
+		return
 	}
+	h.setLabels(c, data...)
+
+	response.Success(c, gin.H{
+		"groupCalls": data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// GetLabels get the labels of a record
+// @Summary get the labels of groupCall
+// @Description get the key/value labels of groupCall by id
+// @Tags groupCall
+// @Param id path string true "id"
+// @Produce json
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/groupCall/{id}/labels [get]
+// @Security BearerAuth
+func (h *groupCallHandler) GetLabels(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	serveGetLabels(c, h.iDao, id)
+}
+
+// SetLabels replace the labels of a record
+// @Summary set the labels of groupCall
+// @Description replace all the key/value labels of groupCall by id, a key starts and ends with a letter or digit and may have - _ . / in between, a value may be empty and may not have /, both have at most 63 characters, a groupCall has at most 64 labels
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.SetLabelsRequest true "labels"
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/groupCall/{id}/labels [put]
+// @Security BearerAuth
+func (h *groupCallHandler) SetLabels(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	serveSetLabels(c, h.iDao, id)
+}
+
+// PatchLabels add, change or remove some labels of a record
+// @Summary patch the labels of groupCall
+// @Description add or change the labels in the request, a label with a null value is removed, the other labels of the groupCall are kept
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.PatchLabelsRequest true "labels"
+// @Success 200 {object} types.LabelsRespond{}
+// @Router /api/v1/groupCall/{id}/labels [patch]
+// @Security BearerAuth
+func (h *groupCallHandler) PatchLabels(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	servePatchLabels(c, h.iDao, id)
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted groupCalls by cursor and limit
+// @Description list of deleted groupCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, group_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListGroupCallsByCursorRespond{}
+// @Router /api/v1/groupCall/trash [get]
+// @Security BearerAuth
+func (h *groupCallHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +706,14 @@ func (h *groupCallHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	groupCalls, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	groupCalls, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -405,9 +726,245 @@ func (h *groupCallHandler) ListByLastID(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"groupCalls": data,
+		"nextCursor": nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore groupCall
+// @Description restore a deleted groupCall by id
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreGroupCallByIDRespond{}
+// @Router /api/v1/groupCall/{id}/restore [post]
+// @Security BearerAuth
+func (h *groupCallHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge groupCall
+// @Description permanently delete a groupCall in the trash by id, it cannot be restored afterwards
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeGroupCallByIDRespond{}
+// @Router /api/v1/groupCall/trash/{id} [delete]
+// @Security BearerAuth
+func (h *groupCallHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getGroupCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// Dial place a call by a group
+// @Summary dial by groupCall
+// @Description choose an active client of the group by its group number and create the dial instruction of the call for it.
+// @Description the client is chosen from the members of the group whose labels match the label selector, with the strategy
+// @Description of the request or else the strategy of the config: roundRobin, leastRecentlyUsed, random or stickyPerCallee
+// @Tags groupCall
+// @accept json
+// @Produce json
+// @Param groupNumber path string true "group number"
+// @Param data body types.DialGroupCallRequest true "call information"
+// @Success 200 {object} types.DialGroupCallRespond{}
+// @Router /api/v1/groupCall/{groupNumber}/dial [post]
+// @Security BearerAuth
+func (h *groupCallHandler) Dial(c *gin.Context) {
+	// the wildcard of the route is named id like the other routes of groupCall, it holds the group number
+	groupNumber := c.Param("id")
+
+	form := &types.DialGroupCallRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Strategy == "" {
+		form.Strategy = h.strategy
+	}
+
+	selector, isAbort := parseLabelSelector(c, form.LabelSelector)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	payload, err := instruction.Validate(instruction.Dial, form.MobileNumber, nil)
+	if err != nil {
+		logger.Warn("Validate instruction error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, instructionError(err))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	groupCall, err := h.iDao.GetByGroupNumber(ctx, groupNumber)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByGroupNumber not found", logger.Err(err), logger.String("groupNumber", groupNumber), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByGroupNumber error", logger.Err(err), logger.String("groupNumber", groupNumber), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	candidates, simIDs, err := h.getDialCandidates(ctx, groupCall.ID, selector, form.SimSlot)
+	if err != nil {
+		logger.Error("getDialCandidates error", logger.Err(err), logger.Any("groupCallId", groupCall.ID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	// the client is chosen and its call created in one transaction that locks the group, the strategy
+	// reads the calls of the dials of the group before it, concurrent dials do not choose the same client
+	var client *model.Clients
+	callHistory, err := h.callHistoryDao.CreateByGroup(ctx, groupCall.ID, func(history dao.CallHistoryDao) (*model.CallHistory, error) {
+		strategy, err := routing.New(form.Strategy, history, routing.WithStickyWindow(h.stickyWindow))
+		if err != nil {
+			return nil, err
+		}
+		call := &routing.Call{GroupCallID: groupCall.ID, MobileNumber: form.MobileNumber}
+		client, err = routing.Select(ctx, strategy, call, candidates)
+		if err != nil {
+			return nil, err
+		}
+		return &model.CallHistory{
+			RequestMachineCode: form.RequestMachineCode,
+			ClientMachineCode:  client.MachineCode,
+			MobileNumber:       form.MobileNumber,
+			Instruction:        instruction.Dial,
+			Payload:            payload,
+			GroupCallID:        groupCall.ID,
+			SimID:              simIDs[client.MachineCode],
+		}, nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, routing.ErrNoCandidate):
+			logger.Warn("Select no candidate", logger.Any("groupCallId", groupCall.ID), logger.String("labelSelector", form.LabelSelector), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrNoClientGroupCall)
+		case errors.Is(err, routing.ErrUnknownStrategy):
+			// the strategy of the request is checked by binding, only the strategy of the config can be unknown
+			logger.Error("routing.New error", logger.Err(err), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		case errors.Is(err, model.ErrRecordNotFound):
+			// the group was deleted after it was read
+			logger.Warn("CreateByGroup not found", logger.Err(err), logger.String("groupNumber", groupNumber), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		case inactiveClientsError(err) != nil:
+			// the client was disabled or quarantined after it was chosen
+			logger.Warn("CreateByGroup error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, inactiveClientsError(err))
+		default:
+			logger.Error("CreateByGroup error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrDialGroupCall)
+		}
+		return
+	}
+	notifyDeviceCommand(c, h.notifier, client.MachineCode)
+
+	response.Success(c, gin.H{
+		"callHistoryId": utils.Uint64ToStr(callHistory.ID),
+		"clientId":      utils.Uint64ToStr(client.ID),
+		"machineCode":   client.MachineCode,
+		"strategy":      form.Strategy,
+	})
+}
+
+// getDialCandidates get the active members of a group that can place a call, ordered by id,
+// the members without a machine code or whose labels do not match the selector are left out.
+// if simSlot is given, the members without a sim in the slot are left out too, and the ids of the sims
+// in the slot are returned by the machine codes of the members.
+func (h *groupCallHandler) getDialCandidates(ctx context.Context, groupCallID uint64, selector labels.Selector,
+	simSlot *int) ([]*model.Clients, map[string]uint64, error) {
+	members, err := h.clientsDao.GetMembersByGroupID(ctx, int(groupCallID), true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var labelMap map[uint64]map[string]string
+	if !selector.Empty() {
+		ids := make([]uint64, 0, len(members))
+		for _, member := range members {
+			ids = append(ids, member.ID)
+		}
+		labelMap, err = h.clientsDao.GetLabelsByIDs(ctx, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	candidates := make([]*model.Clients, 0, len(members))
+	for _, member := range members {
+		if member.MachineCode == "" || !selector.Matches(labelMap[member.ID]) {
+			continue
+		}
+		candidates = append(candidates, member)
+	}
+	if simSlot == nil {
+		return candidates, nil, nil
+	}
+
+	// only the clients with a sim in the slot can call with it
+	machineCodes := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		machineCodes = append(machineCodes, candidate.MachineCode)
+	}
+	simIDs, err := h.simDao.GetIDsBySlot(ctx, machineCodes, *simSlot)
+	if err != nil {
+		return nil, nil, err
+	}
+	withSim := make([]*model.Clients, 0, len(candidates))
+	for _, candidate := range candidates {
+		if simIDs[candidate.MachineCode] != 0 {
+			withSim = append(withSim, candidate)
+		}
+	}
+	return withSim, simIDs, nil
+}
+
 func getGroupCallIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -419,6 +976,18 @@ func getGroupCallIDFromPath(c *gin.Context) (string, uint64, bool) {
 	return idStr, id, false
 }
 
+// setLabels set the labels of the groupCalls, the groupCalls are shown without labels if the labels cannot be read
+func (h *groupCallHandler) setLabels(c *gin.Context, groupCalls ...*types.GroupCallObjDetail) {
+	ids := make([]uint64, 0, len(groupCalls))
+	for _, v := range groupCalls {
+		ids = append(ids, utils.StrToUint64(v.ID))
+	}
+	itemMap := getLabelsByIDs(c, h.iDao, ids)
+	for _, v := range groupCalls {
+		v.Labels = itemMap[utils.StrToUint64(v.ID)]
+	}
+}
+
 func convertGroupCall(groupCall *model.GroupCall) (*types.GroupCallObjDetail, error) {
 	data := &types.GroupCallObjDetail{}
 	err := copier.Copy(data, groupCall)
@@ -427,6 +996,10 @@ func convertGroupCall(groupCall *model.GroupCall) (*types.GroupCallObjDetail, er
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(groupCall.ID)
+	if groupCall.DeletedAt.Valid {
+		deletedAt := groupCall.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
@@ -1,14 +1,19 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/zhufuyi/sponge/pkg/ggorm"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
 	"github.com/zhufuyi/sponge/pkg/gohttp"
 	"github.com/zhufuyi/sponge/pkg/gotest"
@@ -16,7 +21,10 @@ import (
 
 	"caller/internal/cache"
 	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/instruction"
 	"caller/internal/model"
+	"caller/internal/routing"
 	"caller/internal/types"
 )
 
@@ -50,6 +58,12 @@ func newGroupCallHandler() *gotest.Handler {
 			Path:        "/groupCall",
 			HandlerFunc: iHandler.Create,
 		},
+		{
+			FuncName:    "CreateBatch",
+			Method:      http.MethodPost,
+			Path:        "/groupCall/batch",
+			HandlerFunc: iHandler.CreateBatch,
+		},
 		{
 			FuncName:    "DeleteByID",
 			Method:      http.MethodDelete,
@@ -62,6 +76,12 @@ func newGroupCallHandler() *gotest.Handler {
 			Path:        "/groupCall/:id",
 			HandlerFunc: iHandler.UpdateByID,
 		},
+		{
+			FuncName:    "PatchByID",
+			Method:      http.MethodPatch,
+			Path:        "/groupCall/:id",
+			HandlerFunc: iHandler.PatchByID,
+		},
 		{
 			FuncName:    "GetByID",
 			Method:      http.MethodGet,
@@ -98,6 +118,24 @@ func newGroupCallHandler() *gotest.Handler {
 			Path:        "/groupCall/list",
 			HandlerFunc: iHandler.ListByLastID,
 		},
+		{
+			FuncName:    "ListTrash",
+			Method:      http.MethodGet,
+			Path:        "/groupCall/trash",
+			HandlerFunc: iHandler.ListTrash,
+		},
+		{
+			FuncName:    "RestoreByID",
+			Method:      http.MethodPost,
+			Path:        "/groupCall/:id/restore",
+			HandlerFunc: iHandler.RestoreByID,
+		},
+		{
+			FuncName:    "PurgeByID",
+			Method:      http.MethodDelete,
+			Path:        "/groupCall/trash/:id",
+			HandlerFunc: iHandler.PurgeByID,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -129,6 +167,40 @@ func Test_groupCallHandler_Create(t *testing.T) {
 
 }
 
+func Test_groupCallHandler_CreateBatch(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := &types.CreateGroupCallsBatchRequest{
+		Atomic:  true,
+		Records: []types.CreateGroupCallRequest{{}, {}},
+	}
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, result.Code)
+
+	// empty batch
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), &types.CreateGroupCallsBatchRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the whole transaction fails
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	assert.Error(t, err)
+}
+
 func Test_groupCallHandler_DeleteByID(t *testing.T) {
 	h := newGroupCallHandler()
 	defer h.Close()
@@ -190,6 +262,75 @@ func Test_groupCallHandler_UpdateByID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_groupCallHandler_UpdateByIDWithIfMatch(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := &types.UpdateGroupCallByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.GroupCall))
+
+	// the record has been changed by another request
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+
+	req := &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `"1"`)
+	req.SetJSONBody(testData)
+	resp, err := req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	_, _ = resp.ReadBody()
+
+	// invalid If-Match error test
+	req = &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `W/"1"`)
+	req.SetJSONBody(testData)
+	resp, err = req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &gohttp.StdResult{}
+	err = resp.BindJSON(result)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+}
+
+func Test_groupCallHandler_PatchByID(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupCall)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"transferClientId": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// read-only field error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+
+	// patch error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", 111), map[string]interface{}{"transferClientId": nil})
+	assert.Error(t, err)
+}
+
 func Test_groupCallHandler_GetByID(t *testing.T) {
 	h := newGroupCallHandler()
 	defer h.Close()
@@ -233,7 +374,7 @@ func Test_groupCallHandler_List(t *testing.T) {
 	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
 
 	result := &gohttp.StdResult{}
-	err := gohttp.Post(result, h.GetRequestURL("List"), &types.ListGroupCallsRequest{query.Params{
+	err := gohttp.Post(result, h.GetRequestURL("List"), &types.ListGroupCallsRequest{Params: query.Params{
 		Page: 0,
 		Size: 10,
 		Sort: "ignore count", // ignore test count
@@ -250,7 +391,7 @@ func Test_groupCallHandler_List(t *testing.T) {
 	assert.NoError(t, err)
 
 	// get error test
-	err = gohttp.Post(result, h.GetRequestURL("List"), &types.ListGroupCallsRequest{query.Params{
+	err = gohttp.Post(result, h.GetRequestURL("List"), &types.ListGroupCallsRequest{Params: query.Params{
 		Page: 0,
 		Size: 10,
 		Sort: "unknown-column",
@@ -385,6 +526,8 @@ func Test_groupCallHandler_ListByLastID(t *testing.T) {
 	// error test
 	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"lastID": 0, "size": 10, "sort": "unknown-column"})
 	assert.Error(t, err)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"cursor": "invalid", "size": 10})
+	assert.Error(t, err)
 }
 
 func TestNewGroupCallHandler(t *testing.T) {
@@ -393,3 +536,189 @@ func TestNewGroupCallHandler(t *testing.T) {
 	}()
 	_ = NewGroupCallHandler()
 }
+
+func Test_groupCallHandler_ListTrash(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupCall)
+
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"}).
+		AddRow(testData.ID, time.Now())
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// error test
+	err = gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"sort": "unknown-column"})
+	assert.Error(t, err)
+}
+
+func Test_groupCallHandler_RestoreByID(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupCall)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RestoreByID", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 0), nil)
+	assert.NoError(t, err)
+
+	// restore error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 111), nil)
+	assert.Error(t, err)
+}
+
+func Test_groupCallHandler_PurgeByID(t *testing.T) {
+	h := newGroupCallHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupCall)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE FROM `resource_label` .*").
+		WithArgs("group_call", testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("PurgeByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 0))
+	assert.NoError(t, err)
+
+	// purge error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_groupCallHandler_Dial(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &groupCallHandler{
+		iDao:           dao.NewGroupCallDao(db, nil),
+		clientsDao:     dao.NewClientsDao(db, nil),
+		callHistoryDao: dao.NewCallHistoryDao(db, nil),
+		simDao:         dao.NewSimDao(db),
+		notifier:       cache.NewDeviceCommandNotifier(&model.CacheType{}),
+		strategy:       routing.RoundRobin,
+		stickyWindow:   time.Hour,
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/groupCall/:id/dial", h.Dial)
+
+	clients := []*model.Clients{
+		{MachineCode: "m1", Status: model.ClientsStatusActive},
+		{MachineCode: "m2", Status: model.ClientsStatusActive},
+		{MachineCode: "m3", Status: model.ClientsStatusDisabled},
+	}
+	for _, client := range clients {
+		assert.NoError(t, h.clientsDao.Create(ctx, client))
+	}
+	_, err := h.clientsDao.SetLabels(ctx, 2, map[string]string{"site": "shanghai"})
+	assert.NoError(t, err)
+	for _, groupNumber := range []string{"1001", "1002"} {
+		assert.NoError(t, h.iDao.Create(ctx, &model.GroupCall{GroupNumber: groupNumber}))
+	}
+	members := []*model.GroupClient{{GroupID: 1, ClientID: 1}, {GroupID: 1, ClientID: 2}, {GroupID: 1, ClientID: 3}, {GroupID: 2, ClientID: 3}}
+	for _, member := range members {
+		assert.NoError(t, db.Create(member).Error)
+	}
+	dial := func(groupNumber string, body string) (string, *gohttp.StdResult) {
+		result := doLabelsRequest(t, r, http.MethodPost, "/groupCall/"+groupNumber+"/dial", body)
+		data, _ := result.Data.(map[string]interface{})
+		machineCode, _ := data["machineCode"].(string)
+		return machineCode, result
+	}
+
+	// round robin goes through the active members, the disabled client is skipped
+	for i, want := range []string{"m1", "m2", "m1"} {
+		machineCode, result := dial("1001", fmt.Sprintf(`{"mobileNumber":"1390000000%d","requestMachineCode":"r1"}`, i))
+		assert.Equal(t, 0, result.Code, result.Msg)
+		assert.Equal(t, want, machineCode)
+		assert.Equal(t, routing.RoundRobin, result.Data.(map[string]interface{})["strategy"])
+	}
+	callHistory, err := h.callHistoryDao.GetByID(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", callHistory.ClientMachineCode)
+	machineCode, result := dial("1001", `{"mobileNumber":"13900000003"}`)
+	assert.Equal(t, "m2", machineCode)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, "4", result.Data.(map[string]interface{})["callHistoryId"])
+	assert.Equal(t, "2", result.Data.(map[string]interface{})["clientId"])
+	assert.Equal(t, "r1", callHistory.RequestMachineCode)
+	assert.Equal(t, "13900000002", callHistory.MobileNumber)
+	assert.Equal(t, instruction.Dial, callHistory.Instruction)
+	assert.Equal(t, uint64(1), callHistory.GroupCallID)
+
+	// the strategy of the request, the callee sticks to the client that called it
+	machineCode, result = dial("1001", `{"mobileNumber":"13900000001","strategy":"stickyPerCallee"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, "m2", machineCode)
+	assert.Equal(t, routing.StickyPerCallee, result.Data.(map[string]interface{})["strategy"])
+	_, result = dial("1001", `{"mobileNumber":"13900000001","strategy":"first"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// the label selector
+	machineCode, result = dial("1001", `{"mobileNumber":"13900000001","labelSelector":"site=shanghai","strategy":"random"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, "m2", machineCode)
+	_, result = dial("1001", `{"mobileNumber":"13900000001","labelSelector":"site=beijing"}`)
+	assert.Equal(t, ecode.ErrNoClientGroupCall.Code(), result.Code)
+	_, result = dial("1001", `{"mobileNumber":"13900000001","labelSelector":"site in (shanghai"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// only the clients with a sim in the slot
+	_, result = dial("1001", `{"mobileNumber":"13900000001","simSlot":1}`)
+	assert.Equal(t, ecode.ErrNoClientGroupCall.Code(), result.Code)
+	_, err = h.simDao.Report(ctx, 2, []*model.Sim{{ICCID: "89860000000000000001", SlotIndex: 1}})
+	assert.NoError(t, err)
+	machineCode, result = dial("1001", `{"mobileNumber":"13900000001","simSlot":1}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, "m2", machineCode)
+	callHistory, err = h.callHistoryDao.GetByID(ctx, utils.StrToUint64(result.Data.(map[string]interface{})["callHistoryId"].(string)))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), callHistory.SimID)
+
+	// a group without an active member and an unknown group
+	_, result = dial("1002", `{"mobileNumber":"13900000001"}`)
+	assert.Equal(t, ecode.ErrNoClientGroupCall.Code(), result.Code)
+	_, result = dial("1003", `{"mobileNumber":"13900000001"}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	_, result = dial("1001", `{}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+}
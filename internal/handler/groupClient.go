@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
@@ -24,8 +27,10 @@ var _ GroupClientHandler = (*groupClientHandler)(nil)
 // GroupClientHandler defining the handler interface
 type GroupClientHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,10 +38,16 @@ type GroupClientHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+	ListMembers(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
 }
 
 type groupClientHandler struct {
-	iDao dao.GroupClientDao
+	iDao       dao.GroupClientDao
+	clientsDao dao.ClientsDao
 }
 
 // NewGroupClientHandler creating the handler interface
@@ -46,6 +57,10 @@ func NewGroupClientHandler() GroupClientHandler {
 			model.GetDB(),
 			cache.NewGroupClientCache(model.GetCacheType()),
 		),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
 	}
 }
 
@@ -87,6 +102,70 @@ func (h *groupClientHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": groupClient.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create groupClients in batch
+// @Description submit at most 100 groupClients in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param data body types.CreateGroupClientsBatchRequest true "groupClient information"
+// @Success 200 {object} types.CreateGroupClientsBatchRespond{}
+// @Router /api/v1/groupClient/batch [post]
+// @Security BearerAuth
+func (h *groupClientHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateGroupClientsBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.GroupClient, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.GroupClient{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateGroupClient.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateGroupClient.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete groupClient
 // @Description delete groupClient by id
@@ -94,6 +173,7 @@ func (h *groupClientHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteGroupClientByIDRespond{}
 // @Router /api/v1/groupClient/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +184,25 @@ func (h *groupClientHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +216,7 @@ func (h *groupClientHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateGroupClientByIDRequest true "groupClient information"
 // @Success 200 {object} types.UpdateGroupClientByIDRespond{}
 // @Router /api/v1/groupClient/{id} [put]
@@ -133,6 +228,12 @@ func (h *groupClientHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateGroupClientByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +250,76 @@ func (h *groupClientHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	groupClient.Version = version
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, groupClient)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch groupClient
+// @Description update the groupClient fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchGroupClientByIDRequest true "groupClient fields to update"
+// @Success 200 {object} types.PatchGroupClientByIDRespond{}
+// @Router /api/v1/groupClient/{id} [patch]
+// @Security BearerAuth
+func (h *groupClientHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getGroupClientIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +334,7 @@ func (h *groupClientHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetGroupClientByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/groupClient/{id} [get]
 // @Security BearerAuth
 func (h *groupClientHandler) GetByID(c *gin.Context) {
@@ -200,6 +366,7 @@ func (h *groupClientHandler) GetByID(c *gin.Context) {
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
 
+	setETag(c, groupClient.Version)
 	response.Success(c, gin.H{"groupClient": data})
 }
 
@@ -366,23 +533,112 @@ func (h *groupClientHandler) ListByIDs(c *gin.Context) {
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of groupClients by last id and limit
-// @Description list of groupClients by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of groupClients by cursor and limit
+// @Description list of groupClients by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags groupClient
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListGroupClientsRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, group_id, client_id, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListGroupClientsByCursorRespond{}
 // @Router /api/v1/groupClient/list [get]
 // @Security BearerAuth
 func (h *groupClientHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	groupClients, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertGroupClients(groupClients)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDGroupClient)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"groupClients": data,
+		"nextCursor":   nextCursor,
+	})
+}
+
+// ListMembers get the clients of a group
+// @Summary list of the clients of a group
+// @Description list of the clients that are members of a group through groupClient, the disabled and quarantined clients are skipped unless all is true
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param groupId path int true "group id"
+// @Param all query bool false "true: include the clients that are not active" default(false)
+// @Success 200 {object} types.ListGroupClientMembersRespond{}
+// @Router /api/v1/groupClient/members/{groupId} [get]
+// @Security BearerAuth
+func (h *groupClientHandler) ListMembers(c *gin.Context) {
+	groupID, err := strconv.Atoi(c.Param("groupId"))
+	if err != nil || groupID <= 0 {
+		logger.Warn("Atoi error: ", logger.String("groupId", c.Param("groupId")), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	all := false
+	if v := c.Query("all"); v != "" {
+		all, err = strconv.ParseBool(v)
+		if err != nil {
+			logger.Warn("ParseBool error: ", logger.Err(err), logger.String("all", v), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clientss, err := h.clientsDao.GetMembersByGroupID(ctx, groupID, !all)
+	if err != nil {
+		logger.Error("GetMembersByGroupID error", logger.Err(err), logger.Int("groupId", groupID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertClientss(clientss)
+	if err != nil {
+		response.Error(c, ecode.ErrListMembersGroupClient)
+		return
 	}
+
+	response.Success(c, gin.H{
+		"clientss": data,
+	})
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted groupClients by cursor and limit
+// @Description list of deleted groupClients by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, group_id, client_id, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListGroupClientsByCursorRespond{}
+// @Router /api/v1/groupClient/trash [get]
+// @Security BearerAuth
+func (h *groupClientHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +646,14 @@ func (h *groupClientHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	groupClients, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	groupClients, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -405,9 +666,76 @@ func (h *groupClientHandler) ListByLastID(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"groupClients": data,
+		"nextCursor":   nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore groupClient
+// @Description restore a deleted groupClient by id
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreGroupClientByIDRespond{}
+// @Router /api/v1/groupClient/{id}/restore [post]
+// @Security BearerAuth
+func (h *groupClientHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getGroupClientIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge groupClient
+// @Description permanently delete a groupClient in the trash by id, it cannot be restored afterwards
+// @Tags groupClient
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeGroupClientByIDRespond{}
+// @Router /api/v1/groupClient/trash/{id} [delete]
+// @Security BearerAuth
+func (h *groupClientHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getGroupClientIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
 func getGroupClientIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -427,6 +755,10 @@ func convertGroupClient(groupClient *model.GroupClient) (*types.GroupClientObjDe
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(groupClient.ID)
+	if groupClient.DeletedAt.Valid {
+		deletedAt := groupClient.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 
 	"caller/internal/cache"
 	"caller/internal/dao"
+	"caller/internal/ecode"
 	"caller/internal/model"
 	"caller/internal/types"
 )
@@ -40,7 +42,10 @@ func newGroupClientHandler() *gotest.Handler {
 
 	// init mock handler
 	h := gotest.NewHandler(d, testData)
-	h.IHandler = &groupClientHandler{iDao: d.IDao.(dao.GroupClientDao)}
+	h.IHandler = &groupClientHandler{
+		iDao:       d.IDao.(dao.GroupClientDao),
+		clientsDao: dao.NewClientsDao(d.DB, nil),
+	}
 	iHandler := h.IHandler.(GroupClientHandler)
 
 	testFns := []gotest.RouterInfo{
@@ -50,6 +55,12 @@ func newGroupClientHandler() *gotest.Handler {
 			Path:        "/groupClient",
 			HandlerFunc: iHandler.Create,
 		},
+		{
+			FuncName:    "CreateBatch",
+			Method:      http.MethodPost,
+			Path:        "/groupClient/batch",
+			HandlerFunc: iHandler.CreateBatch,
+		},
 		{
 			FuncName:    "DeleteByID",
 			Method:      http.MethodDelete,
@@ -62,6 +73,12 @@ func newGroupClientHandler() *gotest.Handler {
 			Path:        "/groupClient/:id",
 			HandlerFunc: iHandler.UpdateByID,
 		},
+		{
+			FuncName:    "PatchByID",
+			Method:      http.MethodPatch,
+			Path:        "/groupClient/:id",
+			HandlerFunc: iHandler.PatchByID,
+		},
 		{
 			FuncName:    "GetByID",
 			Method:      http.MethodGet,
@@ -98,6 +115,30 @@ func newGroupClientHandler() *gotest.Handler {
 			Path:        "/groupClient/list",
 			HandlerFunc: iHandler.ListByLastID,
 		},
+		{
+			FuncName:    "ListMembers",
+			Method:      http.MethodGet,
+			Path:        "/groupClient/members/:groupId",
+			HandlerFunc: iHandler.ListMembers,
+		},
+		{
+			FuncName:    "ListTrash",
+			Method:      http.MethodGet,
+			Path:        "/groupClient/trash",
+			HandlerFunc: iHandler.ListTrash,
+		},
+		{
+			FuncName:    "RestoreByID",
+			Method:      http.MethodPost,
+			Path:        "/groupClient/:id/restore",
+			HandlerFunc: iHandler.RestoreByID,
+		},
+		{
+			FuncName:    "PurgeByID",
+			Method:      http.MethodDelete,
+			Path:        "/groupClient/trash/:id",
+			HandlerFunc: iHandler.PurgeByID,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -129,6 +170,40 @@ func Test_groupClientHandler_Create(t *testing.T) {
 
 }
 
+func Test_groupClientHandler_CreateBatch(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := &types.CreateGroupClientsBatchRequest{
+		Atomic:  true,
+		Records: []types.CreateGroupClientRequest{{}, {}},
+	}
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, result.Code)
+
+	// empty batch
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), &types.CreateGroupClientsBatchRequest{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// the whole transaction fails
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WillReturnError(errors.New("mock error"))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = gohttp.Post(result, h.GetRequestURL("CreateBatch"), testData)
+	assert.Error(t, err)
+}
+
 func Test_groupClientHandler_DeleteByID(t *testing.T) {
 	h := newGroupClientHandler()
 	defer h.Close()
@@ -190,6 +265,75 @@ func Test_groupClientHandler_UpdateByID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_groupClientHandler_UpdateByIDWithIfMatch(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := &types.UpdateGroupClientByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.GroupClient))
+
+	// the record has been changed by another request
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectCommit()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+
+	req := &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `"1"`)
+	req.SetJSONBody(testData)
+	resp, err := req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	_, _ = resp.ReadBody()
+
+	// invalid If-Match error test
+	req = &gohttp.Request{}
+	req.SetURL(h.GetRequestURL("UpdateByID", testData.ID))
+	req.SetHeader("If-Match", `W/"1"`)
+	req.SetJSONBody(testData)
+	resp, err = req.PUT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &gohttp.StdResult{}
+	err = resp.BindJSON(result)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+}
+
+func Test_groupClientHandler_PatchByID(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupClient)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"clientId": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// read-only field error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", testData.ID), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, result.Code)
+
+	// patch error test
+	err = gohttp.Patch(result, h.GetRequestURL("PatchByID", 111), map[string]interface{}{"clientId": nil})
+	assert.Error(t, err)
+}
+
 func Test_groupClientHandler_GetByID(t *testing.T) {
 	h := newGroupClientHandler()
 	defer h.Close()
@@ -385,6 +529,8 @@ func Test_groupClientHandler_ListByLastID(t *testing.T) {
 	// error test
 	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"lastID": 0, "size": 10, "sort": "unknown-column"})
 	assert.Error(t, err)
+	err = gohttp.Get(result, h.GetRequestURL("ListByLastID"), gohttp.KV{"cursor": "invalid", "size": 10})
+	assert.Error(t, err)
 }
 
 func TestNewGroupClientHandler(t *testing.T) {
@@ -393,3 +539,117 @@ func TestNewGroupClientHandler(t *testing.T) {
 	}()
 	_ = NewGroupClientHandler()
 }
+
+func Test_groupClientHandler_ListMembers(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT clients.\\* FROM `clients` JOIN group_client .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "machine_code", "status"}).AddRow(1, "m1", model.ClientsStatusActive))
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListMembers", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+	assert.Len(t, result.Data.(map[string]interface{})["clientss"], 1)
+
+	// invalid all
+	err = gohttp.Get(result, h.GetRequestURL("ListMembers", 1), gohttp.KV{"all": "some"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// zero group id error test
+	err = gohttp.Get(result, h.GetRequestURL("ListMembers", 0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, result.Code)
+
+	// list members error test
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnError(errors.New("mock error"))
+	err = gohttp.Get(result, h.GetRequestURL("ListMembers", 1), gohttp.KV{"all": "true"})
+	assert.Error(t, err)
+}
+
+func Test_groupClientHandler_ListTrash(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupClient)
+
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"}).
+		AddRow(testData.ID, time.Now())
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// error test
+	err = gohttp.Get(result, h.GetRequestURL("ListTrash"), gohttp.KV{"sort": "unknown-column"})
+	assert.Error(t, err)
+}
+
+func Test_groupClientHandler_RestoreByID(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupClient)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Post(result, h.GetRequestURL("RestoreByID", testData.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 0), nil)
+	assert.NoError(t, err)
+
+	// restore error test
+	err = gohttp.Post(result, h.GetRequestURL("RestoreByID", 111), nil)
+	assert.Error(t, err)
+}
+
+func Test_groupClientHandler_PurgeByID(t *testing.T) {
+	h := newGroupClientHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.GroupClient)
+
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &gohttp.StdResult{}
+	err := gohttp.Delete(result, h.GetRequestURL("PurgeByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 0))
+	assert.NoError(t, err)
+
+	// purge error test
+	err = gohttp.Delete(result, h.GetRequestURL("PurgeByID", 111))
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/zhufuyi/sponge/pkg/errcode"
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+
+	"caller/internal/ecode"
+	"caller/internal/instruction"
+	"caller/internal/types"
+)
+
+var _ InstructionHandler = (*instructionHandler)(nil)
+
+// InstructionHandler defining the handler interface
+type InstructionHandler interface {
+	List(c *gin.Context)
+}
+
+type instructionHandler struct{}
+
+// NewInstructionHandler creating the handler interface
+func NewInstructionHandler() InstructionHandler {
+	return &instructionHandler{}
+}
+
+// List list the supported instructions
+// @Summary list instructions
+// @Description list the instructions that can be sent to the client devices and the fields of their payloads, the app compares the version with the version it was built for to check that it understands all the instructions
+// @Tags instruction
+// @Produce json
+// @Success 200 {object} types.ListInstructionsRespond{}
+// @Router /api/v1/instructions [get]
+// @Security BearerAuth
+func (h *instructionHandler) List(c *gin.Context) {
+	data := []*types.InstructionObjDetail{}
+	err := copier.Copy(&data, instruction.List())
+	if err != nil {
+		logger.Error("Copy error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"version":      instruction.Version,
+		"instructions": data,
+	})
+}
+
+// checkInstruction validate an instruction and its payload, return the payload normalized by instruction.Validate.
+// an empty instruction is allowed if allowEmpty is true and the payload is empty, nothing is sent to the device then.
+func checkInstruction(name string, mobileNumber string, payload json.RawMessage, allowEmpty bool) (string, error) {
+	if name == "" && allowEmpty {
+		if !instruction.IsEmptyPayload(payload) {
+			return "", fmt.Errorf("%w: payload without instruction", instruction.ErrInvalidPayload)
+		}
+		return "", nil
+	}
+	return instruction.Validate(name, mobileNumber, payload)
+}
+
+// instructionError the error code returned for an instruction that fails the validation
+func instructionError(err error) *errcode.Error {
+	switch {
+	case errors.Is(err, instruction.ErrUnknown):
+		return ecode.ErrUnknownInstruction
+	case errors.Is(err, instruction.ErrMobileNumberRequired):
+		return ecode.ErrMobileNumberInstruction
+	case errors.Is(err, instruction.ErrInvalidTarget):
+		return ecode.ErrTargetInstruction
+	case errors.Is(err, instruction.ErrInvalidDigits):
+		return ecode.ErrDigitsInstruction
+	case errors.Is(err, instruction.ErrMutedRequired):
+		return ecode.ErrMutedInstruction
+	}
+	return ecode.ErrPayloadInstruction
+}
+
+// payloadJSON the payload stored in a record as the json of a respond, nil if the instruction has no payload
+func payloadJSON(payload string) json.RawMessage {
+	if payload == "" {
+		return nil
+	}
+	return json.RawMessage(payload)
+}
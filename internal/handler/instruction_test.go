@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/instruction"
+	"caller/internal/model"
+)
+
+func Test_instructionHandler_List(t *testing.T) {
+	h := NewInstructionHandler()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/instructions", h.List)
+
+	result := doLabelsRequest(t, r, http.MethodGet, "/instructions", "")
+	assert.Equal(t, 0, result.Code, result.Msg)
+	data := result.Data.(map[string]interface{})
+	assert.Equal(t, float64(instruction.Version), data["version"])
+	instructions := data["instructions"].([]interface{})
+	assert.Len(t, instructions, len(instruction.List()))
+	transfer := instructions[4].(map[string]interface{})
+	assert.Equal(t, instruction.Transfer, transfer["name"])
+	assert.Equal(t, "target", transfer["payload"].([]interface{})[0].(map[string]interface{})["name"])
+	assert.Equal(t, true, instructions[0].(map[string]interface{})["mobileNumberRequired"])
+}
+
+func Test_callHistoryHandler_instruction(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &callHistoryHandler{
+		iDao:         dao.NewCallHistoryDao(db, nil),
+		simDao:       dao.NewSimDao(db),
+		recordingDao: dao.NewCallRecordingDao(db),
+		notifier:     cache.NewDeviceCommandNotifier(&model.CacheType{}),
+	}
+	commandDao := dao.NewDeviceCommandDao(db)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/callHistory", h.Create)
+	r.POST("/callHistory/batch", h.CreateBatch)
+	r.PUT("/callHistory/:id", h.UpdateByID)
+	r.PATCH("/callHistory/:id", h.PatchByID)
+	r.GET("/callHistory/:id", h.GetByID)
+
+	// typos and invalid payloads are rejected
+	result := doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","mobileNumber":"13800000000","instruction":"dail"}`)
+	assert.Equal(t, ecode.ErrUnknownInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","instruction":"dial"}`)
+	assert.Equal(t, ecode.ErrMobileNumberInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","instruction":"transfer","payload":{"target":"x"}}`)
+	assert.Equal(t, ecode.ErrTargetInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","instruction":"mute","payload":{}}`)
+	assert.Equal(t, ecode.ErrMutedInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","payload":{"muted":true}}`)
+	assert.Equal(t, ecode.ErrPayloadInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/batch",
+		`{"records":[{"clientMachineCode":"m1","instruction":"hangup"},{"clientMachineCode":"m1","instruction":"sendDTMF","payload":{"digits":""}}]}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	results := result.Data.(map[string]interface{})["results"].([]interface{})
+	assert.Equal(t, ecode.ErrDigitsInstruction.Msg(), results[1].(map[string]interface{})["error"])
+
+	// the normalized payload is queued with the instruction
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","instruction":"sendDTMF","payload":{ "digits" : "12#" }}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	commands, err := commandDao.Deliver(ctx, "m1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 2)
+	assert.Equal(t, `{"digits":"12#"}`, commands[1].Payload)
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/2", "")
+	callHistory := result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"digits": "12#"}, callHistory["payload"])
+
+	// the instruction of an update is checked with the payload and the mobile number of the record
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/2", `{"payload":{"digits":"x"}}`)
+	assert.Equal(t, ecode.ErrDigitsInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/2", `{"instruction":"dial"}`)
+	assert.Equal(t, ecode.ErrMobileNumberInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/2", `{"instruction":"mute","payload":{"muted":true}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPut, "/callHistory/100", `{"instruction":"hangup"}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/2", `{"instruction":"transfer"}`)
+	assert.Equal(t, ecode.ErrTargetInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/2", `{"instruction":"reject","payload":{"message":"later"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/2", `{"payload":null}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/2", `{"instruction":"hangup","payload":{"message":"later"}}`)
+	assert.Equal(t, ecode.ErrPayloadInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/2", `{"instruction":1}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	record, err := h.iDao.GetByID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, instruction.Reject, record.Instruction)
+	assert.Equal(t, "", record.Payload)
+	assert.Equal(t, model.CallStateRequested, record.State)
+
+	// the mobile number required by the instruction cannot be patched away
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","mobileNumber":"13800000000","instruction":"dial"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/3", `{"mobileNumber":null}`)
+	assert.Equal(t, ecode.ErrMobileNumberInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/3", `{"mobileNumber":""}`)
+	assert.Equal(t, ecode.ErrMobileNumberInstruction.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/callHistory/3", `{"mobileNumber":"13900000000"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	record, err = h.iDao.GetByID(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "13900000000", record.MobileNumber)
+	assert.Equal(t, instruction.Dial, record.Instruction)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+
+	"caller/internal/ecode"
+	"caller/internal/labels"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+// labelsDao the labels of the records of a resource, it is implemented by the dao of the resources that have labels
+type labelsDao interface {
+	GetLabels(ctx context.Context, id uint64) (map[string]string, error)
+	GetLabelsByIDs(ctx context.Context, ids []uint64) (map[uint64]map[string]string, error)
+	SetLabels(ctx context.Context, id uint64, values map[string]string) (map[string]string, error)
+	PatchLabels(ctx context.Context, id uint64, patch map[string]*string) (map[string]string, error)
+}
+
+// serveGetLabels respond the labels of the record
+func serveGetLabels(c *gin.Context, d labelsDao, id uint64) {
+	values, err := d.GetLabels(middleware.WrapCtx(c), id)
+	respondLabels(c, "GetLabels", id, values, err)
+}
+
+// serveSetLabels replace the labels of the record with the labels of the request
+func serveSetLabels(c *gin.Context, d labelsDao, id uint64) {
+	form := &types.SetLabelsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	values, err := d.SetLabels(middleware.WrapCtx(c), id, form.Labels)
+	respondLabels(c, "SetLabels", id, values, err)
+}
+
+// servePatchLabels merge the labels of the request into the labels of the record
+func servePatchLabels(c *gin.Context, d labelsDao, id uint64) {
+	form := &types.PatchLabelsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	values, err := d.PatchLabels(middleware.WrapCtx(c), id, form.Labels)
+	respondLabels(c, "PatchLabels", id, values, err)
+}
+
+func respondLabels(c *gin.Context, method string, id uint64, values map[string]string, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrRecordNotFound):
+			logger.Warn(method+" not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		case errors.Is(err, labels.ErrInvalidKey), errors.Is(err, labels.ErrInvalidValue), errors.Is(err, labels.ErrTooMany):
+			logger.Warn(method+" invalid labels", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		default:
+			logger.Error(method+" error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c, gin.H{"labels": values})
+}
+
+// parseLabelSelector parse the label selector of a list request, isAbort is true if it cannot be parsed
+func parseLabelSelector(c *gin.Context, str string) (labels.Selector, bool) {
+	selector, err := labels.Parse(str)
+	if err != nil {
+		logger.Warn("parse label selector error", logger.Err(err), logger.String("labelSelector", str), middleware.GCtxRequestIDField(c))
+		return nil, true
+	}
+	return selector, false
+}
+
+// getLabelsByIDs get the labels of the records, no labels are returned if they cannot be read
+func getLabelsByIDs(c *gin.Context, d labelsDao, ids []uint64) map[uint64]map[string]string {
+	if len(ids) == 0 {
+		return nil
+	}
+	itemMap, err := d.GetLabelsByIDs(middleware.WrapCtx(c), ids)
+	if err != nil {
+		logger.Warn("GetLabelsByIDs error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return nil
+	}
+	return itemMap
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"github.com/zhufuyi/sponge/pkg/gohttp"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+)
+
+func doLabelsRequest(t *testing.T, r *gin.Engine, method string, path string, body string) *gohttp.StdResult {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	result := &gohttp.StdResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result), w.Body.String())
+	return result
+}
+
+// listedIDs the ids of the records listed in data under key
+func listedIDs(result *gohttp.StdResult, key string) []string {
+	var ids []string
+	data, _ := result.Data.(map[string]interface{})
+	records, _ := data[key].([]interface{})
+	for _, record := range records {
+		ids = append(ids, record.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+func Test_labels(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	clients := &clientsHandler{
+		iDao:           dao.NewClientsDao(db, nil),
+		presence:       cache.NewClientsPresenceCache(&model.CacheType{CType: "memory"}),
+		offlineTimeout: time.Minute,
+	}
+	groupCall := &groupCallHandler{iDao: dao.NewGroupCallDao(db, nil)}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/clients/:id", clients.GetByID)
+	r.POST("/clients/list", clients.List)
+	r.GET("/clients/list", clients.ListByLastID)
+	r.GET("/clients/:id/labels", clients.GetLabels)
+	r.PUT("/clients/:id/labels", clients.SetLabels)
+	r.PATCH("/clients/:id/labels", clients.PatchLabels)
+	r.POST("/groupCall/list", groupCall.List)
+	r.GET("/groupCall/list", groupCall.ListByLastID)
+	r.PUT("/groupCall/:id/labels", groupCall.SetLabels)
+	r.PATCH("/groupCall/:id/labels", groupCall.PatchLabels)
+
+	for _, machineCode := range []string{"m1", "m2", "m3"} {
+		assert.NoError(t, clients.iDao.Create(ctx, &model.Clients{MachineCode: machineCode}))
+	}
+	assert.NoError(t, groupCall.iDao.Create(ctx, &model.GroupCall{GroupNumber: "1"}))
+	assert.NoError(t, groupCall.iDao.Create(ctx, &model.GroupCall{GroupNumber: "2"}))
+
+	// set and patch
+	result := doLabelsRequest(t, r, http.MethodPut, "/clients/1/labels", `{"labels":{"site":"shanghai","carrier":"cmcc"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPut, "/clients/2/labels", `{"labels":{"site":"shanghai","carrier":"unicom"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPut, "/clients/3/labels", `{"labels":{"site":"beijing"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/clients/3/labels", `{"labels":{"site":null,"customer":"acme"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, map[string]interface{}{"labels": map[string]interface{}{"customer": "acme"}}, result.Data)
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/1/labels", "")
+	assert.Equal(t, map[string]interface{}{"labels": map[string]interface{}{"site": "shanghai", "carrier": "cmcc"}}, result.Data)
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/1", "")
+	assert.Equal(t, "shanghai", result.Data.(map[string]interface{})["clients"].(map[string]interface{})["labels"].(map[string]interface{})["site"])
+
+	// errors
+	result = doLabelsRequest(t, r, http.MethodPut, "/clients/1/labels", `{"labels":{"site,":"shanghai"}}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/clients/1/labels", `{}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPatch, "/clients/100/labels", `{"labels":{"site":"shanghai"}}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/0/labels", "")
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// select the clients
+	result = doLabelsRequest(t, r, http.MethodPost, "/clients/list", `{"size":10,"sort":"id","labelSelector":"site=shanghai,carrier!=cmcc"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Equal(t, []string{"2"}, listedIDs(result, "clientss"))
+	assert.Equal(t, float64(1), result.Data.(map[string]interface{})["total"])
+	result = doLabelsRequest(t, r, http.MethodPost, "/clients/list", `{"size":10,"sort":"id","labelSelector":"!site","online":false}`)
+	assert.Equal(t, []string{"3"}, listedIDs(result, "clientss"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/list?sort=id&labelSelector="+url.QueryEscape("site in (shanghai)"), "")
+	assert.Equal(t, []string{"1", "2"}, listedIDs(result, "clientss"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/list?sort=id", "")
+	assert.Equal(t, []string{"1", "2", "3"}, listedIDs(result, "clientss"))
+	result = doLabelsRequest(t, r, http.MethodPost, "/clients/list", `{"size":10,"labelSelector":"site=="}`)
+	assert.Equal(t, 0, result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/clients/list", `{"size":10,"labelSelector":"site in (shanghai"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/list?labelSelector=%3Dshanghai", "")
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// select the groupCalls
+	result = doLabelsRequest(t, r, http.MethodPut, "/groupCall/2/labels", `{"labels":{"customer":"acme"}}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/groupCall/list", `{"size":10,"labelSelector":"customer=acme"}`)
+	assert.Equal(t, []string{"2"}, listedIDs(result, "groupCalls"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/groupCall/list?labelSelector=customer", "")
+	assert.Equal(t, []string{"2"}, listedIDs(result, "groupCalls"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/groupCall/list?sort=id&labelSelector=!customer", "")
+	assert.Equal(t, []string{"1"}, listedIDs(result, "groupCalls"))
+	result = doLabelsRequest(t, r, http.MethodPatch, "/groupCall/3/labels", `{"labels":{"customer":"acme"}}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+}
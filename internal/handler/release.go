@@ -0,0 +1,591 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/config"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+// appVersionCodeHeader the header in which the app sends its version code
+const appVersionCodeHeader = "X-App-Version-Code"
+
+var _ ReleaseHandler = (*releaseHandler)(nil)
+
+// ReleaseHandler defining the handler interface
+type ReleaseHandler interface {
+	Upload(c *gin.Context)
+	GetByID(c *gin.Context)
+	List(c *gin.Context)
+	Download(c *gin.Context)
+
+	UpsertChannel(c *gin.Context)
+	ListChannels(c *gin.Context)
+	SetChannelClients(c *gin.Context)
+
+	CheckUpdate(c *gin.Context)
+}
+
+type releaseHandler struct {
+	iDao           dao.ReleaseDao
+	clientsDao     dao.ClientsDao
+	storageDir     string // directory of the apk files
+	downloadURL    string // base url of the download links, empty for paths on this server
+	maxFileSize    int64  // bytes
+	minVersionCode int    // the apps with a lower version code must update
+}
+
+// NewReleaseHandler creating the handler interface
+func NewReleaseHandler() ReleaseHandler {
+	cfg := config.Get().Release
+	h := &releaseHandler{
+		iDao: dao.NewReleaseDao(model.GetDB()),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
+		storageDir:     cfg.StorageDir,
+		downloadURL:    strings.TrimSuffix(cfg.DownloadURL, "/"),
+		maxFileSize:    int64(cfg.MaxFileSize) << 20,
+		minVersionCode: cfg.MinVersionCode,
+	}
+	if h.storageDir == "" {
+		h.storageDir = "releases"
+	}
+	if h.maxFileSize <= 0 {
+		h.maxFileSize = 200 << 20
+	}
+	return h
+}
+
+// AppVersionGate reject the calls of the apps that are older than minVersionCode with ecode.ErrAppVersionUnsupported,
+// the version code is read from the X-App-Version-Code header, the apps built before the header existed do not send
+// it, so a call without a valid version code is rejected too, every call passes if minVersionCode is 0.
+func AppVersionGate(minVersionCode int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if minVersionCode <= 0 {
+			c.Next()
+			return
+		}
+		versionCode, ok := getAppVersionCode(c)
+		if !ok || versionCode < minVersionCode {
+			logger.Warn("app version is no longer supported", logger.String(appVersionCodeHeader, c.GetHeader(appVersionCodeHeader)),
+				logger.Int("minVersionCode", minVersionCode), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrAppVersionUnsupported)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Upload upload the apk of a release
+// @Summary upload a release
+// @Description upload the apk of a release as multipart/form-data, the sha256 checksum of the apk is computed and stored with it, the version code of a release is unique
+// @Tags release
+// @accept multipart/form-data
+// @Produce json
+// @Param file formData file true "apk file"
+// @Param versionCode formData int true "versionCode of the apk"
+// @Param versionName formData string true "versionName of the apk"
+// @Param checksum formData string false "hex sha256 of the apk, if set the upload is rejected when the file does not match"
+// @Param releaseNotes formData string false "release notes"
+// @Success 200 {object} types.UploadReleaseRespond{}
+// @Router /api/v1/releases [post]
+// @Security BearerAuth
+func (h *releaseHandler) Upload(c *gin.Context) {
+	// leave room for the other fields of the form
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxFileSize+1<<20)
+	form := &types.UploadReleaseRequest{}
+	err := c.ShouldBind(form)
+	if err != nil {
+		logger.Warn("ShouldBind error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil || file.Size > h.maxFileSize {
+		logger.Warn("FormFile error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	_, err = h.iDao.GetByVersionCode(ctx, form.VersionCode)
+	if err == nil {
+		logger.Warn("version code already exists", logger.Int("versionCode", form.VersionCode), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.AlreadyExists)
+		return
+	}
+	if !errors.Is(err, model.ErrRecordNotFound) {
+		logger.Error("GetByVersionCode error", logger.Err(err), logger.Int("versionCode", form.VersionCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	release := &model.AppRelease{
+		VersionCode:  form.VersionCode,
+		VersionName:  form.VersionName,
+		ReleaseNotes: form.ReleaseNotes,
+	}
+	err = h.saveFile(file, release)
+	if err != nil {
+		logger.Error("saveFile error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrUploadRelease)
+		return
+	}
+	if form.Checksum != "" && !strings.EqualFold(form.Checksum, release.Checksum) {
+		_ = os.Remove(filepath.Join(h.storageDir, release.FileName))
+		logger.Warn("checksum does not match", logger.String("checksum", form.Checksum), logger.String("computed", release.Checksum), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrChecksumRelease)
+		return
+	}
+
+	err = h.iDao.Create(ctx, release)
+	if err != nil {
+		_ = os.Remove(filepath.Join(h.storageDir, release.FileName))
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := h.convertRelease(release)
+	if err != nil {
+		response.Error(c, ecode.ErrUploadRelease)
+		return
+	}
+	response.Success(c, gin.H{"release": data})
+}
+
+// GetByID get a release by id
+// @Summary get release detail
+// @Description get release detail by id
+// @Tags release
+// @Param id path string true "id"
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.GetReleaseByIDRespond{}
+// @Router /api/v1/releases/{id} [get]
+// @Security BearerAuth
+func (h *releaseHandler) GetByID(c *gin.Context) {
+	release, isAbort := h.getReleaseFromPath(c)
+	if isAbort {
+		return
+	}
+
+	data, err := h.convertRelease(release)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDRelease)
+		return
+	}
+	response.Success(c, gin.H{"release": data})
+}
+
+// List get the releases by cursor and limit
+// @Summary list of releases by cursor and limit
+// @Description list of releases by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags release
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, version_code, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-version_code)
+// @Success 200 {object} types.ListReleasesRespond{}
+// @Router /api/v1/releases/list [get]
+// @Security BearerAuth
+func (h *releaseHandler) List(c *gin.Context) {
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+	if sort == "" && cursor == "" {
+		sort = "-version_code"
+	}
+
+	ctx := middleware.WrapCtx(c)
+	releases, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data := make([]*types.ReleaseObjDetail, 0, len(releases))
+	for _, release := range releases {
+		detail, err := h.convertRelease(release)
+		if err != nil {
+			response.Error(c, ecode.ErrListRelease)
+			return
+		}
+		data = append(data, detail)
+	}
+
+	response.Success(c, gin.H{
+		"releases":   data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// Download download the apk of a release
+// @Summary download a release
+// @Description download the apk of a release, the X-Checksum-Sha256 header is the hex sha256 of the apk
+// @Tags release
+// @Param id path string true "id"
+// @Produce application/vnd.android.package-archive
+// @Success 200 {file} file
+// @Router /api/v1/releases/{id}/download [get]
+// @Security BearerAuth
+func (h *releaseHandler) Download(c *gin.Context) {
+	release, isAbort := h.getReleaseFromPath(c)
+	if isAbort {
+		return
+	}
+
+	path := filepath.Join(h.storageDir, release.FileName)
+	if _, err := os.Stat(path); err != nil {
+		logger.Error("apk file of the release is missing", logger.Err(err), logger.String("path", path), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.NotFound)
+		return
+	}
+	c.Header("X-Checksum-Sha256", release.Checksum)
+	c.Header("Content-Type", "application/vnd.android.package-archive")
+	c.FileAttachment(path, fmt.Sprintf("caller-%s.apk", release.VersionName))
+}
+
+// UpsertChannel create a release channel or update its settings
+// @Summary save a release channel
+// @Description create a release channel such as stable or beta, or update its settings. when the release of the channel is replaced, the clients outside the rollout percent are still offered the replaced release. the clients that are not assigned to a channel are placed in the channels by their client percent, the others are in the stable channel
+// @Tags release
+// @accept json
+// @Produce json
+// @Param name path string true "channel name"
+// @Param data body types.UpsertReleaseChannelRequest true "channel settings"
+// @Success 200 {object} types.UpsertReleaseChannelRespond{}
+// @Router /api/v1/releaseChannels/{name} [put]
+// @Security BearerAuth
+func (h *releaseHandler) UpsertChannel(c *gin.Context) {
+	name := c.Param("name")
+	if len(name) > 32 {
+		logger.Warn("channel name is too long", logger.String("name", name), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form := &types.UpsertReleaseChannelRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if form.ReleaseID != nil && *form.ReleaseID != 0 {
+		_, err = h.iDao.GetByID(ctx, *form.ReleaseID)
+		if err != nil {
+			if errors.Is(err, model.ErrRecordNotFound) {
+				logger.Warn("release not found", logger.Any("releaseId", *form.ReleaseID), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.NotFound)
+			} else {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("releaseId", *form.ReleaseID), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			}
+			return
+		}
+	}
+
+	channel, err := h.iDao.UpsertChannel(ctx, name, &dao.ReleaseChannelUpdate{
+		ReleaseID:      form.ReleaseID,
+		RolloutPercent: form.RolloutPercent,
+		ClientPercent:  form.ClientPercent,
+	})
+	if err != nil {
+		if errors.Is(err, dao.ErrReleaseClientPercent) {
+			logger.Warn("UpsertChannel error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrClientPercentRelease)
+			return
+		}
+		logger.Error("UpsertChannel error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertReleaseChannel(channel)
+	if err != nil {
+		response.Error(c, ecode.ErrUpsertReleaseChannel)
+		return
+	}
+	response.Success(c, gin.H{"channel": data})
+}
+
+// ListChannels get all the release channels
+// @Summary list of release channels
+// @Description list of all the release channels, the oldest first, which is the order in which they share the client percent
+// @Tags release
+// @accept json
+// @Produce json
+// @Success 200 {object} types.ListReleaseChannelsRespond{}
+// @Router /api/v1/releaseChannels [get]
+// @Security BearerAuth
+func (h *releaseHandler) ListChannels(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	channels, err := h.iDao.GetChannels(ctx)
+	if err != nil {
+		logger.Error("GetChannels error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data := make([]*types.ReleaseChannelObjDetail, 0, len(channels))
+	for _, channel := range channels {
+		detail, err := convertReleaseChannel(channel)
+		if err != nil {
+			response.Error(c, ecode.ErrListReleaseChannel)
+			return
+		}
+		data = append(data, detail)
+	}
+	response.Success(c, gin.H{"channels": data})
+}
+
+// SetChannelClients assign clients to a release channel
+// @Summary assign clients to a release channel
+// @Description assign the clients with the machine codes to a release channel, an empty channel removes the assignment and the clients are placed by the client percent of the channels again
+// @Tags release
+// @accept json
+// @Produce json
+// @Param data body types.SetReleaseChannelClientsRequest true "machine codes and channel"
+// @Success 200 {object} types.SetReleaseChannelClientsRespond{}
+// @Router /api/v1/clients/releaseChannel [put]
+// @Security BearerAuth
+func (h *releaseHandler) SetChannelClients(c *gin.Context) {
+	form := &types.SetReleaseChannelClientsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if form.Channel != "" {
+		_, err = h.iDao.GetChannelByName(ctx, form.Channel)
+		if err != nil {
+			if errors.Is(err, model.ErrRecordNotFound) {
+				logger.Warn("channel not found", logger.String("channel", form.Channel), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.NotFound)
+			} else {
+				logger.Error("GetChannelByName error", logger.Err(err), logger.String("channel", form.Channel), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			}
+			return
+		}
+	}
+
+	changed, err := h.clientsDao.SetReleaseChannel(ctx, form.MachineCodes, form.Channel)
+	if err != nil {
+		logger.Error("SetReleaseChannel error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrSetReleaseChannelClients)
+		return
+	}
+	response.Success(c, gin.H{"changed": changed})
+}
+
+// CheckUpdate get the release offered to a device
+// @Summary check the update of a device
+// @Description get the release of the channel of the device and whether it is newer than the app, the app sends its version code in the versionCode query or the X-App-Version-Code header. this api is not rejected for outdated apps, mandatory is true if the app is older than the minimum supported version
+// @Tags release
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param versionCode query int false "versionCode of the app, if empty the X-App-Version-Code header is used, 0 means unknown"
+// @Success 200 {object} types.CheckUpdateRespond{}
+// @Router /api/v1/devices/{machineCode}/update [get]
+// @Security BearerAuth
+func (h *releaseHandler) CheckUpdate(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	versionCode := 0
+	if v := c.Query("versionCode"); v != "" {
+		var err error
+		versionCode, err = strconv.Atoi(v)
+		if err != nil || versionCode < 0 {
+			logger.Warn("invalid versionCode", logger.String("versionCode", v), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	} else {
+		var ok bool
+		versionCode, ok = getAppVersionCode(c)
+		if !ok {
+			logger.Warn("invalid app version code", logger.String(appVersionCodeHeader, c.GetHeader(appVersionCodeHeader)), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	ctx := middleware.WrapCtx(c)
+	assignedChannel := ""
+	clients, err := h.clientsDao.GetByMachineCode(ctx, machineCode)
+	if err == nil {
+		assignedChannel = clients.ReleaseChannel
+	} else if !errors.Is(err, model.ErrRecordNotFound) {
+		logger.Error("GetByMachineCode error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	channel, release, err := h.iDao.GetTarget(ctx, machineCode, assignedChannel)
+	if err != nil {
+		logger.Error("GetTarget error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	channelName := ""
+	if channel != nil {
+		channelName = channel.Name
+	}
+	var data *types.ReleaseObjDetail
+	if release != nil {
+		data, err = h.convertRelease(release)
+		if err != nil {
+			response.Error(c, ecode.ErrCheckUpdateRelease)
+			return
+		}
+	}
+	response.Success(c, gin.H{
+		"channel":         channelName,
+		"updateAvailable": release != nil && release.VersionCode > versionCode,
+		"mandatory":       h.minVersionCode > 0 && versionCode < h.minVersionCode,
+		"release":         data,
+	})
+}
+
+// saveFile store the uploaded apk in the storage directory, the name, size and checksum of the file are set in release
+func (h *releaseHandler) saveFile(file *multipart.FileHeader, release *model.AppRelease) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint
+
+	err = os.MkdirAll(h.storageDir, 0o755)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first, so a failed upload does not leave a partial apk
+	tmp, err := os.CreateTemp(h.storageDir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), src)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	release.Checksum = hex.EncodeToString(hash.Sum(nil))
+	release.FileSize = size
+	release.FileName = fmt.Sprintf("caller-%d-%s.apk", release.VersionCode, release.Checksum[:12])
+	return os.Rename(tmp.Name(), filepath.Join(h.storageDir, release.FileName))
+}
+
+// getReleaseFromPath get the release of the id in the path, isAbort is true if the respond has been written
+func (h *releaseHandler) getReleaseFromPath(c *gin.Context) (*model.AppRelease, bool) {
+	idStr := c.Param("id")
+	id, err := utils.StrToUint64E(idStr)
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", idStr), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return nil, true
+	}
+
+	release, err := h.iDao.GetByID(middleware.WrapCtx(c), id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	return release, false
+}
+
+func (h *releaseHandler) convertRelease(release *model.AppRelease) (*types.ReleaseObjDetail, error) {
+	data := &types.ReleaseObjDetail{}
+	err := copier.Copy(data, release)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	data.ID = utils.Uint64ToStr(release.ID)
+	data.DownloadURL = h.downloadURL + "/api/v1/releases/" + data.ID + "/download"
+	return data, nil
+}
+
+func convertReleaseChannel(channel *model.ReleaseChannel) (*types.ReleaseChannelObjDetail, error) {
+	data := &types.ReleaseChannelObjDetail{}
+	err := copier.Copy(data, channel)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	data.ReleaseID = utils.Uint64ToStr(channel.ReleaseID)
+	data.PreviousReleaseID = utils.Uint64ToStr(channel.PreviousReleaseID)
+	return data, nil
+}
+
+// getAppVersionCode get the version code in the X-App-Version-Code header, 0 if the header is not set,
+// ok is false if it is not a version code
+func getAppVersionCode(c *gin.Context) (int, bool) {
+	v := strings.TrimSpace(c.GetHeader(appVersionCodeHeader))
+	if v == "" {
+		return 0, true
+	}
+	versionCode, err := strconv.Atoi(v)
+	if err != nil || versionCode < 0 {
+		return 0, false
+	}
+	return versionCode, true
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+)
+
+type releaseTestRespond struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func newReleaseTestRouter(h *releaseHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/releases", h.Upload)
+	r.GET("/api/v1/releases/list", h.List)
+	r.GET("/api/v1/releases/:id", h.GetByID)
+	r.GET("/api/v1/releases/:id/download", h.Download)
+	r.GET("/api/v1/releaseChannels", h.ListChannels)
+	r.PUT("/api/v1/releaseChannels/:name", h.UpsertChannel)
+	r.PUT("/api/v1/clients/releaseChannel", h.SetChannelClients)
+	r.GET("/api/v1/devices/:machineCode/update", h.CheckUpdate)
+	r.GET("/gated", AppVersionGate(h.minVersionCode), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func doReleaseRequest(t *testing.T, r *gin.Engine, req *http.Request) (*httptest.ResponseRecorder, *releaseTestRespond) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	result := &releaseTestRespond{}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+	}
+	return w, result
+}
+
+func newUploadRequest(t *testing.T, fields map[string]string, apk []byte) *http.Request {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	for k, v := range fields {
+		assert.NoError(t, mw.WriteField(k, v))
+	}
+	fw, err := mw.CreateFormFile("file", "app-release.apk")
+	assert.NoError(t, err)
+	_, _ = fw.Write(apk)
+	assert.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/releases", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func Test_releaseHandler(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	h := &releaseHandler{
+		iDao:           dao.NewReleaseDao(db),
+		clientsDao:     dao.NewClientsDao(db, nil),
+		storageDir:     t.TempDir(),
+		downloadURL:    "https://caller.example.com",
+		maxFileSize:    1 << 20,
+		minVersionCode: 2,
+	}
+	r := newReleaseTestRouter(h)
+
+	apk := []byte("apk content")
+	sum := sha256.Sum256(apk)
+	checksum := hex.EncodeToString(sum[:])
+
+	// upload
+	_, result := doReleaseRequest(t, r, newUploadRequest(t, map[string]string{"versionCode": "3", "versionName": "1.3.0", "checksum": checksum}, apk))
+	assert.Equal(t, 0, result.Code, result.Msg)
+	release := struct {
+		Release struct {
+			ID          string `json:"id"`
+			Checksum    string `json:"checksum"`
+			FileSize    int64  `json:"fileSize"`
+			DownloadURL string `json:"downloadUrl"`
+		} `json:"release"`
+	}{}
+	assert.NoError(t, json.Unmarshal(result.Data, &release))
+	assert.Equal(t, checksum, release.Release.Checksum)
+	assert.Equal(t, int64(len(apk)), release.Release.FileSize)
+	assert.Equal(t, "https://caller.example.com/api/v1/releases/"+release.Release.ID+"/download", release.Release.DownloadURL)
+
+	// the version code is unique
+	_, result = doReleaseRequest(t, r, newUploadRequest(t, map[string]string{"versionCode": "3", "versionName": "1.3.0"}, apk))
+	assert.Equal(t, ecode.AlreadyExists.Code(), result.Code)
+	// the checksum does not match
+	_, result = doReleaseRequest(t, r, newUploadRequest(t, map[string]string{"versionCode": "4", "versionName": "1.4.0", "checksum": strings.Repeat("0", 64)}, apk))
+	assert.Equal(t, ecode.ErrChecksumRelease.Code(), result.Code)
+	// no version
+	_, result = doReleaseRequest(t, r, newUploadRequest(t, map[string]string{"versionName": "1.4.0"}, apk))
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	// the file is too large
+	_, result = doReleaseRequest(t, r, newUploadRequest(t, map[string]string{"versionCode": "4", "versionName": "1.4.0"}, make([]byte, 2<<20)))
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// get, list and download
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/releases/"+release.Release.ID, nil))
+	assert.Equal(t, 0, result.Code)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/releases/100", nil))
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/releases/list", nil))
+	assert.Equal(t, 0, result.Code)
+	assert.Contains(t, string(result.Data), release.Release.ID)
+	w, _ := doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/releases/"+release.Release.ID+"/download", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, apk, w.Body.Bytes())
+	assert.Equal(t, checksum, w.Header().Get("X-Checksum-Sha256"))
+
+	// no channel, no update
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/devices/m1/update?versionCode=1", nil))
+	assert.Equal(t, 0, result.Code)
+	assert.Contains(t, string(result.Data), `"updateAvailable":false`)
+	assert.Contains(t, string(result.Data), `"mandatory":true`)
+
+	// channels
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/stable", strings.NewReader(`{"releaseId":`+release.Release.ID+`}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/beta", strings.NewReader(`{"releaseId":100}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/beta", strings.NewReader(`{"clientPercent":101}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/beta", strings.NewReader(`{"clientPercent":0}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, 0, result.Code)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/stable", strings.NewReader(`{"clientPercent":100}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, 0, result.Code)
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/releaseChannels/beta", strings.NewReader(`{"clientPercent":1}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, ecode.ErrClientPercentRelease.Code(), result.Code)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/releaseChannels", nil))
+	assert.Equal(t, 0, result.Code)
+	assert.Contains(t, string(result.Data), `"name":"beta"`)
+
+	// the version code is read from the header
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/devices/m1/update", nil)
+	req.Header.Set(appVersionCodeHeader, "2")
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, 0, result.Code)
+	assert.Contains(t, string(result.Data), `"channel":"stable"`)
+	assert.Contains(t, string(result.Data), `"updateAvailable":true`)
+	assert.Contains(t, string(result.Data), `"mandatory":false`)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/devices/m1/update?versionCode=3", nil))
+	assert.Contains(t, string(result.Data), `"updateAvailable":false`)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/devices/m1/update?versionCode=x", nil))
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// an assigned client is in its channel
+	assert.NoError(t, h.clientsDao.Create(context.Background(), &model.Clients{MachineCode: "m1"}))
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/clients/releaseChannel", strings.NewReader(`{"machineCodes":["m1"],"channel":"beta"}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, 0, result.Code)
+	assert.JSONEq(t, `{"changed":1}`, string(result.Data))
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/clients/releaseChannel", strings.NewReader(`{"machineCodes":["m1"],"channel":"gone"}`))
+	_, result = doReleaseRequest(t, r, req)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	_, result = doReleaseRequest(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/devices/m1/update?versionCode=2", nil))
+	assert.Contains(t, string(result.Data), `"channel":"beta"`)
+	assert.Contains(t, string(result.Data), `"release":null`)
+
+	// the gate
+	for header, code := range map[string]int{"": ecode.ErrAppVersionUnsupported.Code(), "2": 0, "3": 0,
+		"1": ecode.ErrAppVersionUnsupported.Code(), "x": ecode.ErrAppVersionUnsupported.Code()} {
+		req = httptest.NewRequest(http.MethodGet, "/gated", nil)
+		req.Header.Set(appVersionCodeHeader, header)
+		w, result = doReleaseRequest(t, r, req)
+		assert.Equal(t, code, result.Code, header)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
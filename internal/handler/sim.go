@@ -0,0 +1,661 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"github.com/zhufuyi/sponge/pkg/utils"
+
+	"caller/internal/cache"
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+	"caller/internal/types"
+)
+
+var _ SimHandler = (*simHandler)(nil)
+
+// SimHandler defining the handler interface
+type SimHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	UpdateByID(c *gin.Context)
+	GetByID(c *gin.Context)
+	List(c *gin.Context)
+	ListByLastID(c *gin.Context)
+
+	Bind(c *gin.Context)
+	ListByClient(c *gin.Context)
+	ListDuplicates(c *gin.Context)
+	Report(c *gin.Context)
+}
+
+type simHandler struct {
+	iDao       dao.SimDao
+	clientsDao dao.ClientsDao
+}
+
+// NewSimHandler creating the handler interface
+func NewSimHandler() SimHandler {
+	return &simHandler{
+		iDao: dao.NewSimDao(model.GetDB()),
+		clientsDao: dao.NewClientsDao(
+			model.GetDB(),
+			cache.NewClientsCache(model.GetCacheType()),
+		),
+	}
+}
+
+// Create a record
+// @Summary create sim
+// @Description submit information to create a sim, the sim is not in a device until it is bound or reported by a device
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param data body types.CreateSimRequest true "sim information"
+// @Success 200 {object} types.CreateSimRespond{}
+// @Router /api/v1/sim [post]
+// @Security BearerAuth
+func (h *simHandler) Create(c *gin.Context) {
+	form := &types.CreateSimRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	_, err = h.iDao.GetByICCID(ctx, form.ICCID)
+	if err == nil {
+		logger.Warn("iccid already exists", logger.String("iccid", form.ICCID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.AlreadyExists)
+		return
+	}
+	if !errors.Is(err, model.ErrRecordNotFound) {
+		logger.Error("GetByICCID error", logger.Err(err), logger.String("iccid", form.ICCID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	sim := &model.Sim{}
+	err = copier.Copy(sim, form)
+	if err != nil {
+		response.Error(c, ecode.ErrCreateSim)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	if sim.Status == "" {
+		sim.Status = model.SimStatusActive
+	}
+
+	err = h.iDao.Create(ctx, sim)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{"id": sim.ID})
+}
+
+// DeleteByID delete a record by id
+// @Summary delete sim
+// @Description delete sim by id
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Success 200 {object} types.DeleteSimByIDRespond{}
+// @Router /api/v1/sim/{id} [delete]
+// @Security BearerAuth
+func (h *simHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getSimIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.DeleteByID(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// UpdateByID update information by id
+// @Summary update sim
+// @Description update sim information by id, the empty fields are not changed, use the bind api to move the sim to a device
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.UpdateSimByIDRequest true "sim information"
+// @Success 200 {object} types.UpdateSimByIDRespond{}
+// @Router /api/v1/sim/{id} [put]
+// @Security BearerAuth
+func (h *simHandler) UpdateByID(c *gin.Context) {
+	_, id, isAbort := getSimIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateSimByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.ID = id
+
+	sim := &model.Sim{}
+	err = copier.Copy(sim, form)
+	if err != nil {
+		response.Error(c, ecode.ErrUpdateByIDSim)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	sim.Version = version
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.UpdateByID(ctx, sim)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// GetByID get a record by id
+// @Summary get sim detail
+// @Description get sim detail by id, the version of the sim is returned in the ETag header
+// @Tags sim
+// @Param id path string true "id"
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.GetSimByIDRespond{}
+// @Router /api/v1/sim/{id} [get]
+// @Security BearerAuth
+func (h *simHandler) GetByID(c *gin.Context) {
+	_, id, isAbort := getSimIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	sim, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertSim(sim)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data)
+
+	setETag(c, sim.Version)
+	response.Success(c, gin.H{"sim": data})
+}
+
+// List of records by query parameters
+// @Summary list of sims by query parameters
+// @Description list of sims by paging and conditions, duplicateNumber is true for the sims whose phone number is also in another device
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param data body types.Params true "query parameters"
+// @Success 200 {object} types.ListSimsRespond{}
+// @Router /api/v1/sim/list [post]
+// @Security BearerAuth
+func (h *simHandler) List(c *gin.Context) {
+	form := &types.ListSimsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	sims, total, err := h.iDao.GetByColumns(ctx, &form.Params)
+	if err != nil {
+		logger.Error("GetByColumns error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSims(sims)
+	if err != nil {
+		response.Error(c, ecode.ErrListSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data...)
+
+	response.Success(c, gin.H{
+		"sims":  data,
+		"total": total,
+	})
+}
+
+// ListByLastID get records by cursor and limit
+// @Summary list of sims by cursor
+// @Description list of sims by cursor and limit, pass the nextCursor of the respond to get the next page, the sort of the first page is kept
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, iccid, phone_number, expires_at, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListSimsByCursorRespond{}
+// @Router /api/v1/sim/list [get]
+// @Security BearerAuth
+func (h *simHandler) ListByLastID(c *gin.Context) {
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	sims, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSims(sims)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data...)
+
+	response.Success(c, gin.H{
+		"sims":       data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// Bind put a sim in a slot of a client device
+// @Summary bind a sim to a client
+// @Description put the sim in a slot of a client device, the sim that was in the slot is removed from the device, a clientId of 0 removes the sim from its device
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.BindSimRequest true "client and slot"
+// @Success 200 {object} types.BindSimRespond{}
+// @Router /api/v1/sim/{id}/binding [put]
+// @Security BearerAuth
+func (h *simHandler) Bind(c *gin.Context) {
+	_, id, isAbort := getSimIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.BindSimRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	if form.ClientID != 0 {
+		_, err = h.clientsDao.GetByID(ctx, form.ClientID)
+		if err != nil {
+			if errors.Is(err, model.ErrRecordNotFound) {
+				logger.Warn("client not found", logger.Err(err), logger.Any("clientId", form.ClientID), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.NotFound)
+			} else {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("clientId", form.ClientID), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			}
+			return
+		}
+	}
+
+	sim, err := h.iDao.Bind(ctx, id, version, form.ClientID, form.SlotIndex)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("Bind version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("Bind not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("Bind error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data, err := convertSim(sim)
+	if err != nil {
+		response.Error(c, ecode.ErrBindSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data)
+
+	setETag(c, sim.Version)
+	response.Success(c, gin.H{"sim": data})
+}
+
+// ListByClient list the sims in a client device
+// @Summary list of the sims of a client
+// @Description list the sims in the device of the client, ordered by slot
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param id path string true "id of the client"
+// @Success 200 {object} types.ListClientSimsRespond{}
+// @Router /api/v1/clients/{id}/sims [get]
+// @Security BearerAuth
+func (h *simHandler) ListByClient(c *gin.Context) {
+	_, id, isAbort := getClientsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	_, err := h.clientsDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	sims, err := h.iDao.GetByClientID(ctx, id)
+	if err != nil {
+		logger.Error("GetByClientID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSims(sims)
+	if err != nil {
+		response.Error(c, ecode.ErrListSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data...)
+
+	response.Success(c, gin.H{"sims": data})
+}
+
+// ListDuplicates list the sims whose phone number is in two or more devices
+// @Summary list of the sims sharing a phone number
+// @Description list the sims in the devices whose phone number is also on a sim in another device, a cancelled sim is not counted
+// @Tags sim
+// @accept json
+// @Produce json
+// @Success 200 {object} types.ListDuplicateSimsRespond{}
+// @Router /api/v1/sim/duplicates [get]
+// @Security BearerAuth
+func (h *simHandler) ListDuplicates(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	sims, err := h.iDao.GetDuplicates(ctx)
+	if err != nil {
+		logger.Error("GetDuplicates error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSims(sims)
+	if err != nil {
+		response.Error(c, ecode.ErrListSim)
+		return
+	}
+	for _, v := range data {
+		v.DuplicateNumber = true
+	}
+
+	response.Success(c, gin.H{"sims": data})
+}
+
+// Report replace the sims of a device with the sims it reports
+// @Summary report the sims of a device
+// @Description the device reports all the sims it holds, the sims are matched by iccid, the unknown ones are created and the sims that are no longer reported are removed from the device
+// @Tags sim
+// @accept json
+// @Produce json
+// @Param machineCode path string true "machine code of the device"
+// @Param data body types.ReportSimsRequest true "sims of the device"
+// @Success 200 {object} types.ListClientSimsRespond{}
+// @Router /api/v1/devices/{machineCode}/sims [put]
+// @Security BearerAuth
+func (h *simHandler) Report(c *gin.Context) {
+	machineCode := c.Param("machineCode")
+	if checkDeviceMachineCode(c, &machineCode) {
+		return
+	}
+	form := &types.ReportSimsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	slots := map[int]bool{}
+	iccids := map[string]bool{}
+	reported := make([]*model.Sim, 0, len(form.Sims))
+	for _, v := range form.Sims {
+		if slots[v.SlotIndex] || iccids[v.ICCID] {
+			logger.Warn("slot or iccid reported twice", logger.Int("slotIndex", v.SlotIndex), logger.String("iccid", v.ICCID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+		slots[v.SlotIndex] = true
+		iccids[v.ICCID] = true
+		reported = append(reported, &model.Sim{ICCID: v.ICCID, PhoneNumber: v.PhoneNumber, Carrier: v.Carrier, SlotIndex: v.SlotIndex})
+	}
+
+	ctx := middleware.WrapCtx(c)
+	clients, err := h.clientsDao.GetByMachineCode(ctx, machineCode)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("GetByMachineCode not found", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByMachineCode error", logger.Err(err), logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	sims, err := h.iDao.Report(ctx, clients.ID, reported)
+	if err != nil {
+		logger.Error("Report error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSims(sims)
+	if err != nil {
+		response.Error(c, ecode.ErrReportSim)
+		return
+	}
+	h.setDuplicateNumbers(c, data...)
+	for _, v := range data {
+		if v.DuplicateNumber {
+			logger.Warn("phone number is in another device", logger.String("phoneNumber", v.PhoneNumber),
+				logger.String("machineCode", machineCode), middleware.GCtxRequestIDField(c))
+		}
+	}
+
+	response.Success(c, gin.H{"sims": data})
+}
+
+var (
+	// errSimNotInDevice the sim of a record does not exist or is not in the device of the record
+	errSimNotInDevice = errors.New("the sim is not in the device")
+	// errSimSlotEmpty there is no sim in the slot of the device given in a record
+	errSimSlotEmpty = errors.New("there is no sim in the slot of the device")
+)
+
+// getSimID get the sim of a record in the device of the record, by the sim id or else by the slot of the device,
+// 0 if neither is given. errSimNotInDevice or errSimSlotEmpty is returned if the sim is not found in the device.
+func getSimID(ctx context.Context, d dao.SimDao, machineCode string, simID uint64, slotIndex *int) (uint64, error) {
+	if simID != 0 {
+		ok, err := d.IsInDevice(ctx, simID, machineCode)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, errSimNotInDevice
+		}
+		return simID, nil
+	}
+
+	if slotIndex == nil {
+		return 0, nil
+	}
+	id, err := d.GetIDBySlot(ctx, machineCode, *slotIndex)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, errSimSlotEmpty
+	}
+	return id, nil
+}
+
+// isSimError whether the sim given in a record is not found in the device of the record
+func isSimError(err error) bool {
+	return errors.Is(err, errSimNotInDevice) || errors.Is(err, errSimSlotEmpty)
+}
+
+func getSimIDFromPath(c *gin.Context) (string, uint64, bool) {
+	idStr := c.Param("id")
+	id, err := utils.StrToUint64E(idStr)
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", idStr), middleware.GCtxRequestIDField(c))
+		return "", 0, true
+	}
+
+	return idStr, id, false
+}
+
+// setDuplicateNumbers flag the sims whose phone number is also in another device, no sim is flagged if it cannot be checked
+func (h *simHandler) setDuplicateNumbers(c *gin.Context, sims ...*types.SimObjDetail) {
+	phoneNumbers := make([]string, 0, len(sims))
+	for _, v := range sims {
+		if isCountedSim(v) {
+			phoneNumbers = append(phoneNumbers, v.PhoneNumber)
+		}
+	}
+	itemMap, err := h.iDao.GetDuplicateNumbers(middleware.WrapCtx(c), phoneNumbers)
+	if err != nil {
+		logger.Warn("GetDuplicateNumbers error", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return
+	}
+	for _, v := range sims {
+		v.DuplicateNumber = isCountedSim(v) && itemMap[v.PhoneNumber]
+	}
+}
+
+// isCountedSim check if the sim counts when looking for a phone number in two devices
+func isCountedSim(sim *types.SimObjDetail) bool {
+	return sim.PhoneNumber != "" && sim.ClientID != 0 && sim.Status != model.SimStatusCancelled
+}
+
+func convertSim(sim *model.Sim) (*types.SimObjDetail, error) {
+	data := &types.SimObjDetail{}
+	err := copier.Copy(data, sim)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	data.ID = utils.Uint64ToStr(sim.ID)
+	return data, nil
+}
+
+func convertSims(fromValues []*model.Sim) ([]*types.SimObjDetail, error) {
+	toValues := []*types.SimObjDetail{}
+	for _, v := range fromValues {
+		data, err := convertSim(v)
+		if err != nil {
+			return nil, err
+		}
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+
+	"caller/internal/dao"
+	"caller/internal/ecode"
+	"caller/internal/model"
+)
+
+func Test_simHandler(t *testing.T) {
+	db := newDeviceGatewaySqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	h := &simHandler{
+		iDao:       dao.NewSimDao(db),
+		clientsDao: dao.NewClientsDao(db, nil),
+	}
+	callHistory := &callHistoryHandler{iDao: dao.NewCallHistoryDao(db, nil), simDao: h.iDao, recordingDao: dao.NewCallRecordingDao(db)}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/sim", h.Create)
+	r.PUT("/sim/:id", h.UpdateByID)
+	r.GET("/sim/:id", h.GetByID)
+	r.DELETE("/sim/:id", h.DeleteByID)
+	r.POST("/sim/list", h.List)
+	r.GET("/sim/list", h.ListByLastID)
+	r.PUT("/sim/:id/binding", h.Bind)
+	r.GET("/sim/duplicates", h.ListDuplicates)
+	r.GET("/clients/:id/sims", h.ListByClient)
+	r.PUT("/devices/:machineCode/sims", h.Report)
+	r.POST("/callHistory", callHistory.Create)
+	r.GET("/callHistory/:id", callHistory.GetByID)
+	r.POST("/callHistory/batch", callHistory.CreateBatch)
+	sms := &smsHandler{iDao: dao.NewSmsDao(db, nil), simDao: h.iDao}
+	r.POST("/sms", sms.Create)
+
+	for _, machineCode := range []string{"m1", "m2"} {
+		assert.NoError(t, h.clientsDao.Create(ctx, &model.Clients{MachineCode: machineCode}))
+	}
+
+	// create
+	result := doLabelsRequest(t, r, http.MethodPost, "/sim", `{"iccid":"89860000000000000001","phoneNumber":"13800000001","carrier":"cmcc"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/sim", `{"iccid":"89860000000000000001"}`)
+	assert.Equal(t, ecode.AlreadyExists.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/sim", `{"iccid":"8986","status":"lost"}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/1", `{"carrier":"unicom"}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodGet, "/sim/1", "")
+	sim := result.Data.(map[string]interface{})["sim"].(map[string]interface{})
+	assert.Equal(t, "unicom", sim["carrier"])
+	assert.Equal(t, model.SimStatusActive, sim["status"])
+
+	// bind to a client
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/1/binding", `{"clientId":1,"slotIndex":1}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/1/binding", `{"clientId":100,"slotIndex":1}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/100/binding", `{"clientId":1,"slotIndex":0}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/1/binding", `{"clientId":1,"slotIndex":9}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// the second device reports the same number
+	result = doLabelsRequest(t, r, http.MethodPut, "/devices/m2/sims",
+		`{"sims":[{"slotIndex":0,"iccid":"89860000000000000002","phoneNumber":"13800000001"},{"slotIndex":1,"iccid":"89860000000000000003"}]}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	sims := result.Data.(map[string]interface{})["sims"].([]interface{})
+	assert.Len(t, sims, 2)
+	assert.Equal(t, true, sims[0].(map[string]interface{})["duplicateNumber"])
+	assert.Equal(t, false, sims[1].(map[string]interface{})["duplicateNumber"])
+	result = doLabelsRequest(t, r, http.MethodPut, "/devices/m2/sims", `{"sims":[{"slotIndex":0,"iccid":"89860000000000000002"},{"slotIndex":0,"iccid":"89860000000000000003"}]}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPut, "/devices/m3/sims", `{"sims":[]}`)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	result = doLabelsRequest(t, r, http.MethodGet, "/sim/duplicates", "")
+	assert.Equal(t, []string{"1", "2"}, listedIDs(result, "sims"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/1/sims", "")
+	assert.Equal(t, []string{"1"}, listedIDs(result, "sims"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/clients/100/sims", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/sim/list", `{"size":10,"sort":"id"}`)
+	assert.Equal(t, []string{"1", "2", "3"}, listedIDs(result, "sims"))
+	result = doLabelsRequest(t, r, http.MethodGet, "/sim/list?sort=-phone_number", "")
+	assert.Equal(t, 0, result.Code, result.Msg)
+	assert.Len(t, listedIDs(result, "sims"), 3)
+
+	// the call history records the sim in the slot of the client device
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13900000000","simSlot":1}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodGet, "/callHistory/1", "")
+	assert.Equal(t, float64(3), result.Data.(map[string]interface{})["callHistory"].(map[string]interface{})["simId"])
+
+	// the sim given in a record must be in the device of the record
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13900000000","simId":2}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13900000000","simId":1}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m2","mobileNumber":"13900000000","simId":100}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory/batch",
+		`{"records":[{"clientMachineCode":"m1","mobileNumber":"13900000000","simId":1},{"clientMachineCode":"m1","mobileNumber":"13900000000","simId":2}]}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	results := result.Data.(map[string]interface{})["results"].([]interface{})
+	assert.NotEmpty(t, results[0].(map[string]interface{})["id"])
+	assert.Equal(t, errSimNotInDevice.Error(), results[1].(map[string]interface{})["error"])
+	result = doLabelsRequest(t, r, http.MethodPost, "/callHistory", `{"clientMachineCode":"m1","mobileNumber":"13900000000","simSlot":0}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+	result = doLabelsRequest(t, r, http.MethodPost, "/sms", `{"machineCode":"m2","body":"hello","simId":3}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodPost, "/sms", `{"machineCode":"m1","body":"hello","simId":3}`)
+	assert.Equal(t, ecode.InvalidParams.Code(), result.Code)
+
+	// unbind and delete
+	result = doLabelsRequest(t, r, http.MethodPut, "/sim/2/binding", `{"clientId":0}`)
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodGet, "/sim/duplicates", "")
+	assert.Len(t, listedIDs(result, "sims"), 0)
+	result = doLabelsRequest(t, r, http.MethodDelete, "/sim/2", "")
+	assert.Equal(t, 0, result.Code, result.Msg)
+	result = doLabelsRequest(t, r, http.MethodGet, "/sim/2", "")
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+}
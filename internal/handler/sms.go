@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
@@ -24,8 +26,10 @@ var _ SmsHandler = (*smsHandler)(nil)
 // SmsHandler defining the handler interface
 type SmsHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,10 +37,15 @@ type SmsHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
 }
 
 type smsHandler struct {
-	iDao dao.SmsDao
+	iDao   dao.SmsDao
+	simDao dao.SimDao // find and check the sim of the device
 }
 
 // NewSmsHandler creating the handler interface
@@ -46,6 +55,7 @@ func NewSmsHandler() SmsHandler {
 			model.GetDB(),
 			cache.NewSmsCache(model.GetCacheType()),
 		),
+		simDao: dao.NewSimDao(model.GetDB()),
 	}
 }
 
@@ -67,6 +77,9 @@ func (h *smsHandler) Create(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
+	if checkDeviceMachineCode(c, &form.MachineCode) {
+		return
+	}
 
 	sms := &model.Sms{}
 	err = copier.Copy(sms, form)
@@ -77,6 +90,17 @@ func (h *smsHandler) Create(c *gin.Context) {
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 
 	ctx := middleware.WrapCtx(c)
+	sms.SimID, err = getSimID(ctx, h.simDao, form.MachineCode, form.SimID, form.SimSlot)
+	if err != nil {
+		if isSimError(err) {
+			logger.Warn("getSimID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else {
+			logger.Error("getSimID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
 	err = h.iDao.Create(ctx, sms)
 	if err != nil {
 		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
@@ -87,6 +111,85 @@ func (h *smsHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": sms.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create smss in batch
+// @Description submit at most 100 smss in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags sms
+// @accept json
+// @Produce json
+// @Param data body types.CreateSmssBatchRequest true "sms information"
+// @Success 200 {object} types.CreateSmssBatchRespond{}
+// @Router /api/v1/sms/batch [post]
+// @Security BearerAuth
+func (h *smsHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateSmssBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	for i := range form.Records {
+		if checkDeviceMachineCode(c, &form.Records[i].MachineCode) {
+			return
+		}
+	}
+
+	ctx := middleware.WrapCtx(c)
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.Sms, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.Sms{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateSms.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		record.SimID, err = getSimID(ctx, h.simDao, form.Records[i].MachineCode, form.Records[i].SimID, form.Records[i].SimSlot)
+		if err != nil {
+			if isSimError(err) {
+				results[i].Error = err.Error()
+			} else {
+				logger.Error("getSimID error", logger.Err(err), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+				results[i].Error = ecode.ErrCreateSms.Msg()
+			}
+			continue
+		}
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateSms.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete sms
 // @Description delete sms by id
@@ -94,6 +197,7 @@ func (h *smsHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteSmsByIDRespond{}
 // @Router /api/v1/sms/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +208,25 @@ func (h *smsHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +240,7 @@ func (h *smsHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateSmsByIDRequest true "sms information"
 // @Success 200 {object} types.UpdateSmsByIDRespond{}
 // @Router /api/v1/sms/{id} [put]
@@ -133,6 +252,12 @@ func (h *smsHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateSmsByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +274,76 @@ func (h *smsHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	sms.Version = version
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, sms)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch sms
+// @Description update the sms fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags sms
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchSmsByIDRequest true "sms fields to update"
+// @Success 200 {object} types.PatchSmsByIDRespond{}
+// @Router /api/v1/sms/{id} [patch]
+// @Security BearerAuth
+func (h *smsHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getSmsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +358,7 @@ func (h *smsHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetSmsByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/sms/{id} [get]
 // @Security BearerAuth
 func (h *smsHandler) GetByID(c *gin.Context) {
@@ -200,6 +390,7 @@ func (h *smsHandler) GetByID(c *gin.Context) {
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
 
+	setETag(c, sms.Version)
 	response.Success(c, gin.H{"sms": data})
 }
 
@@ -366,23 +557,65 @@ func (h *smsHandler) ListByIDs(c *gin.Context) {
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of smss by last id and limit
-// @Description list of smss by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of smss by cursor and limit
+// @Description list of smss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags sms
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListSmssRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, machine_code, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListSmssByCursorRespond{}
 // @Router /api/v1/sms/list [get]
 // @Security BearerAuth
 func (h *smsHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	smss, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertSmss(smss)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDSms)
+		return
 	}
+
+	response.Success(c, gin.H{
+		"smss":       data,
+		"nextCursor": nextCursor,
+	})
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted smss by cursor and limit
+// @Description list of deleted smss by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags sms
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, machine_code, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListSmssByCursorRespond{}
+// @Router /api/v1/sms/trash [get]
+// @Security BearerAuth
+func (h *smsHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +623,14 @@ func (h *smsHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	smss, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	smss, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -404,10 +642,77 @@ func (h *smsHandler) ListByLastID(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"smss": data,
+		"smss":       data,
+		"nextCursor": nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore sms
+// @Description restore a deleted sms by id
+// @Tags sms
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreSmsByIDRespond{}
+// @Router /api/v1/sms/{id}/restore [post]
+// @Security BearerAuth
+func (h *smsHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getSmsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge sms
+// @Description permanently delete a sms in the trash by id, it cannot be restored afterwards
+// @Tags sms
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeSmsByIDRespond{}
+// @Router /api/v1/sms/trash/{id} [delete]
+// @Security BearerAuth
+func (h *smsHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getSmsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
 func getSmsIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -427,6 +732,10 @@ func convertSms(sms *model.Sms) (*types.SmsObjDetail, error) {
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(sms.ID)
+	if sms.DeletedAt.Valid {
+		deletedAt := sms.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
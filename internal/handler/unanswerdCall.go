@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
-	"math"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/jinzhu/copier"
 
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
@@ -24,8 +26,10 @@ var _ UnanswerdCallHandler = (*unanswerdCallHandler)(nil)
 // UnanswerdCallHandler defining the handler interface
 type UnanswerdCallHandler interface {
 	Create(c *gin.Context)
+	CreateBatch(c *gin.Context)
 	DeleteByID(c *gin.Context)
 	UpdateByID(c *gin.Context)
+	PatchByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 
@@ -33,6 +37,10 @@ type UnanswerdCallHandler interface {
 	GetByCondition(c *gin.Context)
 	ListByIDs(c *gin.Context)
 	ListByLastID(c *gin.Context)
+
+	ListTrash(c *gin.Context)
+	RestoreByID(c *gin.Context)
+	PurgeByID(c *gin.Context)
 }
 
 type unanswerdCallHandler struct {
@@ -67,6 +75,9 @@ func (h *unanswerdCallHandler) Create(c *gin.Context) {
 		response.Error(c, ecode.InvalidParams)
 		return
 	}
+	if checkDeviceMachineCode(c, &form.ClientMachineCode) {
+		return
+	}
 
 	unanswerdCall := &model.UnanswerdCall{}
 	err = copier.Copy(unanswerdCall, form)
@@ -87,6 +98,75 @@ func (h *unanswerdCallHandler) Create(c *gin.Context) {
 	response.Success(c, gin.H{"id": unanswerdCall.ID})
 }
 
+// CreateBatch create records in batch
+// @Summary create unanswerdCalls in batch
+// @Description submit at most 100 unanswerdCalls in one request, with atomic true all or none of them are created, otherwise the valid records are created and an error is reported for each of the others
+// @Tags unanswerdCall
+// @accept json
+// @Produce json
+// @Param data body types.CreateUnanswerdCallsBatchRequest true "unanswerdCall information"
+// @Success 200 {object} types.CreateUnanswerdCallsBatchRespond{}
+// @Router /api/v1/unanswerdCall/batch [post]
+// @Security BearerAuth
+func (h *unanswerdCallHandler) CreateBatch(c *gin.Context) {
+	form := &types.CreateUnanswerdCallsBatchRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	for i := range form.Records {
+		if checkDeviceMachineCode(c, &form.Records[i].ClientMachineCode) {
+			return
+		}
+	}
+
+	results := make([]types.CreateBatchResult, len(form.Records))
+	records := make([]*model.UnanswerdCall, 0, len(form.Records))
+	indexes := make([]int, 0, len(form.Records)) // index in the request of each record to create
+	for i := range form.Records {
+		results[i].Index = i
+		err = binding.Validator.ValidateStruct(&form.Records[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		record := &model.UnanswerdCall{}
+		err = copier.Copy(record, &form.Records[i])
+		if err != nil {
+			results[i].Error = ecode.ErrCreateUnanswerdCall.Msg()
+			continue
+		}
+		// Note: if copier.Copy cannot assign a value to a field, add it here
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+	if form.Atomic && len(records) < len(form.Records) {
+		logger.Warn("CreateBatch invalid records", logger.Any("results", results), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InvalidParams.ToHTTPCode(), newCreateBatchData(results))
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	errs, err := h.iDao.CreateBatch(ctx, records, form.Atomic)
+	if err != nil {
+		logger.Error("CreateBatch error", logger.Err(err), logger.Int("size", len(records)), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for j, i := range indexes {
+		if errs[j] != nil {
+			logger.Warn("CreateBatch record error", logger.Err(errs[j]), logger.Int("index", i), middleware.GCtxRequestIDField(c))
+			results[i].Error = ecode.ErrCreateUnanswerdCall.Msg()
+			continue
+		}
+		results[i].ID = records[j].ID
+	}
+
+	response.Success(c, newCreateBatchData(results))
+}
+
 // DeleteByID delete a record by id
 // @Summary delete unanswerdCall
 // @Description delete unanswerdCall by id
@@ -94,6 +174,7 @@ func (h *unanswerdCallHandler) Create(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Success 200 {object} types.DeleteUnanswerdCallByIDRespond{}
 // @Router /api/v1/unanswerdCall/{id} [delete]
 // @Security BearerAuth
@@ -104,11 +185,25 @@ func (h *unanswerdCallHandler) DeleteByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.iDao.DeleteByID(ctx, id, version)
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("DeleteByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("DeleteByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -122,6 +217,7 @@ func (h *unanswerdCallHandler) DeleteByID(c *gin.Context) {
 // @accept json
 // @Produce json
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
 // @Param data body types.UpdateUnanswerdCallByIDRequest true "unanswerdCall information"
 // @Success 200 {object} types.UpdateUnanswerdCallByIDRespond{}
 // @Router /api/v1/unanswerdCall/{id} [put]
@@ -133,6 +229,12 @@ func (h *unanswerdCallHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
 	form := &types.UpdateUnanswerdCallByIDRequest{}
 	err := c.ShouldBindJSON(form)
 	if err != nil {
@@ -149,12 +251,76 @@ func (h *unanswerdCallHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	unanswerdCall.Version = version
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.UpdateByID(ctx, unanswerdCall)
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("UpdateByID version mismatch", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("UpdateByID not found", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PatchByID update the fields in the patch by id
+// @Summary patch unanswerdCall
+// @Description update the unanswerdCall fields present in a json merge patch (RFC 7396), null or "" clears a field
+// @Tags unanswerdCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag returned by GetByID, the request fails with 412 if the record has been changed since"
+// @Param data body types.PatchUnanswerdCallByIDRequest true "unanswerdCall fields to update"
+// @Success 200 {object} types.PatchUnanswerdCallByIDRespond{}
+// @Router /api/v1/unanswerdCall/{id} [patch]
+// @Security BearerAuth
+func (h *unanswerdCallHandler) PatchByID(c *gin.Context) {
+	_, id, isAbort := getUnanswerdCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	version, isAbort := getIfMatchVersion(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	patch := map[string]json.RawMessage{}
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.PatchByID(ctx, id, version, patch)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionMismatch) {
+			logger.Warn("PatchByID version mismatch", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, http.StatusPreconditionFailed)
+		} else if errors.Is(err, dao.ErrInvalidPatch) {
+			logger.Warn("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+		} else if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PatchByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PatchByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -169,6 +335,7 @@ func (h *unanswerdCallHandler) UpdateByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.GetUnanswerdCallByIDRespond{}
+// @Header 200 {string} ETag "version of the record, send it in the If-Match header to update or delete the record"
 // @Router /api/v1/unanswerdCall/{id} [get]
 // @Security BearerAuth
 func (h *unanswerdCallHandler) GetByID(c *gin.Context) {
@@ -200,6 +367,7 @@ func (h *unanswerdCallHandler) GetByID(c *gin.Context) {
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = idStr
 
+	setETag(c, unanswerdCall.Version)
 	response.Success(c, gin.H{"unanswerdCall": data})
 }
 
@@ -366,23 +534,65 @@ func (h *unanswerdCallHandler) ListByIDs(c *gin.Context) {
 	})
 }
 
-// ListByLastID get records by last id and limit
-// @Summary list of unanswerdCalls by last id and limit
-// @Description list of unanswerdCalls by last id and limit
+// ListByLastID get records by cursor and limit
+// @Summary list of unanswerdCalls by cursor and limit
+// @Description list of unanswerdCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
 // @Tags unanswerdCall
 // @accept json
 // @Produce json
-// @Param lastID query int true "last id, default is MaxInt32" default(0)
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
 // @Param limit query int false "size in each page" default(10)
-// @Param sort query string false "sort by column name of table, and the "-" sign before column name indicates reverse order" default(-id)
-// @Success 200 {object} types.ListUnanswerdCallsRespond{}
+// @Param sort query string false "sort by id, created_at, updated_at, client_machine_code, mobile_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order, if empty the sort of the cursor is used" default(-id)
+// @Success 200 {object} types.ListUnanswerdCallsByCursorRespond{}
 // @Router /api/v1/unanswerdCall/list [get]
 // @Security BearerAuth
 func (h *unanswerdCallHandler) ListByLastID(c *gin.Context) {
-	lastID := utils.StrToUint64(c.Query("lastID"))
-	if lastID == 0 {
-		lastID = math.MaxInt32
+	cursor := c.Query("cursor")
+	limit := utils.StrToInt(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+	sort := c.Query("sort")
+
+	ctx := middleware.WrapCtx(c)
+	unanswerdCalls, nextCursor, err := h.iDao.GetByCursor(ctx, cursor, limit, sort)
+	if err != nil {
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertUnanswerdCalls(unanswerdCalls)
+	if err != nil {
+		response.Error(c, ecode.ErrListByLastIDUnanswerdCall)
+		return
 	}
+
+	response.Success(c, gin.H{
+		"unanswerdCalls": data,
+		"nextCursor":     nextCursor,
+	})
+}
+
+// ListTrash get deleted records by cursor and limit
+// @Summary list of deleted unanswerdCalls by cursor and limit
+// @Description list of deleted unanswerdCalls by cursor and limit, pass the nextCursor of the respond to get the next page, nextCursor is empty on the last page
+// @Tags unanswerdCall
+// @accept json
+// @Produce json
+// @Param cursor query string false "nextCursor of the previous page, empty means the first page"
+// @Param limit query int false "size in each page" default(10)
+// @Param sort query string false "sort by id, created_at, updated_at, deleted_at, client_machine_code, mobile_number, multiple columns separated by commas, the "-" sign before column name indicates reverse order" default(-deleted_at)
+// @Success 200 {object} types.ListUnanswerdCallsByCursorRespond{}
+// @Router /api/v1/unanswerdCall/trash [get]
+// @Security BearerAuth
+func (h *unanswerdCallHandler) ListTrash(c *gin.Context) {
+	cursor := c.Query("cursor")
 	limit := utils.StrToInt(c.Query("limit"))
 	if limit == 0 {
 		limit = 10
@@ -390,9 +600,14 @@ func (h *unanswerdCallHandler) ListByLastID(c *gin.Context) {
 	sort := c.Query("sort")
 
 	ctx := middleware.WrapCtx(c)
-	unanswerdCalls, err := h.iDao.GetByLastID(ctx, lastID, limit, sort)
+	unanswerdCalls, nextCursor, err := h.iDao.GetDeletedByCursor(ctx, cursor, limit, sort)
 	if err != nil {
-		logger.Error("GetByLastID error", logger.Err(err), logger.Uint64("latsID", lastID), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
+		if errors.Is(err, dao.ErrInvalidCursor) || errors.Is(err, dao.ErrInvalidSort) {
+			logger.Warn("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.String("sort", sort), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InvalidParams.ToHTTPCode())
+			return
+		}
+		logger.Error("GetDeletedByCursor error", logger.Err(err), logger.String("cursor", cursor), logger.Int("limit", limit), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -405,9 +620,76 @@ func (h *unanswerdCallHandler) ListByLastID(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"unanswerdCalls": data,
+		"nextCursor":     nextCursor,
 	})
 }
 
+// RestoreByID restore a deleted record by id
+// @Summary restore unanswerdCall
+// @Description restore a deleted unanswerdCall by id
+// @Tags unanswerdCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.RestoreUnanswerdCallByIDRespond{}
+// @Router /api/v1/unanswerdCall/{id}/restore [post]
+// @Security BearerAuth
+func (h *unanswerdCallHandler) RestoreByID(c *gin.Context) {
+	_, id, isAbort := getUnanswerdCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.RestoreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("RestoreByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("RestoreByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
+// PurgeByID permanently delete a deleted record by id
+// @Summary purge unanswerdCall
+// @Description permanently delete a unanswerdCall in the trash by id, it cannot be restored afterwards
+// @Tags unanswerdCall
+// @accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PurgeUnanswerdCallByIDRespond{}
+// @Router /api/v1/unanswerdCall/trash/{id} [delete]
+// @Security BearerAuth
+func (h *unanswerdCallHandler) PurgeByID(c *gin.Context) {
+	_, id, isAbort := getUnanswerdCallIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.PurgeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			logger.Warn("PurgeByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("PurgeByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c)
+}
+
 func getUnanswerdCallIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -427,6 +709,10 @@ func convertUnanswerdCall(unanswerdCall *model.UnanswerdCall) (*types.UnanswerdC
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
 	data.ID = utils.Uint64ToStr(unanswerdCall.ID)
+	if unanswerdCall.DeletedAt.Valid {
+		deletedAt := unanswerdCall.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
 	return data, nil
 }
 
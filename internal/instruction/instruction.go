@@ -0,0 +1,249 @@
+// Package instruction is the registry of the instructions that can be sent to the client devices,
+// each instruction has its own payload that is validated before the instruction is queued.
+package instruction
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Version the version of the instruction set, it is increased when an instruction or a payload field
+// is added or changed, so the devices can check that they understand all the instructions
+const Version = 1
+
+// MaxPayloadLength the maximum length of a payload in bytes, after it is normalized
+const MaxPayloadLength = 1024
+
+// the supported instructions
+const (
+	Dial     = "dial"
+	Hangup   = "hangup"
+	Answer   = "answer"
+	Reject   = "reject"
+	Transfer = "transfer"
+	SendDTMF = "sendDTMF"
+	Mute     = "mute"
+)
+
+var (
+	// ErrUnknown the instruction is not in the registry
+	ErrUnknown = errors.New("unknown instruction")
+	// ErrMobileNumberRequired the instruction needs a mobile number
+	ErrMobileNumberRequired = errors.New("mobile number is required")
+	// ErrInvalidPayload the payload is not a json object, has unknown fields, a value of the wrong type or is too long
+	ErrInvalidPayload = errors.New("invalid payload")
+	// ErrInvalidTarget the number a call is transferred to is empty or not a phone number
+	ErrInvalidTarget = errors.New("invalid transfer target")
+	// ErrInvalidDigits the dtmf digits are empty, too long or not one of 0-9 * # A-D
+	ErrInvalidDigits = errors.New("invalid dtmf digits")
+	// ErrMutedRequired the mute instruction does not say whether to mute or unmute
+	ErrMutedRequired = errors.New("muted is required")
+)
+
+var (
+	phoneNumberPattern = regexp.MustCompile(`^\+?[0-9]{3,20}$`)
+	dtmfPattern        = regexp.MustCompile(`^[0-9*#A-D]{1,32}$`)
+)
+
+// Field a field of the payload of an instruction
+type Field struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, integer or boolean
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// Spec an instruction in the registry
+type Spec struct {
+	Name                 string  `json:"name"`
+	Description          string  `json:"description"`
+	MobileNumberRequired bool    `json:"mobileNumberRequired"` // the mobile number of the call history or the command is used
+	Payload              []Field `json:"payload"`              // empty if the instruction has no payload
+
+	// newPayload return a pointer to the payload struct of the instruction, nil if it has no payload
+	newPayload func() payload
+}
+
+// payload the payload of an instruction, validate checks the values after the json is decoded
+type payload interface {
+	validate() error
+}
+
+// RejectPayload the payload of reject
+type RejectPayload struct {
+	Message string `json:"message,omitempty"`
+}
+
+func (p *RejectPayload) validate() error {
+	if utf8.RuneCountInString(p.Message) > 160 {
+		return fmt.Errorf("%w: message is longer than 160 characters", ErrInvalidPayload)
+	}
+	return nil
+}
+
+// TransferPayload the payload of transfer
+type TransferPayload struct {
+	Target string `json:"target"`
+}
+
+func (p *TransferPayload) validate() error {
+	if !phoneNumberPattern.MatchString(p.Target) {
+		return fmt.Errorf("%w: %q", ErrInvalidTarget, p.Target)
+	}
+	return nil
+}
+
+// SendDTMFPayload the payload of sendDTMF
+type SendDTMFPayload struct {
+	Digits     string `json:"digits"`
+	IntervalMs int    `json:"intervalMs,omitempty"`
+}
+
+func (p *SendDTMFPayload) validate() error {
+	if !dtmfPattern.MatchString(p.Digits) {
+		return fmt.Errorf("%w: %q", ErrInvalidDigits, p.Digits)
+	}
+	if p.IntervalMs < 0 || p.IntervalMs > 5000 {
+		return fmt.Errorf("%w: intervalMs is not in 0~5000", ErrInvalidPayload)
+	}
+	return nil
+}
+
+// MutePayload the payload of mute
+type MutePayload struct {
+	Muted *bool `json:"muted"`
+}
+
+func (p *MutePayload) validate() error {
+	if p.Muted == nil {
+		return ErrMutedRequired
+	}
+	return nil
+}
+
+// specs the registry, in the order they are listed
+var specs = []*Spec{
+	{
+		Name:                 Dial,
+		Description:          "call the mobile number",
+		MobileNumberRequired: true,
+	},
+	{
+		Name:        Hangup,
+		Description: "end the current call",
+	},
+	{
+		Name:        Answer,
+		Description: "answer the ringing incoming call",
+	},
+	{
+		Name:        Reject,
+		Description: "reject the ringing incoming call",
+		Payload: []Field{
+			{Name: "message", Type: "string", Description: "sms sent to the caller, at most 160 characters"},
+		},
+		newPayload: func() payload { return &RejectPayload{} },
+	},
+	{
+		Name:        Transfer,
+		Description: "transfer the current call to another number",
+		Payload: []Field{
+			{Name: "target", Type: "string", Required: true, Description: "the number the call is transferred to, digits with an optional leading +"},
+		},
+		newPayload: func() payload { return &TransferPayload{} },
+	},
+	{
+		Name:        SendDTMF,
+		Description: "send dtmf tones in the current call",
+		Payload: []Field{
+			{Name: "digits", Type: "string", Required: true, Description: "at most 32 of 0-9 * # A-D"},
+			{Name: "intervalMs", Type: "integer", Description: "pause between two tones in milliseconds, 0~5000, 0 is the default of the device"},
+		},
+		newPayload: func() payload { return &SendDTMFPayload{} },
+	},
+	{
+		Name:        Mute,
+		Description: "mute or unmute the microphone in the current call",
+		Payload: []Field{
+			{Name: "muted", Type: "boolean", Required: true, Description: "true mutes, false unmutes"},
+		},
+		newPayload: func() payload { return &MutePayload{} },
+	},
+}
+
+var registry = func() map[string]*Spec {
+	m := make(map[string]*Spec, len(specs))
+	for _, spec := range specs {
+		m[spec.Name] = spec
+	}
+	return m
+}()
+
+// List the instructions in the registry
+func List() []*Spec {
+	return specs
+}
+
+// Get get an instruction by its name, the names are case sensitive
+func Get(name string) (*Spec, bool) {
+	spec, ok := registry[name]
+	return spec, ok
+}
+
+// Validate check the instruction, its mobile number and its payload, and return the payload normalized
+// to compact json with only the known fields, an empty payload is returned as "". a payload that is
+// empty or null is the same as {}.
+func Validate(name string, mobileNumber string, raw json.RawMessage) (string, error) {
+	spec, ok := registry[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknown, name)
+	}
+	if spec.MobileNumberRequired && mobileNumber == "" {
+		return "", fmt.Errorf("%w: %s", ErrMobileNumberRequired, name)
+	}
+
+	if spec.newPayload == nil {
+		if IsEmptyPayload(raw) {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: %s has no payload", ErrInvalidPayload, name)
+	}
+
+	p := spec.newPayload()
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && string(raw) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(p); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		if decoder.More() {
+			return "", fmt.Errorf("%w: data after the json object", ErrInvalidPayload)
+		}
+	}
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if len(data) > MaxPayloadLength {
+		return "", fmt.Errorf("%w: longer than %d bytes", ErrInvalidPayload, MaxPayloadLength)
+	}
+	if string(data) == "{}" {
+		return "", nil
+	}
+	return string(data), nil
+}
+
+// IsEmptyPayload check whether a payload is empty, null or {}
+func IsEmptyPayload(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || string(raw) == "null" || string(bytes.Join(bytes.Fields(raw), nil)) == "{}"
+}
@@ -0,0 +1,80 @@
+package instruction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		mobileNumber string
+		payload      string
+		want         string
+		wantErr      error
+	}{
+		{Dial, "13800000000", "", "", nil},
+		{Dial, "13800000000", "null", "", nil},
+		{Dial, "13800000000", " { } ", "", nil},
+		{Dial, "", "", "", ErrMobileNumberRequired},
+		{Dial, "13800000000", `{"speaker":true}`, "", ErrInvalidPayload},
+		{"dail", "13800000000", "", "", ErrUnknown},
+		{"Dial", "13800000000", "", "", ErrUnknown},
+		{"", "", "", "", ErrUnknown},
+		{Hangup, "", "", "", nil},
+		{Answer, "", "{}", "", nil},
+		{Reject, "", "", "", nil},
+		{Reject, "", `{"message":"busy, call you later"}`, `{"message":"busy, call you later"}`, nil},
+		{Reject, "", `{"message":"` + strings.Repeat("m", 161) + `"}`, "", ErrInvalidPayload},
+		{Transfer, "", `{"target":"+8613800000000"}`, `{"target":"+8613800000000"}`, nil},
+		{Transfer, "", `{ "target" : "10086" }`, `{"target":"10086"}`, nil},
+		{Transfer, "", "", "", ErrInvalidTarget},
+		{Transfer, "", `{"target":"call me"}`, "", ErrInvalidTarget},
+		{Transfer, "", `{"target":"10086","to":"10010"}`, "", ErrInvalidPayload},
+		{Transfer, "", `["10086"]`, "", ErrInvalidPayload},
+		{Transfer, "", `{"target":"10086"}{}`, "", ErrInvalidPayload},
+		{SendDTMF, "", `{"digits":"123#"}`, `{"digits":"123#"}`, nil},
+		{SendDTMF, "", `{"digits":"*1A","intervalMs":200}`, `{"digits":"*1A","intervalMs":200}`, nil},
+		{SendDTMF, "", `{"digits":"12e"}`, "", ErrInvalidDigits},
+		{SendDTMF, "", `{"digits":"1","intervalMs":6000}`, "", ErrInvalidPayload},
+		{SendDTMF, "", `{"digits":1}`, "", ErrInvalidPayload},
+		{Mute, "", `{"muted":false}`, `{"muted":false}`, nil},
+		{Mute, "", `{}`, "", ErrMutedRequired},
+	}
+	for _, tt := range tests {
+		got, err := Validate(tt.name, tt.mobileNumber, json.RawMessage(tt.payload))
+		if tt.wantErr != nil {
+			assert.ErrorIs(t, err, tt.wantErr, tt.name+" "+tt.payload)
+			continue
+		}
+		assert.NoError(t, err, tt.name+" "+tt.payload)
+		assert.Equal(t, tt.want, got, tt.name+" "+tt.payload)
+	}
+}
+
+func TestList(t *testing.T) {
+	names := []string{}
+	for _, spec := range List() {
+		names = append(names, spec.Name)
+		got, ok := Get(spec.Name)
+		assert.True(t, ok)
+		assert.Equal(t, spec, got)
+		// a payload struct for every instruction with payload fields
+		assert.Equal(t, len(spec.Payload) > 0, spec.newPayload != nil, spec.Name)
+	}
+	assert.Equal(t, []string{Dial, Hangup, Answer, Reject, Transfer, SendDTMF, Mute}, names)
+	_, ok := Get("call")
+	assert.False(t, ok)
+}
+
+func TestIsEmptyPayload(t *testing.T) {
+	for _, payload := range []string{"", " ", "null", "{}", "{ \n}"} {
+		assert.True(t, IsEmptyPayload(json.RawMessage(payload)), payload)
+	}
+	for _, payload := range []string{`{"muted":true}`, "[]", `""`} {
+		assert.False(t, IsEmptyPayload(json.RawMessage(payload)), payload)
+	}
+}
@@ -0,0 +1,234 @@
+// Package labels validates the key/value labels of the records and parses the label selectors
+// that filter the records by their labels.
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLabels the maximum number of labels of a record
+const MaxLabels = 64
+
+// maxLength the maximum length of a key or a value in characters
+const maxLength = 63
+
+var (
+	// ErrInvalidKey the key is empty, too long or has characters that are not allowed
+	ErrInvalidKey = errors.New("invalid label key")
+	// ErrInvalidValue the value is too long or has characters that are not allowed
+	ErrInvalidValue = errors.New("invalid label value")
+	// ErrTooMany a record has more than MaxLabels labels
+	ErrTooMany = fmt.Errorf("a record has at most %d labels", MaxLabels)
+	// ErrInvalidSelector the label selector cannot be parsed
+	ErrInvalidSelector = errors.New("invalid label selector")
+)
+
+var (
+	// a key starts and ends with a letter or digit, and may have '-', '_', '.' and '/' in between
+	keyPattern = regexp.MustCompile(`^[\p{L}\p{N}]([\p{L}\p{N}_./-]*[\p{L}\p{N}])?$`)
+	// a value is empty, or starts and ends with a letter or digit and may have '-', '_' and '.' in between
+	valuePattern = regexp.MustCompile(`^([\p{L}\p{N}]([\p{L}\p{N}_.-]*[\p{L}\p{N}])?)?$`)
+)
+
+// ValidateKey check whether the key of a label is valid
+func ValidateKey(key string) error {
+	if utf8.RuneCountInString(key) > maxLength || !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// ValidateValue check whether the value of a label is valid
+func ValidateValue(value string) error {
+	if utf8.RuneCountInString(value) > maxLength || !valuePattern.MatchString(value) {
+		return fmt.Errorf("%w: %q", ErrInvalidValue, value)
+	}
+	return nil
+}
+
+// Validate check the keys and values of the labels of a record, and that there are at most MaxLabels labels
+func Validate(labels map[string]string) error {
+	if len(labels) > MaxLabels {
+		return ErrTooMany
+	}
+	for key, value := range labels {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if err := ValidateValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Operator the operator of a requirement of a selector
+type Operator string
+
+const (
+	// Equals the label has the value, "key=value" or "key==value"
+	Equals Operator = "="
+	// NotEquals the label does not have the value or the record does not have the label, "key!=value"
+	NotEquals Operator = "!="
+	// In the label has one of the values, "key in (v1,v2)"
+	In Operator = "in"
+	// NotIn the label has none of the values or the record does not have the label, "key notin (v1,v2)"
+	NotIn Operator = "notin"
+	// Exists the record has the label, "key"
+	Exists Operator = "exists"
+	// DoesNotExist the record does not have the label, "!key"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement a condition on one label
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string // one value for Equals and NotEquals, at least one for In and NotIn, none for Exists and DoesNotExist
+}
+
+// Matches check whether the labels meet the requirement
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case Equals, In:
+		return ok && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, value)
+	}
+	return false
+}
+
+// Selector the requirements that the labels of a record all have to meet, an empty selector selects every record
+type Selector []Requirement
+
+// Matches check whether the labels meet all the requirements of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty whether the selector selects every record
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Parse parse a label selector, the requirements are separated by commas, e.g.
+//
+//	site=shanghai,carrier!=cmcc
+//	site in (shanghai,beijing),customer,!retired
+//
+// an empty string is an empty selector.
+func Parse(str string) (Selector, error) {
+	terms, err := splitTerms(str)
+	if err != nil {
+		return nil, err
+	}
+	selector := Selector{}
+	for _, term := range terms {
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// splitTerms split the selector by the commas that are not in parentheses
+func splitTerms(str string) ([]string, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range str {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("%w: nested parentheses", ErrInvalidSelector)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%w: unbalanced parentheses", ErrInvalidSelector)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, str[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unbalanced parentheses", ErrInvalidSelector)
+	}
+	return append(terms, str[start:]), nil
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	term = strings.TrimSpace(term)
+	r := Requirement{}
+	switch {
+	case term == "":
+		return r, fmt.Errorf("%w: empty requirement", ErrInvalidSelector)
+
+	case strings.HasPrefix(term, "!") && !strings.Contains(term, "="):
+		r.Key, r.Operator = strings.TrimSpace(term[1:]), DoesNotExist
+
+	case strings.Contains(term, "!="):
+		key, value, _ := strings.Cut(term, "!=")
+		r.Key, r.Operator, r.Values = strings.TrimSpace(key), NotEquals, []string{strings.TrimSpace(value)}
+
+	case strings.Contains(term, "="):
+		key, value, _ := strings.Cut(term, "=")
+		value = strings.TrimPrefix(value, "=")
+		r.Key, r.Operator, r.Values = strings.TrimSpace(key), Equals, []string{strings.TrimSpace(value)}
+
+	case strings.HasSuffix(term, ")"):
+		head, list, ok := strings.Cut(strings.TrimSuffix(term, ")"), "(")
+		fields := strings.Fields(head)
+		if !ok || len(fields) != 2 || (fields[1] != string(In) && fields[1] != string(NotIn)) {
+			return r, fmt.Errorf("%w: %q", ErrInvalidSelector, term)
+		}
+		r.Key, r.Operator = fields[0], Operator(fields[1])
+		for _, value := range strings.Split(list, ",") {
+			r.Values = append(r.Values, strings.TrimSpace(value))
+		}
+
+	default:
+		r.Key, r.Operator = term, Exists
+	}
+
+	if err := ValidateKey(r.Key); err != nil {
+		return r, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+	}
+	for _, value := range r.Values {
+		if err := ValidateValue(value); err != nil {
+			return r, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+	}
+	return r, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate(map[string]string{"site": "shanghai", "caller.io/customer": "acme-1", "站点": "上海", "retired": ""}))
+
+	for _, key := range []string{"", "-site", "site-", "si te", "site=", strings.Repeat("k", 64)} {
+		assert.ErrorIs(t, Validate(map[string]string{key: "v"}), ErrInvalidKey, key)
+	}
+	for _, value := range []string{"-v", "v/1", "a,b", "(v)", strings.Repeat("v", 64)} {
+		assert.ErrorIs(t, Validate(map[string]string{"k": value}), ErrInvalidValue, value)
+	}
+
+	labels := map[string]string{}
+	for i := 0; i <= MaxLabels; i++ {
+		labels[fmt.Sprintf("k%d", i)] = "v"
+	}
+	assert.ErrorIs(t, Validate(labels), ErrTooMany)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		str  string
+		want Selector
+	}{
+		{"", Selector{}},
+		{"site=shanghai,carrier!=cmcc", Selector{
+			{Key: "site", Operator: Equals, Values: []string{"shanghai"}},
+			{Key: "carrier", Operator: NotEquals, Values: []string{"cmcc"}},
+		}},
+		{" site == shanghai ", Selector{{Key: "site", Operator: Equals, Values: []string{"shanghai"}}}},
+		{"site in (shanghai, beijing),customer notin (acme)", Selector{
+			{Key: "site", Operator: In, Values: []string{"shanghai", "beijing"}},
+			{Key: "customer", Operator: NotIn, Values: []string{"acme"}},
+		}},
+		{"customer,!retired", Selector{
+			{Key: "customer", Operator: Exists},
+			{Key: "retired", Operator: DoesNotExist},
+		}},
+		{"retired=", Selector{{Key: "retired", Operator: Equals, Values: []string{""}}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.str)
+		assert.NoError(t, err, tt.str)
+		assert.Equal(t, tt.want, got, tt.str)
+	}
+
+	for _, str := range []string{",", "site=shanghai,", "=shanghai", "!=cmcc", "site=a=b", "site in shanghai)",
+		"site in (shanghai", "site in ((a))", "site has (a)", "si te"} {
+		_, err := Parse(str)
+		assert.ErrorIs(t, err, ErrInvalidSelector, str)
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{"site": "shanghai", "carrier": "cmcc"}
+	tests := []struct {
+		str  string
+		want bool
+	}{
+		{"", true},
+		{"site=shanghai", true},
+		{"site=beijing", false},
+		{"site=shanghai,carrier!=cmcc", false},
+		{"site=shanghai,carrier!=unicom", true},
+		{"customer!=acme", true},
+		{"site in (beijing,shanghai)", true},
+		{"site notin (beijing,shanghai)", false},
+		{"customer notin (acme)", true},
+		{"customer in (acme)", false},
+		{"carrier", true},
+		{"!carrier", false},
+		{"!customer", true},
+	}
+	for _, tt := range tests {
+		selector, err := Parse(tt.str)
+		assert.NoError(t, err, tt.str)
+		assert.Equal(t, tt.want, selector.Matches(labels), tt.str)
+	}
+}
@@ -0,0 +1,393 @@
+// Package migration applies the versioned database schema migrations embedded in the binary.
+//
+// Migrations live in sql/<dialect>/ and are named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, the dialect is the gorm dialector name: mysql, postgres or sqlite.
+// Applied versions are recorded in the schema_migrations table, and a row in the
+// schema_migrations_lock table prevents two replicas from migrating at the same time.
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var sqlFS embed.FS
+
+const (
+	historyTable = "schema_migrations"
+	lockTable    = "schema_migrations_lock"
+	lockID       = 1
+)
+
+var (
+	// ErrLocked another process holds the migration lock
+	ErrLocked = errors.New("migration lock is held by another process")
+	// ErrUnsupportedDialect no migrations for the database dialect
+	ErrUnsupportedDialect = errors.New("unsupported database dialect")
+)
+
+// Migration a versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status of a migration
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// history record of an applied migration
+type history struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;type:varchar(255)"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+// TableName table name
+func (h *history) TableName() string {
+	return historyTable
+}
+
+// lock the single row that serializes migrations
+type lock struct {
+	ID       int       `gorm:"column:id;primaryKey;autoIncrement:false"`
+	Owner    string    `gorm:"column:owner;type:varchar(128)"`
+	LockedAt time.Time `gorm:"column:locked_at"`
+}
+
+// TableName table name
+func (l *lock) TableName() string {
+	return lockTable
+}
+
+// Migrator runs migrations against a database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+	o          *options
+}
+
+// New create a migrator for the dialect of db
+func New(db *gorm.DB, opts ...Option) (*Migrator, error) {
+	o := defaultOptions()
+	o.apply(opts...)
+
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		o:          o,
+	}, nil
+}
+
+// Load read the embedded migrations of a dialect, sorted by version
+func Load(dialect string) ([]*Migration, error) {
+	dir := path.Join("sql", dialect)
+	entries, err := fs.ReadDir(sqlFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, dialect)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		ss := strings.SplitN(base, "_", 2)
+		if len(ss) != 2 {
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+		version, err := strconv.ParseInt(ss[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s, %v", fileName, err)
+		}
+		content, err := fs.ReadFile(sqlFS, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: ss[1]}
+			byVersion[version] = m
+		} else if m.Name != ss[1] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, m.Name, ss[1])
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up apply pending migrations in version order, steps <= 0 means all pending migrations.
+// it returns the migrations that were applied.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]*Migration, error) {
+	var done []*Migration
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if steps > 0 && len(done) >= steps {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err = m.run(ctx, migration, true)
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down roll back applied migrations in reverse version order, steps <= 0 means 1.
+// it returns the migrations that were rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []*Migration
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err = m.run(ctx, migration, false)
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status list every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	err := m.ensureTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := &Status{Version: migration.Version, Name: migration.Name}
+		if h, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = h.AppliedAt
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	return m.db.WithContext(ctx).AutoMigrate(&history{}, &lock{})
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]*history, error) {
+	var records []*history
+	err := m.db.WithContext(ctx).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]*history, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// run execute the statements of one migration and record the result in the same transaction,
+// mysql commits DDL statements implicitly, so a failed mysql migration may be partially applied.
+func (m *Migrator) run(ctx context.Context, migration *Migration, isUp bool) error {
+	script := migration.Down
+	if isUp {
+		script = migration.Up
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if isUp {
+			return tx.Create(&history{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&history{}).Error
+	})
+	if err != nil {
+		direction := "down"
+		if isUp {
+			direction = "up"
+		}
+		return fmt.Errorf("migration %d_%s %s error: %v", migration.Version, migration.Name, direction, err)
+	}
+
+	return nil
+}
+
+// withLock run fn while holding the migration lock, the lock is refreshed periodically
+// so that it is only taken over by another process after the holder stops.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	err := m.ensureTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = m.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		ticker := time.NewTicker(m.o.staleAfter / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.db.Model(&lock{}).Where("id = ? AND owner = ?", lockID, m.o.owner).Update("locked_at", time.Now())
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		close(stop)
+		<-refreshed
+		m.db.Where("id = ? AND owner = ?", lockID, m.o.owner).Delete(&lock{})
+	}()
+
+	return fn()
+}
+
+func (m *Migrator) acquire(ctx context.Context) error {
+	deadline := time.Now().Add(m.o.lockTimeout)
+	for {
+		err := m.db.WithContext(ctx).Create(&lock{ID: lockID, Owner: m.o.owner, LockedAt: time.Now()}).Error
+		if err == nil {
+			return nil
+		}
+
+		// take over a lock that its holder stopped refreshing
+		result := m.db.WithContext(ctx).Where("id = ? AND locked_at < ?", lockID, time.Now().Add(-m.o.staleAfter)).Delete(&lock{})
+		if result.Error == nil && result.RowsAffected > 0 {
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			holder := &lock{}
+			if e := m.db.WithContext(ctx).Where("id = ?", lockID).First(holder).Error; e == nil {
+				return fmt.Errorf("%w, owner=%s, lockedAt=%s", ErrLocked, holder.Owner, holder.LockedAt.Format(time.RFC3339))
+			}
+			return fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.o.retryInterval):
+		}
+	}
+}
+
+// splitStatements split a script into statements, statements end with a semicolon at the end of a line
+func splitStatements(script string) []string {
+	var statements []string
+	var sb strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		}
+	}
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		statements = append(statements, s)
+	}
+	return statements
+}
+
+func defaultOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
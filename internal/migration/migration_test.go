@@ -0,0 +1,152 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+func newSqliteDB(t *testing.T) *gorm.DB {
+	db, err := ggorm.InitSqlite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestLoad(t *testing.T) {
+	expected, err := Load("mysql")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, expected)
+
+	// every dialect must provide the same versions
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		migrations, err := Load(dialect)
+		assert.NoError(t, err)
+		assert.Equal(t, len(expected), len(migrations), dialect)
+		for i, m := range migrations {
+			assert.Equal(t, expected[i].Version, m.Version, dialect)
+			assert.Equal(t, expected[i].Name, m.Name, dialect)
+			assert.NotEmpty(t, m.Up)
+			assert.NotEmpty(t, m.Down)
+		}
+	}
+
+	_, err = Load("mongodb")
+	assert.ErrorIs(t, err, ErrUnsupportedDialect)
+}
+
+func TestMigrator(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	m, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := len(m.migrations)
+
+	statuses, err := m.Status(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, total)
+	for _, s := range statuses {
+		assert.False(t, s.Applied)
+	}
+
+	done, err := m.Up(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, done, 1)
+
+	done, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, done, total-1)
+	for _, table := range []string{"call_history", "clients", "distribution", "group_call", "group_client", "sms", "unanswerd_call", "user"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	// nothing left to apply
+	done, err = m.Up(ctx, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, done)
+
+	statuses, err = m.Status(ctx)
+	assert.NoError(t, err)
+	for _, s := range statuses {
+		assert.True(t, s.Applied)
+		assert.False(t, s.AppliedAt.IsZero())
+	}
+
+	done, err = m.Down(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, done, 1)
+	assert.Equal(t, m.migrations[total-1].Version, done[0].Version)
+
+	done, err = m.Down(ctx, total)
+	assert.NoError(t, err)
+	assert.Len(t, done, total-1)
+	assert.False(t, db.Migrator().HasTable("clients"))
+
+	// the lock is released after every command
+	var count int64
+	db.Model(&lock{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestMigratorLock(t *testing.T) {
+	db := newSqliteDB(t)
+	defer ggorm.CloseSQLDB(db)
+	ctx := context.Background()
+
+	m, err := New(db,
+		WithOwner("replica-1"),
+		WithLockTimeout(time.Millisecond*50),
+		WithRetryInterval(time.Millisecond*10),
+		WithStaleAfter(time.Minute),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.ensureTables(ctx)
+	assert.NoError(t, err)
+
+	// another replica is migrating
+	err = db.Create(&lock{ID: lockID, Owner: "replica-2", LockedAt: time.Now()}).Error
+	assert.NoError(t, err)
+	_, err = m.Up(ctx, 0)
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.Contains(t, err.Error(), "replica-2")
+
+	// the other replica died without releasing the lock
+	err = db.Model(&lock{}).Where("id = ?", lockID).Update("locked_at", time.Now().Add(-time.Hour)).Error
+	assert.NoError(t, err)
+	done, err := m.Up(ctx, 0)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, done)
+}
+
+func Test_splitStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE a (
+  id int
+);
+
+CREATE INDEX idx ON a (id);
+DROP TABLE b`
+	statements := splitStatements(script)
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n  id int\n);",
+		"CREATE INDEX idx ON a (id);",
+		"DROP TABLE b",
+	}, statements)
+}
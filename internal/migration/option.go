@@ -0,0 +1,65 @@
+package migration
+
+import (
+	"time"
+)
+
+// Option set the migrator options.
+type Option func(*options)
+
+type options struct {
+	owner         string        // identifies the lock holder
+	lockTimeout   time.Duration // how long to wait for another process to release the lock
+	retryInterval time.Duration // interval between attempts to take the lock
+	staleAfter    time.Duration // a lock not refreshed for this long is considered abandoned
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// default settings
+func defaultOptions() *options {
+	return &options{
+		owner:         defaultOwner(),
+		lockTimeout:   time.Minute,
+		retryInterval: time.Second,
+		staleAfter:    5 * time.Minute,
+	}
+}
+
+// WithOwner set the name recorded as the lock holder, default is hostname-pid
+func WithOwner(owner string) Option {
+	return func(o *options) {
+		if owner != "" {
+			o.owner = owner
+		}
+	}
+}
+
+// WithLockTimeout set how long to wait for the lock
+func WithLockTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = d
+	}
+}
+
+// WithRetryInterval set the interval between attempts to take the lock
+func WithRetryInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.retryInterval = d
+		}
+	}
+}
+
+// WithStaleAfter set the age after which an unrefreshed lock is taken over
+func WithStaleAfter(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.staleAfter = d
+		}
+	}
+}
@@ -0,0 +1,92 @@
+CREATE TABLE IF NOT EXISTS `call_history` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `request_machine_code` varchar(32) DEFAULT NULL,
+  `client_machine_code` varchar(32) DEFAULT NULL,
+  `mobile_number` varchar(11) DEFAULT NULL,
+  `instruction` varchar(16) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_call_history_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `clients` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `machine_code` varchar(32) DEFAULT NULL,
+  `ip_address` varchar(32) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_clients_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `distribution` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `user_id` int(11) DEFAULT NULL,
+  `group_call_id` int(11) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_distribution_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `group_call` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `group_number` varchar(4) DEFAULT NULL,
+  `phone_number` varchar(11) DEFAULT NULL,
+  `transfer_client_id` varchar(32) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_group_call_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `group_client` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `group_id` int(11) NOT NULL,
+  `client_id` int(11) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_group_client_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `sms` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `machine_code` varchar(32) DEFAULT NULL,
+  `address` varchar(255) DEFAULT NULL,
+  `date` varchar(32) DEFAULT NULL,
+  `body` text,
+  `sms_type` varchar(16) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_sms_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `unanswerd_call` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `client_machine_code` varchar(32) DEFAULT NULL,
+  `mobile_number` varchar(11) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_unanswerd_call_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `user` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `machine_code` varchar(32) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
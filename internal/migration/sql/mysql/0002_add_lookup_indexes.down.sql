@@ -0,0 +1,9 @@
+DROP INDEX `idx_user_machine_code` ON `user`;
+DROP INDEX `idx_unanswerd_call_mobile_number` ON `unanswerd_call`;
+DROP INDEX `idx_unanswerd_call_client_machine_code` ON `unanswerd_call`;
+DROP INDEX `idx_sms_machine_code` ON `sms`;
+DROP INDEX `idx_group_call_group_number` ON `group_call`;
+DROP INDEX `idx_clients_machine_code` ON `clients`;
+DROP INDEX `idx_call_history_mobile_number` ON `call_history`;
+DROP INDEX `idx_call_history_client_machine_code` ON `call_history`;
+DROP INDEX `idx_call_history_request_machine_code` ON `call_history`;
@@ -0,0 +1,16 @@
+ALTER TABLE `clients` ADD COLUMN `credential_hash` varchar(64) DEFAULT NULL;
+ALTER TABLE `clients` ADD COLUMN `credential_issued_at` datetime(3) DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS `enrollment_code` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `code_hash` varchar(64) NOT NULL,
+  `machine_code` varchar(32) DEFAULT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `used_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_enrollment_code_code_hash` (`code_hash`),
+  KEY `idx_enrollment_code_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
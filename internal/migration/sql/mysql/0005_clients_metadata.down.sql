@@ -0,0 +1,12 @@
+DROP INDEX `idx_clients_battery_level` ON `clients`;
+DROP INDEX `idx_clients_app_version` ON `clients`;
+
+ALTER TABLE `clients` DROP COLUMN `metadata_updated_at`;
+ALTER TABLE `clients` DROP COLUMN `sim_slot_count`;
+ALTER TABLE `clients` DROP COLUMN `carrier`;
+ALTER TABLE `clients` DROP COLUMN `signal_strength`;
+ALTER TABLE `clients` DROP COLUMN `is_charging`;
+ALTER TABLE `clients` DROP COLUMN `battery_level`;
+ALTER TABLE `clients` DROP COLUMN `app_version`;
+ALTER TABLE `clients` DROP COLUMN `android_version`;
+ALTER TABLE `clients` DROP COLUMN `device_model`;
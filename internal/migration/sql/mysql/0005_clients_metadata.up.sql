@@ -0,0 +1,12 @@
+ALTER TABLE `clients` ADD COLUMN `device_model` varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE `clients` ADD COLUMN `android_version` varchar(16) NOT NULL DEFAULT '';
+ALTER TABLE `clients` ADD COLUMN `app_version` varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE `clients` ADD COLUMN `battery_level` int NOT NULL DEFAULT 0;
+ALTER TABLE `clients` ADD COLUMN `is_charging` tinyint(1) NOT NULL DEFAULT 0;
+ALTER TABLE `clients` ADD COLUMN `signal_strength` int NOT NULL DEFAULT 0;
+ALTER TABLE `clients` ADD COLUMN `carrier` varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE `clients` ADD COLUMN `sim_slot_count` int NOT NULL DEFAULT 0;
+ALTER TABLE `clients` ADD COLUMN `metadata_updated_at` datetime(3) DEFAULT NULL;
+
+CREATE INDEX `idx_clients_app_version` ON `clients` (`app_version`);
+CREATE INDEX `idx_clients_battery_level` ON `clients` (`battery_level`);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `client_ip_history` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `machine_code` varchar(32) NOT NULL DEFAULT '',
+  `previous_ip` varchar(64) NOT NULL DEFAULT '',
+  `ip_address` varchar(64) NOT NULL,
+  `subnet_changed` tinyint(1) NOT NULL DEFAULT 0,
+  `mass_change` tinyint(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `idx_client_ip_history_client_id` (`client_id`),
+  KEY `idx_client_ip_history_created_at` (`created_at`),
+  KEY `idx_client_ip_history_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
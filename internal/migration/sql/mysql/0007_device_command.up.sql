@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `device_command` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `machine_code` varchar(32) NOT NULL,
+  `call_history_id` bigint unsigned NOT NULL DEFAULT 0,
+  `instruction` varchar(64) NOT NULL,
+  `mobile_number` varchar(11) NOT NULL DEFAULT '',
+  `state` varchar(16) NOT NULL DEFAULT 'pending',
+  `expires_at` datetime(3) NOT NULL,
+  `delivered_at` datetime(3) DEFAULT NULL,
+  `acked_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_device_command_machine_code_state` (`machine_code`, `state`),
+  KEY `idx_device_command_call_history_id` (`call_history_id`),
+  KEY `idx_device_command_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DROP INDEX `idx_clients_release_channel` ON `clients`;
+ALTER TABLE `clients` DROP COLUMN `release_channel`;
+
+DROP TABLE IF EXISTS `release_channel`;
+DROP TABLE IF EXISTS `app_release`;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS `app_release` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `version_code` int NOT NULL,
+  `version_name` varchar(32) NOT NULL,
+  `file_name` varchar(128) NOT NULL,
+  `file_size` bigint NOT NULL DEFAULT 0,
+  `checksum` varchar(64) NOT NULL,
+  `release_notes` varchar(1024) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_app_release_version_code` (`version_code`),
+  KEY `idx_app_release_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `release_channel` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `name` varchar(32) NOT NULL,
+  `release_id` bigint unsigned NOT NULL DEFAULT 0,
+  `previous_release_id` bigint unsigned NOT NULL DEFAULT 0,
+  `rollout_percent` int NOT NULL DEFAULT 100,
+  `client_percent` int NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_release_channel_name` (`name`),
+  KEY `idx_release_channel_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `clients` ADD COLUMN `release_channel` varchar(32) NOT NULL DEFAULT '';
+CREATE INDEX `idx_clients_release_channel` ON `clients` (`release_channel`);
@@ -0,0 +1,6 @@
+DROP INDEX `idx_clients_status` ON `clients`;
+
+ALTER TABLE `clients` DROP COLUMN `status_updated_at`;
+ALTER TABLE `clients` DROP COLUMN `status_operator`;
+ALTER TABLE `clients` DROP COLUMN `status_reason`;
+ALTER TABLE `clients` DROP COLUMN `status`;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `resource_label` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `resource` varchar(32) NOT NULL,
+  `resource_id` bigint unsigned NOT NULL,
+  `label_key` varchar(63) NOT NULL,
+  `label_value` varchar(63) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_resource_label_resource_key` (`resource`, `resource_id`, `label_key`),
+  KEY `idx_resource_label_key_value` (`resource`, `label_key`, `label_value`),
+  KEY `idx_resource_label_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,6 @@
+DROP INDEX `idx_sms_sim_id` ON `sms`;
+ALTER TABLE `sms` DROP COLUMN `sim_id`;
+DROP INDEX `idx_call_history_sim_id` ON `call_history`;
+ALTER TABLE `call_history` DROP COLUMN `sim_id`;
+
+DROP TABLE IF EXISTS `sim`;
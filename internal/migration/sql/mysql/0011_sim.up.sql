@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS `sim` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `iccid` varchar(22) NOT NULL,
+  `phone_number` varchar(20) NOT NULL DEFAULT '',
+  `carrier` varchar(64) NOT NULL DEFAULT '',
+  `client_id` bigint unsigned NOT NULL DEFAULT 0,
+  `slot_index` int NOT NULL DEFAULT 0,
+  `status` varchar(16) NOT NULL DEFAULT 'active',
+  `expires_at` datetime(3) DEFAULT NULL,
+  `version` bigint(20) unsigned NOT NULL DEFAULT 1,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_sim_iccid` (`iccid`),
+  KEY `idx_sim_client_id` (`client_id`, `slot_index`),
+  KEY `idx_sim_phone_number` (`phone_number`),
+  KEY `idx_sim_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `call_history` ADD COLUMN `sim_id` bigint unsigned NOT NULL DEFAULT 0;
+CREATE INDEX `idx_call_history_sim_id` ON `call_history` (`sim_id`);
+ALTER TABLE `sms` ADD COLUMN `sim_id` bigint unsigned NOT NULL DEFAULT 0;
+CREATE INDEX `idx_sms_sim_id` ON `sms` (`sim_id`);
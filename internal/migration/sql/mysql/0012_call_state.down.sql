@@ -0,0 +1,10 @@
+DROP INDEX `idx_call_history_state` ON `call_history`;
+
+ALTER TABLE `call_history` DROP COLUMN `failure_reason`;
+ALTER TABLE `call_history` DROP COLUMN `talk_duration`;
+ALTER TABLE `call_history` DROP COLUMN `ended_at`;
+ALTER TABLE `call_history` DROP COLUMN `answered_at`;
+ALTER TABLE `call_history` DROP COLUMN `ringing_at`;
+ALTER TABLE `call_history` DROP COLUMN `dispatched_at`;
+ALTER TABLE `call_history` DROP COLUMN `state_updated_at`;
+ALTER TABLE `call_history` DROP COLUMN `state`;
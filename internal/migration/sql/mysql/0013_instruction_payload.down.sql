@@ -0,0 +1,2 @@
+ALTER TABLE `device_command` DROP COLUMN `payload`;
+ALTER TABLE `call_history` DROP COLUMN `payload`;
@@ -0,0 +1,2 @@
+ALTER TABLE `call_history` ADD COLUMN `payload` varchar(1024) NOT NULL DEFAULT '';
+ALTER TABLE `device_command` ADD COLUMN `payload` varchar(1024) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX `idx_call_history_group_call_id` ON `call_history`;
+
+ALTER TABLE `call_history` DROP COLUMN `group_call_id`;
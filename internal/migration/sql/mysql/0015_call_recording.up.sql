@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `call_recording` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  `call_history_id` bigint unsigned NOT NULL,
+  `file_key` varchar(128) NOT NULL,
+  `codec` varchar(32) NOT NULL,
+  `duration` int NOT NULL DEFAULT 0,
+  `size` bigint NOT NULL DEFAULT 0,
+  `checksum` varchar(64) NOT NULL,
+  `status` varchar(16) NOT NULL DEFAULT 'uploading',
+  `uploaded_at` datetime(3) DEFAULT NULL,
+  `version` bigint(20) unsigned NOT NULL DEFAULT 1,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_call_recording_call_history_id` (`call_history_id`),
+  KEY `idx_call_recording_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
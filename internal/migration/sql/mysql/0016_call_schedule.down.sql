@@ -0,0 +1,3 @@
+DROP INDEX `idx_call_history_state_scheduled_at` ON `call_history`;
+
+ALTER TABLE `call_history` DROP COLUMN `scheduled_at`;
@@ -0,0 +1,3 @@
+DROP INDEX `idx_clients_app_version_code` ON `clients`;
+
+ALTER TABLE `clients` DROP COLUMN `app_version_code`;
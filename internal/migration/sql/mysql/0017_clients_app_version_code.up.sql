@@ -0,0 +1,3 @@
+ALTER TABLE `clients` ADD COLUMN `app_version_code` int NOT NULL DEFAULT 0;
+
+CREATE INDEX `idx_clients_app_version_code` ON `clients` (`app_version_code`);
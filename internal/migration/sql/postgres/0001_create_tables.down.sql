@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS "unanswerd_call";
+DROP TABLE IF EXISTS "sms";
+DROP TABLE IF EXISTS "group_client";
+DROP TABLE IF EXISTS "group_call";
+DROP TABLE IF EXISTS "distribution";
+DROP TABLE IF EXISTS "clients";
+DROP TABLE IF EXISTS "call_history";
@@ -0,0 +1,84 @@
+CREATE TABLE IF NOT EXISTS "call_history" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "request_machine_code" varchar(32),
+  "client_machine_code" varchar(32),
+  "mobile_number" varchar(11),
+  "instruction" varchar(16)
+);
+CREATE INDEX IF NOT EXISTS "idx_call_history_deleted_at" ON "call_history" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "clients" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "machine_code" varchar(32),
+  "ip_address" varchar(32)
+);
+CREATE INDEX IF NOT EXISTS "idx_clients_deleted_at" ON "clients" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "distribution" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "user_id" integer,
+  "group_call_id" integer
+);
+CREATE INDEX IF NOT EXISTS "idx_distribution_deleted_at" ON "distribution" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "group_call" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "group_number" varchar(4),
+  "phone_number" varchar(11),
+  "transfer_client_id" varchar(32)
+);
+CREATE INDEX IF NOT EXISTS "idx_group_call_deleted_at" ON "group_call" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "group_client" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "group_id" integer NOT NULL,
+  "client_id" integer NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_group_client_deleted_at" ON "group_client" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "sms" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "machine_code" varchar(32),
+  "address" varchar(255),
+  "date" varchar(32),
+  "body" text,
+  "sms_type" varchar(16)
+);
+CREATE INDEX IF NOT EXISTS "idx_sms_deleted_at" ON "sms" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "unanswerd_call" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "client_machine_code" varchar(32),
+  "mobile_number" varchar(11)
+);
+CREATE INDEX IF NOT EXISTS "idx_unanswerd_call_deleted_at" ON "unanswerd_call" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "user" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "machine_code" varchar(32)
+);
+CREATE INDEX IF NOT EXISTS "idx_user_deleted_at" ON "user" ("deleted_at");
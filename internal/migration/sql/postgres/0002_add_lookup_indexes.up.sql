@@ -0,0 +1,9 @@
+CREATE INDEX IF NOT EXISTS "idx_call_history_request_machine_code" ON "call_history" ("request_machine_code");
+CREATE INDEX IF NOT EXISTS "idx_call_history_client_machine_code" ON "call_history" ("client_machine_code");
+CREATE INDEX IF NOT EXISTS "idx_call_history_mobile_number" ON "call_history" ("mobile_number");
+CREATE INDEX IF NOT EXISTS "idx_clients_machine_code" ON "clients" ("machine_code");
+CREATE INDEX IF NOT EXISTS "idx_group_call_group_number" ON "group_call" ("group_number");
+CREATE INDEX IF NOT EXISTS "idx_sms_machine_code" ON "sms" ("machine_code");
+CREATE INDEX IF NOT EXISTS "idx_unanswerd_call_client_machine_code" ON "unanswerd_call" ("client_machine_code");
+CREATE INDEX IF NOT EXISTS "idx_unanswerd_call_mobile_number" ON "unanswerd_call" ("mobile_number");
+CREATE INDEX IF NOT EXISTS "idx_user_machine_code" ON "user" ("machine_code");
@@ -0,0 +1,8 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "unanswerd_call" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "sms" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "group_client" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "group_call" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "distribution" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "call_history" DROP COLUMN IF EXISTS "version";
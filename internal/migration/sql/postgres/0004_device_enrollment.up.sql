@@ -0,0 +1,15 @@
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "credential_hash" varchar(64);
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "credential_issued_at" timestamptz;
+
+CREATE TABLE IF NOT EXISTS "enrollment_code" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "code_hash" varchar(64) NOT NULL,
+  "machine_code" varchar(32),
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_enrollment_code_code_hash" ON "enrollment_code" ("code_hash");
+CREATE INDEX IF NOT EXISTS "idx_enrollment_code_deleted_at" ON "enrollment_code" ("deleted_at");
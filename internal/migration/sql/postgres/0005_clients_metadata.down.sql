@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS "idx_clients_battery_level";
+DROP INDEX IF EXISTS "idx_clients_app_version";
+
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "metadata_updated_at";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "sim_slot_count";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "carrier";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "signal_strength";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "is_charging";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "battery_level";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "app_version";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "android_version";
+ALTER TABLE "clients" DROP COLUMN IF EXISTS "device_model";
@@ -0,0 +1,12 @@
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "device_model" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "android_version" varchar(16) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "app_version" varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "battery_level" integer NOT NULL DEFAULT 0;
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "is_charging" boolean NOT NULL DEFAULT false;
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "signal_strength" integer NOT NULL DEFAULT 0;
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "carrier" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "sim_slot_count" integer NOT NULL DEFAULT 0;
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "metadata_updated_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "idx_clients_app_version" ON "clients" ("app_version");
+CREATE INDEX IF NOT EXISTS "idx_clients_battery_level" ON "clients" ("battery_level");
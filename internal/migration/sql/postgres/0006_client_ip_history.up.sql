@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "client_ip_history" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "client_id" bigint NOT NULL,
+  "machine_code" varchar(32) NOT NULL DEFAULT '',
+  "previous_ip" varchar(64) NOT NULL DEFAULT '',
+  "ip_address" varchar(64) NOT NULL,
+  "subnet_changed" boolean NOT NULL DEFAULT false,
+  "mass_change" boolean NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS "idx_client_ip_history_client_id" ON "client_ip_history" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_client_ip_history_created_at" ON "client_ip_history" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_client_ip_history_deleted_at" ON "client_ip_history" ("deleted_at");
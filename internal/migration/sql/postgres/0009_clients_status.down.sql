@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "idx_clients_status";
+
+ALTER TABLE "clients" DROP COLUMN "status_updated_at";
+ALTER TABLE "clients" DROP COLUMN "status_operator";
+ALTER TABLE "clients" DROP COLUMN "status_reason";
+ALTER TABLE "clients" DROP COLUMN "status";
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "resource_label" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "resource" varchar(32) NOT NULL,
+  "resource_id" bigint NOT NULL,
+  "label_key" varchar(63) NOT NULL,
+  "label_value" varchar(63) NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_resource_label_resource_key" ON "resource_label" ("resource", "resource_id", "label_key");
+CREATE INDEX IF NOT EXISTS "idx_resource_label_key_value" ON "resource_label" ("resource", "label_key", "label_value");
+CREATE INDEX IF NOT EXISTS "idx_resource_label_deleted_at" ON "resource_label" ("deleted_at");
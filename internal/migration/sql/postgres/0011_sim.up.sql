@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS "sim" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "iccid" varchar(22) NOT NULL,
+  "phone_number" varchar(20) NOT NULL DEFAULT '',
+  "carrier" varchar(64) NOT NULL DEFAULT '',
+  "client_id" bigint NOT NULL DEFAULT 0,
+  "slot_index" integer NOT NULL DEFAULT 0,
+  "status" varchar(16) NOT NULL DEFAULT 'active',
+  "expires_at" timestamptz,
+  "version" bigint NOT NULL DEFAULT 1
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sim_iccid" ON "sim" ("iccid");
+CREATE INDEX IF NOT EXISTS "idx_sim_client_id" ON "sim" ("client_id", "slot_index");
+CREATE INDEX IF NOT EXISTS "idx_sim_phone_number" ON "sim" ("phone_number");
+CREATE INDEX IF NOT EXISTS "idx_sim_deleted_at" ON "sim" ("deleted_at");
+
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "sim_id" bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_call_history_sim_id" ON "call_history" ("sim_id");
+ALTER TABLE "sms" ADD COLUMN IF NOT EXISTS "sim_id" bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_sms_sim_id" ON "sms" ("sim_id");
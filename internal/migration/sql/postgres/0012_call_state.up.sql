@@ -0,0 +1,10 @@
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "state" varchar(16) NOT NULL DEFAULT 'requested';
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "state_updated_at" timestamptz;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "dispatched_at" timestamptz;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "ringing_at" timestamptz;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "answered_at" timestamptz;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "ended_at" timestamptz;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "talk_duration" integer NOT NULL DEFAULT 0;
+ALTER TABLE "call_history" ADD COLUMN IF NOT EXISTS "failure_reason" varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "idx_call_history_state" ON "call_history" ("state");
@@ -0,0 +1,3 @@
+ALTER TABLE "clients" ADD COLUMN IF NOT EXISTS "app_version_code" integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS "idx_clients_app_version_code" ON "clients" ("app_version_code");
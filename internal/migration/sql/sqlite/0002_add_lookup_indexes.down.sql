@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "idx_user_machine_code";
+DROP INDEX IF EXISTS "idx_unanswerd_call_mobile_number";
+DROP INDEX IF EXISTS "idx_unanswerd_call_client_machine_code";
+DROP INDEX IF EXISTS "idx_sms_machine_code";
+DROP INDEX IF EXISTS "idx_group_call_group_number";
+DROP INDEX IF EXISTS "idx_clients_machine_code";
+DROP INDEX IF EXISTS "idx_call_history_mobile_number";
+DROP INDEX IF EXISTS "idx_call_history_client_machine_code";
+DROP INDEX IF EXISTS "idx_call_history_request_machine_code";
@@ -0,0 +1,8 @@
+ALTER TABLE "user" DROP COLUMN "version";
+ALTER TABLE "unanswerd_call" DROP COLUMN "version";
+ALTER TABLE "sms" DROP COLUMN "version";
+ALTER TABLE "group_client" DROP COLUMN "version";
+ALTER TABLE "group_call" DROP COLUMN "version";
+ALTER TABLE "distribution" DROP COLUMN "version";
+ALTER TABLE "clients" DROP COLUMN "version";
+ALTER TABLE "call_history" DROP COLUMN "version";
@@ -0,0 +1,8 @@
+ALTER TABLE "call_history" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "clients" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "distribution" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "group_call" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "group_client" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "sms" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "unanswerd_call" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "user" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
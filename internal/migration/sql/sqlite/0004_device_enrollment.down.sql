@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "enrollment_code";
+
+ALTER TABLE "clients" DROP COLUMN "credential_issued_at";
+ALTER TABLE "clients" DROP COLUMN "credential_hash";
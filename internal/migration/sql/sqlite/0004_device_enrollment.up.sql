@@ -0,0 +1,15 @@
+ALTER TABLE "clients" ADD COLUMN "credential_hash" varchar(64);
+ALTER TABLE "clients" ADD COLUMN "credential_issued_at" datetime;
+
+CREATE TABLE IF NOT EXISTS "enrollment_code" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "code_hash" varchar(64) NOT NULL,
+  "machine_code" varchar(32),
+  "expires_at" datetime NOT NULL,
+  "used_at" datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_enrollment_code_code_hash" ON "enrollment_code" ("code_hash");
+CREATE INDEX IF NOT EXISTS "idx_enrollment_code_deleted_at" ON "enrollment_code" ("deleted_at");
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "device_command" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "machine_code" varchar(32) NOT NULL,
+  "call_history_id" integer NOT NULL DEFAULT 0,
+  "instruction" varchar(64) NOT NULL,
+  "mobile_number" varchar(11) NOT NULL DEFAULT '',
+  "state" varchar(16) NOT NULL DEFAULT 'pending',
+  "expires_at" datetime NOT NULL,
+  "delivered_at" datetime,
+  "acked_at" datetime
+);
+CREATE INDEX IF NOT EXISTS "idx_device_command_machine_code_state" ON "device_command" ("machine_code", "state");
+CREATE INDEX IF NOT EXISTS "idx_device_command_call_history_id" ON "device_command" ("call_history_id");
+CREATE INDEX IF NOT EXISTS "idx_device_command_deleted_at" ON "device_command" ("deleted_at");
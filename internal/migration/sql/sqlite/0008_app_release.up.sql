@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS "app_release" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "version_code" integer NOT NULL,
+  "version_name" varchar(32) NOT NULL,
+  "file_name" varchar(128) NOT NULL,
+  "file_size" bigint NOT NULL DEFAULT 0,
+  "checksum" varchar(64) NOT NULL,
+  "release_notes" varchar(1024) NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_app_release_version_code" ON "app_release" ("version_code");
+CREATE INDEX IF NOT EXISTS "idx_app_release_deleted_at" ON "app_release" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "release_channel" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "name" varchar(32) NOT NULL,
+  "release_id" integer NOT NULL DEFAULT 0,
+  "previous_release_id" integer NOT NULL DEFAULT 0,
+  "rollout_percent" integer NOT NULL DEFAULT 100,
+  "client_percent" integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_release_channel_name" ON "release_channel" ("name");
+CREATE INDEX IF NOT EXISTS "idx_release_channel_deleted_at" ON "release_channel" ("deleted_at");
+
+ALTER TABLE "clients" ADD COLUMN "release_channel" varchar(32) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "idx_clients_release_channel" ON "clients" ("release_channel");
@@ -0,0 +1,6 @@
+ALTER TABLE "clients" ADD COLUMN "status" varchar(16) NOT NULL DEFAULT 'active';
+ALTER TABLE "clients" ADD COLUMN "status_reason" varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN "status_operator" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "clients" ADD COLUMN "status_updated_at" datetime;
+
+CREATE INDEX IF NOT EXISTS "idx_clients_status" ON "clients" ("status");
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "idx_sms_sim_id";
+ALTER TABLE "sms" DROP COLUMN "sim_id";
+DROP INDEX IF EXISTS "idx_call_history_sim_id";
+ALTER TABLE "call_history" DROP COLUMN "sim_id";
+
+DROP TABLE IF EXISTS "sim";
@@ -0,0 +1,10 @@
+ALTER TABLE "call_history" ADD COLUMN "state" varchar(16) NOT NULL DEFAULT 'requested';
+ALTER TABLE "call_history" ADD COLUMN "state_updated_at" datetime;
+ALTER TABLE "call_history" ADD COLUMN "dispatched_at" datetime;
+ALTER TABLE "call_history" ADD COLUMN "ringing_at" datetime;
+ALTER TABLE "call_history" ADD COLUMN "answered_at" datetime;
+ALTER TABLE "call_history" ADD COLUMN "ended_at" datetime;
+ALTER TABLE "call_history" ADD COLUMN "talk_duration" integer NOT NULL DEFAULT 0;
+ALTER TABLE "call_history" ADD COLUMN "failure_reason" varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "idx_call_history_state" ON "call_history" ("state");
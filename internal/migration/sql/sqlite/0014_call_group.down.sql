@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_call_history_group_call_id";
+
+ALTER TABLE "call_history" DROP COLUMN "group_call_id";
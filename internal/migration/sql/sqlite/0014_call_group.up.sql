@@ -0,0 +1,3 @@
+ALTER TABLE "call_history" ADD COLUMN "group_call_id" integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS "idx_call_history_group_call_id" ON "call_history" ("group_call_id");
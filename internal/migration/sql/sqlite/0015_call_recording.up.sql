@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "call_recording" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "call_history_id" integer NOT NULL,
+  "file_key" varchar(128) NOT NULL,
+  "codec" varchar(32) NOT NULL,
+  "duration" integer NOT NULL DEFAULT 0,
+  "size" bigint NOT NULL DEFAULT 0,
+  "checksum" varchar(64) NOT NULL,
+  "status" varchar(16) NOT NULL DEFAULT 'uploading',
+  "uploaded_at" datetime,
+  "version" bigint NOT NULL DEFAULT 1
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_call_recording_call_history_id" ON "call_recording" ("call_history_id");
+CREATE INDEX IF NOT EXISTS "idx_call_recording_deleted_at" ON "call_recording" ("deleted_at");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_call_history_state_scheduled_at";
+
+ALTER TABLE "call_history" DROP COLUMN "scheduled_at";
@@ -0,0 +1,3 @@
+ALTER TABLE "call_history" ADD COLUMN "scheduled_at" datetime;
+
+CREATE INDEX IF NOT EXISTS "idx_call_history_state_scheduled_at" ON "call_history" ("state", "scheduled_at");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_clients_app_version_code";
+
+ALTER TABLE "clients" DROP COLUMN "app_version_code";
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// DefaultReleaseChannel the channel of the clients that are neither assigned to a channel nor in the client percent of one
+const DefaultReleaseChannel = "stable"
+
+// AppRelease an uploaded apk of the caller app
+type AppRelease struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	VersionCode  int    `gorm:"column:version_code;type:int;NOT NULL" json:"versionCode"` // versionCode of the apk, a higher one is newer
+	VersionName  string `gorm:"column:version_name;type:varchar(32);NOT NULL" json:"versionName"`
+	FileName     string `gorm:"column:file_name;type:varchar(128);NOT NULL" json:"fileName"` // name of the apk in the storage directory
+	FileSize     int64  `gorm:"column:file_size;type:bigint(20);NOT NULL;default:0" json:"fileSize"`
+	Checksum     string `gorm:"column:checksum;type:varchar(64);NOT NULL" json:"checksum"` // hex sha256 of the apk
+	ReleaseNotes string `gorm:"column:release_notes;type:varchar(1024);NOT NULL;default:''" json:"releaseNotes"`
+}
+
+// TableName table name
+func (m *AppRelease) TableName() string {
+	return "app_release"
+}
+
+// ReleaseChannel a channel of the releases such as stable or beta, the clients of the channel are offered its release
+type ReleaseChannel struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	Name              string `gorm:"column:name;type:varchar(32);NOT NULL" json:"name"`
+	ReleaseID         uint64 `gorm:"column:release_id;type:bigint(20);NOT NULL;default:0" json:"releaseId"`                  // 0 if the channel has no release
+	PreviousReleaseID uint64 `gorm:"column:previous_release_id;type:bigint(20);NOT NULL;default:0" json:"previousReleaseId"` // offered to the clients outside the rollout
+	RolloutPercent    int    `gorm:"column:rollout_percent;type:int;NOT NULL;default:100" json:"rolloutPercent"`             // percentage of the clients of the channel that are offered the release
+	ClientPercent     int    `gorm:"column:client_percent;type:int;NOT NULL;default:0" json:"clientPercent"`                 // percentage of the unassigned clients that are in the channel
+}
+
+// TableName table name
+func (m *ReleaseChannel) TableName() string {
+	return "release_channel"
+}
@@ -1,16 +1,71 @@
 package model
 
 import (
+	"time"
+
 	"github.com/zhufuyi/sponge/pkg/ggorm"
 )
 
+// the states of a call, a call is requested when the call history is created and dispatched when its command
+// is delivered to the client device, the device reports the other states. ended, failed and busy are final.
+// a call created with a time in the future is scheduled until the scheduler requests it at that time,
+// a scheduled call can be cancelled, cancelled is final.
+const (
+	CallStateScheduled  = "scheduled"
+	CallStateCancelled  = "cancelled"
+	CallStateRequested  = "requested"
+	CallStateDispatched = "dispatched"
+	CallStateRinging    = "ringing"
+	CallStateAnswered   = "answered"
+	CallStateEnded      = "ended"
+	CallStateFailed     = "failed"
+	CallStateBusy       = "busy"
+)
+
+// callStateTransitions the states a call can move to from each state
+var callStateTransitions = map[string][]string{
+	CallStateScheduled:  {CallStateRequested, CallStateCancelled},
+	CallStateRequested:  {CallStateDispatched, CallStateFailed},
+	CallStateDispatched: {CallStateRinging, CallStateAnswered, CallStateEnded, CallStateFailed, CallStateBusy},
+	CallStateRinging:    {CallStateAnswered, CallStateEnded, CallStateFailed, CallStateBusy},
+	CallStateAnswered:   {CallStateEnded, CallStateFailed},
+}
+
+// CanTransitCallState check if a call in the state from can move to the state to
+func CanTransitCallState(from string, to string) bool {
+	for _, state := range callStateTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinalCallState check if a call in the state cannot move to another state
+func IsFinalCallState(state string) bool {
+	return state == CallStateEnded || state == CallStateFailed || state == CallStateBusy || state == CallStateCancelled
+}
+
 type CallHistory struct {
 	ggorm.Model `gorm:"embedded"` // embed id and time
 
-	RequestMachineCode string `gorm:"column:request_machine_code;type:varchar(32)" json:"requestMachineCode"`
-	ClientMachineCode  string `gorm:"column:client_machine_code;type:varchar(32)" json:"clientMachineCode"`
-	MobileNumber       string `gorm:"column:mobile_number;type:varchar(11)" json:"mobileNumber"`
-	Instruction        string `gorm:"column:instruction;type:varchar(16)" json:"instruction"`
+	RequestMachineCode string     `gorm:"column:request_machine_code;type:varchar(32)" json:"requestMachineCode"`
+	ClientMachineCode  string     `gorm:"column:client_machine_code;type:varchar(32)" json:"clientMachineCode"`
+	MobileNumber       string     `gorm:"column:mobile_number;type:varchar(11)" json:"mobileNumber"`
+	Instruction        string     `gorm:"column:instruction;type:varchar(16)" json:"instruction"`
+	Payload            string     `gorm:"column:payload;type:varchar(1024);NOT NULL;default:''" json:"payload"`       // json of the payload of the instruction, empty if it has none
+	SimID              uint64     `gorm:"column:sim_id;type:bigint(20);NOT NULL;default:0" json:"simId"`              // the sim the client device called with, 0 if unknown
+	GroupCallID        uint64     `gorm:"column:group_call_id;type:bigint(20);NOT NULL;default:0" json:"groupCallId"` // the group the client was chosen from, 0 if the call was not dialed by group
+	State              string     `gorm:"column:state;type:varchar(16);NOT NULL;default:requested" json:"state"`
+	ScheduledAt        *time.Time `gorm:"column:scheduled_at;type:datetime" json:"scheduledAt"` // time the instruction is sent to the client device, nil if it is sent when the call is created
+	StateUpdatedAt     *time.Time `gorm:"column:state_updated_at;type:datetime" json:"stateUpdatedAt"`
+	DispatchedAt       *time.Time `gorm:"column:dispatched_at;type:datetime" json:"dispatchedAt"` // time the command of the call was delivered to the client device
+	RingingAt          *time.Time `gorm:"column:ringing_at;type:datetime" json:"ringingAt"`
+	AnsweredAt         *time.Time `gorm:"column:answered_at;type:datetime" json:"answeredAt"`
+	EndedAt            *time.Time `gorm:"column:ended_at;type:datetime" json:"endedAt"`                         // time the call reached a final state
+	TalkDuration       int        `gorm:"column:talk_duration;type:int;NOT NULL;default:0" json:"talkDuration"` // seconds from answered to ended
+	FailureReason      string     `gorm:"column:failure_reason;type:varchar(255);NOT NULL;default:''" json:"failureReason"`
+	Version            uint64     `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
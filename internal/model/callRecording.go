@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// the statuses of a call recording
+const (
+	CallRecordingStatusUploading = "uploading" // the device is uploading the audio, it cannot be downloaded yet
+	CallRecordingStatusReady     = "ready"     // the audio is uploaded and matches its checksum
+)
+
+// CallRecording the audio of a call recorded by the client device, a call has at most one recording
+type CallRecording struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	CallHistoryID uint64     `gorm:"column:call_history_id;type:bigint(20);NOT NULL" json:"callHistoryId"`
+	FileKey       string     `gorm:"column:file_key;type:varchar(128);NOT NULL" json:"fileKey"`   // key of the audio in the storage
+	Codec         string     `gorm:"column:codec;type:varchar(32);NOT NULL" json:"codec"`         // codec of the audio, e.g. amr, aac, opus
+	Duration      int        `gorm:"column:duration;type:int;NOT NULL;default:0" json:"duration"` // milliseconds of audio
+	Size          int64      `gorm:"column:size;type:bigint(20);NOT NULL;default:0" json:"size"`  // bytes of the audio
+	Checksum      string     `gorm:"column:checksum;type:varchar(64);NOT NULL" json:"checksum"`   // hex sha256 of the audio
+	Status        string     `gorm:"column:status;type:varchar(16);NOT NULL;default:uploading" json:"status"`
+	UploadedAt    *time.Time `gorm:"column:uploaded_at;type:datetime" json:"uploadedAt"`               // nil until the upload is complete
+	Version       uint64     `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
+}
+
+// TableName table name
+func (m *CallRecording) TableName() string {
+	return "call_recording"
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// ClientIPHistory a change of the ip address of a client
+type ClientIPHistory struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	ClientID      uint64 `gorm:"column:client_id;type:bigint(20);NOT NULL" json:"clientId"`
+	MachineCode   string `gorm:"column:machine_code;type:varchar(32);NOT NULL" json:"machineCode"`
+	PreviousIP    string `gorm:"column:previous_ip;type:varchar(64);NOT NULL" json:"previousIp"` // empty if the client had no ip address
+	IPAddress     string `gorm:"column:ip_address;type:varchar(64);NOT NULL" json:"ipAddress"`
+	SubnetChanged bool   `gorm:"column:subnet_changed;type:tinyint(1);NOT NULL;default:0" json:"subnetChanged"` // the ip address moved to another /24 (ipv4) or /64 (ipv6) network
+	MassChange    bool   `gorm:"column:mass_change;type:tinyint(1);NOT NULL;default:0" json:"massChange"`       // many clients changed their ip address at the same time
+}
+
+// TableName table name
+func (m *ClientIPHistory) TableName() string {
+	return "client_ip_history"
+}
@@ -1,12 +1,62 @@
 package model
 
 import (
+	"errors"
+	"time"
+
 	"github.com/zhufuyi/sponge/pkg/ggorm"
 )
 
+// the statuses of a client, only an active client is routed calls and can call the device apis
+const (
+	ClientsStatusActive      = "active"
+	ClientsStatusDisabled    = "disabled"
+	ClientsStatusQuarantined = "quarantined"
+)
+
+var (
+	// ErrClientsDisabled the client is disabled, no instruction is queued for it
+	ErrClientsDisabled = errors.New("client is disabled")
+	// ErrClientsQuarantined the client is quarantined, no instruction is queued for it
+	ErrClientsQuarantined = errors.New("client is quarantined")
+)
+
 type Clients struct {
 	ggorm.Model `gorm:"embedded"` // embed id and time
 
 	MachineCode string `gorm:"column:machine_code;type:varchar(32)" json:"machineCode"`
 	IPAddress   string `gorm:"column:ip_address;type:varchar(32)" json:"ipAddress"`
+	Version     uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
+
+	CredentialHash     string     `gorm:"column:credential_hash;type:varchar(64)" json:"credentialHash"` // sha256 of the secret of the device credential, empty if none is issued or it is revoked
+	CredentialIssuedAt *time.Time `gorm:"column:credential_issued_at;type:datetime" json:"credentialIssuedAt"`
+
+	// metadata reported by the device, 0 or empty means unknown
+	DeviceModel       string     `gorm:"column:device_model;type:varchar(64);NOT NULL;default:''" json:"deviceModel"`
+	AndroidVersion    string     `gorm:"column:android_version;type:varchar(16);NOT NULL;default:''" json:"androidVersion"`
+	AppVersion        string     `gorm:"column:app_version;type:varchar(32);NOT NULL;default:''" json:"appVersion"`
+	AppVersionCode    int        `gorm:"column:app_version_code;type:int;NOT NULL;default:0" json:"appVersionCode"` // versionCode of the app, unlike app_version it is ordered
+	BatteryLevel      int        `gorm:"column:battery_level;type:int;NOT NULL;default:0" json:"batteryLevel"`      // percentage
+	IsCharging        bool       `gorm:"column:is_charging;type:tinyint(1);NOT NULL;default:0" json:"isCharging"`
+	SignalStrength    int        `gorm:"column:signal_strength;type:int;NOT NULL;default:0" json:"signalStrength"` // dBm
+	Carrier           string     `gorm:"column:carrier;type:varchar(64);NOT NULL;default:''" json:"carrier"`
+	SimSlotCount      int        `gorm:"column:sim_slot_count;type:int;NOT NULL;default:0" json:"simSlotCount"`
+	MetadataUpdatedAt *time.Time `gorm:"column:metadata_updated_at;type:datetime" json:"metadataUpdatedAt"`
+
+	ReleaseChannel string `gorm:"column:release_channel;type:varchar(32);NOT NULL;default:''" json:"releaseChannel"` // the release channel the client is assigned to, empty if it is not assigned
+
+	Status          string     `gorm:"column:status;type:varchar(16);NOT NULL;default:active" json:"status"` // active, disabled or quarantined
+	StatusReason    string     `gorm:"column:status_reason;type:varchar(255);NOT NULL;default:''" json:"statusReason"`
+	StatusOperator  string     `gorm:"column:status_operator;type:varchar(64);NOT NULL;default:''" json:"statusOperator"` // the operator who set the status
+	StatusUpdatedAt *time.Time `gorm:"column:status_updated_at;type:datetime" json:"statusUpdatedAt"`
+}
+
+// TableName table name
+func (m *Clients) TableName() string {
+	return "clients"
+}
+
+// IsActive whether the client is active, a client created before it had a status is active
+func (m *Clients) IsActive() bool {
+	return m.Status == "" || m.Status == ClientsStatusActive
 }
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// the states of a device command, a command goes from pending to delivered to acked,
+// a pending command that is not delivered before it expires is expired.
+const (
+	DeviceCommandPending   = "pending"
+	DeviceCommandDelivered = "delivered"
+	DeviceCommandAcked     = "acked"
+	DeviceCommandExpired   = "expired"
+)
+
+// DeviceCommandTTL the default time to live of the commands queued for the instructions of call history
+var DeviceCommandTTL = 10 * time.Minute
+
+// DeviceCommand a command queued for a device, it is delivered to the device only once
+type DeviceCommand struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	MachineCode   string     `gorm:"column:machine_code;type:varchar(32);NOT NULL" json:"machineCode"`               // the device that receives the command
+	CallHistoryID uint64     `gorm:"column:call_history_id;type:bigint(20);NOT NULL;default:0" json:"callHistoryId"` // 0 if the command was not queued for a call history
+	Instruction   string     `gorm:"column:instruction;type:varchar(64);NOT NULL" json:"instruction"`
+	Payload       string     `gorm:"column:payload;type:varchar(1024);NOT NULL;default:''" json:"payload"` // json of the payload of the instruction, empty if it has none
+	MobileNumber  string     `gorm:"column:mobile_number;type:varchar(11);NOT NULL" json:"mobileNumber"`
+	State         string     `gorm:"column:state;type:varchar(16);NOT NULL;default:pending" json:"state"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at;type:datetime;NOT NULL" json:"expiresAt"` // a command that is still pending at this time is not delivered
+	DeliveredAt   *time.Time `gorm:"column:delivered_at;type:datetime" json:"deliveredAt"`
+	AckedAt       *time.Time `gorm:"column:acked_at;type:datetime" json:"ackedAt"`
+}
+
+// TableName table name
+func (m *DeviceCommand) TableName() string {
+	return "device_command"
+}
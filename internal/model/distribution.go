@@ -7,8 +7,9 @@ import (
 type Distribution struct {
 	ggorm.Model `gorm:"embedded"` // embed id and time
 
-	UserID      int `gorm:"column:user_id;type:int(11)" json:"userId"`
-	GroupCallID int `gorm:"column:group_call_id;type:int(11)" json:"groupCallId"`
+	UserID      int    `gorm:"column:user_id;type:int(11)" json:"userId"`
+	GroupCallID int    `gorm:"column:group_call_id;type:int(11)" json:"groupCallId"`
+	Version     uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
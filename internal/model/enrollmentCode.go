@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// EnrollmentCode a one-time code issued by an admin that a device exchanges for its credential
+type EnrollmentCode struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	CodeHash    string     `gorm:"column:code_hash;type:varchar(64);NOT NULL" json:"codeHash"` // sha256 of the code, the code itself is not stored
+	MachineCode string     `gorm:"column:machine_code;type:varchar(32)" json:"machineCode"`    // if set when issued only this device can use the code, set to the enrolled device when used
+	ExpiresAt   time.Time  `gorm:"column:expires_at;type:datetime;NOT NULL" json:"expiresAt"`
+	UsedAt      *time.Time `gorm:"column:used_at;type:datetime" json:"usedAt"` // empty until the code is used
+}
+
+// TableName table name
+func (m *EnrollmentCode) TableName() string {
+	return "enrollment_code"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"reflect"
+)
+
+// GetID get the id of a table struct that embeds ggorm.Model, return 0 if there is no id field
+func GetID(table interface{}) uint64 {
+	return getUint64Field(table, "ID")
+}
+
+// GetVersion get the version of a table struct, return 0 if there is no version field
+func GetVersion(table interface{}) uint64 {
+	return getUint64Field(table, "Version")
+}
+
+func getUint64Field(table interface{}, name string) uint64 {
+	v := reflect.Indirect(reflect.ValueOf(table))
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+
+	field := v.FieldByName(name)
+	if !field.IsValid() || field.Kind() != reflect.Uint64 {
+		return 0
+	}
+
+	return field.Uint()
+}
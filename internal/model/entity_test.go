@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetID(t *testing.T) {
+	record := &CallHistory{}
+	record.ID = 10
+	assert.Equal(t, uint64(10), GetID(record))
+	assert.Equal(t, uint64(10), GetID(*record))
+
+	var nilRecord *CallHistory
+	assert.Equal(t, uint64(0), GetID(nilRecord))
+	assert.Equal(t, uint64(0), GetID("id"))
+	assert.Equal(t, uint64(0), GetID(struct{ ID string }{ID: "1"}))
+}
+
+func TestGetVersion(t *testing.T) {
+	record := &GroupCall{Version: 3}
+	assert.Equal(t, uint64(3), GetVersion(record))
+	assert.Equal(t, uint64(0), GetVersion(struct{ ID uint64 }{ID: 1}))
+}
@@ -10,6 +10,7 @@ type GroupCall struct {
 	GroupNumber      string `gorm:"column:group_number;type:varchar(4)" json:"groupNumber"`
 	PhoneNumber      string `gorm:"column:phone_number;type:varchar(11)" json:"phoneNumber"`
 	TransferClientID string `gorm:"column:transfer_client_id;type:varchar(32)" json:"transferClientId"`
+	Version          uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
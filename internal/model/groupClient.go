@@ -7,8 +7,9 @@ import (
 type GroupClient struct {
 	ggorm.Model `gorm:"embedded"` // embed id and time
 
-	GroupID  int `gorm:"column:group_id;type:int(11);NOT NULL" json:"groupId"`
-	ClientID int `gorm:"column:client_id;type:int(11);NOT NULL" json:"clientId"`
+	GroupID  int    `gorm:"column:group_id;type:int(11);NOT NULL" json:"groupId"`
+	ClientID int    `gorm:"column:client_id;type:int(11);NOT NULL" json:"clientId"`
+	Version  uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
@@ -2,12 +2,15 @@
 package model
 
 import (
+	"database/sql"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 
 	"github.com/zhufuyi/sponge/pkg/ggorm"
 	"github.com/zhufuyi/sponge/pkg/goredis"
@@ -114,6 +117,10 @@ func InitDB() {
 	switch strings.ToLower(config.Get().Database.Driver) {
 	case ggorm.DBDriverMysql, ggorm.DBDriverTidb:
 		InitMysql()
+	case ggorm.DBDriverPostgresql:
+		InitPostgresql()
+	case ggorm.DBDriverSqlite:
+		InitSqlite()
 	default:
 		panic("InitDB error, unsupported database driver: " + config.Get().Database.Driver)
 	}
@@ -155,6 +162,130 @@ func InitMysql() {
 	}
 }
 
+// InitPostgresql connect postgresql
+func InitPostgresql() {
+	opts := []ggorm.Option{
+		ggorm.WithMaxIdleConns(config.Get().Database.Postgresql.MaxIdleConns),
+		ggorm.WithMaxOpenConns(config.Get().Database.Postgresql.MaxOpenConns),
+		ggorm.WithConnMaxLifetime(time.Duration(config.Get().Database.Postgresql.ConnMaxLifetime) * time.Minute),
+	}
+	if config.Get().Database.Postgresql.EnableLog {
+		opts = append(opts,
+			ggorm.WithLogging(logger.Get()),
+			ggorm.WithLogRequestIDKey("request_id"),
+		)
+	}
+
+	if config.Get().App.EnableTrace {
+		opts = append(opts, ggorm.WithEnableTrace())
+	}
+
+	// setting postgresql slave and master dsn addresses, ggorm.WithRWSeparation only
+	// supports mysql, so the dbresolver plugin is registered with postgresql dialects here
+	if len(config.Get().Database.Postgresql.SlavesDsn) > 0 {
+		opts = append(opts, ggorm.WithGormPlugin(postgresqlRWSeparation(
+			config.Get().Database.Postgresql.SlavesDsn,
+			config.Get().Database.Postgresql.MastersDsn,
+		)))
+	}
+
+	var dsn = utils.AdaptivePostgresqlDsn(config.Get().Database.Postgresql.Dsn)
+	var err error
+	db, err = ggorm.InitPostgresql(dsn, opts...)
+	if err != nil {
+		panic("InitPostgresql error: " + err.Error())
+	}
+
+	// ggorm.InitPostgresql does not apply the connection pool settings
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("InitPostgresql error: " + err.Error())
+	}
+	setConnPool(sqlDB,
+		config.Get().Database.Postgresql.MaxIdleConns,
+		config.Get().Database.Postgresql.MaxOpenConns,
+		config.Get().Database.Postgresql.ConnMaxLifetime,
+	)
+}
+
+func postgresqlRWSeparation(slavesDsn []string, mastersDsn []string) gorm.Plugin {
+	slaves := []gorm.Dialector{}
+	for _, dsn := range slavesDsn {
+		slaves = append(slaves, postgres.Open(utils.AdaptivePostgresqlDsn(dsn)))
+	}
+
+	masters := []gorm.Dialector{}
+	for _, dsn := range mastersDsn {
+		masters = append(masters, postgres.Open(utils.AdaptivePostgresqlDsn(dsn)))
+	}
+
+	return dbresolver.Register(dbresolver.Config{
+		Sources:  masters,
+		Replicas: slaves,
+		Policy:   dbresolver.RandomPolicy{},
+	})
+}
+
+// sqliteMemory is the dbFile value that opens an in-memory sqlite database
+const sqliteMemory = ":memory:"
+
+// InitSqlite connect sqlite, dbFile is a file path or ":memory:" for an in-memory database
+func InitSqlite() {
+	opts := []ggorm.Option{
+		ggorm.WithMaxIdleConns(config.Get().Database.Sqlite.MaxIdleConns),
+		ggorm.WithMaxOpenConns(config.Get().Database.Sqlite.MaxOpenConns),
+		ggorm.WithConnMaxLifetime(time.Duration(config.Get().Database.Sqlite.ConnMaxLifetime) * time.Minute),
+	}
+	if config.Get().Database.Sqlite.EnableLog {
+		opts = append(opts,
+			ggorm.WithLogging(logger.Get()),
+			ggorm.WithLogRequestIDKey("request_id"),
+		)
+	}
+
+	if config.Get().App.EnableTrace {
+		opts = append(opts, ggorm.WithEnableTrace())
+	}
+
+	var dbFile = utils.AdaptiveSqlite(config.Get().Database.Sqlite.DBFile)
+	var err error
+	db, err = ggorm.InitSqlite(dbFile, opts...)
+	if err != nil {
+		panic("InitSqlite error: " + err.Error())
+	}
+
+	// ggorm.InitSqlite does not apply the connection pool settings
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("InitSqlite error: " + err.Error())
+	}
+	if dbFile == sqliteMemory {
+		// every connection to ":memory:" opens a new empty database, so keep exactly one connection alive
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		return
+	}
+	setConnPool(sqlDB,
+		config.Get().Database.Sqlite.MaxIdleConns,
+		config.Get().Database.Sqlite.MaxOpenConns,
+		config.Get().Database.Sqlite.ConnMaxLifetime,
+	)
+}
+
+// setConnPool applies the pool settings that are greater than 0, connMaxLifetime unit is minute
+func setConnPool(sqlDB *sql.DB, maxIdleConns int, maxOpenConns int, connMaxLifetime int) {
+	if maxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(maxIdleConns)
+	}
+	if maxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(maxOpenConns)
+	}
+	if connMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(connMaxLifetime) * time.Minute)
+	}
+}
+
 // GetDB get db
 func GetDB() *gorm.DB {
 	if db == nil {
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"caller/internal/config"
+)
+
+func TestInitSqlite(t *testing.T) {
+	config.Set(&config.Config{
+		Database: config.Database{
+			Driver: "sqlite",
+			Sqlite: config.Sqlite{
+				DBFile:          ":memory:",
+				EnableLog:       false,
+				MaxIdleConns:    3,
+				MaxOpenConns:    100,
+				ConnMaxLifetime: 30,
+			},
+		},
+	})
+	defer func() {
+		_ = CloseDB()
+		db = nil
+	}()
+
+	InitDB()
+	assert.NotNil(t, db)
+
+	// the in-memory database must survive across statements
+	err := db.AutoMigrate(&Clients{}, &Sms{})
+	assert.NoError(t, err)
+	err = db.Create(&Clients{MachineCode: "m1", IPAddress: "127.0.0.1"}).Error
+	assert.NoError(t, err)
+	var total int64
+	err = db.Model(&Clients{}).Count(&total).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestInitDBUnsupported(t *testing.T) {
+	config.Set(&config.Config{Database: config.Database{Driver: "unknown"}})
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+	InitDB()
+}
+
+func TestInitPostgresql(t *testing.T) {
+	config.Set(&config.Config{
+		Database: config.Database{
+			Driver: "postgresql",
+			Postgresql: config.Postgresql{
+				// nothing listens on port 1, connecting fails immediately
+				Dsn:       "root:123456@(127.0.0.1:1)/caller",
+				SlavesDsn: []string{"root:123456@(127.0.0.1:1)/caller"},
+			},
+		},
+	})
+	defer func() {
+		assert.NotNil(t, recover())
+		db = nil
+	}()
+	InitDB()
+}
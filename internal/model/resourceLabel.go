@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// ResourceLabel a key/value label of a record, such as the site or the customer of a client
+type ResourceLabel struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	Resource   string `gorm:"column:resource;type:varchar(32);NOT NULL" json:"resource"` // table name of the record, e.g. clients
+	ResourceID uint64 `gorm:"column:resource_id;type:bigint(20);NOT NULL" json:"resourceId"`
+	Key        string `gorm:"column:label_key;type:varchar(63);NOT NULL" json:"key"`
+	Value      string `gorm:"column:label_value;type:varchar(63);NOT NULL;default:''" json:"value"`
+}
+
+// TableName table name
+func (m *ResourceLabel) TableName() string {
+	return "resource_label"
+}
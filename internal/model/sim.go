@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// the statuses of a sim card, only an active sim is expected to make calls and send sms
+const (
+	SimStatusActive    = "active"
+	SimStatusSuspended = "suspended"
+	SimStatusCancelled = "cancelled"
+)
+
+// Sim a sim card, it is bound to a slot of a client device while it is in the device
+type Sim struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	ICCID       string     `gorm:"column:iccid;type:varchar(22);NOT NULL" json:"iccid"` // integrated circuit card id printed on the sim, unique
+	PhoneNumber string     `gorm:"column:phone_number;type:varchar(20);NOT NULL;default:''" json:"phoneNumber"`
+	Carrier     string     `gorm:"column:carrier;type:varchar(64);NOT NULL;default:''" json:"carrier"`
+	ClientID    uint64     `gorm:"column:client_id;type:bigint(20);NOT NULL;default:0" json:"clientId"`  // 0 if the sim is not in a device
+	SlotIndex   int        `gorm:"column:slot_index;type:int;NOT NULL;default:0" json:"slotIndex"`       // slot of the device holding the sim, starting from 0
+	Status      string     `gorm:"column:status;type:varchar(16);NOT NULL;default:active" json:"status"` // active, suspended or cancelled
+	ExpiresAt   *time.Time `gorm:"column:expires_at;type:datetime" json:"expiresAt"`                     // nil if the sim does not expire
+	Version     uint64     `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"`     // optimistic lock version
+}
+
+// TableName table name
+func (m *Sim) TableName() string {
+	return "sim"
+}
@@ -12,4 +12,11 @@ type Sms struct {
 	Date        string `gorm:"column:date;type:varchar(32)" json:"date"`
 	Body        string `gorm:"column:body;type:text" json:"body"`
 	SmsType     string `gorm:"column:sms_type;type:varchar(16)" json:"smsType"`
+	SimID       uint64 `gorm:"column:sim_id;type:bigint(20);NOT NULL;default:0" json:"simId"`    // the sim the sms was sent or received with, 0 if unknown
+	Version     uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
+}
+
+// TableName table name
+func (m *Sms) TableName() string {
+	return "sms"
 }
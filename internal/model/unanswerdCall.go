@@ -9,6 +9,7 @@ type UnanswerdCall struct {
 
 	ClientMachineCode string `gorm:"column:client_machine_code;type:varchar(32)" json:"clientMachineCode"`
 	MobileNumber      string `gorm:"column:mobile_number;type:varchar(11)" json:"mobileNumber"`
+	Version           uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
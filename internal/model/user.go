@@ -8,6 +8,7 @@ type User struct {
 	ggorm.Model `gorm:"embedded"` // embed id and time
 
 	MachineCode string `gorm:"column:machine_code;type:varchar(32)" json:"machineCode"`
+	Version     uint64 `gorm:"column:version;type:bigint(20);NOT NULL;default:1" json:"version"` // optimistic lock version
 }
 
 // TableName table name
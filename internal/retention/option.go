@@ -0,0 +1,56 @@
+package retention
+
+import (
+	"time"
+)
+
+// Option set the worker options.
+type Option func(*options)
+
+type options struct {
+	interval      time.Duration // interval between two runs
+	batchSize     int           // records deleted per batch
+	batchInterval time.Duration // pause between two batches, throttles the load on the database
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// default settings
+func defaultOptions() *options {
+	return &options{
+		interval:      time.Hour,
+		batchSize:     500,
+		batchInterval: 100 * time.Millisecond,
+	}
+}
+
+// WithInterval set the interval between two runs
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithBatchSize set the number of records deleted per batch
+func WithBatchSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithBatchInterval set the pause between two batches
+func WithBatchInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.batchInterval = d
+		}
+	}
+}
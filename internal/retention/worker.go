@@ -0,0 +1,132 @@
+// Package retention periodically deletes the records that are older than the configured max age.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/app"
+	"github.com/zhufuyi/sponge/pkg/logger"
+)
+
+var _ app.IServer = (*Worker)(nil)
+
+// Deleter delete the records of a table created before a time in batches, implemented by the daos
+type Deleter interface {
+	DeleteCreatedBefore(ctx context.Context, before time.Time, limit int, hard bool) (int64, error)
+}
+
+// Policy how long the records of a table are kept
+type Policy struct {
+	Table      string
+	MaxAge     time.Duration
+	HardDelete bool
+	Deleter    Deleter
+}
+
+// Worker delete expired records on an interval
+type Worker struct {
+	policies []Policy
+	opts     *options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewWorker create a retention worker
+func NewWorker(policies []Policy, opts ...Option) *Worker {
+	o := defaultOptions()
+	o.apply(opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		policies: policies,
+		opts:     o,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start run the policies immediately and then on every interval, it blocks until Stop is called
+func (w *Worker) Start() error {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+	for {
+		w.Run(w.ctx)
+		select {
+		case <-w.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop cancel the running batch and wait for Start to return
+func (w *Worker) Stop() error {
+	w.once.Do(w.cancel)
+	select {
+	case <-w.done:
+	case <-time.After(10 * time.Second):
+		return errors.New("timeout waiting for the retention worker to stop")
+	}
+	return nil
+}
+
+// String comment
+func (w *Worker) String() string {
+	tables := make([]string, 0, len(w.policies))
+	for _, p := range w.policies {
+		tables = append(tables, p.Table)
+	}
+	return "retention worker, tables: " + strings.Join(tables, ",")
+}
+
+// Run apply every policy once, log a summary of the records removed and return the count per table
+func (w *Worker) Run(ctx context.Context) map[string]int64 {
+	start := time.Now()
+	removed := make(map[string]int64, len(w.policies))
+	fields := make([]logger.Field, 0, len(w.policies)+1)
+
+	for _, p := range w.policies {
+		n, err := w.apply(ctx, p, start.Add(-p.MaxAge))
+		removed[p.Table] = n
+		fields = append(fields, logger.Int64(p.Table, n))
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("retention delete error", logger.Err(err), logger.String("table", p.Table))
+		}
+	}
+
+	fields = append(fields, logger.String("elapsed", time.Since(start).String()))
+	logger.Info("retention removed expired records", fields...)
+	return removed
+}
+
+// apply delete the records of a policy in batches until none is left or ctx is done
+func (w *Worker) apply(ctx context.Context, p Policy, before time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := p.Deleter.DeleteCreatedBefore(ctx, before, w.opts.batchSize, p.HardDelete)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("delete %s: %w", p.Table, err)
+		}
+		if n < int64(w.opts.batchSize) {
+			return total, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-time.After(w.opts.batchInterval):
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDeleter pretend to hold remaining expired records
+type fakeDeleter struct {
+	mu        sync.Mutex
+	remaining int64
+	calls     int
+	hard      bool
+	err       error
+}
+
+func (d *fakeDeleter) DeleteCreatedBefore(_ context.Context, _ time.Time, limit int, hard bool) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	d.hard = hard
+	if d.err != nil {
+		return 0, d.err
+	}
+	n := int64(limit)
+	if d.remaining < n {
+		n = d.remaining
+	}
+	d.remaining -= n
+	return n, nil
+}
+
+func TestWorker_Run(t *testing.T) {
+	callHistory := &fakeDeleter{remaining: 25}
+	sms := &fakeDeleter{remaining: 0}
+	broken := &fakeDeleter{err: errors.New("database is down")}
+	w := NewWorker([]Policy{
+		{Table: "call_history", MaxAge: time.Hour, Deleter: callHistory},
+		{Table: "sms", MaxAge: time.Hour, HardDelete: true, Deleter: sms},
+		{Table: "unanswerd_call", MaxAge: time.Hour, Deleter: broken},
+	}, WithBatchSize(10), WithBatchInterval(0))
+
+	removed := w.Run(context.Background())
+	assert.Equal(t, map[string]int64{"call_history": 25, "sms": 0, "unanswerd_call": 0}, removed)
+	assert.Equal(t, 3, callHistory.calls)
+	assert.Equal(t, 1, sms.calls)
+	assert.True(t, sms.hard)
+	assert.False(t, callHistory.hard)
+	assert.Contains(t, w.String(), "call_history,sms,unanswerd_call")
+}
+
+func TestWorker_StartStop(t *testing.T) {
+	deleter := &fakeDeleter{remaining: 1 << 20}
+	w := NewWorker([]Policy{{Table: "sms", MaxAge: time.Hour, Deleter: deleter}},
+		WithInterval(time.Hour), WithBatchSize(1), WithBatchInterval(10*time.Millisecond))
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- w.Start() }()
+	time.Sleep(50 * time.Millisecond)
+
+	// stop interrupts the throttled batches
+	assert.NoError(t, w.Stop())
+	assert.NoError(t, <-errCh)
+	assert.NoError(t, w.Stop())
+	deleter.mu.Lock()
+	defer deleter.mu.Unlock()
+	assert.Greater(t, deleter.remaining, int64(0))
+}
@@ -17,8 +17,10 @@ func callHistoryRouter(group *gin.RouterGroup, h handler.CallHistoryHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/callHistory", h.Create)
+	group.POST("/callHistory/batch", h.CreateBatch)
 	group.DELETE("/callHistory/:id", h.DeleteByID)
 	group.PUT("/callHistory/:id", h.UpdateByID)
+	group.PATCH("/callHistory/:id", h.PatchByID)
 	group.GET("/callHistory/:id", h.GetByID)
 	group.POST("/callHistory/list", h.List)
 
@@ -26,4 +28,13 @@ func callHistoryRouter(group *gin.RouterGroup, h handler.CallHistoryHandler) {
 	group.POST("/callHistory/condition", h.GetByCondition)
 	group.POST("/callHistory/list/ids", h.ListByIDs)
 	group.GET("/callHistory/list", h.ListByLastID)
+
+	group.GET("/callHistory/trash", h.ListTrash)
+	group.POST("/callHistory/:id/restore", h.RestoreByID)
+	group.DELETE("/callHistory/trash/:id", h.PurgeByID)
+
+	group.POST("/callHistory/:id/cancel", h.Cancel)
+	group.PUT("/callHistory/:id/schedule", h.Reschedule)
+
+	group.POST("/devices/:machineCode/callHistory/:id/state", deviceAuth(), appVersionGate(), h.ReportState)
 }
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"caller/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		callRecordingRouter(group, handler.NewCallRecordingHandler())
+	})
+}
+
+func callRecordingRouter(group *gin.RouterGroup, h handler.CallRecordingHandler) {
+	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
+	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
+
+	group.GET("/callHistory/:id/recording/download", h.Download)
+
+	// the client device uploads the recordings of its calls
+	group.POST("/devices/:machineCode/callHistory/:id/recording", deviceAuth(), appVersionGate(), h.Start)
+	group.GET("/devices/:machineCode/callHistory/:id/recording", deviceAuth(), appVersionGate(), h.GetUpload)
+	group.PUT("/devices/:machineCode/callHistory/:id/recording/content", deviceAuth(), appVersionGate(), h.Upload)
+}
@@ -3,6 +3,8 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+
 	"caller/internal/handler"
 )
 
@@ -17,8 +19,18 @@ func clientsRouter(group *gin.RouterGroup, h handler.ClientsHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/clients", h.Create)
+	group.POST("/clients/batch", h.CreateBatch)
+	group.POST("/clients/heartbeat", deviceAuth(), appVersionGate(), h.Heartbeat)
+	group.PUT("/clients/metadata", deviceAuth(), appVersionGate(), h.UpsertMetadata)
+	// the enrollment codes are issued, the credentials are revoked and the status is set by the operators
+	group.POST("/clients/enrollmentCode", middleware.Auth(), h.IssueEnrollmentCode)
+	group.POST("/clients/enroll", h.Enroll)
+	group.POST("/clients/:id/credential/rotate", deviceAuth(), appVersionGate(), h.RotateCredential)
+	group.DELETE("/clients/:id/credential", middleware.Auth(), h.RevokeCredential)
+	group.PUT("/clients/:id/status", middleware.Auth(), h.SetStatus)
 	group.DELETE("/clients/:id", h.DeleteByID)
 	group.PUT("/clients/:id", h.UpdateByID)
+	group.PATCH("/clients/:id", h.PatchByID)
 	group.GET("/clients/:id", h.GetByID)
 	group.POST("/clients/list", h.List)
 
@@ -26,4 +38,12 @@ func clientsRouter(group *gin.RouterGroup, h handler.ClientsHandler) {
 	group.POST("/clients/condition", h.GetByCondition)
 	group.POST("/clients/list/ids", h.ListByIDs)
 	group.GET("/clients/list", h.ListByLastID)
+	group.GET("/clients/:id/ip-history", h.ListIPHistory)
+	group.GET("/clients/:id/labels", h.GetLabels)
+	group.PUT("/clients/:id/labels", h.SetLabels)
+	group.PATCH("/clients/:id/labels", h.PatchLabels)
+
+	group.GET("/clients/trash", h.ListTrash)
+	group.POST("/clients/:id/restore", h.RestoreByID)
+	group.DELETE("/clients/trash/:id", h.PurgeByID)
 }
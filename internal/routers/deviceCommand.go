@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+
+	"caller/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		deviceCommandRouter(group, handler.NewDeviceCommandHandler())
+	})
+}
+
+func deviceCommandRouter(group *gin.RouterGroup, h handler.DeviceCommandHandler) {
+	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
+	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
+
+	group.POST("/devices/:machineCode/commands", h.Create)
+	group.GET("/devices/:machineCode/commands", deviceAuth(), appVersionGate(), h.Poll)
+	group.POST("/devices/:machineCode/commands/:id/ack", deviceAuth(), appVersionGate(), h.Ack)
+	group.GET("/devices/:machineCode/commands/list", h.List)
+	// a command is delivered again only when an operator asks for it
+	group.POST("/devices/:machineCode/commands/:id/redeliver", middleware.Auth(), h.Redeliver)
+}
@@ -17,8 +17,10 @@ func distributionRouter(group *gin.RouterGroup, h handler.DistributionHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/distribution", h.Create)
+	group.POST("/distribution/batch", h.CreateBatch)
 	group.DELETE("/distribution/:id", h.DeleteByID)
 	group.PUT("/distribution/:id", h.UpdateByID)
+	group.PATCH("/distribution/:id", h.PatchByID)
 	group.GET("/distribution/:id", h.GetByID)
 	group.POST("/distribution/list", h.List)
 
@@ -26,4 +28,8 @@ func distributionRouter(group *gin.RouterGroup, h handler.DistributionHandler) {
 	group.POST("/distribution/condition", h.GetByCondition)
 	group.POST("/distribution/list/ids", h.ListByIDs)
 	group.GET("/distribution/list", h.ListByLastID)
+
+	group.GET("/distribution/trash", h.ListTrash)
+	group.POST("/distribution/:id/restore", h.RestoreByID)
+	group.DELETE("/distribution/trash/:id", h.PurgeByID)
 }
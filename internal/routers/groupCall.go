@@ -17,8 +17,10 @@ func groupCallRouter(group *gin.RouterGroup, h handler.GroupCallHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/groupCall", h.Create)
+	group.POST("/groupCall/batch", h.CreateBatch)
 	group.DELETE("/groupCall/:id", h.DeleteByID)
 	group.PUT("/groupCall/:id", h.UpdateByID)
+	group.PATCH("/groupCall/:id", h.PatchByID)
 	group.GET("/groupCall/:id", h.GetByID)
 	group.POST("/groupCall/list", h.List)
 
@@ -26,4 +28,14 @@ func groupCallRouter(group *gin.RouterGroup, h handler.GroupCallHandler) {
 	group.POST("/groupCall/condition", h.GetByCondition)
 	group.POST("/groupCall/list/ids", h.ListByIDs)
 	group.GET("/groupCall/list", h.ListByLastID)
+	group.GET("/groupCall/:id/labels", h.GetLabels)
+	group.PUT("/groupCall/:id/labels", h.SetLabels)
+	group.PATCH("/groupCall/:id/labels", h.PatchLabels)
+
+	group.GET("/groupCall/trash", h.ListTrash)
+	group.POST("/groupCall/:id/restore", h.RestoreByID)
+	group.DELETE("/groupCall/trash/:id", h.PurgeByID)
+
+	// the wildcard holds the group number, gin needs the wildcards at the same place of the paths to have the same name
+	group.POST("/groupCall/:id/dial", h.Dial)
 }
@@ -17,8 +17,10 @@ func groupClientRouter(group *gin.RouterGroup, h handler.GroupClientHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/groupClient", h.Create)
+	group.POST("/groupClient/batch", h.CreateBatch)
 	group.DELETE("/groupClient/:id", h.DeleteByID)
 	group.PUT("/groupClient/:id", h.UpdateByID)
+	group.PATCH("/groupClient/:id", h.PatchByID)
 	group.GET("/groupClient/:id", h.GetByID)
 	group.POST("/groupClient/list", h.List)
 
@@ -26,4 +28,9 @@ func groupClientRouter(group *gin.RouterGroup, h handler.GroupClientHandler) {
 	group.POST("/groupClient/condition", h.GetByCondition)
 	group.POST("/groupClient/list/ids", h.ListByIDs)
 	group.GET("/groupClient/list", h.ListByLastID)
+	group.GET("/groupClient/members/:groupId", h.ListMembers)
+
+	group.GET("/groupClient/trash", h.ListTrash)
+	group.POST("/groupClient/:id/restore", h.RestoreByID)
+	group.DELETE("/groupClient/trash/:id", h.PurgeByID)
 }
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"caller/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		instructionRouter(group, handler.NewInstructionHandler())
+	})
+}
+
+func instructionRouter(group *gin.RouterGroup, h handler.InstructionHandler) {
+	group.GET("/instructions", h.List)
+}
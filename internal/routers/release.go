@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+
+	"caller/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		releaseRouter(group, handler.NewReleaseHandler())
+	})
+}
+
+func releaseRouter(group *gin.RouterGroup, h handler.ReleaseHandler) {
+	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
+	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
+
+	// the apks are uploaded and the channels are assigned by the operators
+	group.POST("/releases", middleware.Auth(), h.Upload)
+	group.GET("/releases/list", h.List)
+	group.GET("/releases/:id", h.GetByID)
+	group.GET("/releases/:id/download", h.Download)
+
+	group.GET("/releaseChannels", h.ListChannels)
+	group.PUT("/releaseChannels/:name", middleware.Auth(), h.UpsertChannel)
+	group.PUT("/clients/releaseChannel", middleware.Auth(), h.SetChannelClients)
+
+	// not gated by the app version, the outdated apps use it to update
+	group.GET("/devices/:machineCode/update", deviceAuth(), h.CheckUpdate)
+}
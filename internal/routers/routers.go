@@ -22,6 +22,8 @@ import (
 
 	"caller/docs"
 	"caller/internal/config"
+	"caller/internal/gateway"
+	"caller/internal/handler"
 )
 
 var (
@@ -101,6 +103,9 @@ func NewRouter() *gin.Engine {
 	// access path /swagger/index.html
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// websocket of the devices, the connections are drained by the http server when it stops
+	r.GET("/ws/device", deviceAuth(), appVersionGate(), handler.NewDeviceGatewayHandler(gateway.DefaultHub).Connect)
+
 	// register routers, middleware support
 	registerRouters(r, "/api/v1", apiV1RouterFns)
 	// if you have other group routes you can add them here
@@ -110,6 +115,20 @@ func NewRouter() *gin.Engine {
 	return r
 }
 
+// deviceAuth authenticate the routes called by the devices with the credential issued by enrollment,
+// the routes are not authenticated if enableDeviceAuth is false
+func deviceAuth() gin.HandlerFunc {
+	if !config.Get().App.EnableDeviceAuth {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return handler.DeviceAuth()
+}
+
+// appVersionGate reject the calls of the apps older than the minVersionCode of the release config
+func appVersionGate() gin.HandlerFunc {
+	return handler.AppVersionGate(config.Get().Release.MinVersionCode)
+}
+
 func registerRouters(r *gin.Engine, groupPath string, routerFns []func(*gin.RouterGroup), handlers ...gin.HandlerFunc) {
 	rg := r.Group(groupPath, handlers...)
 	for _, fn := range routerFns {
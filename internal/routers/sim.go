@@ -0,0 +1,30 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"caller/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		simRouter(group, handler.NewSimHandler())
+	})
+}
+
+func simRouter(group *gin.RouterGroup, h handler.SimHandler) {
+	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
+	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
+
+	group.POST("/sim", h.Create)
+	group.DELETE("/sim/:id", h.DeleteByID)
+	group.PUT("/sim/:id", h.UpdateByID)
+	group.GET("/sim/:id", h.GetByID)
+	group.POST("/sim/list", h.List)
+	group.GET("/sim/list", h.ListByLastID)
+	group.PUT("/sim/:id/binding", h.Bind)
+	group.GET("/sim/duplicates", h.ListDuplicates)
+	group.GET("/clients/:id/sims", h.ListByClient)
+
+	group.PUT("/devices/:machineCode/sims", deviceAuth(), appVersionGate(), h.Report)
+}
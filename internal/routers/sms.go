@@ -16,9 +16,11 @@ func smsRouter(group *gin.RouterGroup, h handler.SmsHandler) {
 	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
-	group.POST("/sms", h.Create)
+	group.POST("/sms", deviceAuth(), appVersionGate(), h.Create)
+	group.POST("/sms/batch", deviceAuth(), appVersionGate(), h.CreateBatch)
 	group.DELETE("/sms/:id", h.DeleteByID)
 	group.PUT("/sms/:id", h.UpdateByID)
+	group.PATCH("/sms/:id", h.PatchByID)
 	group.GET("/sms/:id", h.GetByID)
 	group.POST("/sms/list", h.List)
 
@@ -26,4 +28,8 @@ func smsRouter(group *gin.RouterGroup, h handler.SmsHandler) {
 	group.POST("/sms/condition", h.GetByCondition)
 	group.POST("/sms/list/ids", h.ListByIDs)
 	group.GET("/sms/list", h.ListByLastID)
+
+	group.GET("/sms/trash", h.ListTrash)
+	group.POST("/sms/:id/restore", h.RestoreByID)
+	group.DELETE("/sms/trash/:id", h.PurgeByID)
 }
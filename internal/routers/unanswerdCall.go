@@ -16,9 +16,11 @@ func unanswerdCallRouter(group *gin.RouterGroup, h handler.UnanswerdCallHandler)
 	//group.Use(middleware.Auth()) // all of the following routes use jwt authentication
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
-	group.POST("/unanswerdCall", h.Create)
+	group.POST("/unanswerdCall", deviceAuth(), appVersionGate(), h.Create)
+	group.POST("/unanswerdCall/batch", deviceAuth(), appVersionGate(), h.CreateBatch)
 	group.DELETE("/unanswerdCall/:id", h.DeleteByID)
 	group.PUT("/unanswerdCall/:id", h.UpdateByID)
+	group.PATCH("/unanswerdCall/:id", h.PatchByID)
 	group.GET("/unanswerdCall/:id", h.GetByID)
 	group.POST("/unanswerdCall/list", h.List)
 
@@ -26,4 +28,8 @@ func unanswerdCallRouter(group *gin.RouterGroup, h handler.UnanswerdCallHandler)
 	group.POST("/unanswerdCall/condition", h.GetByCondition)
 	group.POST("/unanswerdCall/list/ids", h.ListByIDs)
 	group.GET("/unanswerdCall/list", h.ListByLastID)
+
+	group.GET("/unanswerdCall/trash", h.ListTrash)
+	group.POST("/unanswerdCall/:id/restore", h.RestoreByID)
+	group.DELETE("/unanswerdCall/trash/:id", h.PurgeByID)
 }
@@ -17,8 +17,10 @@ func userRouter(group *gin.RouterGroup, h handler.UserHandler) {
 	// or group.Use(middleware.Auth(middleware.WithVerify(verify))) // token authentication
 
 	group.POST("/user", h.Create)
+	group.POST("/user/batch", h.CreateBatch)
 	group.DELETE("/user/:id", h.DeleteByID)
 	group.PUT("/user/:id", h.UpdateByID)
+	group.PATCH("/user/:id", h.PatchByID)
 	group.GET("/user/:id", h.GetByID)
 	group.POST("/user/list", h.List)
 
@@ -26,4 +28,8 @@ func userRouter(group *gin.RouterGroup, h handler.UserHandler) {
 	group.POST("/user/condition", h.GetByCondition)
 	group.POST("/user/list/ids", h.ListByIDs)
 	group.GET("/user/list", h.ListByLastID)
+
+	group.GET("/user/trash", h.ListTrash)
+	group.POST("/user/:id/restore", h.RestoreByID)
+	group.DELETE("/user/trash/:id", h.PurgeByID)
 }
@@ -0,0 +1,34 @@
+package routing
+
+import (
+	"time"
+)
+
+// Option set the strategy options.
+type Option func(*options)
+
+type options struct {
+	stickyWindow time.Duration // how long a callee sticks to the client that called it
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// default settings
+func defaultOptions() *options {
+	return &options{
+		stickyWindow: 7 * 24 * time.Hour,
+	}
+}
+
+// WithStickyWindow set how long a callee sticks to the client that called it, only used by StickyPerCallee
+func WithStickyWindow(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.stickyWindow = d
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package routing chooses the client device of a group that places a call, the strategies choose by the past calls.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"caller/internal/model"
+)
+
+// the strategies
+const (
+	RoundRobin        = "roundRobin"        // the member after the client of the last call dialed by the group, in the order of the client ids
+	LeastRecentlyUsed = "leastRecentlyUsed" // the member whose last call is the oldest, a member that never called comes first
+	Random            = "random"            // any member
+	StickyPerCallee   = "stickyPerCallee"   // the member that last called the callee within the sticky window, else the least recently used
+)
+
+var (
+	// ErrUnknownStrategy the name is not one of the strategies
+	ErrUnknownStrategy = errors.New("unknown routing strategy")
+	// ErrNoCandidate the group has no eligible client
+	ErrNoCandidate = errors.New("no eligible client")
+)
+
+// History the past calls the strategies choose by, implemented by the call history dao
+type History interface {
+	GetLastClientOfGroup(ctx context.Context, groupCallID uint64) (string, error)
+	GetLastCallTimes(ctx context.Context, machineCodes []string) (map[string]time.Time, error)
+	GetLastClientOfCallee(ctx context.Context, mobileNumber string, machineCodes []string, since time.Time) (string, error)
+}
+
+// Call the call a client is chosen for
+type Call struct {
+	GroupCallID  uint64
+	MobileNumber string // the callee
+}
+
+// Strategy choose the client that places a call from the eligible clients of a group,
+// the candidates are ordered by id and are not empty
+type Strategy interface {
+	Select(ctx context.Context, call *Call, candidates []*model.Clients) (*model.Clients, error)
+}
+
+// Names the names of the strategies
+func Names() []string {
+	return []string{RoundRobin, LeastRecentlyUsed, Random, StickyPerCallee}
+}
+
+// New create a strategy by its name
+func New(name string, history History, opts ...Option) (Strategy, error) {
+	o := defaultOptions()
+	o.apply(opts...)
+
+	switch name {
+	case RoundRobin:
+		return &roundRobin{history: history}, nil
+	case LeastRecentlyUsed:
+		return &leastRecentlyUsed{history: history}, nil
+	case Random:
+		return &random{}, nil
+	case StickyPerCallee:
+		return &stickyPerCallee{history: history, window: o.stickyWindow, fallback: &leastRecentlyUsed{history: history}}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+}
+
+// Select choose a client for the call with the strategy, return ErrNoCandidate if there are no candidates
+func Select(ctx context.Context, strategy Strategy, call *Call, candidates []*model.Clients) (*model.Clients, error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoCandidate
+	}
+	return strategy.Select(ctx, call, candidates)
+}
+
+type roundRobin struct {
+	history History
+}
+
+func (s *roundRobin) Select(ctx context.Context, call *Call, candidates []*model.Clients) (*model.Clients, error) {
+	last, err := s.history.GetLastClientOfGroup(ctx, call.GroupCallID)
+	if err != nil {
+		return nil, err
+	}
+	for i, candidate := range candidates {
+		if candidate.MachineCode == last {
+			return candidates[(i+1)%len(candidates)], nil
+		}
+	}
+	// the last client is no longer a candidate, or the group has not called yet
+	return candidates[0], nil
+}
+
+type leastRecentlyUsed struct {
+	history History
+}
+
+func (s *leastRecentlyUsed) Select(ctx context.Context, call *Call, candidates []*model.Clients) (*model.Clients, error) {
+	lastCallTimes, err := s.history.GetLastCallTimes(ctx, machineCodes(candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	var selected *model.Clients
+	var selectedAt time.Time
+	for _, candidate := range candidates {
+		at, ok := lastCallTimes[candidate.MachineCode]
+		if !ok {
+			return candidate, nil
+		}
+		if selected == nil || at.Before(selectedAt) {
+			selected, selectedAt = candidate, at
+		}
+	}
+	return selected, nil
+}
+
+type random struct{}
+
+func (s *random) Select(_ context.Context, _ *Call, candidates []*model.Clients) (*model.Clients, error) {
+	return candidates[rand.Intn(len(candidates))], nil //nolint
+}
+
+type stickyPerCallee struct {
+	history  History
+	window   time.Duration
+	fallback Strategy
+}
+
+func (s *stickyPerCallee) Select(ctx context.Context, call *Call, candidates []*model.Clients) (*model.Clients, error) {
+	last, err := s.history.GetLastClientOfCallee(ctx, call.MobileNumber, machineCodes(candidates), time.Now().Add(-s.window))
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if candidate.MachineCode == last {
+			return candidate, nil
+		}
+	}
+	return s.fallback.Select(ctx, call, candidates)
+}
+
+func machineCodes(clients []*model.Clients) []string {
+	codes := make([]string, 0, len(clients))
+	for _, v := range clients {
+		codes = append(codes, v.MachineCode)
+	}
+	return codes
+}
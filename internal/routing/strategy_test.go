@@ -0,0 +1,109 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"caller/internal/model"
+)
+
+type fakeHistory struct {
+	lastOfGroup   string
+	lastCallTimes map[string]time.Time
+	lastOfCallee  map[string]string
+	err           error
+}
+
+func (h *fakeHistory) GetLastClientOfGroup(_ context.Context, _ uint64) (string, error) {
+	return h.lastOfGroup, h.err
+}
+
+func (h *fakeHistory) GetLastCallTimes(_ context.Context, _ []string) (map[string]time.Time, error) {
+	return h.lastCallTimes, h.err
+}
+
+func (h *fakeHistory) GetLastClientOfCallee(_ context.Context, mobileNumber string, _ []string, _ time.Time) (string, error) {
+	return h.lastOfCallee[mobileNumber], h.err
+}
+
+func newCandidates(machineCodes ...string) []*model.Clients {
+	candidates := []*model.Clients{}
+	for _, machineCode := range machineCodes {
+		candidates = append(candidates, &model.Clients{MachineCode: machineCode})
+	}
+	return candidates
+}
+
+func selectMachineCode(t *testing.T, name string, history History, call *Call, candidates []*model.Clients) string {
+	strategy, err := New(name, history)
+	assert.NoError(t, err)
+	client, err := Select(context.Background(), strategy, call, candidates)
+	assert.NoError(t, err)
+	return client.MachineCode
+}
+
+func TestRoundRobin(t *testing.T) {
+	candidates := newCandidates("m1", "m2", "m3")
+	call := &Call{GroupCallID: 1, MobileNumber: "13800000000"}
+
+	assert.Equal(t, "m1", selectMachineCode(t, RoundRobin, &fakeHistory{}, call, candidates))
+	assert.Equal(t, "m3", selectMachineCode(t, RoundRobin, &fakeHistory{lastOfGroup: "m2"}, call, candidates))
+	assert.Equal(t, "m1", selectMachineCode(t, RoundRobin, &fakeHistory{lastOfGroup: "m3"}, call, candidates))
+	assert.Equal(t, "m1", selectMachineCode(t, RoundRobin, &fakeHistory{lastOfGroup: "removed"}, call, candidates))
+}
+
+func TestLeastRecentlyUsed(t *testing.T) {
+	candidates := newCandidates("m1", "m2", "m3")
+	call := &Call{GroupCallID: 1, MobileNumber: "13800000000"}
+	now := time.Now()
+
+	history := &fakeHistory{lastCallTimes: map[string]time.Time{"m1": now, "m2": now.Add(-time.Hour), "m3": now.Add(-time.Minute)}}
+	assert.Equal(t, "m2", selectMachineCode(t, LeastRecentlyUsed, history, call, candidates))
+	delete(history.lastCallTimes, "m3")
+	assert.Equal(t, "m3", selectMachineCode(t, LeastRecentlyUsed, history, call, candidates))
+}
+
+func TestRandom(t *testing.T) {
+	candidates := newCandidates("m1", "m2")
+	selected := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		selected[selectMachineCode(t, Random, nil, &Call{}, candidates)] = true
+	}
+	assert.Equal(t, map[string]bool{"m1": true, "m2": true}, selected)
+}
+
+func TestStickyPerCallee(t *testing.T) {
+	candidates := newCandidates("m1", "m2", "m3")
+	now := time.Now()
+	history := &fakeHistory{
+		lastCallTimes: map[string]time.Time{"m1": now, "m2": now, "m3": now.Add(-time.Hour)},
+		lastOfCallee:  map[string]string{"13800000001": "m2", "13800000002": "removed"},
+	}
+
+	assert.Equal(t, "m2", selectMachineCode(t, StickyPerCallee, history, &Call{MobileNumber: "13800000001"}, candidates))
+	// the callee has not been called by a candidate, the least recently used is chosen
+	assert.Equal(t, "m3", selectMachineCode(t, StickyPerCallee, history, &Call{MobileNumber: "13800000002"}, candidates))
+	assert.Equal(t, "m3", selectMachineCode(t, StickyPerCallee, history, &Call{MobileNumber: "13800000003"}, candidates))
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range Names() {
+		_, err := New(name, &fakeHistory{}, WithStickyWindow(time.Hour))
+		assert.NoError(t, err, name)
+	}
+	_, err := New("roundrobin", &fakeHistory{})
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+}
+
+func TestSelect(t *testing.T) {
+	strategy, err := New(RoundRobin, &fakeHistory{err: errors.New("mock error")})
+	assert.NoError(t, err)
+	_, err = Select(context.Background(), strategy, &Call{}, nil)
+	assert.ErrorIs(t, err, ErrNoCandidate)
+	_, err = Select(context.Background(), strategy, &Call{}, newCandidates("m1"))
+	assert.Error(t, err)
+}
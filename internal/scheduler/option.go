@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// Option set the worker options.
+type Option func(*options)
+
+type options struct {
+	interval  time.Duration // interval between two runs, a call is released at most this late
+	batchSize int           // calls released per batch
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// default settings
+func defaultOptions() *options {
+	return &options{
+		interval:  5 * time.Second,
+		batchSize: 100,
+	}
+}
+
+// WithInterval set the interval between two runs
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithBatchSize set the number of calls released per batch
+func WithBatchSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
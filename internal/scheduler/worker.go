@@ -0,0 +1,127 @@
+// Package scheduler periodically requests the scheduled calls that are due and wakes up their client devices.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/app"
+	"github.com/zhufuyi/sponge/pkg/logger"
+
+	"caller/internal/model"
+)
+
+var _ app.IServer = (*Worker)(nil)
+
+// Releaser request the scheduled calls that are due at now in a batch and return them, a call is requested only once
+// when several replicas release at the same time, implemented by the call history dao
+type Releaser interface {
+	ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*model.CallHistory, error)
+}
+
+// Notifier wake up the polls of a client device, implemented by the device command notifier
+type Notifier interface {
+	Notify(ctx context.Context, machineCode string) error
+}
+
+// Worker release the due calls on an interval
+type Worker struct {
+	releaser Releaser
+	notifier Notifier
+	opts     *options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewWorker create a scheduler worker
+func NewWorker(releaser Releaser, notifier Notifier, opts ...Option) *Worker {
+	o := defaultOptions()
+	o.apply(opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		releaser: releaser,
+		notifier: notifier,
+		opts:     o,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start release the due calls immediately and then on every interval, it blocks until Stop is called
+func (w *Worker) Start() error {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+	for {
+		w.Run(w.ctx)
+		select {
+		case <-w.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop cancel the running batch and wait for Start to return
+func (w *Worker) Stop() error {
+	w.once.Do(w.cancel)
+	select {
+	case <-w.done:
+	case <-time.After(10 * time.Second):
+		return errors.New("timeout waiting for the scheduler worker to stop")
+	}
+	return nil
+}
+
+// String comment
+func (w *Worker) String() string {
+	return "scheduler worker, interval: " + w.opts.interval.String()
+}
+
+// Run release the calls that are due in batches until none is left or ctx is done, wake up the client devices
+// of the released calls and return the number of calls released
+func (w *Worker) Run(ctx context.Context) int {
+	total := 0
+	for {
+		calls, err := w.releaser.ReleaseDue(ctx, time.Now(), w.opts.batchSize)
+		total += len(calls)
+		w.notify(ctx, calls)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("scheduler release error", logger.Err(err))
+			}
+			break
+		}
+		if len(calls) < w.opts.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info("scheduler released the due calls", logger.Int("count", total))
+	}
+	return total
+}
+
+// notify wake up the client devices of the requested calls once each
+func (w *Worker) notify(ctx context.Context, calls []*model.CallHistory) {
+	notified := make(map[string]bool, len(calls))
+	for _, call := range calls {
+		if call.State != model.CallStateRequested || call.Instruction == "" || call.ClientMachineCode == "" || notified[call.ClientMachineCode] {
+			continue
+		}
+		notified[call.ClientMachineCode] = true
+		err := w.notifier.Notify(ctx, call.ClientMachineCode)
+		if err != nil {
+			logger.Warn("Notify device command error", logger.Err(err), logger.String("machineCode", call.ClientMachineCode))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"caller/internal/model"
+)
+
+// fakeReleaser pretend to hold due calls
+type fakeReleaser struct {
+	mu    sync.Mutex
+	due   []*model.CallHistory
+	calls int
+	err   error
+}
+
+func (r *fakeReleaser) ReleaseDue(_ context.Context, _ time.Time, limit int) ([]*model.CallHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	n := limit
+	if len(r.due) < n {
+		n = len(r.due)
+	}
+	released := r.due[:n]
+	r.due = r.due[n:]
+	return released, nil
+}
+
+type fakeNotifier struct {
+	mu           sync.Mutex
+	machineCodes []string
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, machineCode string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.machineCodes = append(n.machineCodes, machineCode)
+	return nil
+}
+
+func TestWorker_Run(t *testing.T) {
+	releaser := &fakeReleaser{due: []*model.CallHistory{
+		{ClientMachineCode: "m1", Instruction: "dial", State: model.CallStateRequested},
+		{ClientMachineCode: "m2", Instruction: "dial", State: model.CallStateRequested},
+		{ClientMachineCode: "m1", Instruction: "hangup", State: model.CallStateRequested},
+		{ClientMachineCode: "m3", State: model.CallStateRequested},
+		{ClientMachineCode: "m3", Instruction: "dial", State: model.CallStateRequested},
+		{ClientMachineCode: "m4", Instruction: "dial", State: model.CallStateFailed},
+	}}
+	notifier := &fakeNotifier{}
+	w := NewWorker(releaser, notifier, WithBatchSize(2))
+
+	assert.Equal(t, 6, w.Run(context.Background()))
+	assert.Equal(t, 4, releaser.calls)
+	// a device is woken up once per batch, a call without an instruction or that failed wakes up nothing
+	assert.Equal(t, []string{"m1", "m2", "m1", "m3"}, notifier.machineCodes)
+	assert.Equal(t, 0, w.Run(context.Background()))
+
+	releaser.err = errors.New("database is down")
+	assert.Equal(t, 0, w.Run(context.Background()))
+	assert.Contains(t, w.String(), "scheduler")
+}
+
+func TestWorker_StartStop(t *testing.T) {
+	releaser := &fakeReleaser{}
+	w := NewWorker(releaser, &fakeNotifier{}, WithInterval(10*time.Millisecond))
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- w.Start() }()
+	time.Sleep(50 * time.Millisecond)
+
+	assert.NoError(t, w.Stop())
+	assert.NoError(t, <-errCh)
+	assert.NoError(t, w.Stop())
+	releaser.mu.Lock()
+	defer releaser.mu.Unlock()
+	assert.Greater(t, releaser.calls, 1)
+}
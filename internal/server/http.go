@@ -11,6 +11,7 @@ import (
 	"github.com/zhufuyi/sponge/pkg/app"
 	"github.com/zhufuyi/sponge/pkg/servicerd/registry"
 
+	"caller/internal/gateway"
 	"caller/internal/routers"
 )
 
@@ -51,6 +52,8 @@ func (s *httpServer) Stop() error {
 	}
 
 	ctx, _ := context.WithTimeout(context.Background(), 3*time.Second) //nolint
+	// the websocket connections are hijacked, so Shutdown of the server does not wait for them
+	_ = gateway.DefaultHub.Shutdown(ctx)
 	return s.server.Shutdown(ctx)
 }
 
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var _ Storage = (*localStorage)(nil)
+
+type localStorage struct {
+	dir string
+}
+
+// NewLocal create a storage in a directory of the local filesystem, the directory is created by the first write
+func NewLocal(dir string) Storage {
+	return &localStorage{dir: dir}
+}
+
+// Append write the data of r at the end of the file of the object
+func (s *localStorage) Append(ctx context.Context, key string, offset int64, r io.Reader) (int64, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return 0, err
+	}
+
+	flag := os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flag |= os.O_CREATE
+	}
+	file, err := os.OpenFile(name, flag, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrOffsetMismatch
+		}
+		return 0, err
+	}
+	defer file.Close() //nolint
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() != offset {
+		return info.Size(), ErrOffsetMismatch
+	}
+
+	n, err := io.Copy(file, &contextReader{ctx: ctx, r: r})
+	if err != nil {
+		return offset + n, err
+	}
+	return offset + n, file.Sync()
+}
+
+// Size get the size of the file of the object
+func (s *localStorage) Size(_ context.Context, key string) (int64, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrNotExist
+		}
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Open open the file of the object
+func (s *localStorage) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotExist
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete remove the file of the object
+func (s *localStorage) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path the file of the object
+func (s *localStorage) path(key string) (string, error) {
+	err := checkKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// contextReader stop reading when the context is done, so a cancelled request stops the write
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	s, err := New(Local, WithDir(t.TempDir()))
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+	_, err = New("ftp")
+	assert.ErrorIs(t, err, ErrUnknownDriver)
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocal(t.TempDir())
+	key := "recordings/1/a.amr"
+
+	// the object does not exist before the first write
+	_, err := s.Size(ctx, key)
+	assert.ErrorIs(t, err, ErrNotExist)
+	_, err = s.Open(ctx, key)
+	assert.ErrorIs(t, err, ErrNotExist)
+	_, err = s.Append(ctx, key, 3, strings.NewReader("def"))
+	assert.ErrorIs(t, err, ErrOffsetMismatch)
+
+	// append the chunks
+	size, err := s.Append(ctx, key, 0, strings.NewReader("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), size)
+	size, err = s.Append(ctx, key, 0, strings.NewReader("abc"))
+	assert.ErrorIs(t, err, ErrOffsetMismatch)
+	assert.Equal(t, int64(3), size)
+	size, err = s.Append(ctx, key, 3, strings.NewReader("def"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), size)
+
+	// an interrupted write keeps the data read before
+	size, err = s.Append(ctx, key, 6, io.MultiReader(strings.NewReader("gh"), &errReader{}))
+	assert.Error(t, err)
+	assert.Equal(t, int64(8), size)
+	size, err = s.Size(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+
+	// read a range
+	file, err := s.Open(ctx, key)
+	assert.NoError(t, err)
+	_, err = file.Seek(2, io.SeekStart)
+	assert.NoError(t, err)
+	data := make([]byte, 3)
+	_, err = io.ReadFull(file, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "cde", string(data))
+	assert.NoError(t, file.Close())
+
+	// delete
+	assert.NoError(t, s.Delete(ctx, key))
+	assert.NoError(t, s.Delete(ctx, key))
+	_, err = s.Size(ctx, key)
+	assert.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestLocal_invalidKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocal(t.TempDir())
+	for _, key := range []string{"", "/etc/passwd", "../a", "a/../../b", "a//b", `a\b`} {
+		_, err := s.Append(ctx, key, 0, strings.NewReader("a"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+type errReader struct{}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
@@ -0,0 +1,30 @@
+package storage
+
+// Option set the storage options.
+type Option func(*options)
+
+type options struct {
+	dir string // directory of the objects, only used by Local
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// default settings
+func defaultOptions() *options {
+	return &options{
+		dir: "storage",
+	}
+}
+
+// WithDir set the directory of the objects, only used by Local
+func WithDir(dir string) Option {
+	return func(o *options) {
+		if dir != "" {
+			o.dir = dir
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package storage keeps the files uploaded by the devices, such as the call recordings, in a pluggable store.
+// the objects are written by appending chunks, so an interrupted upload can be resumed from the size of the object.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// the drivers
+const (
+	Local = "local" // a directory of the local filesystem, every replica must share it
+)
+
+var (
+	// ErrUnknownDriver the name is not one of the drivers
+	ErrUnknownDriver = errors.New("unknown storage driver")
+	// ErrNotExist the object does not exist
+	ErrNotExist = errors.New("object does not exist")
+	// ErrOffsetMismatch the offset of a write is not the size of the object
+	ErrOffsetMismatch = errors.New("offset does not match the size of the object")
+	// ErrInvalidKey the key is empty or is not a relative slash separated path
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage the objects stored by key, a key is a slash separated path such as "recordings/1/abc.amr"
+type Storage interface {
+	// Append write the data of r at offset of the object and return the size of the object after the write,
+	// offset must be the size of the object, an object is created by writing at offset 0. if reading r fails,
+	// the data read before is kept and the size is returned with the error.
+	Append(ctx context.Context, key string, offset int64, r io.Reader) (int64, error)
+	// Size get the size of the object
+	Size(ctx context.Context, key string) (int64, error)
+	// Open open the object to read, the object can seek, so that ranges of it can be served
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete delete the object, deleting an object that does not exist is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New create a storage by the name of its driver
+func New(driver string, opts ...Option) (Storage, error) {
+	o := defaultOptions()
+	o.apply(opts...)
+
+	switch driver {
+	case Local:
+		return NewLocal(o.dir), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, driver)
+}
+
+// checkKey check that the key is a relative path that stays in the storage
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
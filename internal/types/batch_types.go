@@ -0,0 +1,15 @@
+package types
+
+// CreateBatchResult the result of a record in a batch create, ID is set if the record is created
+type CreateBatchResult struct {
+	Index int    `json:"index"`           // index of the record in the request
+	ID    uint64 `json:"id,omitempty"`    // id of the created record
+	Error string `json:"error,omitempty"` // reason why the record is invalid or failed to be created
+}
+
+// CreateBatchData the respond data of a batch create
+type CreateBatchData struct {
+	Created int                 `json:"created"` // number of records created
+	Failed  int                 `json:"failed"`  // number of records not created
+	Results []CreateBatchResult `json:"results"` // result of each record, in the order of the request
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
@@ -12,32 +13,66 @@ var _ time.Time
 
 // CreateCallHistoryRequest request params
 type CreateCallHistoryRequest struct {
-	RequestMachineCode string `json:"requestMachineCode" binding:""`
-	ClientMachineCode  string `json:"clientMachineCode" binding:""`
-	MobileNumber       string `json:"mobileNumber" binding:""`
-	Instruction        string `json:"instruction" binding:""`
+	RequestMachineCode string          `json:"requestMachineCode" binding:""`
+	ClientMachineCode  string          `json:"clientMachineCode" binding:""`
+	MobileNumber       string          `json:"mobileNumber" binding:""`
+	Instruction        string          `json:"instruction" binding:""`                  // one of the instructions listed by GET /api/v1/instructions, empty if nothing is sent to the client device
+	Payload            json.RawMessage `json:"payload" copier:"-" swaggertype:"object"` // payload of the instruction, see the fields of the instruction in GET /api/v1/instructions
+	SimID              uint64          `json:"simId" binding:""`                        // the sim used, it must be in the device of the client, 0 if unknown
+	SimSlot            *int            `json:"simSlot" binding:"omitempty,gte=0,lte=7"` // slot of the device the sim is in, used to find the sim if simId is 0, the slot must not be empty
+	ScheduledAt        *time.Time      `json:"scheduledAt" copier:"-"`                  // the instruction is sent to the client device at this time, empty or a time in the past sends it now
+	ScheduleIn         string          `json:"scheduleIn" copier:"-"`                   // a duration such as 30m or 2h, the instruction is sent this long after the call is created, cannot be used with scheduledAt
 }
 
 // UpdateCallHistoryByIDRequest request params
 type UpdateCallHistoryByIDRequest struct {
 	ID uint64 `json:"id" binding:""` // uint64 id
 
-	RequestMachineCode string `json:"requestMachineCode" binding:""`
-	ClientMachineCode  string `json:"clientMachineCode" binding:""`
-	MobileNumber       string `json:"mobileNumber" binding:""`
-	Instruction        string `json:"instruction" binding:""`
+	RequestMachineCode string          `json:"requestMachineCode" binding:""`
+	ClientMachineCode  string          `json:"clientMachineCode" binding:""`
+	MobileNumber       string          `json:"mobileNumber" binding:""`
+	Instruction        string          `json:"instruction" binding:""`
+	Payload            json.RawMessage `json:"payload" copier:"-" swaggertype:"object"` // replaces the payload, if the instruction is changed and the payload is empty the instruction has no payload
+	SimID              uint64          `json:"simId" binding:""`
+}
+
+// PatchCallHistoryByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchCallHistoryByIDRequest struct {
+	RequestMachineCode *string          `json:"requestMachineCode,omitempty"`
+	ClientMachineCode  *string          `json:"clientMachineCode,omitempty"`
+	MobileNumber       *string          `json:"mobileNumber,omitempty"`
+	Instruction        *string          `json:"instruction,omitempty"`
+	Payload            *json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	SimID              *uint64          `json:"simId,omitempty"`
 }
 
 // CallHistoryObjDetail detail
 type CallHistoryObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	RequestMachineCode string    `json:"requestMachineCode"`
-	ClientMachineCode  string    `json:"clientMachineCode"`
-	MobileNumber       string    `json:"mobileNumber"`
-	Instruction        string    `json:"instruction"`
-	CreatedAt          time.Time `json:"createdAt"`
-	UpdatedAt          time.Time `json:"updatedAt"`
+	RequestMachineCode string          `json:"requestMachineCode"`
+	ClientMachineCode  string          `json:"clientMachineCode"`
+	MobileNumber       string          `json:"mobileNumber"`
+	Instruction        string          `json:"instruction"`
+	Payload            json.RawMessage `json:"payload,omitempty" copier:"-" swaggertype:"object"`
+	SimID              uint64          `json:"simId"`       // 0 if unknown
+	GroupCallID        uint64          `json:"groupCallId"` // the group the call was dialed by, 0 if the client was given
+	State              string          `json:"state"`       // scheduled, requested, dispatched, ringing, answered, ended, failed, busy or cancelled
+	ScheduledAt        *time.Time      `json:"scheduledAt"` // the time the instruction is sent to the client device, empty if it was sent when the call was created
+	StateUpdatedAt     *time.Time      `json:"stateUpdatedAt"`
+	DispatchedAt       *time.Time      `json:"dispatchedAt"`
+	RingingAt          *time.Time      `json:"ringingAt"`
+	AnsweredAt         *time.Time      `json:"answeredAt"`
+	EndedAt            *time.Time      `json:"endedAt"`
+	TalkDuration       int             `json:"talkDuration"` // seconds
+	FailureReason      string          `json:"failureReason"`
+	Version            uint64          `json:"version"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+	DeletedAt          *time.Time      `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
+
+	Recording *CallRecordingObjDetail `json:"recording,omitempty" copier:"-"` // the recording of the call, only in the detail of a call
 }
 
 // CreateCallHistoryRespond only for api docs
@@ -49,11 +84,39 @@ type CreateCallHistoryRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateCallHistorysBatchRequest request params
+type CreateCallHistorysBatchRequest struct {
+	Atomic  bool                       `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateCallHistoryRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateCallHistorysBatchRespond only for api docs
+type CreateCallHistorysBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateCallHistoryByIDRespond only for api docs
 type UpdateCallHistoryByIDRespond struct {
 	Result
 }
 
+// PatchCallHistoryByIDRespond only for api docs
+type PatchCallHistoryByIDRespond struct {
+	Result
+}
+
+// RestoreCallHistoryByIDRespond only for api docs
+type RestoreCallHistoryByIDRespond struct {
+	Result
+}
+
+// PurgeCallHistoryByIDRespond only for api docs
+type PurgeCallHistoryByIDRespond struct {
+	Result
+}
+
 // GetCallHistoryByIDRespond only for api docs
 type GetCallHistoryByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -119,3 +182,54 @@ type ListCallHistorysByIDsRespond struct {
 		CallHistorys []CallHistoryObjDetail `json:"callHistorys"`
 	} `json:"data"` // return data
 }
+
+// ListCallHistorysByCursorRespond only for api docs
+type ListCallHistorysByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallHistorys []CallHistoryObjDetail `json:"callHistorys"`
+		NextCursor   string                 `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// ReportCallHistoryStateRequest request params
+type ReportCallHistoryStateRequest struct {
+	State         string     `json:"state" binding:"required,oneof=dispatched ringing answered ended failed busy"`
+	At            *time.Time `json:"at"`                              // time of the change on the device, empty is now, a time in the future is now
+	TalkDuration  int        `json:"talkDuration" binding:"gte=0"`    // seconds, for a final state, if 0 it is counted from the time the call was answered
+	FailureReason string     `json:"failureReason" binding:"max=255"` // for failed and busy
+}
+
+// ReportCallHistoryStateRespond only for api docs
+type ReportCallHistoryStateRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallHistory CallHistoryObjDetail `json:"callHistory"`
+	} `json:"data"` // return data
+}
+
+// CancelCallHistoryRespond only for api docs
+type CancelCallHistoryRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallHistory CallHistoryObjDetail `json:"callHistory"`
+	} `json:"data"` // return data
+}
+
+// RescheduleCallHistoryRequest request params, one of scheduledAt and scheduleIn is required
+type RescheduleCallHistoryRequest struct {
+	ScheduledAt *time.Time `json:"scheduledAt"` // a time in the past sends the instruction at the next run of the scheduler
+	ScheduleIn  string     `json:"scheduleIn"`  // a duration such as 30m or 2h from now
+}
+
+// RescheduleCallHistoryRespond only for api docs
+type RescheduleCallHistoryRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallHistory CallHistoryObjDetail `json:"callHistory"`
+	} `json:"data"` // return data
+}
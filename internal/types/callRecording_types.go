@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// StartCallRecordingRequest request params
+type StartCallRecordingRequest struct {
+	Codec    string `json:"codec" binding:"required,max=32,printascii"`     // codec of the audio, e.g. amr, aac, opus
+	Duration int    `json:"duration" binding:"gte=0"`                       // milliseconds of audio
+	Size     int64  `json:"size" binding:"required,gt=0"`                   // bytes of the audio
+	Checksum string `json:"checksum" binding:"required,len=64,hexadecimal"` // hex sha256 of the audio, the upload is rejected when the audio does not match
+}
+
+// CallRecordingObjDetail detail
+type CallRecordingObjDetail struct {
+	ID string `json:"id"` // convert to string id
+
+	CallHistoryID uint64     `json:"callHistoryId"`
+	Codec         string     `json:"codec"`
+	Duration      int        `json:"duration"`                      // milliseconds of audio
+	Size          int64      `json:"size"`                          // bytes of the audio
+	Checksum      string     `json:"checksum"`                      // hex sha256 of the audio
+	Status        string     `json:"status"`                        // uploading or ready, only a ready recording can be downloaded
+	Received      int64      `json:"received,omitempty" copier:"-"` // bytes uploaded, the upload is resumed from here, only in the responds to the device
+	UploadedAt    *time.Time `json:"uploadedAt"`
+	DownloadURL   string     `json:"downloadUrl" copier:"-"` // empty until the upload is complete
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// StartCallRecordingRespond only for api docs
+type StartCallRecordingRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallRecording CallRecordingObjDetail `json:"callRecording"`
+	} `json:"data"` // return data
+}
+
+// GetCallRecordingUploadRespond only for api docs
+type GetCallRecordingUploadRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallRecording CallRecordingObjDetail `json:"callRecording"`
+	} `json:"data"` // return data
+}
+
+// UploadCallRecordingRespond only for api docs
+type UploadCallRecordingRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallRecording CallRecordingObjDetail `json:"callRecording"`
+	} `json:"data"` // return data
+}
@@ -24,14 +24,47 @@ type UpdateClientsByIDRequest struct {
 	IPAddress   string `json:"ipAddress" binding:""`
 }
 
+// PatchClientsByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchClientsByIDRequest struct {
+	MachineCode *string `json:"machineCode,omitempty"`
+	IPAddress   *string `json:"ipAddress,omitempty"`
+}
+
 // ClientsObjDetail detail
 type ClientsObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	MachineCode string    `json:"machineCode"`
-	IPAddress   string    `json:"ipAddress"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	MachineCode string     `json:"machineCode"`
+	IPAddress   string     `json:"ipAddress"`
+	Version     uint64     `json:"version"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" copier:"-"`  // only set for records in the trash
+	Online      bool       `json:"online" copier:"-"`               // whether a heartbeat was received within the offline timeout
+	LastSeenAt  *time.Time `json:"lastSeenAt,omitempty" copier:"-"` // time of the last heartbeat, empty if none was received
+
+	CredentialIssuedAt *time.Time `json:"credentialIssuedAt,omitempty"` // time the device credential was issued, empty if the client has no valid credential
+
+	DeviceModel       string     `json:"deviceModel"`
+	AndroidVersion    string     `json:"androidVersion"`
+	AppVersion        string     `json:"appVersion"`
+	AppVersionCode    int        `json:"appVersionCode"` // versionCode of the app, sort and filter by it to find the outdated devices
+	BatteryLevel      int        `json:"batteryLevel"`   // percent, 0-100
+	IsCharging        bool       `json:"isCharging"`
+	SignalStrength    int        `json:"signalStrength"` // dBm
+	Carrier           string     `json:"carrier"`
+	SimSlotCount      int        `json:"simSlotCount"`
+	MetadataUpdatedAt *time.Time `json:"metadataUpdatedAt,omitempty"` // time the device last reported its metadata, empty if it never did
+
+	ReleaseChannel string `json:"releaseChannel"` // release channel the client is assigned to, empty if it is not assigned
+
+	Status          string     `json:"status"`                    // active, disabled or quarantined
+	StatusReason    string     `json:"statusReason"`              // why the status was set
+	StatusOperator  string     `json:"statusOperator"`            // the operator who set the status
+	StatusUpdatedAt *time.Time `json:"statusUpdatedAt,omitempty"` // time the status was set, empty if it never changed
+
+	Labels map[string]string `json:"labels,omitempty" copier:"-"` // key/value labels, e.g. the site or the customer of the client
 }
 
 // CreateClientsRespond only for api docs
@@ -43,11 +76,39 @@ type CreateClientsRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateClientssBatchRequest request params
+type CreateClientssBatchRequest struct {
+	Atomic  bool                   `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateClientsRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateClientssBatchRespond only for api docs
+type CreateClientssBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateClientsByIDRespond only for api docs
 type UpdateClientsByIDRespond struct {
 	Result
 }
 
+// PatchClientsByIDRespond only for api docs
+type PatchClientsByIDRespond struct {
+	Result
+}
+
+// RestoreClientsByIDRespond only for api docs
+type RestoreClientsByIDRespond struct {
+	Result
+}
+
+// PurgeClientsByIDRespond only for api docs
+type PurgeClientsByIDRespond struct {
+	Result
+}
+
 // GetClientsByIDRespond only for api docs
 type GetClientsByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -70,6 +131,9 @@ type DeleteClientssByIDsRespond struct {
 // ListClientssRequest request params
 type ListClientssRequest struct {
 	query.Params
+
+	Online        *bool  `json:"online,omitempty"`        // true: only online clients, false: only offline clients, empty: all clients
+	LabelSelector string `json:"labelSelector,omitempty"` // select the clients by their labels, e.g. site=shanghai,carrier!=cmcc
 }
 
 // ListClientssRespond only for api docs
@@ -113,3 +177,136 @@ type ListClientssByIDsRespond struct {
 		Clientss []ClientsObjDetail `json:"clientss"`
 	} `json:"data"` // return data
 }
+
+// ListClientssByCursorRespond only for api docs
+type ListClientssByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clientss   []ClientsObjDetail `json:"clientss"`
+		NextCursor string             `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// SetClientsStatusRequest request params
+type SetClientsStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active disabled quarantined"` // a disabled or quarantined client is not routed calls and its device apis are rejected
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// SetClientsStatusRespond only for api docs
+type SetClientsStatusRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clients ClientsObjDetail `json:"clients"`
+	} `json:"data"` // return data
+}
+
+// HeartbeatClientsRequest request params
+type HeartbeatClientsRequest struct {
+	MachineCode string `json:"machineCode" binding:"required"`
+	IPAddress   string `json:"ipAddress" binding:""` // if empty, the ip address of the request is used
+}
+
+// HeartbeatClientsRespond only for api docs
+type HeartbeatClientsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clients ClientsObjDetail `json:"clients"`
+	} `json:"data"` // return data
+}
+
+// IssueClientsEnrollmentCodeRequest request params
+type IssueClientsEnrollmentCodeRequest struct {
+	MachineCode string `json:"machineCode" binding:""`                // if set, only the device with this machine code can use the code
+	ExpiresIn   int    `json:"expiresIn" binding:"gte=0,lte=2592000"` // seconds until the code expires, default is 86400, at most 30 days
+}
+
+// IssueClientsEnrollmentCodeRespond only for api docs
+type IssueClientsEnrollmentCodeRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		EnrollmentCode string    `json:"enrollmentCode"` // one-time code, it is only returned here
+		ExpiresAt      time.Time `json:"expiresAt"`
+	} `json:"data"` // return data
+}
+
+// EnrollClientsRequest request params
+type EnrollClientsRequest struct {
+	EnrollmentCode string `json:"enrollmentCode" binding:"required"`
+	MachineCode    string `json:"machineCode" binding:"required"`
+	IPAddress      string `json:"ipAddress" binding:""` // if empty, the ip address of the request is used
+}
+
+// EnrollClientsRespond only for api docs
+type EnrollClientsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clients    ClientsObjDetail `json:"clients"`
+		Credential string           `json:"credential"` // send it in the header "Authorization: Device <credential>", it is only returned here
+	} `json:"data"` // return data
+}
+
+// RotateClientsCredentialRespond only for api docs
+type RotateClientsCredentialRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Credential string `json:"credential"` // the new credential, the previous one is no longer valid
+	} `json:"data"` // return data
+}
+
+// RevokeClientsCredentialRespond only for api docs
+type RevokeClientsCredentialRespond struct {
+	Result
+}
+
+// UpsertClientsMetadataRequest request params, empty fields are not changed
+type UpsertClientsMetadataRequest struct {
+	MachineCode    string  `json:"machineCode" binding:""` // if empty, the machine code of the device credential is used
+	DeviceModel    *string `json:"deviceModel" binding:"omitempty,max=64"`
+	AndroidVersion *string `json:"androidVersion" binding:"omitempty,max=16"`
+	AppVersion     *string `json:"appVersion" binding:"omitempty,max=32"`
+	AppVersionCode *int    `json:"appVersionCode" binding:"omitempty,gte=0"`       // versionCode of the app, if empty the X-App-Version-Code header is used
+	BatteryLevel   *int    `json:"batteryLevel" binding:"omitempty,gte=0,lte=100"` // percent
+	IsCharging     *bool   `json:"isCharging" binding:""`
+	SignalStrength *int    `json:"signalStrength" binding:""` // dBm
+	Carrier        *string `json:"carrier" binding:"omitempty,max=64"`
+	SimSlotCount   *int    `json:"simSlotCount" binding:"omitempty,gte=0"`
+}
+
+// UpsertClientsMetadataRespond only for api docs
+type UpsertClientsMetadataRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clients ClientsObjDetail `json:"clients"`
+	} `json:"data"` // return data
+}
+
+// ClientsIPHistoryObjDetail a change of the ip address of a clients
+type ClientsIPHistoryObjDetail struct {
+	ID string `json:"id"` // convert to string id
+
+	ClientID      string    `json:"clientId"`
+	MachineCode   string    `json:"machineCode"`
+	PreviousIP    string    `json:"previousIp"` // empty if the clients had no ip address
+	IPAddress     string    `json:"ipAddress"`
+	SubnetChanged bool      `json:"subnetChanged"` // the ip address moved to another /24 (ipv4) or /64 (ipv6) network
+	MassChange    bool      `json:"massChange"`    // many clients changed their ip address at the same time
+	CreatedAt     time.Time `json:"createdAt"`     // time of the change
+}
+
+// ListClientsIPHistoryRespond only for api docs
+type ListClientsIPHistoryRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		IPHistory  []ClientsIPHistoryObjDetail `json:"ipHistory"`
+		NextCursor string                      `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
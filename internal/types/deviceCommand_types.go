@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// CreateDeviceCommandRequest request params
+type CreateDeviceCommandRequest struct {
+	Instruction  string          `json:"instruction" binding:"required,max=64"`   // one of the instructions listed by GET /api/v1/instructions
+	Payload      json.RawMessage `json:"payload" copier:"-" swaggertype:"object"` // payload of the instruction
+	MobileNumber string          `json:"mobileNumber" binding:"max=11"`
+	ExpiresIn    int             `json:"expiresIn" binding:"gte=0,lte=86400"` // seconds until the command expires if it is not delivered, default is the deviceCommandTTL of the config, at most 1 day
+}
+
+// DeviceCommandObjDetail detail
+type DeviceCommandObjDetail struct {
+	ID string `json:"id"` // convert to string id
+
+	MachineCode   string          `json:"machineCode"`
+	CallHistoryID string          `json:"callHistoryId"` // "0" if the command was not queued for a call history
+	Instruction   string          `json:"instruction"`
+	Payload       json.RawMessage `json:"payload,omitempty" copier:"-" swaggertype:"object"` // omitted if the instruction has no payload
+	MobileNumber  string          `json:"mobileNumber"`
+	State         string          `json:"state"` // pending, delivered, acked or expired
+	ExpiresAt     time.Time       `json:"expiresAt"`
+	DeliveredAt   *time.Time      `json:"deliveredAt,omitempty"`
+	AckedAt       *time.Time      `json:"ackedAt,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// CreateDeviceCommandRespond only for api docs
+type CreateDeviceCommandRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// PollDeviceCommandsRespond only for api docs
+type PollDeviceCommandsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Commands []DeviceCommandObjDetail `json:"commands"` // empty if no command was queued before the wait ended
+	} `json:"data"` // return data
+}
+
+// AckDeviceCommandRespond only for api docs
+type AckDeviceCommandRespond struct {
+	Result
+}
+
+// RedeliverDeviceCommandRespond only for api docs
+type RedeliverDeviceCommandRespond struct {
+	Result
+}
+
+// ListDeviceCommandsRespond only for api docs
+type ListDeviceCommandsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Commands   []DeviceCommandObjDetail `json:"commands"`
+		NextCursor string                   `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
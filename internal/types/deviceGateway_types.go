@@ -0,0 +1,19 @@
+package types
+
+// the types of the messages of the device websocket
+const (
+	DeviceMessageCommands = "commands" // server to device, commands queued for the device, ack each after it is executed
+	DeviceMessageAck      = "ack"      // device to server, a command was executed, id is the id of the command
+	DeviceMessageAcked    = "acked"    // server to device, the ack of the command with the id was saved
+	DeviceMessageStatus   = "status"   // device to server, the metadata of the device in status, also keeps the device online
+	DeviceMessageError    = "error"    // server to device, a message of the device was not handled, msg is the reason
+)
+
+// DeviceMessage a message of the device websocket, a json text message
+type DeviceMessage struct {
+	Type     string                        `json:"type"`
+	ID       string                        `json:"id,omitempty"`       // id of the command of ack and acked messages
+	Commands []*DeviceCommandObjDetail     `json:"commands,omitempty"` // commands of commands messages
+	Status   *UpsertClientsMetadataRequest `json:"status,omitempty"`   // metadata of status messages, the machine code is the one of the connection
+	Msg      string                        `json:"msg,omitempty"`      // reason of error messages
+}
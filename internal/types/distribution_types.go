@@ -24,14 +24,23 @@ type UpdateDistributionByIDRequest struct {
 	GroupCallID int `json:"groupCallId" binding:""`
 }
 
+// PatchDistributionByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchDistributionByIDRequest struct {
+	UserID      *int `json:"userId,omitempty"`
+	GroupCallID *int `json:"groupCallId,omitempty"`
+}
+
 // DistributionObjDetail detail
 type DistributionObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	UserID      int       `json:"userId"`
-	GroupCallID int       `json:"groupCallId"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	UserID      int        `json:"userId"`
+	GroupCallID int        `json:"groupCallId"`
+	Version     uint64     `json:"version"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
 }
 
 // CreateDistributionRespond only for api docs
@@ -43,11 +52,39 @@ type CreateDistributionRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateDistributionsBatchRequest request params
+type CreateDistributionsBatchRequest struct {
+	Atomic  bool                        `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateDistributionRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateDistributionsBatchRespond only for api docs
+type CreateDistributionsBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateDistributionByIDRespond only for api docs
 type UpdateDistributionByIDRespond struct {
 	Result
 }
 
+// PatchDistributionByIDRespond only for api docs
+type PatchDistributionByIDRespond struct {
+	Result
+}
+
+// RestoreDistributionByIDRespond only for api docs
+type RestoreDistributionByIDRespond struct {
+	Result
+}
+
+// PurgeDistributionByIDRespond only for api docs
+type PurgeDistributionByIDRespond struct {
+	Result
+}
+
 // GetDistributionByIDRespond only for api docs
 type GetDistributionByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -113,3 +150,13 @@ type ListDistributionsByIDsRespond struct {
 		Distributions []DistributionObjDetail `json:"distributions"`
 	} `json:"data"` // return data
 }
+
+// ListDistributionsByCursorRespond only for api docs
+type ListDistributionsByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Distributions []DistributionObjDetail `json:"distributions"`
+		NextCursor    string                  `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
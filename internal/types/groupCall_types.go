@@ -26,15 +26,27 @@ type UpdateGroupCallByIDRequest struct {
 	TransferClientID string `json:"transferClientId" binding:""`
 }
 
+// PatchGroupCallByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchGroupCallByIDRequest struct {
+	GroupNumber      *string `json:"groupNumber,omitempty"`
+	PhoneNumber      *string `json:"phoneNumber,omitempty"`
+	TransferClientID *string `json:"transferClientId,omitempty"`
+}
+
 // GroupCallObjDetail detail
 type GroupCallObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	GroupNumber      string    `json:"groupNumber"`
-	PhoneNumber      string    `json:"phoneNumber"`
-	TransferClientID string    `json:"transferClientId"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	GroupNumber      string     `json:"groupNumber"`
+	PhoneNumber      string     `json:"phoneNumber"`
+	TransferClientID string     `json:"transferClientId"`
+	Version          uint64     `json:"version"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	DeletedAt        *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
+
+	Labels map[string]string `json:"labels,omitempty" copier:"-"` // key/value labels, e.g. the customer of the group
 }
 
 // CreateGroupCallRespond only for api docs
@@ -46,11 +58,39 @@ type CreateGroupCallRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateGroupCallsBatchRequest request params
+type CreateGroupCallsBatchRequest struct {
+	Atomic  bool                     `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateGroupCallRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateGroupCallsBatchRespond only for api docs
+type CreateGroupCallsBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateGroupCallByIDRespond only for api docs
 type UpdateGroupCallByIDRespond struct {
 	Result
 }
 
+// PatchGroupCallByIDRespond only for api docs
+type PatchGroupCallByIDRespond struct {
+	Result
+}
+
+// RestoreGroupCallByIDRespond only for api docs
+type RestoreGroupCallByIDRespond struct {
+	Result
+}
+
+// PurgeGroupCallByIDRespond only for api docs
+type PurgeGroupCallByIDRespond struct {
+	Result
+}
+
 // GetGroupCallByIDRespond only for api docs
 type GetGroupCallByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -73,6 +113,8 @@ type DeleteGroupCallsByIDsRespond struct {
 // ListGroupCallsRequest request params
 type ListGroupCallsRequest struct {
 	query.Params
+
+	LabelSelector string `json:"labelSelector,omitempty"` // select the groupCalls by their labels, e.g. site=shanghai,carrier!=cmcc
 }
 
 // ListGroupCallsRespond only for api docs
@@ -116,3 +158,34 @@ type ListGroupCallsByIDsRespond struct {
 		GroupCalls []GroupCallObjDetail `json:"groupCalls"`
 	} `json:"data"` // return data
 }
+
+// ListGroupCallsByCursorRespond only for api docs
+type ListGroupCallsByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		GroupCalls []GroupCallObjDetail `json:"groupCalls"`
+		NextCursor string               `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// DialGroupCallRequest request params
+type DialGroupCallRequest struct {
+	MobileNumber       string `json:"mobileNumber" binding:"required,max=11"` // the number to call
+	RequestMachineCode string `json:"requestMachineCode" binding:""`
+	Strategy           string `json:"strategy" binding:"omitempty,oneof=roundRobin leastRecentlyUsed random stickyPerCallee"` // how the client is chosen, default is the strategy of the config
+	LabelSelector      string `json:"labelSelector" binding:""`                                                               // only the clients whose labels match it are chosen, e.g. "site=shanghai,carrier!=cmcc"
+	SimSlot            *int   `json:"simSlot" binding:"omitempty,gte=0,lte=7"`                                                // slot of the sim the client calls with, only the clients with a sim in the slot are chosen
+}
+
+// DialGroupCallRespond only for api docs
+type DialGroupCallRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		CallHistoryID string `json:"callHistoryId"` // the call history created for the call
+		ClientID      string `json:"clientId"`      // the client chosen to place the call
+		MachineCode   string `json:"machineCode"`   // machine code of the client
+		Strategy      string `json:"strategy"`      // the strategy the client was chosen with
+	} `json:"data"` // return data
+}
@@ -24,14 +24,23 @@ type UpdateGroupClientByIDRequest struct {
 	ClientID int `json:"clientId" binding:""`
 }
 
+// PatchGroupClientByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchGroupClientByIDRequest struct {
+	GroupID  *int `json:"groupId,omitempty"`
+	ClientID *int `json:"clientId,omitempty"`
+}
+
 // GroupClientObjDetail detail
 type GroupClientObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	GroupID   int       `json:"groupId"`
-	ClientID  int       `json:"clientId"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	GroupID   int        `json:"groupId"`
+	ClientID  int        `json:"clientId"`
+	Version   uint64     `json:"version"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
 }
 
 // CreateGroupClientRespond only for api docs
@@ -43,11 +52,39 @@ type CreateGroupClientRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateGroupClientsBatchRequest request params
+type CreateGroupClientsBatchRequest struct {
+	Atomic  bool                       `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateGroupClientRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateGroupClientsBatchRespond only for api docs
+type CreateGroupClientsBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateGroupClientByIDRespond only for api docs
 type UpdateGroupClientByIDRespond struct {
 	Result
 }
 
+// PatchGroupClientByIDRespond only for api docs
+type PatchGroupClientByIDRespond struct {
+	Result
+}
+
+// RestoreGroupClientByIDRespond only for api docs
+type RestoreGroupClientByIDRespond struct {
+	Result
+}
+
+// PurgeGroupClientByIDRespond only for api docs
+type PurgeGroupClientByIDRespond struct {
+	Result
+}
+
 // GetGroupClientByIDRespond only for api docs
 type GetGroupClientByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -113,3 +150,22 @@ type ListGroupClientsByIDsRespond struct {
 		GroupClients []GroupClientObjDetail `json:"groupClients"`
 	} `json:"data"` // return data
 }
+
+// ListGroupClientsByCursorRespond only for api docs
+type ListGroupClientsByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		GroupClients []GroupClientObjDetail `json:"groupClients"`
+		NextCursor   string                 `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// ListGroupClientMembersRespond only for api docs
+type ListGroupClientMembersRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Clientss []ClientsObjDetail `json:"clientss"`
+	} `json:"data"` // return data
+}
@@ -0,0 +1,27 @@
+package types
+
+// InstructionObjDetail detail
+type InstructionObjDetail struct {
+	Name                 string                      `json:"name"`
+	Description          string                      `json:"description"`
+	MobileNumberRequired bool                        `json:"mobileNumberRequired"` // the instruction needs the mobile number of the call history or the command
+	Payload              []InstructionFieldObjDetail `json:"payload"`              // fields of the payload, empty if the instruction has no payload
+}
+
+// InstructionFieldObjDetail a field of the payload of an instruction
+type InstructionFieldObjDetail struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, integer or boolean
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// ListInstructionsRespond only for api docs
+type ListInstructionsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Version      int                    `json:"version"` // version of the instruction set, increased when an instruction or a payload field is added or changed
+		Instructions []InstructionObjDetail `json:"instructions"`
+	} `json:"data"` // return data
+}
@@ -0,0 +1,21 @@
+package types
+
+// SetLabelsRequest request params
+type SetLabelsRequest struct {
+	Labels map[string]string `json:"labels" binding:"required"` // replace all the labels of the record, {} removes them
+}
+
+// PatchLabelsRequest request params, the labels are a json merge patch (RFC 7396) of the labels of the record,
+// only the labels present are changed and null removes a label
+type PatchLabelsRequest struct {
+	Labels map[string]*string `json:"labels" binding:"required"`
+}
+
+// LabelsRespond only for api docs
+type LabelsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Labels map[string]string `json:"labels"` // the labels of the record
+	} `json:"data"` // return data
+}
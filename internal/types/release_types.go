@@ -0,0 +1,120 @@
+package types
+
+import (
+	"time"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// UploadReleaseRequest request params, sent as multipart/form-data with the apk in the file field
+type UploadReleaseRequest struct {
+	VersionCode  int    `form:"versionCode" binding:"required,gt=0"`
+	VersionName  string `form:"versionName" binding:"required,max=32"`
+	Checksum     string `form:"checksum" binding:"omitempty,len=64,hexadecimal"` // hex sha256 of the apk, if set the upload is rejected when the file does not match
+	ReleaseNotes string `form:"releaseNotes" binding:"max=1024"`
+}
+
+// UpsertReleaseChannelRequest request params, the fields that are not set are not changed
+type UpsertReleaseChannelRequest struct {
+	ReleaseID      *uint64 `json:"releaseId"`                                        // release offered by the channel, the replaced release is still offered to the clients outside the rollout, 0 removes the release
+	RolloutPercent *int    `json:"rolloutPercent" binding:"omitempty,gte=0,lte=100"` // percentage of the clients of the channel that are offered the release, a new channel has 100
+	ClientPercent  *int    `json:"clientPercent" binding:"omitempty,gte=0,lte=100"`  // percentage of the unassigned clients that are in the channel, the channels share at most 100
+}
+
+// SetReleaseChannelClientsRequest request params
+type SetReleaseChannelClientsRequest struct {
+	MachineCodes []string `json:"machineCodes" binding:"required,min=1,max=1000,dive,required,max=32"`
+	Channel      string   `json:"channel" binding:"max=32"` // empty removes the assignment, the clients are then placed by the client percent of the channels
+}
+
+// ReleaseObjDetail detail
+type ReleaseObjDetail struct {
+	ID string `json:"id"` // convert to string id
+
+	VersionCode  int       `json:"versionCode"`
+	VersionName  string    `json:"versionName"`
+	FileSize     int64     `json:"fileSize"` // bytes
+	Checksum     string    `json:"checksum"` // hex sha256 of the apk
+	ReleaseNotes string    `json:"releaseNotes"`
+	DownloadURL  string    `json:"downloadUrl" copier:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// ReleaseChannelObjDetail detail
+type ReleaseChannelObjDetail struct {
+	Name              string    `json:"name"`
+	ReleaseID         string    `json:"releaseId" copier:"-"`         // "0" if the channel has no release
+	PreviousReleaseID string    `json:"previousReleaseId" copier:"-"` // offered to the clients outside the rollout, "0" if none
+	RolloutPercent    int       `json:"rolloutPercent"`
+	ClientPercent     int       `json:"clientPercent"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// UploadReleaseRespond only for api docs
+type UploadReleaseRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Release ReleaseObjDetail `json:"release"`
+	} `json:"data"` // return data
+}
+
+// GetReleaseByIDRespond only for api docs
+type GetReleaseByIDRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Release ReleaseObjDetail `json:"release"`
+	} `json:"data"` // return data
+}
+
+// ListReleasesRespond only for api docs
+type ListReleasesRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Releases   []ReleaseObjDetail `json:"releases"`
+		NextCursor string             `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// UpsertReleaseChannelRespond only for api docs
+type UpsertReleaseChannelRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Channel ReleaseChannelObjDetail `json:"channel"`
+	} `json:"data"` // return data
+}
+
+// ListReleaseChannelsRespond only for api docs
+type ListReleaseChannelsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Channels []ReleaseChannelObjDetail `json:"channels"`
+	} `json:"data"` // return data
+}
+
+// SetReleaseChannelClientsRespond only for api docs
+type SetReleaseChannelClientsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Changed int64 `json:"changed"` // number of clients whose channel changed
+	} `json:"data"` // return data
+}
+
+// CheckUpdateRespond only for api docs
+type CheckUpdateRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Channel         string            `json:"channel"`         // release channel of the device, empty if there is no channel
+		UpdateAvailable bool              `json:"updateAvailable"` // whether the release is newer than the app of the device
+		Mandatory       bool              `json:"mandatory"`       // the app is older than the minimum supported version, the device apis reject it until it is updated
+		Release         *ReleaseObjDetail `json:"release"`         // the release offered to the device, null if there is none
+	} `json:"data"` // return data
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/ggorm/query"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// CreateSimRequest request params
+type CreateSimRequest struct {
+	ICCID       string     `json:"iccid" binding:"required,min=18,max=22,alphanum"`
+	PhoneNumber string     `json:"phoneNumber" binding:"max=20"`
+	Carrier     string     `json:"carrier" binding:"max=64"`
+	Status      string     `json:"status" binding:"omitempty,oneof=active suspended cancelled"` // default is active
+	ExpiresAt   *time.Time `json:"expiresAt"`                                                   // empty if the sim does not expire
+}
+
+// UpdateSimByIDRequest request params
+type UpdateSimByIDRequest struct {
+	ID uint64 `json:"id" binding:""` // uint64 id
+
+	PhoneNumber string     `json:"phoneNumber" binding:"max=20"`
+	Carrier     string     `json:"carrier" binding:"max=64"`
+	Status      string     `json:"status" binding:"omitempty,oneof=active suspended cancelled"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+// BindSimRequest request params
+type BindSimRequest struct {
+	ClientID  uint64 `json:"clientId" binding:""`             // 0 removes the sim from its device
+	SlotIndex int    `json:"slotIndex" binding:"gte=0,lte=7"` // slot of the device, starting from 0, the sim in the slot is removed from it
+}
+
+// ReportSimsRequest request params
+type ReportSimsRequest struct {
+	Sims []ReportSimRequest `json:"sims" binding:"max=8,dive"` // all the sims in the device, an empty list if it has none
+}
+
+// ReportSimRequest a sim reported by a device
+type ReportSimRequest struct {
+	SlotIndex   int    `json:"slotIndex" binding:"gte=0,lte=7"`
+	ICCID       string `json:"iccid" binding:"required,min=18,max=22,alphanum"`
+	PhoneNumber string `json:"phoneNumber" binding:"max=20"` // empty if the device cannot read it, the known number is kept
+	Carrier     string `json:"carrier" binding:"max=64"`
+}
+
+// SimObjDetail detail
+type SimObjDetail struct {
+	ID string `json:"id"` // convert to string id
+
+	ICCID           string     `json:"iccid"`
+	PhoneNumber     string     `json:"phoneNumber"`
+	Carrier         string     `json:"carrier"`
+	ClientID        uint64     `json:"clientId"` // 0 if the sim is not in a device
+	SlotIndex       int        `json:"slotIndex"`
+	Status          string     `json:"status"` // active, suspended or cancelled
+	ExpiresAt       *time.Time `json:"expiresAt"`
+	DuplicateNumber bool       `json:"duplicateNumber" copier:"-"` // true if the phone number is also on a sim in another device
+	Version         uint64     `json:"version"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// CreateSimRespond only for api docs
+type CreateSimRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// UpdateSimByIDRespond only for api docs
+type UpdateSimByIDRespond struct {
+	Result
+}
+
+// GetSimByIDRespond only for api docs
+type GetSimByIDRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sim SimObjDetail `json:"sim"`
+	} `json:"data"` // return data
+}
+
+// DeleteSimByIDRespond only for api docs
+type DeleteSimByIDRespond struct {
+	Result
+}
+
+// BindSimRespond only for api docs
+type BindSimRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sim SimObjDetail `json:"sim"`
+	} `json:"data"` // return data
+}
+
+// ListSimsRequest request params
+type ListSimsRequest struct {
+	query.Params
+}
+
+// ListSimsRespond only for api docs
+type ListSimsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sims  []SimObjDetail `json:"sims"`
+		Total int64          `json:"total"`
+	} `json:"data"` // return data
+}
+
+// ListSimsByCursorRespond only for api docs
+type ListSimsByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sims       []SimObjDetail `json:"sims"`
+		NextCursor string         `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
+
+// ListClientSimsRespond only for api docs
+type ListClientSimsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sims []SimObjDetail `json:"sims"` // ordered by slot
+	} `json:"data"` // return data
+}
+
+// ListDuplicateSimsRespond only for api docs
+type ListDuplicateSimsRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sims []SimObjDetail `json:"sims"` // ordered by phone number, the sims sharing a number are next to each other
+	} `json:"data"` // return data
+}
@@ -17,6 +17,8 @@ type CreateSmsRequest struct {
 	Date        string `json:"date" binding:""`
 	Body        string `json:"body" binding:""`
 	SmsType     string `json:"smsType" binding:""`
+	SimID       uint64 `json:"simId" binding:""`                        // the sim used, it must be in the device, 0 if unknown
+	SimSlot     *int   `json:"simSlot" binding:"omitempty,gte=0,lte=7"` // slot of the device the sim is in, used to find the sim if simId is 0, the slot must not be empty
 }
 
 // UpdateSmsByIDRequest request params
@@ -28,19 +30,34 @@ type UpdateSmsByIDRequest struct {
 	Date        string `json:"date" binding:""`
 	Body        string `json:"body" binding:""`
 	SmsType     string `json:"smsType" binding:""`
+	SimID       uint64 `json:"simId" binding:""`
+}
+
+// PatchSmsByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchSmsByIDRequest struct {
+	MachineCode *string `json:"machineCode,omitempty"`
+	Address     *string `json:"address,omitempty"`
+	Date        *string `json:"date,omitempty"`
+	Body        *string `json:"body,omitempty"`
+	SmsType     *string `json:"smsType,omitempty"`
+	SimID       *uint64 `json:"simId,omitempty"`
 }
 
 // SmsObjDetail detail
 type SmsObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	MachineCode string    `json:"machineCode"`
-	Address     string    `json:"address"`
-	Date        string    `json:"date"`
-	Body        string    `json:"body"`
-	SmsType     string    `json:"smsType"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	MachineCode string     `json:"machineCode"`
+	Address     string     `json:"address"`
+	Date        string     `json:"date"`
+	Body        string     `json:"body"`
+	SmsType     string     `json:"smsType"`
+	SimID       uint64     `json:"simId"` // 0 if unknown
+	Version     uint64     `json:"version"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
 }
 
 // CreateSmsRespond only for api docs
@@ -52,11 +69,39 @@ type CreateSmsRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateSmssBatchRequest request params
+type CreateSmssBatchRequest struct {
+	Atomic  bool               `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateSmsRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateSmssBatchRespond only for api docs
+type CreateSmssBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateSmsByIDRespond only for api docs
 type UpdateSmsByIDRespond struct {
 	Result
 }
 
+// PatchSmsByIDRespond only for api docs
+type PatchSmsByIDRespond struct {
+	Result
+}
+
+// RestoreSmsByIDRespond only for api docs
+type RestoreSmsByIDRespond struct {
+	Result
+}
+
+// PurgeSmsByIDRespond only for api docs
+type PurgeSmsByIDRespond struct {
+	Result
+}
+
 // GetSmsByIDRespond only for api docs
 type GetSmsByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -122,3 +167,13 @@ type ListSmssByIDsRespond struct {
 		Smss []SmsObjDetail `json:"smss"`
 	} `json:"data"` // return data
 }
+
+// ListSmssByCursorRespond only for api docs
+type ListSmssByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Smss       []SmsObjDetail `json:"smss"`
+		NextCursor string         `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
@@ -33,3 +33,10 @@ type Column struct {
 type Conditions struct {
 	Columns []Column `json:"columns"` // columns info
 }
+
+// LabelParams query parameters of the records that have labels
+type LabelParams struct {
+	Params
+
+	LabelSelector string `json:"labelSelector,omitempty"` // select the records by their labels, e.g. site=shanghai,carrier!=cmcc
+}
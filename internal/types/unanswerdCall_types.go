@@ -24,14 +24,23 @@ type UpdateUnanswerdCallByIDRequest struct {
 	MobileNumber      string `json:"mobileNumber" binding:""`
 }
 
+// PatchUnanswerdCallByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchUnanswerdCallByIDRequest struct {
+	ClientMachineCode *string `json:"clientMachineCode,omitempty"`
+	MobileNumber      *string `json:"mobileNumber,omitempty"`
+}
+
 // UnanswerdCallObjDetail detail
 type UnanswerdCallObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	ClientMachineCode string    `json:"clientMachineCode"`
-	MobileNumber      string    `json:"mobileNumber"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
+	ClientMachineCode string     `json:"clientMachineCode"`
+	MobileNumber      string     `json:"mobileNumber"`
+	Version           uint64     `json:"version"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
 }
 
 // CreateUnanswerdCallRespond only for api docs
@@ -43,11 +52,39 @@ type CreateUnanswerdCallRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateUnanswerdCallsBatchRequest request params
+type CreateUnanswerdCallsBatchRequest struct {
+	Atomic  bool                         `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateUnanswerdCallRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateUnanswerdCallsBatchRespond only for api docs
+type CreateUnanswerdCallsBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateUnanswerdCallByIDRespond only for api docs
 type UpdateUnanswerdCallByIDRespond struct {
 	Result
 }
 
+// PatchUnanswerdCallByIDRespond only for api docs
+type PatchUnanswerdCallByIDRespond struct {
+	Result
+}
+
+// RestoreUnanswerdCallByIDRespond only for api docs
+type RestoreUnanswerdCallByIDRespond struct {
+	Result
+}
+
+// PurgeUnanswerdCallByIDRespond only for api docs
+type PurgeUnanswerdCallByIDRespond struct {
+	Result
+}
+
 // GetUnanswerdCallByIDRespond only for api docs
 type GetUnanswerdCallByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -113,3 +150,13 @@ type ListUnanswerdCallsByIDsRespond struct {
 		UnanswerdCalls []UnanswerdCallObjDetail `json:"unanswerdCalls"`
 	} `json:"data"` // return data
 }
+
+// ListUnanswerdCallsByCursorRespond only for api docs
+type ListUnanswerdCallsByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		UnanswerdCalls []UnanswerdCallObjDetail `json:"unanswerdCalls"`
+		NextCursor     string                   `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}
@@ -22,13 +22,21 @@ type UpdateUserByIDRequest struct {
 	MachineCode string `json:"machineCode" binding:""`
 }
 
+// PatchUserByIDRequest request params, a json merge patch (RFC 7396), only the fields present are updated,
+// null or "" clears a field
+type PatchUserByIDRequest struct {
+	MachineCode *string `json:"machineCode,omitempty"`
+}
+
 // UserObjDetail detail
 type UserObjDetail struct {
 	ID string `json:"id"` // convert to string id
 
-	MachineCode string    `json:"machineCode"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	MachineCode string     `json:"machineCode"`
+	Version     uint64     `json:"version"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" copier:"-"` // only set for records in the trash
 }
 
 // CreateUserRespond only for api docs
@@ -40,11 +48,39 @@ type CreateUserRespond struct {
 	} `json:"data"` // return data
 }
 
+// CreateUsersBatchRequest request params
+type CreateUsersBatchRequest struct {
+	Atomic  bool                `json:"atomic"`                                   // true: create all or none of the records, false: create the valid records and report the others
+	Records []CreateUserRequest `json:"records" binding:"required,min=1,max=100"` // at most 100 records
+}
+
+// CreateUsersBatchRespond only for api docs
+type CreateUsersBatchRespond struct {
+	Code int             `json:"code"` // return code
+	Msg  string          `json:"msg"`  // return information description
+	Data CreateBatchData `json:"data"` // return data
+}
+
 // UpdateUserByIDRespond only for api docs
 type UpdateUserByIDRespond struct {
 	Result
 }
 
+// PatchUserByIDRespond only for api docs
+type PatchUserByIDRespond struct {
+	Result
+}
+
+// RestoreUserByIDRespond only for api docs
+type RestoreUserByIDRespond struct {
+	Result
+}
+
+// PurgeUserByIDRespond only for api docs
+type PurgeUserByIDRespond struct {
+	Result
+}
+
 // GetUserByIDRespond only for api docs
 type GetUserByIDRespond struct {
 	Code int    `json:"code"` // return code
@@ -110,3 +146,13 @@ type ListUsersByIDsRespond struct {
 		Users []UserObjDetail `json:"users"`
 	} `json:"data"` // return data
 }
+
+// ListUsersByCursorRespond only for api docs
+type ListUsersByCursorRespond struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Users      []UserObjDetail `json:"users"`
+		NextCursor string          `json:"nextCursor"` // cursor of the next page, empty if there are no more records
+	} `json:"data"` // return data
+}